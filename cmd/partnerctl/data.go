@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"customer-partner/internal/db"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"customer-partner/internal/partnerio"
+	"customer-partner/internal/seed"
+)
+
+// demoSource is the data source of the demo data of the in-memory repository.
+const demoSource = "demo"
+
+// addDataFlag adds the flag selecting the data source to flags.
+func addDataFlag(flags *flag.FlagSet) *string {
+	return flags.String("data", envOr("PARTNERCTL_DATA", demoSource),
+		`data source: "demo", "seed:COUNT[:SEED]", a CSV or JSON Lines file, or the URL of a server`)
+}
+
+// newService returns the partner service on an in-memory repository holding the partners of the source.
+func newService(ctx context.Context, source string) (*domain.PartnerService, error) {
+	partners, err := loadPartners(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	repo := db.NewPartnerInMemoryRepositoryFrom(partners, nil)
+	return domain.NewPartnerService(repo, logging.Discard()), nil
+}
+
+// loadPartners returns the partners of the source. Partners of files without id are identified by their external id.
+func loadPartners(ctx context.Context, source string) ([]entities.Partner, error) {
+	switch {
+	case source == demoSource:
+		return db.NewPartnerInMemoryRepository(nil).GetAllPartners(ctx)
+	case strings.HasPrefix(source, seedSourcePrefix):
+		opts, err := parseSeedSource(source)
+		if err != nil {
+			return nil, err
+		}
+		return seed.Partners(opts), nil
+	case strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://"):
+		query := url.Values{"format": {partnerio.FormatJSONL}}
+		resp, err := do(http.MethodGet, source, "/partners/exports", query, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		return readPartners(resp.Body, partnerio.FormatJSONL, source)
+	default:
+		format := partnerio.FormatOf(source)
+		if format == "" {
+			return nil, fmt.Errorf("cannot tell the format of %s, use a .csv or .jsonl file", source)
+		}
+		file, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return readPartners(file, format, source)
+	}
+}
+
+// readPartners reads all partners of a file. Unlike imports, a single row which cannot be read fails the whole file.
+func readPartners(r io.Reader, format, name string) ([]entities.Partner, error) {
+	rows, err := partnerio.Read(r, format)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	partners := make([]entities.Partner, 0, len(rows))
+	for _, row := range rows {
+		if row.Err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, row.Line, row.Err)
+		}
+		partner := row.Partner
+		if partner.ID == "" {
+			partner.ID = partner.ExternalID
+		}
+		if partner.ID == "" {
+			return nil, fmt.Errorf("%s:%d: partner has neither id nor external id", name, row.Line)
+		}
+		partners = append(partners, partner)
+	}
+	return partners, nil
+}
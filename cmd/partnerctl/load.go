@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"customer-partner/internal/auth"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/seed"
+)
+
+// loadResult is the outcome of a single request of a load test.
+type loadResult struct {
+	latency time.Duration
+	// status is the status code of the response, 0 when no response was received.
+	status int
+}
+
+// runLoad replays generated customers against the matching of a running server and reports the throughput and the
+// latency percentiles. The server should run with RATE_LIMITS=off, or most requests are rejected.
+func runLoad(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("load", stderr)
+	server := flags.String("server", envOr("PARTNERCTL_SERVER", defaultServer), "base URL of the server")
+	requests := flags.Int("n", 1000, "number of requests, ignored with -duration")
+	duration := flags.Duration("duration", 0, "time to send requests for instead of a number of requests")
+	concurrency := flags.Int("c", 10, "number of requests sent at once")
+	randomSeed := flags.Int64("seed", 1, "seed of the customers, the same seed always replays the same customers")
+	cities := flags.String("cities", "",
+		`cities as "name=lat:long[:spread_km[:weight]],...", by default the largest cities`)
+	floorSize := flags.Float64("floor-size", 0, "size of the floors in square meters, to estimate prices")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		fmt.Fprintln(stderr, "load expects no arguments")
+		return errUsage
+	}
+	if *requests < 1 || *concurrency < 1 || *duration < 0 {
+		fmt.Fprintln(stderr, "-n and -c must be positive, -duration must not be negative")
+		return errUsage
+	}
+	if *floorSize != 0 && (*floorSize < domain.MinFloorSize || *floorSize > domain.MaxFloorSize) {
+		fmt.Fprintf(stderr, "-floor-size must be between %d and %d\n", domain.MinFloorSize, domain.MaxFloorSize)
+		return errUsage
+	}
+	opts := seed.Options{Count: 10000, Seed: *randomSeed}
+	if *cities != "" {
+		var err error
+		if opts.Cities, err = seed.ParseCities(*cities); err != nil {
+			fmt.Fprintln(stderr, err)
+			return errUsage
+		}
+	}
+	targets := make([]string, 0, opts.Count)
+	for _, customer := range seed.Customers(opts) {
+		query := url.Values{
+			"material": {customer.Material},
+			"lat":      {strconv.FormatFloat(customer.Latitude, 'f', -1, 64)},
+			"long":     {strconv.FormatFloat(customer.Longitude, 'f', -1, 64)},
+		}
+		if *floorSize > 0 {
+			query.Set("floor_size", strconv.FormatFloat(*floorSize, 'f', -1, 64))
+		}
+		targets = append(targets, strings.TrimSuffix(*server, "/")+"/partners?"+query.Encode())
+	}
+
+	ctx := context.Background()
+	if *duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *duration)
+		defer cancel()
+		*requests = 0
+	}
+	client := &http.Client{
+		Timeout:   requestTimeout,
+		Transport: &http.Transport{MaxIdleConnsPerHost: *concurrency},
+	}
+	next := make(chan string)
+	results := make(chan loadResult)
+	var wg sync.WaitGroup
+	for i := 0; i < *concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range next {
+				results <- send(ctx, client, target)
+			}
+		}()
+	}
+	go func() {
+		defer close(next)
+		for i := 0; *requests == 0 || i < *requests; i++ {
+			select {
+			case next <- targets[i%len(targets)]:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	start := time.Now()
+	var collected []loadResult
+	for result := range results {
+		// Requests canceled at the end of the duration are not part of the results.
+		if result.status == 0 && ctx.Err() != nil {
+			continue
+		}
+		collected = append(collected, result)
+	}
+	printLoadReport(stdout, collected, time.Since(start))
+	return nil
+}
+
+// send sends a match request. Responses are read completely, so that connections are reused.
+func send(ctx context.Context, client *http.Client, target string) loadResult {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return loadResult{}
+	}
+	if key := os.Getenv("PARTNERCTL_API_KEY"); key != "" {
+		req.Header.Set(auth.APIKeyHeader, key)
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return loadResult{latency: time.Since(start)}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return loadResult{latency: time.Since(start), status: resp.StatusCode}
+}
+
+// printLoadReport prints the throughput, the responses by status and the latency percentiles of successful requests.
+func printLoadReport(w io.Writer, results []loadResult, elapsed time.Duration) {
+	statuses := map[int]int{}
+	latencies := make([]time.Duration, 0, len(results))
+	for _, result := range results {
+		statuses[result.status]++
+		if result.status == http.StatusOK {
+			latencies = append(latencies, result.latency)
+		}
+	}
+	fmt.Fprintf(w, "requests:   %d in %s, %.1f/s\n", len(results), elapsed.Round(time.Millisecond),
+		float64(len(results))/elapsed.Seconds())
+	codes := make([]int, 0, len(statuses))
+	for code := range statuses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		status := "no response"
+		if code != 0 {
+			status = fmt.Sprintf("%d %s", code, http.StatusText(code))
+		}
+		fmt.Fprintf(w, "status:     %s: %d\n", status, statuses[code])
+	}
+	if len(latencies) == 0 {
+		return
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	fmt.Fprintf(w, "latency:    p50 %s, p95 %s, p99 %s, max %s\n", percentile(latencies, 50), percentile(latencies, 95),
+		percentile(latencies, 99), latencies[len(latencies)-1])
+}
+
+// percentile returns the p-th percentile of sorted latencies by the nearest rank.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1].Round(time.Microsecond)
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/partnerio"
+)
+
+// Statuses partners are listed by.
+const (
+	statusAll       = "all"
+	statusActive    = "active"
+	statusSuspended = "suspended"
+	statusDeleted   = "deleted"
+)
+
+// runList prints the partners of the data source which match the search text, the material and the status.
+func runList(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("list", stderr)
+	data := addDataFlag(flags)
+	query := flags.String("q", "", "text the id, external id, name, city or district contains, ignoring case")
+	material := flags.String("material", "", "material the partners are experienced in")
+	status := flags.String("status", statusAll, "status of the partners: all, active, suspended or deleted")
+	asJSON := flags.Bool("json", false, "print json instead of a table")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *status != statusAll && *status != statusActive && *status != statusSuspended && *status != statusDeleted {
+		fmt.Fprintf(stderr, "unknown status %q\n", *status)
+		return errUsage
+	}
+	ctx := context.Background()
+	service, err := newService(ctx, *data)
+	if err != nil {
+		return err
+	}
+	partners, err := service.GetAllPartners(ctx)
+	if err != nil {
+		return err
+	}
+	found := make([]entities.Partner, 0, len(partners))
+	for _, partner := range partners {
+		matchesStatus := *status == statusAll || *status == statusOf(partner)
+		if contains(partner, *query) && experienced(partner, *material) && matchesStatus {
+			found = append(found, partner)
+		}
+	}
+	if *asJSON {
+		return printJSON(stdout, found)
+	}
+	table := newTable(stdout, "ID", "EXTERNAL ID", "NAME", "MATERIALS", "CITY", "RATING", "VERIFICATION", "STATUS")
+	for _, partner := range found {
+		table.row(partner.ID, partner.ExternalID, partner.Name, strings.Join(partner.ExperiencedMaterial, ","),
+			partner.Address.City, partner.Rating, partner.VerificationStatus, statusOf(partner))
+	}
+	return table.flush()
+}
+
+// matchFlags are the flags of the commands running a match.
+type matchFlags struct {
+	flags             *flag.FlagSet
+	data              *string
+	material          *string
+	lat               *float64
+	long              *float64
+	floorSize         *float64
+	sort              *string
+	includeUnverified *bool
+	includeInactive   *bool
+	asJSON            *bool
+}
+
+func addMatchFlags(flags *flag.FlagSet) matchFlags {
+	materials := strings.Join(entities.Materials, ", ")
+	return matchFlags{
+		flags:             flags,
+		data:              addDataFlag(flags),
+		material:          flags.String("material", "", "material of the floor, one of "+materials),
+		lat:               flags.Float64("lat", 0, "latitude of the customer"),
+		long:              flags.Float64("long", 0, "longitude of the customer"),
+		floorSize:         flags.Float64("floor-size", 0, "size of the floor in square meters, to estimate prices"),
+		sort:              flags.String("sort", domain.SortByRating, "order of the partners: rating or price"),
+		includeUnverified: flags.Bool("include-unverified", false, "also match partners who are not vetted"),
+		includeInactive:   flags.Bool("include-inactive", false, "also match suspended and deleted partners"),
+		asJSON:            flags.Bool("json", false, "print json instead of a table"),
+	}
+}
+
+// opts returns the options of the match. The checks mirror the validation of GET /partners.
+func (f matchFlags) opts(stderr io.Writer) (domain.GetPartnersOpts, error) {
+	var problem string
+	switch {
+	case *f.material == "":
+		problem = "-material is required"
+	case !isOneOf(*f.material, entities.Materials):
+		problem = fmt.Sprintf("unknown material %q", *f.material)
+	case !isSet(f.flags, "lat") || !isSet(f.flags, "long"):
+		problem = "-lat and -long are required"
+	case *f.lat < -90 || *f.lat > 90:
+		problem = "-lat must be between -90 and 90"
+	case *f.long < -180 || *f.long > 180:
+		problem = "-long must be between -180 and 180"
+	case *f.floorSize != 0 && (*f.floorSize < domain.MinFloorSize || *f.floorSize > domain.MaxFloorSize):
+		problem = fmt.Sprintf("-floor-size must be between %d and %d", domain.MinFloorSize, domain.MaxFloorSize)
+	case !isOneOf(*f.sort, domain.SortOrders):
+		problem = fmt.Sprintf("unknown order %q", *f.sort)
+	case *f.sort == domain.SortByPrice && *f.floorSize == 0:
+		problem = "sorting by price requires -floor-size"
+	}
+	if problem != "" {
+		fmt.Fprintln(stderr, problem)
+		return domain.GetPartnersOpts{}, errUsage
+	}
+	return domain.GetPartnersOpts{
+		Material:            *f.material,
+		CustomerAddressLat:  *f.lat,
+		CustomerAddressLong: *f.long,
+		FloorSize:           *f.floorSize,
+		SortBy:              *f.sort,
+		IncludeUnverified:   *f.includeUnverified,
+		IncludeInactive:     *f.includeInactive,
+	}, nil
+}
+
+// runMatch prints the partners matched for a customer, best match first.
+func runMatch(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("match", stderr)
+	matchFlags := addMatchFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	opts, err := matchFlags.opts(stderr)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	service, err := newService(ctx, *matchFlags.data)
+	if err != nil {
+		return err
+	}
+	partners, err := service.GetPartners(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if *matchFlags.asJSON {
+		return printJSON(stdout, partners)
+	}
+	table := newTable(stdout, "RANK", "ID", "NAME", "RATING", "RADIUS", "CITY", "PRICE", "NEXT AVAILABLE")
+	for i, partner := range partners {
+		table.row(i+1, partner.ID, partner.Name, partner.Rating, partner.OperatingRadius, partner.Address.City,
+			formatPrice(partner.PriceEstimate), formatNextAvailable(partner))
+	}
+	return table.flush()
+}
+
+// runExplain prints every partner experienced in the material with their rank or the reason they are not matched.
+func runExplain(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("explain", stderr)
+	matchFlags := addMatchFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	opts, err := matchFlags.opts(stderr)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	service, err := newService(ctx, *matchFlags.data)
+	if err != nil {
+		return err
+	}
+	explanations, err := service.ExplainMatch(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if *matchFlags.asJSON {
+		return printJSON(stdout, explanations)
+	}
+	order := "rating, then distance"
+	if opts.SortBy == domain.SortByPrice {
+		order = "lower bound of the price estimate, partners without estimate last"
+	}
+	fmt.Fprintf(stdout, "%d partners experienced in %s, ranked by %s\n\n", len(explanations), opts.Material, order)
+	table := newTable(stdout, "RANK", "ID", "NAME", "RATING", "DISTANCE", "RADIUS", "PRICE", "EXCLUDED")
+	for _, e := range explanations {
+		rank := "-"
+		if e.Rank > 0 {
+			rank = strconv.Itoa(e.Rank)
+		}
+		table.row(rank, e.Partner.ID, e.Partner.Name, e.Partner.Rating, fmt.Sprintf("%.1f km", e.Distance),
+			fmt.Sprintf("%d km", e.Partner.OperatingRadius), formatPrice(e.Partner.PriceEstimate), e.Excluded)
+	}
+	return table.flush()
+}
+
+// runValidate checks a file as an import into the data source would, without storing anything.
+func runValidate(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("validate", stderr)
+	data := addDataFlag(flags)
+	format := flags.String("format", "", "format of the file, by default taken from its extension")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "validate expects exactly one file")
+		return errUsage
+	}
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = partnerio.FormatOf(path)
+	}
+	if !isOneOf(*format, partnerio.Formats) {
+		fmt.Fprintf(stderr, "cannot tell the format of %s, set -format\n", path)
+		return errUsage
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	rows, err := partnerio.Read(file, *format)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	ctx := context.Background()
+	service, err := newService(ctx, *data)
+	if err != nil {
+		return err
+	}
+	result, err := service.ImportPartners(ctx, rows, true)
+	if err != nil {
+		return err
+	}
+	printImportResult(stdout, path, result)
+	if result.Failed > 0 {
+		return errRowsFailed
+	}
+	return nil
+}
+
+// contains reports whether the id, external id, name, city or district of the partner contain the text, ignoring
+// case. Every partner contains the empty text.
+func contains(p entities.Partner, text string) bool {
+	text = strings.ToLower(text)
+	for _, attribute := range []string{p.ID, p.ExternalID, p.Name, p.Address.City, p.Address.District} {
+		if strings.Contains(strings.ToLower(attribute), text) {
+			return true
+		}
+	}
+	return text == ""
+}
+
+// experienced reports whether the partner is experienced in the material. Every partner is experienced in the empty
+// material.
+func experienced(p entities.Partner, material string) bool {
+	return material == "" || isOneOf(material, p.ExperiencedMaterial)
+}
+
+func statusOf(p entities.Partner) string {
+	switch {
+	case p.DeletedAt != nil:
+		return statusDeleted
+	case p.SuspendedAt != nil:
+		return statusSuspended
+	default:
+		return statusActive
+	}
+}
+
+// isSet reports whether the flag was given on the command line.
+func isSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func isOneOf(s string, list []string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// formatPrice formats the bounds of a price estimate in euros, "-" when there is none.
+func formatPrice(estimate *entities.PriceEstimate) string {
+	if estimate == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d-%d %s", estimate.Min/100, estimate.Max/100, entities.Currency)
+}
+
+// formatNextAvailable tells when the partner can start a job next.
+func formatNextAvailable(p entities.Partner) string {
+	switch {
+	case p.OpenNow != nil && *p.OpenNow:
+		return "now"
+	case p.NextAvailableAt != nil:
+		return p.NextAvailableAt.Format(time.RFC3339)
+	default:
+		return "-"
+	}
+}
+
+func printJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// table prints aligned columns.
+type table struct {
+	w *tabwriter.Writer
+}
+
+func newTable(w io.Writer, header ...any) table {
+	t := table{w: tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)}
+	t.row(header...)
+	return t
+}
+
+func (t table) row(cells ...any) {
+	for i, cell := range cells {
+		if i > 0 {
+			fmt.Fprint(t.w, "\t")
+		}
+		fmt.Fprint(t.w, cell)
+	}
+	fmt.Fprintln(t.w)
+}
+
+func (t table) flush() error {
+	return t.w.Flush()
+}
@@ -0,0 +1,137 @@
+// Command partnerctl inspects and operates the partners of the service.
+//
+// Usage:
+//
+//	partnerctl list [-data SOURCE] [-q TEXT] [-material MATERIAL] [-status STATUS] [-json]
+//	partnerctl match [-data SOURCE] -material MATERIAL -lat LAT -long LONG [-floor-size M2] [-sort ORDER] [-json]
+//	partnerctl explain [-data SOURCE] -material MATERIAL -lat LAT -long LONG [-floor-size M2] [-sort ORDER] [-json]
+//	partnerctl validate [-data SOURCE] [-format csv|jsonl] FILE
+//	partnerctl migrate [-format csv|jsonl] [-dry-run] [-o FILE] FILE
+//	partnerctl seed [-n COUNT] [-seed SEED] [-cities CITIES] [-format csv|jsonl] [-o FILE]
+//	partnerctl import [-server URL] [-format csv|jsonl] [-dry-run] FILE
+//	partnerctl export [-server URL] [-format csv|jsonl] [-o FILE]
+//	partnerctl load [-server URL] [-n REQUESTS | -duration DURATION] [-c CONCURRENCY] [-seed SEED] [-cities CITIES]
+//	                [-floor-size M2]
+//
+// list, match, explain and validate run the domain services on the partners of a data source: the demo data of the
+// in-memory repository, partners generated by the seed package, a CSV or JSON Lines export, or the URL of a running
+// server whose partners are exported. The source defaults to PARTNERCTL_DATA or the demo data. seed writes generated
+// partners to a file. import and export send the file to a running server, which defaults to PARTNERCTL_SERVER or
+// http://localhost:8080. load replays generated customers against the matching of a running server and reports the
+// throughput and latency, with -floor-size the matches estimate prices as well. Requests to servers are authenticated
+// with the admin API key in PARTNERCTL_API_KEY.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	defaultServer = "http://localhost:8080"
+	// requestTimeout limits the time of a request, imports of large files take a while.
+	requestTimeout = 5 * time.Minute
+)
+
+// errUsage is returned when the command line is invalid. The usage was printed already.
+var errUsage = errors.New("invalid usage")
+
+// errRowsFailed is returned when rows of an import or a validation failed. The failed rows were printed already.
+var errRowsFailed = errors.New("rows failed")
+
+const usage = `Usage:
+  partnerctl list [-data SOURCE] [-q TEXT] [-material MATERIAL] [-status STATUS] [-json]
+  partnerctl match [-data SOURCE] -material MATERIAL -lat LAT -long LONG [-floor-size M2] [-sort ORDER] [-json]
+  partnerctl explain [-data SOURCE] -material MATERIAL -lat LAT -long LONG [-floor-size M2] [-sort ORDER] [-json]
+  partnerctl validate [-data SOURCE] [-format csv|jsonl] FILE
+  partnerctl migrate [-format csv|jsonl] [-dry-run] [-o FILE] FILE
+  partnerctl seed [-n COUNT] [-seed SEED] [-cities CITIES] [-format csv|jsonl] [-o FILE]
+  partnerctl import [-server URL] [-format csv|jsonl] [-dry-run] FILE
+  partnerctl export [-server URL] [-format csv|jsonl] [-o FILE]
+  partnerctl load [-server URL] [-n REQUESTS | -duration DURATION] [-c CONCURRENCY] [-seed SEED] [-cities CITIES]
+                  [-floor-size M2]
+
+SOURCE is "demo" for the demo data, "seed:COUNT[:SEED]" for generated partners, a CSV or JSON Lines file,
+or the URL of a server. It defaults to PARTNERCTL_DATA or "demo". The server of import, export and load defaults
+to PARTNERCTL_SERVER or ` + defaultServer + `. Requests to servers are authenticated with the admin API key
+in PARTNERCTL_API_KEY.
+Run "partnerctl COMMAND -h" for the flags of a command.
+`
+
+// commands maps the name of every command to its function.
+var commands = map[string]func(args []string, stdout, stderr io.Writer) error{
+	"list":     runList,
+	"match":    runMatch,
+	"explain":  runExplain,
+	"validate": runValidate,
+	"migrate":  runMigrate,
+	"seed":     runSeed,
+	"import":   runImport,
+	"export":   runExport,
+	"load":     runLoad,
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdout, os.Stderr)
+	switch {
+	case err == nil:
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	case errors.Is(err, errRowsFailed):
+		os.Exit(1)
+	default:
+		fmt.Fprintln(os.Stderr, "partnerctl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return errUsage
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		fmt.Fprint(stdout, usage)
+		return nil
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return errUsage
+	}
+	err := command(args[1:], stdout, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	return flags
+}
+
+// parseFlags parses the flags of a command. The flag package prints the problem, so errors are errUsage, except for
+// flag.ErrHelp when the flags were asked for.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	if err != nil {
+		return errUsage
+	}
+	return nil
+}
+
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
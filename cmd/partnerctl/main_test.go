@@ -0,0 +1,337 @@
+package main
+
+import (
+	"bytes"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/partnerio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	insuredUntil = time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+	suspendedAt  = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+)
+
+// partners are two partners in Munich matched for customers in the city centre, a suspended partner next to them and a
+// partner in Berlin out of reach.
+var partners = []entities.Partner{
+	partner("near", "Near Floors", 48.14, 11.58, 3),
+	partner("far", "Far Floors", 48.30, 11.60, 5),
+	func() entities.Partner {
+		p := partner("suspended", "Suspended Floors", 48.13, 11.57, 4)
+		p.SuspendedAt, p.SuspensionReason = &suspendedAt, "Complaints"
+		return p
+	}(),
+	func() entities.Partner {
+		p := partner("remote", "Remote Floors", 52.52, 13.40, 5)
+		p.OperatingRadius = 10
+		return p
+	}(),
+}
+
+// customer is the command line of a customer in the centre of Munich asking for wood.
+var customer = []string{"-material", "wood", "-lat", "48.137", "-long", "11.575"}
+
+func partner(id, name string, lat, long float64, rating int) entities.Partner {
+	return entities.Partner{
+		ID:                  id,
+		Name:                name,
+		ExperiencedMaterial: []string{"wood"},
+		Address:             entities.Address{Latitude: lat, Longitude: long, City: "München"},
+		OperatingRadius:     50,
+		Rating:              rating,
+		VerificationStatus:  entities.VerificationVerified,
+		InsuranceExpiresAt:  &insuredUntil,
+	}
+}
+
+// writePartners writes the partners to a JSON Lines file and returns its path.
+func writePartners(t *testing.T, partners []entities.Partner) string {
+	t.Helper()
+	var content bytes.Buffer
+	require.NoError(t, partnerio.Write(&content, partnerio.FormatJSONL, partners))
+	path := filepath.Join(t.TempDir(), "partners.jsonl")
+	require.NoError(t, os.WriteFile(path, content.Bytes(), 0o600))
+	return path
+}
+
+func readPartnersFile(t *testing.T, path string) []entities.Partner {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	read, err := readPartners(file, partnerio.FormatJSONL, path)
+	require.NoError(t, err)
+	return read
+}
+
+// runCommand runs partnerctl with the arguments and returns what it printed.
+func runCommand(args ...string) (stdout, stderr string, err error) {
+	var out, errOut bytes.Buffer
+	err = run(args, &out, &errOut)
+	return out.String(), errOut.String(), err
+}
+
+func ids(partners []entities.Partner) []string {
+	ids := make([]string, 0, len(partners))
+	for _, p := range partners {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+func TestRun(t *testing.T) {
+	_, stderr, err := runCommand()
+	assert.ErrorIs(t, err, errUsage)
+	assert.Contains(t, stderr, "Usage:")
+
+	_, stderr, err = runCommand("unknown")
+	assert.ErrorIs(t, err, errUsage)
+	assert.Contains(t, stderr, `unknown command "unknown"`)
+
+	stdout, _, err := runCommand("help")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "[-floor-size M2]")
+}
+
+func TestRun_List(t *testing.T) {
+	data := writePartners(t, partners)
+
+	stdout, _, err := runCommand("list", "-data", data, "-status", "suspended", "-json")
+	require.NoError(t, err)
+	var listed []entities.Partner
+	require.NoError(t, json.Unmarshal([]byte(stdout), &listed))
+	assert.Equal(t, []string{"suspended"}, ids(listed))
+
+	stdout, _, err = runCommand("list", "-data", data, "-q", "remote")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Remote Floors")
+	assert.NotContains(t, stdout, "Near Floors")
+
+	_, stderr, err := runCommand("list", "-data", data, "-status", "retired")
+	assert.ErrorIs(t, err, errUsage)
+	assert.Contains(t, stderr, `unknown status "retired"`)
+}
+
+func TestRun_Match(t *testing.T) {
+	data := writePartners(t, partners)
+
+	stdout, _, err := runCommand(append([]string{"match", "-data", data, "-json"}, customer...)...)
+	require.NoError(t, err)
+	var matched []entities.Partner
+	require.NoError(t, json.Unmarshal([]byte(stdout), &matched))
+	assert.Equal(t, []string{"far", "near"}, ids(matched), "higher rated partner comes first")
+
+	stdout, _, err = runCommand(append([]string{"match", "-data", data}, customer...)...)
+	require.NoError(t, err)
+	assert.Regexp(t, `(?m)^1\s+far\s+Far Floors\s+5`, stdout)
+}
+
+func TestRun_Match_Usage(t *testing.T) {
+	type testCase struct {
+		name       string
+		args       []string
+		expProblem string
+	}
+	tests := []testCase{
+		{name: "Requires material", args: []string{"-lat", "48", "-long", "11"}, expProblem: "-material is required"},
+		{name: "Rejects unknown material", args: []string{"-material", "glass", "-lat", "48", "-long", "11"},
+			expProblem: `unknown material "glass"`},
+		{name: "Requires location", args: []string{"-material", "wood", "-lat", "48"},
+			expProblem: "-lat and -long are required"},
+		{name: "Rejects latitude out of range", args: []string{"-material", "wood", "-lat", "91", "-long", "11"},
+			expProblem: "-lat must be between -90 and 90"},
+		{name: "Requires floor size to sort by price", args: append([]string{"-sort", "price"}, customer...),
+			expProblem: "sorting by price requires -floor-size"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, err := runCommand(append([]string{"match", "-data", "demo"}, tt.args...)...)
+
+			assert.ErrorIs(t, err, errUsage)
+			assert.Contains(t, stderr, tt.expProblem)
+		})
+	}
+}
+
+func TestRun_Explain(t *testing.T) {
+	data := writePartners(t, partners)
+
+	stdout, _, err := runCommand(append([]string{"explain", "-data", data, "-json"}, customer...)...)
+	require.NoError(t, err)
+	var explanations []domain.MatchExplanation
+	require.NoError(t, json.Unmarshal([]byte(stdout), &explanations))
+	got := map[string]domain.MatchExplanation{}
+	for _, e := range explanations {
+		got[e.Partner.ID] = e
+	}
+	assert.Len(t, explanations, 4)
+	assert.Equal(t, 1, got["far"].Rank)
+	assert.Equal(t, 2, got["near"].Rank)
+	assert.Equal(t, domain.ExcludedInactive, got["suspended"].Excluded)
+	assert.Equal(t, domain.ExcludedOutOfRadius, got["remote"].Excluded)
+
+	stdout, _, err = runCommand(append([]string{"explain", "-data", data}, customer...)...)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "4 partners experienced in wood, ranked by rating, then distance")
+	assert.Regexp(t, `(?m)^-\s+remote\s+Remote Floors\s+5\s+[\d.]+ km\s+10 km\s+-\s+out_of_radius`, stdout)
+}
+
+func TestRun_Validate(t *testing.T) {
+	data := writePartners(t, partners)
+	invalid := partner("", "Broken Floors", 48.1, 11.5, 9)
+	invalid.ExternalID = "broken"
+	valid := partner("", "New Floors", 48.1, 11.5, 4)
+	valid.ExternalID = "new"
+	file := writePartners(t, []entities.Partner{valid, invalid})
+
+	stdout, _, err := runCommand("validate", "-data", data, file)
+
+	assert.ErrorIs(t, err, errRowsFailed)
+	assert.Contains(t, stdout, "dry run, nothing was stored")
+	assert.Contains(t, stdout, "created 1, updated 0, unchanged 0, failed 1")
+	assert.Contains(t, stdout, file+":2 (broken): invalid rating: must be between 0 and 5")
+	assert.Equal(t, ids(partners), ids(readPartnersFile(t, data)), "data source is not changed")
+
+	_, stderr, err := runCommand("validate", "-data", data)
+	assert.ErrorIs(t, err, errUsage)
+	assert.Contains(t, stderr, "validate expects exactly one file")
+}
+
+func TestRun_Migrate(t *testing.T) {
+	open := true
+	old := partner("old", "Old Floors", 48.1, 11.5, 4)
+	old.VerificationStatus, old.OpenNow = "", &open
+
+	t.Run("Migrates file in place and keeps its mode", func(t *testing.T) {
+		path := writePartners(t, []entities.Partner{old, partners[0]})
+		require.NoError(t, os.Chmod(path, 0o640))
+
+		stdout, _, err := runCommand("migrate", path)
+
+		require.NoError(t, err)
+		assert.Contains(t, stdout, "pending_verification: partners without verification status are pending "+
+			"verification, 1 of 2 partners migrated")
+		assert.Contains(t, stdout, "drop_response_attributes: ")
+		migrated := readPartnersFile(t, path)
+		require.Len(t, migrated, 2)
+		assert.Equal(t, entities.VerificationPending, migrated[0].VerificationStatus)
+		assert.Nil(t, migrated[0].OpenNow)
+		assert.Equal(t, partners[0].VerificationStatus, migrated[1].VerificationStatus)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+	})
+
+	t.Run("Writes migrated partners to other file", func(t *testing.T) {
+		path := writePartners(t, []entities.Partner{old})
+		output := filepath.Join(t.TempDir(), "migrated.jsonl")
+
+		_, _, err := runCommand("migrate", "-o", output, path)
+
+		require.NoError(t, err)
+		assert.Equal(t, "", readPartnersFile(t, path)[0].VerificationStatus, "migrated file is not changed")
+		assert.Equal(t, entities.VerificationPending, readPartnersFile(t, output)[0].VerificationStatus)
+	})
+
+	t.Run("Does not write on dry run", func(t *testing.T) {
+		path := writePartners(t, []entities.Partner{old})
+		before, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		stdout, _, err := runCommand("migrate", "-dry-run", path)
+
+		require.NoError(t, err)
+		assert.Contains(t, stdout, "dry run, nothing was written")
+		after, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+
+	t.Run("Requires known format", func(t *testing.T) {
+		_, stderr, err := runCommand("migrate", "partners.txt")
+
+		assert.ErrorIs(t, err, errUsage)
+		assert.Contains(t, stderr, "cannot tell the format of partners.txt, set -format")
+	})
+}
+
+func TestRun_Load_Usage(t *testing.T) {
+	type testCase struct {
+		name       string
+		args       []string
+		expProblem string
+	}
+	tests := []testCase{
+		{name: "Rejects arguments", args: []string{"extra"}, expProblem: "load expects no arguments"},
+		{name: "Rejects no requests", args: []string{"-n", "0"}, expProblem: "-n and -c must be positive"},
+		{name: "Rejects no concurrency", args: []string{"-c", "0"}, expProblem: "-n and -c must be positive"},
+		{name: "Rejects negative duration", args: []string{"-duration", "-1s"},
+			expProblem: "-duration must not be negative"},
+		{name: "Rejects floor size out of range", args: []string{"-floor-size", "0.5"},
+			expProblem: "-floor-size must be between 1 and 100000"},
+		{name: "Rejects invalid cities", args: []string{"-cities", "Munich"}, expProblem: "Munich"},
+		{name: "Rejects unknown flag", args: []string{"-rate", "10"}, expProblem: "flag provided but not defined: -rate"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, err := runCommand(append([]string{"load"}, tt.args...)...)
+
+			assert.ErrorIs(t, err, errUsage)
+			assert.Contains(t, stderr, tt.expProblem)
+		})
+	}
+
+	t.Run("Prints flags on help", func(t *testing.T) {
+		_, stderr, err := runCommand("load", "-h")
+
+		assert.NoError(t, err)
+		assert.Contains(t, stderr, "-floor-size")
+	})
+}
+
+func TestRun_Export(t *testing.T) {
+	const export = "id,name\n1,Near Floors\n"
+	complete := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/partners/exports", r.URL.Path)
+		assert.Equal(t, partnerio.FormatCSV, r.URL.Query().Get("format"))
+		if !complete {
+			// The connection is closed before the announced body was sent.
+			w.Header().Set("Content-Length", "1000")
+		}
+		_, _ = w.Write([]byte(export))
+	}))
+	t.Cleanup(server.Close)
+	dir := t.TempDir()
+	output := filepath.Join(dir, "partners.csv")
+
+	_, _, err := runCommand("export", "-server", server.URL, "-o", output)
+	require.NoError(t, err)
+	written, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, export, string(written))
+
+	complete = false
+	require.NoError(t, os.WriteFile(output, []byte("previous export"), 0o600))
+	_, _, err = runCommand("export", "-server", server.URL, "-o", output)
+	assert.Error(t, err)
+	written, err = os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "previous export", string(written), "failed export leaves the file unchanged")
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary file is removed")
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"customer-partner/internal/entities"
+	"customer-partner/internal/partnerio"
+)
+
+// migration upgrades partners written by an older version of the service to the current data model. The service keeps
+// its data in memory, so migrations apply to data files, e.g. exports which are imported again or used as data source.
+type migration struct {
+	name        string
+	description string
+	// apply migrates the partner in place and reports whether it changed.
+	apply func(p *entities.Partner) bool
+}
+
+// migrations are applied in order. Every migration must leave partners which are migrated already unchanged.
+var migrations = []migration{
+	{
+		name:        "pending_verification",
+		description: "partners without verification status are pending verification",
+		apply: func(p *entities.Partner) bool {
+			if p.VerificationStatus != "" {
+				return false
+			}
+			p.VerificationStatus = entities.VerificationPending
+			return true
+		},
+	},
+	{
+		name:        "drop_response_attributes",
+		description: "availability and price estimates are only set on responses and never stored",
+		apply: func(p *entities.Partner) bool {
+			if p.OpenNow == nil && p.NextAvailableAt == nil && p.PriceEstimate == nil {
+				return false
+			}
+			p.OpenNow, p.NextAvailableAt, p.PriceEstimate = nil, nil, nil
+			return true
+		},
+	},
+}
+
+// runMigrate applies the migrations to the partners of a data file and writes them back, or to another file.
+func runMigrate(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("migrate", stderr)
+	format := flags.String("format", "", "format of the file, by default taken from its extension")
+	dryRun := flags.Bool("dry-run", false, "report what would be migrated without writing anything")
+	output := flags.String("o", "", "file to write, by default the migrated file is replaced")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "migrate expects exactly one file")
+		return errUsage
+	}
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = partnerio.FormatOf(path)
+	}
+	if !isOneOf(*format, partnerio.Formats) {
+		fmt.Fprintf(stderr, "cannot tell the format of %s, set -format\n", path)
+		return errUsage
+	}
+	if *output == "" {
+		*output = path
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	rows, err := partnerio.Read(file, *format)
+	_ = file.Close()
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	partners := make([]entities.Partner, 0, len(rows))
+	for _, row := range rows {
+		if row.Err != nil {
+			return fmt.Errorf("%s:%d: %w", path, row.Line, row.Err)
+		}
+		partners = append(partners, row.Partner)
+	}
+	for _, m := range migrations {
+		migrated := 0
+		for i := range partners {
+			if m.apply(&partners[i]) {
+				migrated++
+			}
+		}
+		fmt.Fprintf(stdout, "%s: %s, %d of %d partners migrated\n", m.name, m.description, migrated, len(partners))
+	}
+	if *dryRun {
+		fmt.Fprintln(stdout, "dry run, nothing was written")
+		return nil
+	}
+	return writeFile(*output, *format, partners)
+}
+
+// writeFile writes the partners to a file in the format, see replaceFile.
+func writeFile(path, format string, partners []entities.Partner) error {
+	return replaceFile(path, func(w io.Writer) error {
+		return partnerio.Write(w, format, partners)
+	})
+}
+
+// replaceFile writes a file with write. The file is replaced at once, so that it is never left half-written when write
+// fails, and keeps its mode. New files are readable by everyone.
+func replaceFile(path string, write func(w io.Writer) error) error {
+	mode := fs.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := write(tmp); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"customer-partner/internal/auth"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/partnerio"
+)
+
+// runImport sends a file to the import endpoint and prints the outcome. Failed rows are printed with their line.
+func runImport(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("import", stderr)
+	server := flags.String("server", envOr("PARTNERCTL_SERVER", defaultServer), "base URL of the server")
+	format := flags.String("format", "", "format of the file, by default taken from its extension")
+	dryRun := flags.Bool("dry-run", false, "validate the file and report what an import would do without storing anything")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "import expects exactly one file")
+		return errUsage
+	}
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = partnerio.FormatOf(path)
+	}
+	if *format == "" {
+		fmt.Fprintf(stderr, "cannot tell the format of %s, set -format\n", path)
+		return errUsage
+	}
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	query := url.Values{"format": {*format}, "dry_run": {strconv.FormatBool(*dryRun)}}
+	contentType := partnerio.ContentType(*format)
+	resp, err := do(http.MethodPost, *server, "/partners/imports", query, contentType, bytes.NewReader(file))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var result domain.ImportResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	printImportResult(stdout, path, result)
+	if result.Failed > 0 {
+		return errRowsFailed
+	}
+	return nil
+}
+
+// printImportResult prints the counts of the import and a line per failed row.
+func printImportResult(w io.Writer, path string, result domain.ImportResult) {
+	if result.DryRun {
+		fmt.Fprintln(w, "dry run, nothing was stored")
+	}
+	fmt.Fprintf(w, "created %d, updated %d, unchanged %d, failed %d\n",
+		result.Created, result.Updated, result.Unchanged, result.Failed)
+	for _, e := range result.Errors {
+		location := fmt.Sprintf("%s:%d", path, e.Line)
+		if e.ExternalID != "" {
+			location += fmt.Sprintf(" (%s)", e.ExternalID)
+		}
+		if e.Field != "" {
+			fmt.Fprintf(w, "%s: invalid %s: %s\n", location, e.Field, e.Reason)
+		} else {
+			fmt.Fprintf(w, "%s: %s\n", location, e.Reason)
+		}
+	}
+}
+
+// runExport writes all partners from the export endpoint to a file or stdout. The file is only replaced when the
+// export was received completely.
+func runExport(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("export", stderr)
+	server := flags.String("server", envOr("PARTNERCTL_SERVER", defaultServer), "base URL of the server")
+	format := flags.String("format", "", "format of the file, by default taken from the extension of -o or csv")
+	output := flags.String("o", "", "file to write, by default stdout")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		fmt.Fprintln(stderr, "export expects no arguments")
+		return errUsage
+	}
+	if *format == "" {
+		*format = partnerio.FormatOf(*output)
+	}
+	if *format == "" {
+		*format = partnerio.FormatCSV
+	}
+	resp, err := do(http.MethodGet, *server, "/partners/exports", url.Values{"format": {*format}}, "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if *output == "" {
+		_, err = io.Copy(stdout, resp.Body)
+		return err
+	}
+	return replaceFile(*output, func(w io.Writer) error {
+		_, err := io.Copy(w, resp.Body)
+		return err
+	})
+}
+
+// do sends a request authenticated with the API key to the server. Responses other than 200 are returned as error
+// with the message of the server.
+func do(method, server, path string, query url.Values, contentType string, body io.Reader) (*http.Response, error) {
+	target := strings.TrimSuffix(server, "/") + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if key := os.Getenv("PARTNERCTL_API_KEY"); key != "" {
+		req.Header.Set(auth.APIKeyHeader, key)
+	}
+	client := &http.Client{Timeout: requestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
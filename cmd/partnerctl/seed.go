@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"customer-partner/internal/partnerio"
+	"customer-partner/internal/seed"
+)
+
+// seedSourcePrefix starts data sources of generated partners, e.g. "seed:10000" or "seed:10000:7" with the seed 7.
+const seedSourcePrefix = "seed:"
+
+// runSeed writes generated partners to a file, e.g. to import them into a server or to use them as data source.
+func runSeed(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("seed", stderr)
+	count := flags.Int("n", 1000, "number of partners")
+	randomSeed := flags.Int64("seed", 1, "seed of the generator, the same seed always generates the same partners")
+	cities := flags.String("cities", "",
+		`cities as "name=lat:long[:spread_km[:weight]],...", by default the largest cities`)
+	format := flags.String("format", "", "format of the file, by default taken from the extension of -o or csv")
+	output := flags.String("o", "", "file to write, by default stdout")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		fmt.Fprintln(stderr, "seed expects no arguments")
+		return errUsage
+	}
+	if *count < 0 {
+		fmt.Fprintln(stderr, "-n must not be negative")
+		return errUsage
+	}
+	opts := seed.Options{Count: *count, Seed: *randomSeed}
+	if *cities != "" {
+		var err error
+		if opts.Cities, err = seed.ParseCities(*cities); err != nil {
+			fmt.Fprintln(stderr, err)
+			return errUsage
+		}
+	}
+	if *format == "" {
+		*format = partnerio.FormatOf(*output)
+	}
+	if *format == "" {
+		*format = partnerio.FormatCSV
+	}
+	if !isOneOf(*format, partnerio.Formats) {
+		fmt.Fprintf(stderr, "unknown format %q\n", *format)
+		return errUsage
+	}
+	partners := seed.Partners(opts)
+	if *output == "" {
+		return partnerio.Write(stdout, *format, partners)
+	}
+	return writeFile(*output, *format, partners)
+}
+
+// parseSeedSource parses a data source of generated partners, "seed:COUNT[:SEED]". The seed defaults to 1.
+func parseSeedSource(source string) (seed.Options, error) {
+	count, randomSeed, hasSeed := strings.Cut(strings.TrimPrefix(source, seedSourcePrefix), ":")
+	opts := seed.Options{Seed: 1}
+	var err error
+	if opts.Count, err = strconv.Atoi(count); err != nil || opts.Count < 0 {
+		return seed.Options{}, fmt.Errorf("data source %q: invalid number of partners", source)
+	}
+	if hasSeed {
+		if opts.Seed, err = strconv.ParseInt(randomSeed, 10, 64); err != nil {
+			return seed.Options{}, fmt.Errorf("data source %q: invalid seed", source)
+		}
+	}
+	return opts, nil
+}
@@ -1,17 +1,530 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"customer-partner/internal/audit"
+	"customer-partner/internal/auth"
+	"customer-partner/internal/cache"
 	"customer-partner/internal/db"
 	"customer-partner/internal/domain"
+	"customer-partner/internal/events"
+	"customer-partner/internal/jobs"
+	"customer-partner/internal/logging"
+	"customer-partner/internal/metrics"
+	"customer-partner/internal/notifications"
+	"customer-partner/internal/privacy"
+	"customer-partner/internal/ratelimit"
+	"customer-partner/internal/seed"
+	"customer-partner/internal/tracing"
 	"customer-partner/internal/web"
+	"customer-partner/internal/webhooks"
+)
+
+const (
+	addr        = ":8080"
+	serviceName = "customer-partner"
+	// defaultDrainDelay is the time readiness fails before the server stops accepting requests, so that load
+	// balancers notice the shutdown. It can be changed with SHUTDOWN_DELAY.
+	defaultDrainDelay = 5 * time.Second
+	// shutdownTimeout limits the time in-flight requests have to complete on shutdown.
+	shutdownTimeout = 15 * time.Second
+	// defaultRetention is the time the contact data of offer requests is kept. It can be changed with
+	// OFFER_REQUEST_RETENTION.
+	defaultRetention = 90 * 24 * time.Hour
+	// defaultLeadPartners is the maximum number of partners a lead is dispatched to. It can be changed with
+	// LEAD_MAX_PARTNERS.
+	defaultLeadPartners = 3
+	// purgeInterval defines how often expired contact data is purged.
+	purgeInterval = time.Hour
+	// certificationInterval defines how often expiring certifications are flagged.
+	certificationInterval = time.Hour
+	// relayInterval defines how often events are moved from the outbox to the publishers.
+	relayInterval = time.Second
+	// webhookInterval defines how often due webhook deliveries are sent.
+	webhookInterval = time.Second
+	// notificationInterval defines how often due notifications are sent.
+	notificationInterval = time.Second
 )
 
 func main() {
-	fmt.Println("Starting Server")
-	repo := db.NewPartnerInMemoryRepository()
-	service := domain.NewPartnerService(repo)
-	api := web.NewPartnerAPI(service)
-	api.ListenAndServe()
+	logger := logging.New(os.Stdout, os.Getenv("LOG_LEVEL"), os.Getenv("LOG_FORMAT"))
+	if err := run(logger); err != nil {
+		logger.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(logger *slog.Logger) error {
+	startTime := time.Now()
+	logger.Info("starting server")
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		ServiceName: serviceName,
+		Exporter:    os.Getenv("OTEL_TRACES_EXPORTER"),
+		File:        os.Getenv("OTEL_TRACES_FILE"),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("flushing traces failed", "error", err)
+		}
+	}()
+	m := metrics.New()
+	authenticator, err := newAuthenticator()
+	if err != nil {
+		return err
+	}
+	obfuscator, err := newObfuscator(logger)
+	if err != nil {
+		return err
+	}
+	rateLimiter, err := newRateLimiter()
+	if err != nil {
+		return err
+	}
+	cipher, err := newCipher(logger)
+	if err != nil {
+		return err
+	}
+	retention := defaultRetention
+	if v := os.Getenv("OFFER_REQUEST_RETENTION"); v != "" {
+		if retention, err = time.ParseDuration(v); err != nil {
+			return fmt.Errorf("parsing OFFER_REQUEST_RETENTION: %w", err)
+		}
+	}
+	leadPartners := defaultLeadPartners
+	if v := os.Getenv("LEAD_MAX_PARTNERS"); v != "" {
+		if leadPartners, err = strconv.Atoi(v); err != nil || leadPartners < 1 {
+			return errors.New("parsing LEAD_MAX_PARTNERS: must be a positive number")
+		}
+	}
+
+	sinks, closeSinks, err := newEventSinks()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := closeSinks(); err != nil {
+			logger.Error("closing event sinks failed", "error", err)
+		}
+	}()
+	channels, closeChannels, err := newNotificationChannels()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := closeChannels(); err != nil {
+			logger.Error("closing notification channels failed", "error", err)
+		}
+	}()
+	templates, err := newNotificationTemplates()
+	if err != nil {
+		return err
+	}
+	outbox := db.NewOutboxInMemoryRepository()
+	webhookRepo := db.NewWebhookInMemoryRepository()
+	dispatcher := webhooks.NewDispatcher(webhookRepo, nil, webhooks.DefaultConfig, logger.With("component", "webhooks"))
+
+	store, err := newPartnerStore(outbox, logger)
+	if err != nil {
+		return err
+	}
+	partnerChanges := db.NewPartnerChangeInMemoryRepository()
+	var repo domain.PartnerRepository = audit.NewAuditedPartnerRepository(store, partnerChanges)
+	repo = metrics.NewInstrumentedPartnerRepository(tracing.NewTracedPartnerRepository(repo), m)
+	service := domain.NewPartnerService(repo, logger.With("component", "domain"))
+	cacheConfig, err := newMatchCacheConfig()
+	if err != nil {
+		return err
+	}
+	var partnerService metrics.PartnerService = service
+	var matchCache *cache.CachedPartnerService
+	if cacheConfig.Size > 0 {
+		matchCache = cache.NewCachedPartnerService(service, cacheConfig)
+		m.ObserveMatchCache(matchCache)
+		partnerService = matchCache
+	}
+	offerRequestStore := db.NewOfferRequestInMemoryRepository(cipher, outbox)
+	offerRequestRepo := metrics.NewInstrumentedOfferRequestRepository(
+		tracing.NewTracedOfferRequestRepository(offerRequestStore),
+		m,
+	)
+	quoteRepo := metrics.NewInstrumentedQuoteRepository(
+		tracing.NewTracedQuoteRepository(offerRequestStore),
+		m,
+	)
+	offerRequests := domain.NewOfferRequestService(
+		offerRequestRepo,
+		repo,
+		retention,
+		logger.With("component", "domain"),
+	)
+	dataRequests := domain.NewDataRequestService(
+		offerRequestRepo,
+		repo,
+		partnerChanges,
+		webhookRepo,
+		outbox,
+		db.NewDataRequestInMemoryRepository(),
+		logger.With("component", "domain"),
+	)
+	api := web.NewPartnerAPI(
+		web.Services{
+			Partners:      metrics.NewInstrumentedPartnerService(partnerService, m),
+			OfferRequests: offerRequests,
+			DataRequests:  dataRequests,
+			History:       domain.NewPartnerHistoryService(repo, partnerChanges),
+			Webhooks: domain.NewWebhookService(
+				webhookRepo,
+				repo,
+				os.Getenv("WEBHOOK_ALLOW_HTTP") == "true",
+				logger.With("component", "domain"),
+			),
+			Leads:  domain.NewLeadService(offerRequestRepo, service, leadPartners, logger.With("component", "domain")),
+			Quotes: domain.NewQuoteService(quoteRepo, offerRequestRepo, logger.With("component", "domain")),
+		},
+		authenticator,
+		obfuscator,
+		rateLimiter,
+		logger.With("component", "web"),
+	)
+	health := web.NewHealthAPI(
+		web.ReadVersionInfo(startTime),
+		map[string]web.HealthChecker{"partner_repository": store},
+		logger.With("component", "health"),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	mux.Handle("/healthz", health.Handler())
+	mux.Handle("/readyz", health.Handler())
+	mux.Handle("/version", health.Handler())
+	mux.Handle("/", m.Middleware(api.Route)(api.Handler()))
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	purgeDone := jobs.Start(ctx, logger.With("component", "jobs"), jobs.Job{
+		Name:     "purge_offer_request_contact_data",
+		Interval: purgeInterval,
+		Run: func(ctx context.Context) error {
+			_, err := offerRequests.PurgeExpiredContactData(ctx, time.Now())
+			return err
+		},
+	})
+	certificationsDone := jobs.Start(ctx, logger.With("component", "jobs"), jobs.Job{
+		Name:     "flag_expiring_certifications",
+		Interval: certificationInterval,
+		Run: func(ctx context.Context) error {
+			_, err := service.FlagExpiringCertifications(ctx, time.Now())
+			return err
+		},
+	})
+
+	publishers := append(events.Publishers{dispatcher}, sinks...)
+	if matchCache != nil {
+		// Clearing the cache cannot fail, so it comes first and is not held up by failing publishers. Events are
+		// published again while a later publisher fails; the cache skips events it has seen.
+		publishers = append(events.Publishers{matchCache}, publishers...)
+	}
+	notificationQueue := notifications.NewQueue(
+		channels,
+		notifications.DefaultQueueConfig,
+		logger.With("component", "notifications"),
+	)
+	if len(channels) > 0 {
+		// Notifications are not idempotent, so they come last: an event is only published again to them when the
+		// queue is full.
+		publishers = append(publishers, notifications.NewOfferRequestNotifier(
+			offerRequestRepo,
+			repo,
+			templates,
+			channels,
+			notificationQueue,
+			logger.With("component", "notifications"),
+		))
+	}
+	relay := events.NewRelay(outbox, publishers, logger.With("component", "events"))
+	relayDone := jobs.Start(ctx, logger.With("component", "jobs"), jobs.Job{
+		Name:     "relay_events",
+		Interval: relayInterval,
+		Run:      relay.Run,
+	})
+	webhooksDone := jobs.Start(ctx, logger.With("component", "jobs"), jobs.Job{
+		Name:     "deliver_webhooks",
+		Interval: webhookInterval,
+		Run:      dispatcher.Deliver,
+	})
+	notificationsDone := jobs.Start(ctx, logger.With("component", "jobs"), jobs.Job{
+		Name:     "send_notifications",
+		Interval: notificationInterval,
+		Run:      notificationQueue.Send,
+	})
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("listening", "addr", addr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	drainDelay := defaultDrainDelay
+	if v := os.Getenv("SHUTDOWN_DELAY"); v != "" {
+		if drainDelay, err = time.ParseDuration(v); err != nil {
+			return err
+		}
+	}
+	logger.Info("shutting down", "drain_delay", drainDelay)
+	health.ShutDown()
+	time.Sleep(drainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	<-purgeDone
+	<-certificationsDone
+	<-relayDone
+	<-webhooksDone
+	<-notificationsDone
+	// Events of the last requests are published before the outbox is lost with the process.
+	if err := relay.Run(shutdownCtx); err != nil {
+		logger.Error("publishing remaining events failed", "error", err)
+	}
+	if err := dispatcher.Deliver(shutdownCtx); err != nil {
+		logger.Error("delivering remaining webhooks failed", "error", err)
+	}
+	if err := notificationQueue.Send(shutdownCtx); err != nil {
+		logger.Error("sending remaining notifications failed", "error", err)
+	}
+	if n := notificationQueue.Len(); n > 0 {
+		logger.Warn("notifications lost on shutdown", "count", n)
+	}
+	logger.Info("server stopped")
+	return nil
+}
+
+// newEventSinks creates the publishers of events besides the webhooks of partners: EVENTS_FILE appends them to a file
+// as newline delimited json, EVENTS_WEBHOOK_URL posts them to a webhook. The returned function closes the file.
+func newEventSinks() (events.Publishers, func() error, error) {
+	var publishers events.Publishers
+	closeFile := func() error { return nil }
+	if path := os.Getenv("EVENTS_FILE"); path != "" {
+		file, err := events.OpenFilePublisher(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("opening EVENTS_FILE: %w", err)
+		}
+		publishers = append(publishers, file)
+		closeFile = file.Close
+	}
+	if url := os.Getenv("EVENTS_WEBHOOK_URL"); url != "" {
+		publishers = append(publishers, events.NewHTTPPublisher(url, nil))
+	}
+	return publishers, closeFile, nil
+}
+
+// newNotificationChannels creates the providers of notifications. SMTP_ADDR and SMTP_FROM send emails through a mail
+// server, authenticated with SMTP_USERNAME and SMTP_PASSWORD when set. SMS_GATEWAY_URL sends text messages through an
+// HTTP gateway with the bearer token SMS_GATEWAY_TOKEN. NOTIFICATIONS_FILE writes the messages of all channels to a
+// file instead, for local development. Without any, notifications are disabled. The returned function closes the file.
+func newNotificationChannels() (notifications.Channels, func() error, error) {
+	channels := notifications.Channels{}
+	if path := os.Getenv("NOTIFICATIONS_FILE"); path != "" {
+		file, err := notifications.OpenFileNotifier(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("opening NOTIFICATIONS_FILE: %w", err)
+		}
+		channels[notifications.ChannelEmail] = file
+		channels[notifications.ChannelSMS] = file
+		return channels, file.Close, nil
+	}
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		if os.Getenv("SMTP_FROM") == "" {
+			return nil, nil, errors.New("SMTP_FROM must be set with SMTP_ADDR")
+		}
+		channels[notifications.ChannelEmail] = notifications.NewSMTPNotifier(notifications.SMTPConfig{
+			Addr:     addr,
+			From:     os.Getenv("SMTP_FROM"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+		})
+	}
+	if url := os.Getenv("SMS_GATEWAY_URL"); url != "" {
+		channels[notifications.ChannelSMS] = notifications.NewSMSGatewayNotifier(url, os.Getenv("SMS_GATEWAY_TOKEN"), nil)
+	}
+	return channels, func() error { return nil }, nil
+}
+
+// newNotificationTemplates loads the templates from the directory NOTIFICATION_TEMPLATES_DIR, or the shipped ones
+// when it is not set.
+func newNotificationTemplates() (*notifications.Templates, error) {
+	fsys := notifications.DefaultTemplates
+	if dir := os.Getenv("NOTIFICATION_TEMPLATES_DIR"); dir != "" {
+		fsys = os.DirFS(dir)
+	}
+	templates, err := notifications.LoadTemplates(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("loading notification templates: %w", err)
+	}
+	return templates, nil
+}
+
+// newAuthenticator loads the API keys from AUTH_API_KEYS_FILE and the JWKS from AUTH_JWKS_FILE. Tokens must be issued
+// by AUTH_JWT_ISSUER for AUTH_JWT_AUDIENCE when these are set. Credentials of an unconfigured kind are rejected.
+func newAuthenticator() (*auth.Authenticator, error) {
+	var apiKeys []auth.APIKey
+	if path := os.Getenv("AUTH_API_KEYS_FILE"); path != "" {
+		var err error
+		if apiKeys, err = auth.LoadAPIKeys(path); err != nil {
+			return nil, fmt.Errorf("loading api keys: %w", err)
+		}
+	}
+	var verifier *auth.JWTVerifier
+	if path := os.Getenv("AUTH_JWKS_FILE"); path != "" {
+		var err error
+		verifier, err = auth.LoadJWTVerifier(path, auth.JWTConfig{
+			Issuer:   os.Getenv("AUTH_JWT_ISSUER"),
+			Audience: os.Getenv("AUTH_JWT_AUDIENCE"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("loading jwks: %w", err)
+		}
+	}
+	return auth.NewAuthenticator(apiKeys, verifier), nil
+}
+
+// newObfuscator blurs public partner addresses with the key in PRIVACY_KEY. Without a key a random one is used, which
+// changes the public locations on every restart.
+func newObfuscator(logger *slog.Logger) (*privacy.Obfuscator, error) {
+	if key := os.Getenv("PRIVACY_KEY"); key != "" {
+		return privacy.NewObfuscator([]byte(key), privacy.DefaultDecimals), nil
+	}
+	logger.Warn("PRIVACY_KEY not set, public partner locations change on restart")
+	return privacy.NewRandomObfuscator(privacy.DefaultDecimals)
+}
+
+// newCipher creates the cipher encrypting the contact data of customers with the base64 encoded key in PII_KEY or in
+// the file PII_KEY_FILE. Without a key a random one is used, which is only suitable while data is held in memory.
+func newCipher(logger *slog.Logger) (*privacy.Cipher, error) {
+	var key []byte
+	var err error
+	switch {
+	case os.Getenv("PII_KEY_FILE") != "":
+		key, err = privacy.LoadKey(os.Getenv("PII_KEY_FILE"))
+	case os.Getenv("PII_KEY") != "":
+		key, err = privacy.ParseKey(os.Getenv("PII_KEY"))
+	default:
+		logger.Warn("PII_KEY and PII_KEY_FILE not set, using a random key for personal data")
+		return privacy.NewRandomCipher()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading pii key: %w", err)
+	}
+	return privacy.NewCipher(key)
+}
+
+// defaultRateLimits are the limits per client applied when RATE_LIMITS does not override them. Creating partners is
+// an admin task and limited stronger than searching. A lead notifies several partners, so it is limited strongest.
+var defaultRateLimits = ratelimit.Policy{
+	Default: ratelimit.Limit{Requests: 120, Period: time.Minute, Burst: 30},
+	Routes: map[string]ratelimit.Limit{
+		"/partners": {Requests: 30, Period: time.Minute, Burst: 10},
+		"/leads":    {Requests: 10, Period: time.Minute, Burst: 5},
+	},
+}
+
+// newPartnerStore returns the repository of partners with the demo data, or with partners generated by the seed
+// package when SEED_PARTNERS is set to "count[:seed]", e.g. to load test the matching. SEED_CITIES replaces the cities
+// the partners are generated around.
+func newPartnerStore(outbox *db.OutboxInMemoryRepository, logger *slog.Logger) (*db.PartnerInMemoryRepository, error) {
+	spec := os.Getenv("SEED_PARTNERS")
+	if spec == "" {
+		return db.NewPartnerInMemoryRepository(outbox), nil
+	}
+	count, randomSeed, hasSeed := strings.Cut(spec, ":")
+	opts := seed.Options{Seed: 1}
+	var err error
+	if opts.Count, err = strconv.Atoi(count); err != nil || opts.Count < 0 {
+		return nil, errors.New("parsing SEED_PARTNERS: invalid number of partners")
+	}
+	if hasSeed {
+		if opts.Seed, err = strconv.ParseInt(randomSeed, 10, 64); err != nil {
+			return nil, errors.New("parsing SEED_PARTNERS: invalid seed")
+		}
+	}
+	if cities := os.Getenv("SEED_CITIES"); cities != "" {
+		if opts.Cities, err = seed.ParseCities(cities); err != nil {
+			return nil, fmt.Errorf("parsing SEED_CITIES: %w", err)
+		}
+	}
+	logger.Info("seeding partners", "count", opts.Count, "seed", opts.Seed)
+	return db.NewPartnerInMemoryRepositoryFrom(seed.Partners(opts), outbox), nil
+}
+
+// newMatchCacheConfig applies MATCH_CACHE_SIZE, MATCH_CACHE_TTL and MATCH_CACHE_PRECISION on top of
+// cache.DefaultConfig. MATCH_CACHE_SIZE=0 disables the cache.
+func newMatchCacheConfig() (cache.Config, error) {
+	cfg := cache.DefaultConfig
+	var err error
+	if v := os.Getenv("MATCH_CACHE_SIZE"); v != "" {
+		if cfg.Size, err = strconv.Atoi(v); err != nil || cfg.Size < 0 {
+			return cache.Config{}, errors.New("parsing MATCH_CACHE_SIZE: must not be negative")
+		}
+	}
+	if v := os.Getenv("MATCH_CACHE_TTL"); v != "" {
+		if cfg.TTL, err = time.ParseDuration(v); err != nil || cfg.TTL <= 0 {
+			return cache.Config{}, errors.New("parsing MATCH_CACHE_TTL: must be a positive duration")
+		}
+	}
+	if v := os.Getenv("MATCH_CACHE_PRECISION"); v != "" {
+		if cfg.Precision, err = strconv.Atoi(v); err != nil || cfg.Precision < 1 || cfg.Precision > 12 {
+			return cache.Config{}, errors.New("parsing MATCH_CACHE_PRECISION: must be between 1 and 12")
+		}
+	}
+	return cfg, nil
+}
+
+// newRateLimiter applies the limits of RATE_LIMITS on top of defaultRateLimits. RATE_LIMIT_TRUSTED_PROXIES is the
+// number of proxies whose X-Forwarded-For entries identify anonymous clients, RATE_LIMIT_TRUST_FORWARDED_FOR=true is
+// the same as one proxy. RATE_LIMITS=off disables rate limiting.
+func newRateLimiter() (web.RateLimiter, error) {
+	spec := os.Getenv("RATE_LIMITS")
+	if spec == "off" {
+		return nil, nil
+	}
+	policy, err := ratelimit.ParsePolicy(spec, defaultRateLimits)
+	if err != nil {
+		return nil, fmt.Errorf("parsing RATE_LIMITS: %w", err)
+	}
+	if os.Getenv("RATE_LIMIT_TRUST_FORWARDED_FOR") == "true" {
+		policy.TrustedProxies = 1
+	}
+	if v := os.Getenv("RATE_LIMIT_TRUSTED_PROXIES"); v != "" {
+		if policy.TrustedProxies, err = strconv.Atoi(v); err != nil || policy.TrustedProxies < 0 {
+			return nil, errors.New("parsing RATE_LIMIT_TRUSTED_PROXIES: must not be a negative number")
+		}
+	}
+	return ratelimit.NewEnforcer(ratelimit.NewMemoryLimiter(), policy), nil
 }
@@ -0,0 +1,169 @@
+// Package audit records who changed which partner attributes when, so that disputes about partner data can be
+// resolved.
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"customer-partner/internal/auth"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"encoding/hex"
+	"reflect"
+	"time"
+)
+
+// SystemActor is the actor of changes which were not made on behalf of an authenticated caller, e.g. by jobs.
+const SystemActor = "system"
+
+// fields lists the audited attributes of a partner by their json name.
+var fields = []struct {
+	name  string
+	value func(entities.Partner) any
+}{
+	{"name", func(p entities.Partner) any { return p.Name }},
+	{"experienced_material", func(p entities.Partner) any { return p.ExperiencedMaterial }},
+	{"address", func(p entities.Partner) any { return p.Address }},
+	{"operating_radius", func(p entities.Partner) any { return p.OperatingRadius }},
+	{"rating", func(p entities.Partner) any { return p.Rating }},
+	{"daily_lead_cap", func(p entities.Partner) any { return p.DailyLeadCap }},
+	{"contact", func(p entities.Partner) any { return p.Contact }},
+	{"price_lists", func(p entities.Partner) any { return p.PriceLists }},
+	{"time_zone", func(p entities.Partner) any { return p.TimeZone }},
+	{"opening_hours", func(p entities.Partner) any { return p.OpeningHours }},
+	{"holidays", func(p entities.Partner) any { return p.Holidays }},
+	{"verification_status", func(p entities.Partner) any { return p.VerificationStatus }},
+	{"certifications", func(p entities.Partner) any { return p.Certifications }},
+	{"insurance_expires_at", func(p entities.Partner) any { return p.InsuranceExpiresAt }},
+	{"suspended_at", func(p entities.Partner) any { return p.SuspendedAt }},
+	{"suspension_reason", func(p entities.Partner) any { return p.SuspensionReason }},
+	{"deleted_at", func(p entities.Partner) any { return p.DeletedAt }},
+	{"deletion_reason", func(p entities.Partner) any { return p.DeletionReason }},
+	{"external_id", func(p entities.Partner) any { return p.ExternalID }},
+}
+
+// personalFields are the fields holding personal data. Only that they changed is recorded, without the values, the
+// same as for the redacted changes of erased partners.
+var personalFields = map[string]bool{"contact": true}
+
+func NewAuditedPartnerRepository(
+	next domain.PartnerRepository,
+	changes domain.PartnerChangeRepository,
+) *AuditedPartnerRepository {
+	return &AuditedPartnerRepository{next: next, changes: changes, now: time.Now}
+}
+
+// AuditedPartnerRepository appends an entities.PartnerChange to the audit trail for every successful write. Reads
+// are passed through. The actor is the subject of the principal in the context, SystemActor otherwise.
+//
+// A change is recorded after the write succeeded and with a context which is not canceled with the request, so that
+// a client disconnecting in between does not leave a gap in the trail. When recording fails, the error is returned
+// even though the write already happened.
+type AuditedPartnerRepository struct {
+	next    domain.PartnerRepository
+	changes domain.PartnerChangeRepository
+	now     func() time.Time
+}
+
+func (r *AuditedPartnerRepository) GetPartnersByMaterial(ctx context.Context, material string) ([]entities.Partner, error) {
+	return r.next.GetPartnersByMaterial(ctx, material)
+}
+
+func (r *AuditedPartnerRepository) GetPartnerByID(ctx context.Context, id string) (entities.Partner, error) {
+	return r.next.GetPartnerByID(ctx, id)
+}
+
+func (r *AuditedPartnerRepository) GetAllPartners(ctx context.Context) ([]entities.Partner, error) {
+	return r.next.GetAllPartners(ctx)
+}
+
+func (r *AuditedPartnerRepository) GetPartnersWithExpiringCertifications(
+	ctx context.Context,
+	before time.Time,
+) ([]entities.Partner, error) {
+	return r.next.GetPartnersWithExpiringCertifications(ctx, before)
+}
+
+func (r *AuditedPartnerRepository) CreatePartner(ctx context.Context, partner entities.Partner, events ...entities.Event) error {
+	if err := r.next.CreatePartner(ctx, partner, events...); err != nil {
+		return err
+	}
+	return r.record(ctx, partner.ID, entities.PartnerCreated, diff(nil, &partner))
+}
+
+func (r *AuditedPartnerRepository) UpdatePartner(ctx context.Context, partner entities.Partner, events ...entities.Event) error {
+	before, err := r.next.GetPartnerByID(ctx, partner.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.next.UpdatePartner(ctx, partner, events...); err != nil {
+		return err
+	}
+	changes := diff(&before, &partner)
+	if len(changes) == 0 {
+		return nil
+	}
+	return r.record(ctx, partner.ID, entities.PartnerUpdated, changes)
+}
+
+func (r *AuditedPartnerRepository) DeletePartner(ctx context.Context, id string, events ...entities.Event) error {
+	before, err := r.next.GetPartnerByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := r.next.DeletePartner(ctx, id, events...); err != nil {
+		return err
+	}
+	return r.record(ctx, id, entities.PartnerDeleted, diff(&before, nil))
+}
+
+func (r *AuditedPartnerRepository) record(
+	ctx context.Context,
+	partnerID string,
+	action string,
+	changes []entities.FieldChange,
+) error {
+	actor := SystemActor
+	if principal, ok := auth.FromContext(ctx); ok {
+		actor = principal.Subject
+	}
+	return r.changes.AppendPartnerChange(context.WithoutCancel(ctx), entities.PartnerChange{
+		ID:        newID(),
+		PartnerID: partnerID,
+		Action:    action,
+		Actor:     actor,
+		RequestID: logging.RequestIDFromContext(ctx),
+		ChangedAt: r.now().UTC(),
+		Changes:   changes,
+	})
+}
+
+// diff returns the attributes which differ between before and after. before is nil for created partners, after is
+// nil for deleted partners.
+func diff(before, after *entities.Partner) []entities.FieldChange {
+	changes := []entities.FieldChange{}
+	for _, field := range fields {
+		change := entities.FieldChange{Field: field.name}
+		if before != nil {
+			change.Before = field.value(*before)
+		}
+		if after != nil {
+			change.After = field.value(*after)
+		}
+		if before != nil && after != nil && reflect.DeepEqual(change.Before, change.After) {
+			continue
+		}
+		if personalFields[field.name] {
+			change.Before, change.After = nil, nil
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
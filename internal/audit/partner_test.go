@@ -0,0 +1,142 @@
+package audit_test
+
+import (
+	"context"
+	"customer-partner/internal/audit"
+	"customer-partner/internal/auth"
+	"customer-partner/internal/db"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditedPartnerRepository(t *testing.T) {
+	changes := db.NewPartnerChangeInMemoryRepository()
+	repo := audit.NewAuditedPartnerRepository(db.NewPartnerInMemoryRepository(nil), changes)
+	admin := auth.WithPrincipal(context.Background(), auth.Principal{
+		Subject: "apikey:crm",
+		Roles:   []auth.Role{auth.RoleAdmin},
+	})
+	admin = logging.WithRequestID(admin, "req-1")
+	partner := entities.Partner{
+		ID:                  "new",
+		Name:                "Floors & More",
+		ExperiencedMaterial: []string{"wood"},
+		Address:             entities.Address{Latitude: 48.1, Longitude: 11.5},
+		OperatingRadius:     10,
+		Rating:              3,
+	}
+
+	require.NoError(t, repo.CreatePartner(admin, partner))
+	require.NoError(t, repo.UpdatePartner(admin, partner))
+	updated := partner
+	updated.OperatingRadius = 20
+	updated.ExperiencedMaterial = []string{"wood", "tiles"}
+	require.NoError(t, repo.UpdatePartner(context.Background(), updated))
+	require.NoError(t, repo.DeletePartner(admin, partner.ID))
+	assert.Equal(t, entities.ErrRecordNotExist, repo.UpdatePartner(admin, partner))
+	assert.Equal(t, entities.ErrRecordNotExist, repo.DeletePartner(admin, partner.ID))
+
+	history, err := changes.GetPartnerChanges(context.Background(), partner.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 3, "unchanged and failed writes are not recorded")
+
+	created := history[0]
+	assert.Equal(t, entities.PartnerCreated, created.Action)
+	assert.Equal(t, "apikey:crm", created.Actor)
+	assert.Equal(t, "req-1", created.RequestID)
+	assert.NotEmpty(t, created.ID)
+	assert.False(t, created.ChangedAt.IsZero())
+	assert.Len(t, created.Changes, 19)
+	assert.Equal(t, entities.FieldChange{Field: "name", After: "Floors & More"}, created.Changes[0])
+
+	assert.Equal(t, entities.PartnerUpdated, history[1].Action)
+	assert.Equal(t, audit.SystemActor, history[1].Actor)
+	assert.Equal(t, []entities.FieldChange{
+		{Field: "experienced_material", Before: []string{"wood"}, After: []string{"wood", "tiles"}},
+		{Field: "operating_radius", Before: 10, After: 20},
+	}, history[1].Changes)
+
+	assert.Equal(t, entities.PartnerDeleted, history[2].Action)
+	assert.Len(t, history[2].Changes, 19)
+	assert.Equal(t, entities.FieldChange{Field: "operating_radius", Before: 20}, history[2].Changes[3])
+}
+
+func TestAuditedPartnerRepository_UpdatedFields(t *testing.T) {
+	type testCase struct {
+		name      string
+		update    func(p *entities.Partner)
+		expChange entities.FieldChange
+	}
+	priceLists := []entities.PriceList{{Material: "wood", MinPerSquareMeter: 3000, MaxPerSquareMeter: 4500}}
+	openingHours := []entities.OpeningHours{{Weekday: "monday", Opens: "08:00", Closes: "17:00"}}
+	tests := []testCase{
+		{
+			name:      "Records daily lead cap",
+			update:    func(p *entities.Partner) { p.DailyLeadCap = 5 },
+			expChange: entities.FieldChange{Field: "daily_lead_cap", Before: 0, After: 5},
+		},
+		{
+			name:      "Records that the contact changed without its values",
+			update:    func(p *entities.Partner) { p.Contact = &entities.Contact{Email: "info@floors.example"} },
+			expChange: entities.FieldChange{Field: "contact"},
+		},
+		{
+			name:      "Records price lists",
+			update:    func(p *entities.Partner) { p.PriceLists = priceLists },
+			expChange: entities.FieldChange{Field: "price_lists", Before: []entities.PriceList(nil), After: priceLists},
+		},
+		{
+			name:      "Records time zone",
+			update:    func(p *entities.Partner) { p.TimeZone = "Europe/Vienna" },
+			expChange: entities.FieldChange{Field: "time_zone", Before: "", After: "Europe/Vienna"},
+		},
+		{
+			name:   "Records opening hours",
+			update: func(p *entities.Partner) { p.OpeningHours = openingHours },
+			expChange: entities.FieldChange{
+				Field:  "opening_hours",
+				Before: []entities.OpeningHours(nil),
+				After:  openingHours,
+			},
+		},
+		{
+			name:      "Records holidays",
+			update:    func(p *entities.Partner) { p.Holidays = []string{"2099-12-24"} },
+			expChange: entities.FieldChange{Field: "holidays", Before: []string(nil), After: []string{"2099-12-24"}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			changes := db.NewPartnerChangeInMemoryRepository()
+			repo := audit.NewAuditedPartnerRepository(db.NewPartnerInMemoryRepository(nil), changes)
+			partner := entities.Partner{ID: "new", Name: "Floors"}
+			require.NoError(t, repo.CreatePartner(context.Background(), partner))
+			tt.update(&partner)
+
+			require.NoError(t, repo.UpdatePartner(context.Background(), partner))
+
+			history, err := changes.GetPartnerChanges(context.Background(), partner.ID)
+			require.NoError(t, err)
+			require.Len(t, history, 2)
+			assert.Equal(t, []entities.FieldChange{tt.expChange}, history[1].Changes)
+		})
+	}
+}
+
+func TestAuditedPartnerRepository_CanceledContext(t *testing.T) {
+	changes := db.NewPartnerChangeInMemoryRepository()
+	repo := audit.NewAuditedPartnerRepository(db.NewPartnerInMemoryRepository(nil), changes)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, repo.CreatePartner(ctx, entities.Partner{ID: "new"}), context.Canceled)
+
+	history, err := changes.GetPartnerChanges(context.Background(), "new")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
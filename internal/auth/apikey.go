@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// APIKey is a static key of an internal service. Only the SHA-256 hash of the key is stored.
+type APIKey struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+	Roles  []Role `json:"roles"`
+}
+
+// LoadAPIKeys reads API keys from a json file holding a list of APIKey. Hashes may be hex encoded in either case, they
+// are returned in lower case like HashAPIKey returns them.
+func LoadAPIKeys(path string) ([]APIKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []APIKey
+	if err := json.Unmarshal(raw, &keys); err != nil {
+		return nil, fmt.Errorf("parsing api keys: %w", err)
+	}
+	for i, key := range keys {
+		if key.Name == "" {
+			return nil, fmt.Errorf("api key %d: name missing", i)
+		}
+		if _, err := hex.DecodeString(key.SHA256); err != nil || len(key.SHA256) != sha256.Size*2 {
+			return nil, fmt.Errorf("api key %s: invalid sha256", key.Name)
+		}
+		keys[i].SHA256 = strings.ToLower(key.SHA256)
+		for _, role := range key.Roles {
+			if !role.valid() {
+				return nil, fmt.Errorf("api key %s: unknown role %q", key.Name, role)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// HashAPIKey returns the hex encoded SHA-256 hash of key as stored in APIKey.SHA256.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func lookupAPIKey(keys []APIKey, key string) (Principal, bool) {
+	hash := []byte(HashAPIKey(key))
+	for _, k := range keys {
+		if subtle.ConstantTimeCompare(hash, []byte(k.SHA256)) == 1 {
+			return Principal{Subject: "apikey:" + k.Name, Roles: k.Roles}, true
+		}
+	}
+	return Principal{}, false
+}
@@ -0,0 +1,211 @@
+package auth_test
+
+import (
+	"customer-partner/internal/auth"
+	"customer-partner/internal/auth/authtest"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	otherKeys := authtest.NewKeySet(t)
+	verifier, err := auth.LoadJWTVerifier(keys.WriteJWKS(t), auth.JWTConfig{
+		Issuer:   authtest.Issuer,
+		Audience: authtest.Audience,
+	})
+	require.NoError(t, err)
+	authenticator := auth.NewAuthenticator(authtest.APIKeys(), verifier)
+
+	type testCase struct {
+		name         string
+		header       string
+		value        string
+		expOK        bool
+		expErr       error
+		expPrincipal auth.Principal
+	}
+	tests := []testCase{
+		{
+			name:  "Anonymous request without credentials",
+			expOK: false,
+		},
+		{
+			name:         "Valid API key",
+			header:       auth.APIKeyHeader,
+			value:        authtest.AdminAPIKey,
+			expOK:        true,
+			expPrincipal: auth.Principal{Subject: "apikey:test-admin", Roles: []auth.Role{auth.RoleAdmin}},
+		},
+		{
+			name:   "Unknown API key",
+			header: auth.APIKeyHeader,
+			value:  "guessed",
+			expErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:   "Valid partner token",
+			header: "Authorization",
+			value:  authtest.Bearer(keys.PartnerToken(t, "user-1", "2")),
+			expOK:  true,
+			expPrincipal: auth.Principal{
+				Subject:   "user-1",
+				Roles:     []auth.Role{auth.RolePartner},
+				PartnerID: "2",
+			},
+		},
+		{
+			name:   "Partner id claim ignored without partner role",
+			header: "Authorization",
+			value: authtest.Bearer(keys.Token(t, auth.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: "user-2"},
+				Roles:            []auth.Role{auth.RoleCustomer, "superuser"},
+				PartnerID:        "2",
+			})),
+			expOK:        true,
+			expPrincipal: auth.Principal{Subject: "user-2", Roles: []auth.Role{auth.RoleCustomer}},
+		},
+		{
+			name:   "Token signed by unknown key",
+			header: "Authorization",
+			value:  authtest.Bearer(otherKeys.AdminToken(t, "user-1")),
+			expErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:   "Expired token",
+			header: "Authorization",
+			value: authtest.Bearer(keys.Token(t, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "user-1",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+			}})),
+			expErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:   "Token of other issuer",
+			header: "Authorization",
+			value: authtest.Bearer(keys.Token(t, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{
+				Subject: "user-1",
+				Issuer:  "https://evil.test",
+			}})),
+			expErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:   "Malformed authorization header",
+			header: "Authorization",
+			value:  "Basic dXNlcjpwYXNz",
+			expErr: auth.ErrInvalidCredentials,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			p, ok, err := authenticator.Authenticate(req)
+
+			assert.ErrorIs(t, err, tt.expErr)
+			assert.Equal(t, tt.expOK, ok)
+			assert.Equal(t, tt.expPrincipal, p)
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	authenticator := keys.Authenticator(t)
+	handler := authenticator.Middleware(auth.RequireRole(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}, auth.RoleAdmin))
+
+	type testCase struct {
+		name      string
+		auth      string
+		expStatus int
+	}
+	tests := []testCase{
+		{name: "Rejects anonymous request", auth: "", expStatus: http.StatusUnauthorized},
+		{name: "Rejects invalid token", auth: "Bearer invalid", expStatus: http.StatusUnauthorized},
+		{name: "Rejects principal without role", auth: authtest.Bearer(keys.CustomerToken(t, "c")), expStatus: http.StatusForbidden},
+		{name: "Passes principal with role", auth: authtest.Bearer(keys.AdminToken(t, "a")), expStatus: http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/partners", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+		})
+	}
+}
+
+func TestPrincipal_CanManagePartner(t *testing.T) {
+	admin := auth.Principal{Roles: []auth.Role{auth.RoleAdmin}}
+	owner := auth.Principal{Roles: []auth.Role{auth.RolePartner}, PartnerID: "1"}
+	customer := auth.Principal{Roles: []auth.Role{auth.RoleCustomer}, PartnerID: "1"}
+	orphan := auth.Principal{Roles: []auth.Role{auth.RolePartner}}
+
+	assert.True(t, admin.CanManagePartner("1"))
+	assert.True(t, owner.CanManagePartner("1"))
+	assert.False(t, owner.CanManagePartner("2"))
+	assert.False(t, customer.CanManagePartner("1"))
+	assert.False(t, orphan.CanManagePartner(""))
+}
+
+func TestPrincipal_CanActForCustomer(t *testing.T) {
+	admin := auth.Principal{Subject: "apikey:crm", Roles: []auth.Role{auth.RoleAdmin}}
+	customer := auth.Principal{Subject: "customer-1", Roles: []auth.Role{auth.RoleCustomer}}
+	partner := auth.Principal{Subject: "customer-1", Roles: []auth.Role{auth.RolePartner}, PartnerID: "1"}
+
+	assert.True(t, admin.CanActForCustomer("customer-1"))
+	assert.True(t, customer.CanActForCustomer("customer-1"))
+	assert.False(t, customer.CanActForCustomer("customer-2"))
+	assert.False(t, customer.CanActForCustomer(""))
+	assert.False(t, partner.CanActForCustomer("customer-1"))
+	assert.Equal(t, "customer-1", customer.CustomerID())
+	assert.Empty(t, partner.CustomerID())
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`[{"name":"crm","sha256":"`+auth.HashAPIKey("secret")+`","roles":["admin"]}]`), 0o600))
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`[{"name":"crm","sha256":"`+auth.HashAPIKey("secret")+`","roles":["root"]}]`), 0o600))
+
+	keys, err := auth.LoadAPIKeys(valid)
+	require.NoError(t, err)
+	assert.Equal(t, []auth.APIKey{{Name: "crm", SHA256: auth.HashAPIKey("secret"), Roles: []auth.Role{auth.RoleAdmin}}}, keys)
+
+	_, err = auth.LoadAPIKeys(invalid)
+	assert.Error(t, err)
+
+	upper := filepath.Join(dir, "upper.json")
+	hash := strings.ToUpper(auth.HashAPIKey("secret"))
+	require.NoError(t, os.WriteFile(upper, []byte(`[{"name":"crm","sha256":"`+hash+`","roles":["admin"]}]`), 0o600))
+	keys, err = auth.LoadAPIKeys(upper)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, "/partners", nil)
+	req.Header.Set(auth.APIKeyHeader, "secret")
+	p, ok, err := auth.NewAuthenticator(keys, nil).Authenticate(req)
+	require.NoError(t, err)
+	assert.True(t, ok, "upper case hashes match")
+	assert.Equal(t, "apikey:crm", p.Subject)
+}
@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims are the claims of a JWT accepted by the api.
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles     []Role `json:"roles"`
+	PartnerID string `json:"partner_id,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set as defined in RFC 7517.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is a single public key of a JWKS. RSA and EC (P-256) keys are supported.
+type JWK struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWTConfig configures the verification of JWTs.
+type JWTConfig struct {
+	// Issuer is required as iss claim when set.
+	Issuer string
+	// Audience is required as aud claim when set.
+	Audience string
+}
+
+// JWTVerifier verifies JWTs signed by one of the keys of a JWKS.
+type JWTVerifier struct {
+	keys   map[string]any
+	parser *jwt.Parser
+}
+
+// LoadJWTVerifier creates a JWTVerifier for the JWKS stored in the file at path.
+func LoadJWTVerifier(path string, cfg JWTConfig) (*JWTVerifier, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set JWKS
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("parsing jwks: %w", err)
+	}
+	return NewJWTVerifier(set, cfg)
+}
+
+// NewJWTVerifier creates a JWTVerifier for the keys of set.
+func NewJWTVerifier(set JWKS, cfg JWTConfig) (*JWTVerifier, error) {
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwk %s: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256"}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	return &JWTVerifier{keys: keys, parser: jwt.NewParser(opts...)}, nil
+}
+
+// Verify checks the signature and the claims of the token and returns the principal it was issued for.
+func (v *JWTVerifier) Verify(token string) (Principal, error) {
+	var claims Claims
+	_, err := v.parser.ParseWithClaims(token, &claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := v.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		return key, nil
+	})
+	if err != nil {
+		return Principal{}, err
+	}
+	if claims.Subject == "" {
+		return Principal{}, errors.New("token has no subject")
+	}
+	p := Principal{Subject: claims.Subject}
+	for _, role := range claims.Roles {
+		if role.valid() {
+			p.Roles = append(p.Roles, role)
+		}
+	}
+	if p.HasRole(RolePartner) {
+		p.PartnerID = claims.PartnerID
+	}
+	return p, nil
+}
+
+func (k JWK) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("decoding n: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("decoding e: %w", err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decoding x: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("decoding y: %w", err)
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("point not on curve")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
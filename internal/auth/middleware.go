@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// APIKeyHeader is the header carrying a static API key.
+const APIKeyHeader = "X-API-Key"
+
+// ErrInvalidCredentials is returned for API keys or tokens which could not be verified.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+func NewAuthenticator(apiKeys []APIKey, verifier *JWTVerifier) *Authenticator {
+	return &Authenticator{apiKeys: apiKeys, verifier: verifier}
+}
+
+// Authenticator resolves the credentials of a request to a Principal. Either of the API keys and the JWT verifier may
+// be absent, which rejects the respective credentials.
+type Authenticator struct {
+	apiKeys  []APIKey
+	verifier *JWTVerifier
+}
+
+// Authenticate returns the principal of the request. ok is false for anonymous requests without any credentials. An
+// error is returned for credentials which are invalid.
+func (a *Authenticator) Authenticate(r *http.Request) (p Principal, ok bool, err error) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		p, ok := lookupAPIKey(a.apiKeys, key)
+		if !ok {
+			return Principal{}, false, ErrInvalidCredentials
+		}
+		return p, true, nil
+	}
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return Principal{}, false, nil
+	}
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found || a.verifier == nil {
+		return Principal{}, false, ErrInvalidCredentials
+	}
+	p, err = a.verifier.Verify(strings.TrimSpace(token))
+	if err != nil {
+		return Principal{}, false, ErrInvalidCredentials
+	}
+	return p, true, nil
+}
+
+// Middleware attaches the principal of authenticated requests to the request context. Anonymous requests are passed
+// on unchanged, requests with invalid credentials are rejected with 401.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, ok, err := a.Authenticate(r)
+		if err != nil {
+			Unauthorized(w)
+			return
+		}
+		if ok {
+			r = r.WithContext(WithPrincipal(r.Context(), p))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireRole only passes on requests of principals having one of the roles. Anonymous requests are rejected with 401,
+// requests of principals without any of the roles with 403.
+func RequireRole(next http.HandlerFunc, roles ...Role) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, ok := FromContext(r.Context())
+		if !ok {
+			Unauthorized(w)
+			return
+		}
+		for _, role := range roles {
+			if p.HasRole(role) {
+				next(w, r)
+				return
+			}
+		}
+		Forbidden(w)
+	}
+}
+
+// Unauthorized responds with 401 and asks the client to authenticate.
+func Unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="customer-partner"`)
+	http.Error(w, "Unauthorized", http.StatusUnauthorized)
+}
+
+// Forbidden responds with 403.
+func Forbidden(w http.ResponseWriter) {
+	http.Error(w, "Forbidden", http.StatusForbidden)
+}
@@ -0,0 +1,80 @@
+// Package auth authenticates callers of the api by static API keys or by JWTs verified against a local JWKS file and
+// authorizes them by their roles.
+package auth
+
+import "context"
+
+// Role grants access to a group of operations.
+type Role string
+
+const (
+	RoleCustomer Role = "customer"
+	RolePartner  Role = "partner"
+	RoleAdmin    Role = "admin"
+)
+
+func (r Role) valid() bool {
+	switch r {
+	case RoleCustomer, RolePartner, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// Principal is an authenticated caller.
+type Principal struct {
+	// Subject identifies the caller, e.g. the sub claim of a JWT or the name of an API key.
+	Subject string
+	Roles   []Role
+	// PartnerID is the id of the partner record the caller owns. It is only set for callers with RolePartner.
+	PartnerID string
+}
+
+// HasRole reports whether the principal was granted the role.
+func (p Principal) HasRole(role Role) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// CanManagePartner reports whether the principal may change the partner with the given id. Admins may change every
+// partner, partners only their own record.
+func (p Principal) CanManagePartner(partnerID string) bool {
+	if p.HasRole(RoleAdmin) {
+		return true
+	}
+	return p.HasRole(RolePartner) && p.PartnerID != "" && p.PartnerID == partnerID
+}
+
+// CanActForCustomer reports whether the principal may act for the customer with the given id, which is the subject of
+// the customer. Admins may act for every customer.
+func (p Principal) CanActForCustomer(customerID string) bool {
+	if p.HasRole(RoleAdmin) {
+		return true
+	}
+	return p.HasRole(RoleCustomer) && customerID != "" && p.Subject == customerID
+}
+
+// CustomerID returns the id of the customer the principal is, empty when the principal is no customer.
+func (p Principal) CustomerID() string {
+	if p.HasRole(RoleCustomer) {
+		return p.Subject
+	}
+	return ""
+}
+
+type ctxKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, p)
+}
+
+// FromContext returns the principal carried by ctx. ok is false for anonymous requests.
+func FromContext(ctx context.Context) (p Principal, ok bool) {
+	p, ok = ctx.Value(ctxKey{}).(Principal)
+	return p, ok
+}
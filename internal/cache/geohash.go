@@ -0,0 +1,40 @@
+package cache
+
+// base32 is the alphabet of geohashes.
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// geohash returns the geohash cell of the given precision containing the location, and the centre of the cell. Cells
+// of precision 6 are about 1.2 km wide and 0.6 km high.
+func geohash(lat, long float64, precision int) (hash string, centreLat, centreLong float64) {
+	minLat, maxLat := -90.0, 90.0
+	minLong, maxLong := -180.0, 180.0
+	cell := make([]byte, 0, precision)
+	even := true
+	for len(cell) < precision {
+		index := 0
+		for bit := 0; bit < 5; bit++ {
+			index <<= 1
+			// Bits alternate between longitude and latitude, starting with longitude.
+			if even {
+				mid := (minLong + maxLong) / 2
+				if long >= mid {
+					index |= 1
+					minLong = mid
+				} else {
+					maxLong = mid
+				}
+			} else {
+				mid := (minLat + maxLat) / 2
+				if lat >= mid {
+					index |= 1
+					minLat = mid
+				} else {
+					maxLat = mid
+				}
+			}
+			even = !even
+		}
+		cell = append(cell, base32[index])
+	}
+	return string(cell), (minLat + maxLat) / 2, (minLong + maxLong) / 2
+}
@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeohash(t *testing.T) {
+	type testCase struct {
+		name      string
+		lat       float64
+		long      float64
+		precision int
+		expHash   string
+	}
+	tests := []testCase{
+		{name: "Encodes location", lat: 57.64911, long: 10.40744, precision: 11, expHash: "u4pruydqqvj"},
+		{name: "Encodes location with low precision", lat: 48.1372, long: 11.5756, precision: 5, expHash: "u281z"},
+		{name: "Encodes southern and western location", lat: -33.4489, long: -70.6693, precision: 6, expHash: "66j9xy"},
+		{name: "Encodes corner", lat: 90, long: 180, precision: 3, expHash: "zzz"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			hash, lat, long := geohash(tt.lat, tt.long, tt.precision)
+
+			assert.Equal(t, tt.expHash, hash)
+			centre, _, _ := geohash(lat, long, tt.precision)
+			assert.Equal(t, tt.expHash, centre, "centre must be in the cell")
+		})
+	}
+}
@@ -0,0 +1,288 @@
+// Package cache caches the results of matches, so that customers in the same neighbourhood asking for the same material
+// do not recompute the same match.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PartnerService is the service interface decorated by CachedPartnerService. It matches web.PartnerService.
+type PartnerService interface {
+	GetPartners(ctx context.Context, opts domain.GetPartnersOpts) ([]entities.Partner, error)
+	GetPartner(ctx context.Context, id string) (entities.Partner, error)
+	CreatePartner(ctx context.Context, partner entities.Partner) (entities.Partner, error)
+	UpdatePartner(ctx context.Context, partner entities.Partner) (entities.Partner, error)
+	SuspendPartner(ctx context.Context, id, reason string) (entities.Partner, error)
+	ReinstatePartner(ctx context.Context, id string) (entities.Partner, error)
+	DeletePartner(ctx context.Context, id, reason string) error
+	GetAllPartners(ctx context.Context) ([]entities.Partner, error)
+	ImportPartners(ctx context.Context, rows []domain.ImportRow, dryRun bool) (domain.ImportResult, error)
+}
+
+// Config bounds the cache.
+type Config struct {
+	// Size is the maximum number of cached matches. The least recently used match is evicted when it is exceeded.
+	Size int
+	// TTL is the time a match is cached. It bounds how stale the availability of partners may get, which changes with
+	// time rather than with events.
+	TTL time.Duration
+	// Precision is the length of the geohash cells customers are grouped by.
+	Precision int
+}
+
+// DefaultConfig groups customers by cells of about 1.2 by 0.6 km and keeps their matches for a minute.
+var DefaultConfig = Config{
+	Size:      10000,
+	TTL:       time.Minute,
+	Precision: 6,
+}
+
+// seenEvents is the number of ids of published events remembered to recognise events published again.
+const seenEvents = 1024
+
+// Stats are the counts of a cache since it was created.
+type Stats struct {
+	Hits          uint64
+	Misses        uint64
+	Evictions     uint64
+	Invalidations uint64
+	// Entries is the number of cached matches.
+	Entries int
+}
+
+func NewCachedPartnerService(next PartnerService, cfg Config) *CachedPartnerService {
+	return &CachedPartnerService{
+		next:    next,
+		cfg:     cfg,
+		now:     time.Now,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+		seen:    map[string]bool{},
+	}
+}
+
+// CachedPartnerService caches matches by the material and the other options of the match, and the geohash cell of the
+// customer. Matches are run for the centre of the cell, so that all customers of the cell get the same result no
+// matter who asked first.
+//
+// Partner changes are rare compared to matches, so every change clears the whole cache instead of tracking which
+// matches a partner appears in: writes through the service clear it right away, changes made elsewhere when their
+// event is published.
+type CachedPartnerService struct {
+	next PartnerService
+	cfg  Config
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// lru holds the entries, most recently used first.
+	lru *list.List
+	// generation is increased by every invalidation, so that matches started before are not cached.
+	generation uint64
+	stats      Stats
+	// seen holds the ids of the latest published events, recent lists them in the order they were seen.
+	seen   map[string]bool
+	recent []string
+}
+
+// entry is a cached match.
+type entry struct {
+	key       string
+	partners  []entities.Partner
+	expiresAt time.Time
+}
+
+// WithClock replaces the clock the expiry of matches is checked with, e.g. by a fixed time in tests.
+func (s *CachedPartnerService) WithClock(now func() time.Time) *CachedPartnerService {
+	s.now = now
+	return s
+}
+
+// GetPartners returns the cached match of the cell of the customer, or runs the match for the centre of the cell.
+func (s *CachedPartnerService) GetPartners(ctx context.Context, opts domain.GetPartnersOpts) ([]entities.Partner, error) {
+	cell, lat, long := geohash(opts.CustomerAddressLat, opts.CustomerAddressLong, s.cfg.Precision)
+	opts.CustomerAddressLat, opts.CustomerAddressLong = lat, long
+	key := cacheKey(cell, opts)
+	partners, generation, ok := s.get(key)
+	if ok {
+		return partners, nil
+	}
+	partners, err := s.next.GetPartners(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	s.put(key, partners, generation)
+	return clone(partners), nil
+}
+
+func cacheKey(cell string, opts domain.GetPartnersOpts) string {
+	var from, until int64
+	if !opts.AvailableFrom.IsZero() {
+		from = opts.AvailableFrom.Unix()
+	}
+	if !opts.AvailableUntil.IsZero() {
+		until = opts.AvailableUntil.Unix()
+	}
+	return fmt.Sprintf("%s|%s|%g|%s|%t|%t|%d|%d", opts.Material, cell, opts.FloorSize, opts.SortBy,
+		opts.IncludeUnverified, opts.IncludeInactive, from, until)
+}
+
+// get returns a copy of the cached match of key. On a miss it returns the generation to put the match with.
+func (s *CachedPartnerService) get(key string) ([]entities.Partner, uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	element, ok := s.entries[key]
+	if ok && s.now().Before(element.Value.(*entry).expiresAt) {
+		s.stats.Hits++
+		s.lru.MoveToFront(element)
+		return clone(element.Value.(*entry).partners), 0, true
+	}
+	if ok {
+		s.remove(element)
+	}
+	s.stats.Misses++
+	return nil, s.generation, false
+}
+
+// put caches the match unless the cache was invalidated since the match started.
+func (s *CachedPartnerService) put(key string, partners []entities.Partner, generation uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if generation != s.generation || s.cfg.Size < 1 {
+		return
+	}
+	if element, ok := s.entries[key]; ok {
+		s.remove(element)
+	}
+	s.entries[key] = s.lru.PushFront(&entry{key: key, partners: partners, expiresAt: s.now().Add(s.cfg.TTL)})
+	for s.lru.Len() > s.cfg.Size {
+		s.remove(s.lru.Back())
+		s.stats.Evictions++
+	}
+}
+
+func (s *CachedPartnerService) remove(element *list.Element) {
+	s.lru.Remove(element)
+	delete(s.entries, element.Value.(*entry).key)
+}
+
+// clone copies the slice of partners, so that callers cannot reorder or replace the cached partners.
+func clone(partners []entities.Partner) []entities.Partner {
+	return append(make([]entities.Partner, 0, len(partners)), partners...)
+}
+
+// Invalidate clears the cache.
+func (s *CachedPartnerService) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = map[string]*list.Element{}
+	s.lru.Init()
+	s.generation++
+	s.stats.Invalidations++
+}
+
+// Stats returns the counts of the cache.
+func (s *CachedPartnerService) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	stats.Entries = s.lru.Len()
+	return stats
+}
+
+// Publish clears the cache on events about partners. It lets the cache follow changes which are not made through the
+// service, e.g. by other instances. The relay publishes an event again while any of its publishers fails, so events
+// already seen are skipped instead of clearing the cache on every retry.
+func (s *CachedPartnerService) Publish(_ context.Context, event entities.Event) error {
+	switch event.Type {
+	case entities.EventPartnerCreated, entities.EventPartnerUpdated, entities.EventPartnerDeleted:
+		if s.see(event.ID) {
+			s.Invalidate()
+		}
+	}
+	return nil
+}
+
+// see remembers the id of a published event and reports whether it is seen for the first time.
+func (s *CachedPartnerService) see(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[id] {
+		return false
+	}
+	if len(s.recent) == seenEvents {
+		delete(s.seen, s.recent[0])
+		s.recent = s.recent[1:]
+	}
+	s.seen[id] = true
+	s.recent = append(s.recent, id)
+	return true
+}
+
+func (s *CachedPartnerService) GetPartner(ctx context.Context, id string) (entities.Partner, error) {
+	return s.next.GetPartner(ctx, id)
+}
+
+func (s *CachedPartnerService) CreatePartner(ctx context.Context, partner entities.Partner) (entities.Partner, error) {
+	created, err := s.next.CreatePartner(ctx, partner)
+	if err == nil {
+		s.Invalidate()
+	}
+	return created, err
+}
+
+func (s *CachedPartnerService) UpdatePartner(ctx context.Context, partner entities.Partner) (entities.Partner, error) {
+	updated, err := s.next.UpdatePartner(ctx, partner)
+	if err == nil {
+		s.Invalidate()
+	}
+	return updated, err
+}
+
+func (s *CachedPartnerService) SuspendPartner(ctx context.Context, id, reason string) (entities.Partner, error) {
+	suspended, err := s.next.SuspendPartner(ctx, id, reason)
+	if err == nil {
+		s.Invalidate()
+	}
+	return suspended, err
+}
+
+func (s *CachedPartnerService) ReinstatePartner(ctx context.Context, id string) (entities.Partner, error) {
+	reinstated, err := s.next.ReinstatePartner(ctx, id)
+	if err == nil {
+		s.Invalidate()
+	}
+	return reinstated, err
+}
+
+func (s *CachedPartnerService) DeletePartner(ctx context.Context, id, reason string) error {
+	err := s.next.DeletePartner(ctx, id, reason)
+	if err == nil {
+		s.Invalidate()
+	}
+	return err
+}
+
+func (s *CachedPartnerService) GetAllPartners(ctx context.Context) ([]entities.Partner, error) {
+	return s.next.GetAllPartners(ctx)
+}
+
+// ImportPartners clears the cache after imports which stored partners. Imports which fail halfway may have stored
+// some, so the cache is cleared on errors as well.
+func (s *CachedPartnerService) ImportPartners(
+	ctx context.Context,
+	rows []domain.ImportRow,
+	dryRun bool,
+) (domain.ImportResult, error) {
+	result, err := s.next.ImportPartners(ctx, rows, dryRun)
+	if !dryRun && (err != nil || result.Created > 0 || result.Updated > 0) {
+		s.Invalidate()
+	}
+	return result, err
+}
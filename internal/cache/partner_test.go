@@ -0,0 +1,285 @@
+package cache_test
+
+import (
+	"context"
+	"customer-partner/internal/cache"
+	"customer-partner/internal/db"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/events"
+	"customer-partner/internal/logging"
+	"customer-partner/internal/web/mocks"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Customers in Munich whose locations share the geohash cell u281z9 of precision 6.
+var (
+	customer  = domain.GetPartnersOpts{Material: "wood", CustomerAddressLat: 48.1372, CustomerAddressLong: 11.5756}
+	neighbour = domain.GetPartnersOpts{Material: "wood", CustomerAddressLat: 48.1390, CustomerAddressLong: 11.5770}
+)
+
+var matched = []entities.Partner{{ID: "1"}, {ID: "2"}}
+
+func newService(t *testing.T, cfg cache.Config) (*cache.CachedPartnerService, *mocks.PartnerService) {
+	t.Helper()
+	next := &mocks.PartnerService{}
+	return cache.NewCachedPartnerService(next, cfg), next
+}
+
+func TestCachedPartnerService_GetPartners(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Caches match of cell", func(t *testing.T) {
+		service, next := newService(t, cache.DefaultConfig)
+		var asked domain.GetPartnersOpts
+		next.On("GetPartners", ctx, mock.Anything).
+			Run(func(args mock.Arguments) { asked = args.Get(1).(domain.GetPartnersOpts) }).
+			Return(matched, nil).Once()
+
+		first, err := service.GetPartners(ctx, customer)
+		require.NoError(t, err)
+		first[0] = entities.Partner{ID: "changed"}
+		second, err := service.GetPartners(ctx, neighbour)
+		require.NoError(t, err)
+
+		assert.Equal(t, matched, second, "cached match must not be changed by callers")
+		next.AssertExpectations(t)
+		assert.InDelta(t, 48.1372, asked.CustomerAddressLat, 0.006)
+		assert.InDelta(t, 11.5756, asked.CustomerAddressLong, 0.006)
+		assert.NotEqual(t, customer.CustomerAddressLat, asked.CustomerAddressLat, "match must run for centre of cell")
+		assert.Equal(t, cache.Stats{Hits: 1, Misses: 1, Entries: 1}, service.Stats())
+	})
+
+	t.Run("Misses on other options", func(t *testing.T) {
+		service, next := newService(t, cache.DefaultConfig)
+		next.On("GetPartners", ctx, mock.Anything).Return(matched, nil).Times(4)
+		tiles := customer
+		tiles.Material = "tiles"
+		byPrice := customer
+		byPrice.FloorSize, byPrice.SortBy = 40, domain.SortByPrice
+		elsewhere := customer
+		elsewhere.CustomerAddressLat = 48.2
+
+		for _, opts := range []domain.GetPartnersOpts{customer, tiles, byPrice, elsewhere} {
+			_, err := service.GetPartners(ctx, opts)
+			require.NoError(t, err)
+		}
+
+		next.AssertExpectations(t)
+		assert.Equal(t, uint64(4), service.Stats().Misses)
+	})
+
+	t.Run("Expires matches after ttl", func(t *testing.T) {
+		now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+		service, next := newService(t, cache.DefaultConfig)
+		service.WithClock(func() time.Time { return now })
+		next.On("GetPartners", ctx, mock.Anything).Return(matched, nil).Twice()
+
+		_, err := service.GetPartners(ctx, customer)
+		require.NoError(t, err)
+		now = now.Add(cache.DefaultConfig.TTL)
+		_, err = service.GetPartners(ctx, customer)
+		require.NoError(t, err)
+
+		next.AssertExpectations(t)
+		assert.Equal(t, cache.Stats{Misses: 2, Entries: 1}, service.Stats())
+	})
+
+	t.Run("Evicts least recently used match", func(t *testing.T) {
+		cfg := cache.DefaultConfig
+		cfg.Size = 2
+		service, next := newService(t, cfg)
+		next.On("GetPartners", ctx, mock.Anything).Return(matched, nil)
+		carpet, tiles := customer, customer
+		carpet.Material, tiles.Material = "carpet", "tiles"
+
+		for _, opts := range []domain.GetPartnersOpts{customer, carpet, customer, tiles, customer, carpet} {
+			_, err := service.GetPartners(ctx, opts)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, cache.Stats{Hits: 2, Misses: 4, Evictions: 2, Entries: 2}, service.Stats())
+	})
+
+	t.Run("Does not cache errors", func(t *testing.T) {
+		service, next := newService(t, cache.DefaultConfig)
+		nextErr := errors.New("unavailable")
+		next.On("GetPartners", ctx, mock.Anything).Return(nil, nextErr).Twice()
+
+		_, err := service.GetPartners(ctx, customer)
+		assert.ErrorIs(t, err, nextErr)
+		_, err = service.GetPartners(ctx, customer)
+		assert.ErrorIs(t, err, nextErr)
+
+		next.AssertExpectations(t)
+		assert.Zero(t, service.Stats().Entries)
+	})
+
+	t.Run("Does not cache match started before invalidation", func(t *testing.T) {
+		service, next := newService(t, cache.DefaultConfig)
+		next.On("GetPartners", ctx, mock.Anything).
+			Run(func(mock.Arguments) { service.Invalidate() }).
+			Return(matched, nil).Once()
+
+		_, err := service.GetPartners(ctx, customer)
+
+		require.NoError(t, err)
+		assert.Zero(t, service.Stats().Entries)
+	})
+}
+
+func TestCachedPartnerService_Invalidation(t *testing.T) {
+	ctx := context.Background()
+	partner := entities.Partner{ID: "1"}
+	type testCase struct {
+		name          string
+		setup         func(next *mocks.PartnerService)
+		change        func(service *cache.CachedPartnerService) error
+		expInvalidate bool
+	}
+	tests := []testCase{
+		{
+			name:          "Invalidates on created partner",
+			setup:         func(next *mocks.PartnerService) { next.On("CreatePartner", ctx, partner).Return(partner, nil) },
+			change:        func(s *cache.CachedPartnerService) error { _, err := s.CreatePartner(ctx, partner); return err },
+			expInvalidate: true,
+		},
+		{
+			name:          "Invalidates on updated partner",
+			setup:         func(next *mocks.PartnerService) { next.On("UpdatePartner", ctx, partner).Return(partner, nil) },
+			change:        func(s *cache.CachedPartnerService) error { _, err := s.UpdatePartner(ctx, partner); return err },
+			expInvalidate: true,
+		},
+		{
+			name: "Keeps matches when update fails",
+			setup: func(next *mocks.PartnerService) {
+				next.On("UpdatePartner", ctx, partner).Return(entities.Partner{}, entities.ErrRecordNotExist)
+			},
+			change: func(s *cache.CachedPartnerService) error { _, err := s.UpdatePartner(ctx, partner); return err },
+		},
+		{
+			name: "Invalidates on suspended partner",
+			setup: func(next *mocks.PartnerService) {
+				next.On("SuspendPartner", ctx, "1", "Complaints").Return(partner, nil)
+			},
+			change: func(s *cache.CachedPartnerService) error {
+				_, err := s.SuspendPartner(ctx, "1", "Complaints")
+				return err
+			},
+			expInvalidate: true,
+		},
+		{
+			name:          "Invalidates on reinstated partner",
+			setup:         func(next *mocks.PartnerService) { next.On("ReinstatePartner", ctx, "1").Return(partner, nil) },
+			change:        func(s *cache.CachedPartnerService) error { _, err := s.ReinstatePartner(ctx, "1"); return err },
+			expInvalidate: true,
+		},
+		{
+			name:          "Invalidates on deleted partner",
+			setup:         func(next *mocks.PartnerService) { next.On("DeletePartner", ctx, "1", "Closed").Return(nil) },
+			change:        func(s *cache.CachedPartnerService) error { return s.DeletePartner(ctx, "1", "Closed") },
+			expInvalidate: true,
+		},
+		{
+			name: "Invalidates on import",
+			setup: func(next *mocks.PartnerService) {
+				next.On("ImportPartners", ctx, mock.Anything, false).Return(domain.ImportResult{Updated: 1}, nil)
+			},
+			change: func(s *cache.CachedPartnerService) error {
+				_, err := s.ImportPartners(ctx, nil, false)
+				return err
+			},
+			expInvalidate: true,
+		},
+		{
+			name: "Keeps matches on dry run import",
+			setup: func(next *mocks.PartnerService) {
+				next.On("ImportPartners", ctx, mock.Anything, true).Return(domain.ImportResult{DryRun: true, Updated: 1}, nil)
+			},
+			change: func(s *cache.CachedPartnerService) error {
+				_, err := s.ImportPartners(ctx, nil, true)
+				return err
+			},
+		},
+		{
+			name: "Invalidates on partner event",
+			change: func(s *cache.CachedPartnerService) error {
+				return s.Publish(ctx, entities.Event{ID: "e1", Type: entities.EventPartnerUpdated, AggregateID: "1"})
+			},
+			expInvalidate: true,
+		},
+		{
+			name: "Invalidates once on partner event published again",
+			change: func(s *cache.CachedPartnerService) error {
+				event := entities.Event{ID: "e1", Type: entities.EventPartnerUpdated, AggregateID: "1"}
+				for i := 0; i < 3; i++ {
+					if err := s.Publish(ctx, event); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			expInvalidate: true,
+		},
+		{
+			name: "Keeps matches on other events",
+			change: func(s *cache.CachedPartnerService) error {
+				return s.Publish(ctx, entities.Event{ID: "e2", Type: entities.EventOfferRequested, AggregateID: "2"})
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service, next := newService(t, cache.DefaultConfig)
+			next.On("GetPartners", ctx, mock.Anything).Return(matched, nil)
+			if tt.setup != nil {
+				tt.setup(next)
+			}
+			_, err := service.GetPartners(ctx, customer)
+			require.NoError(t, err)
+
+			_ = tt.change(service)
+			_, err = service.GetPartners(ctx, customer)
+			require.NoError(t, err)
+
+			stats := service.Stats()
+			if tt.expInvalidate {
+				assert.Equal(t, cache.Stats{Misses: 2, Invalidations: 1, Entries: 1}, stats)
+			} else {
+				assert.Equal(t, cache.Stats{Hits: 1, Misses: 1, Entries: 1}, stats)
+			}
+		})
+	}
+}
+
+// failingPublisher stands for a publisher after the cache which is down.
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(context.Context, entities.Event) error {
+	return errors.New("sink unavailable")
+}
+
+func TestCachedPartnerService_Publish_Redelivery(t *testing.T) {
+	ctx := context.Background()
+	outbox := db.NewOutboxInMemoryRepository()
+	event := entities.Event{ID: "e1", Type: entities.EventPartnerUpdated, AggregateID: "1", Data: []byte(`{}`)}
+	require.NoError(t, db.NewPartnerInMemoryRepository(outbox).CreatePartner(ctx, entities.Partner{ID: "1"}, event))
+	service, next := newService(t, cache.DefaultConfig)
+	next.On("GetPartners", ctx, mock.Anything).Return(matched, nil)
+	relay := events.NewRelay(outbox, events.Publishers{service, failingPublisher{}}, logging.Discard())
+
+	for i := 0; i < 3; i++ {
+		assert.Error(t, relay.Run(ctx), "event is published again on every run")
+		_, err := service.GetPartners(ctx, customer)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, cache.Stats{Hits: 2, Misses: 1, Invalidations: 1, Entries: 1}, service.Stats())
+}
@@ -0,0 +1,370 @@
+package contract_test
+
+import (
+	"bytes"
+	"context"
+	"customer-partner/internal/audit"
+	"customer-partner/internal/auth"
+	"customer-partner/internal/auth/authtest"
+	"customer-partner/internal/db"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"customer-partner/internal/privacy"
+	"customer-partner/internal/web"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const specPath = "../../openapi.yml"
+
+// exampleOfferRequestID is the id of the offer request used as example in the specification.
+const exampleOfferRequestID = "7d4f0a9b2c6e1f38a5b0c4d2e9f1a6b3"
+
+// exampleLeadID is the id of the lead used as example in the specification.
+const exampleLeadID = "3f6a9c2e5b8d1f4a7c0e3b6d9f2a5c8e"
+
+// exampleQuoteID is the id of the pending quote of the example lead used as example in the specification.
+const exampleQuoteID = "c2e9a5f1b7d3c8e4a0f6b2d9e5a1c7f3"
+
+// exampleWebhookID is the id of the webhook subscription used as example in the specification.
+const exampleWebhookID = "0c9e2b7a4f1d6e3a8b5c2d9f0e7a4b1c"
+
+// newServer starts the api with the real services and the in-memory repositories holding the demo data, the example
+// offer request, the example lead with a quote and the example webhook with a delivery. Requests to secured operations
+// are authenticated with authtest.AdminAPIKey.
+func newServer(t *testing.T) *httptest.Server {
+	partnerChanges := db.NewPartnerChangeInMemoryRepository()
+	repo := audit.NewAuditedPartnerRepository(db.NewPartnerInMemoryRepository(nil), partnerChanges)
+	service := domain.NewPartnerService(repo, logging.Discard())
+	cipher, err := privacy.NewRandomCipher()
+	require.NoError(t, err)
+	offerRequestRepo := db.NewOfferRequestInMemoryRepository(cipher, nil)
+	require.NoError(t, offerRequestRepo.CreateOfferRequest(context.Background(), entities.OfferRequest{
+		ID:        exampleOfferRequestID,
+		PartnerID: "1",
+		FloorSize: 42.5,
+		Phone:     "+49 170 1234567",
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}))
+	require.NoError(t, offerRequestRepo.CreateOfferRequests(context.Background(), []entities.OfferRequest{{
+		ID:        "a1c4e7b0d3f6a9c2e5b8d1f4a7c0e3b6",
+		PartnerID: "1",
+		FloorSize: 42.5,
+		Phone:     "+49 170 1234567",
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		LeadID:    exampleLeadID,
+	}}))
+	require.NoError(t, offerRequestRepo.CreateQuote(context.Background(), entities.Quote{
+		ID:             exampleQuoteID,
+		OfferRequestID: "a1c4e7b0d3f6a9c2e5b8d1f4a7c0e3b6",
+		PartnerID:      "1",
+		Currency:       "EUR",
+		LineItems: []entities.QuoteLineItem{{
+			Kind:        entities.LineItemLabour,
+			Description: "Laying",
+			Quantity:    16,
+			Unit:        "h",
+			UnitPrice:   5500,
+			Amount:      88000,
+		}},
+		VATRate:     1900,
+		NetAmount:   88000,
+		VATAmount:   16720,
+		TotalAmount: 104720,
+		ValidUntil:  time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second),
+		Status:      entities.QuotePending,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	}))
+	webhookRepo := db.NewWebhookInMemoryRepository()
+	require.NoError(t, webhookRepo.CreateWebhookSubscription(context.Background(), entities.WebhookSubscription{
+		ID:         exampleWebhookID,
+		PartnerID:  "1",
+		URL:        "https://example.com/webhooks",
+		Secret:     "whsec_example",
+		EventTypes: []string{entities.EventOfferRequested},
+	}))
+	require.NoError(t, webhookRepo.CreateWebhookDelivery(context.Background(), entities.WebhookDelivery{
+		ID:             "5b8e1f4a7c2d9e6b3a0f5c8d1e4b7a2f",
+		SubscriptionID: exampleWebhookID,
+		PartnerID:      "1",
+		EventID:        "e3a7c1f9b5d2e8a4c6f0b3d7e1a9c5f2",
+		EventType:      entities.EventOfferRequested,
+		Status:         entities.WebhookDeliveryDelivered,
+		Attempts:       []entities.WebhookAttempt{{At: time.Now().UTC(), StatusCode: http.StatusOK}},
+		CreatedAt:      time.Now().UTC(),
+	}))
+	api := web.NewPartnerAPI(
+		web.Services{
+			Partners:      service,
+			OfferRequests: domain.NewOfferRequestService(offerRequestRepo, repo, time.Hour, logging.Discard()),
+			DataRequests: domain.NewDataRequestService(
+				offerRequestRepo,
+				repo,
+				partnerChanges,
+				webhookRepo,
+				db.NewOutboxInMemoryRepository(),
+				db.NewDataRequestInMemoryRepository(),
+				logging.Discard(),
+			),
+			History:  domain.NewPartnerHistoryService(repo, partnerChanges),
+			Webhooks: domain.NewWebhookService(webhookRepo, repo, false, logging.Discard()),
+			Leads:    domain.NewLeadService(offerRequestRepo, service, 3, logging.Discard()),
+			Quotes:   domain.NewQuoteService(offerRequestRepo, offerRequestRepo, logging.Discard()),
+		},
+		authtest.NewKeySet(t).Authenticator(t),
+		privacy.NewObfuscator(nil, privacy.DefaultDecimals),
+		nil,
+		logging.Discard(),
+	)
+	server := httptest.NewServer(api.Handler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+// contractRequest describes a request generated from an operation of the specification.
+type contractRequest struct {
+	method     string
+	path       string
+	pathParams map[string]string
+	query      url.Values
+	header     http.Header
+	body       any
+}
+
+func (c contractRequest) do(t *testing.T, baseURL string) *http.Response {
+	path := c.path
+	for name, value := range c.pathParams {
+		path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(value))
+	}
+	target := baseURL + path
+	if len(c.query) > 0 {
+		target += "?" + c.query.Encode()
+	}
+	var body io.Reader
+	if c.body != nil {
+		raw, err := json.Marshal(c.body)
+		require.NoError(t, err)
+		body = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(c.method, target, body)
+	require.NoError(t, err)
+	for name, values := range c.header {
+		req.Header[name] = values
+	}
+	if c.body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func (c contractRequest) clone() contractRequest {
+	clone := c
+	clone.pathParams = map[string]string{}
+	for k, v := range c.pathParams {
+		clone.pathParams[k] = v
+	}
+	clone.query = url.Values{}
+	for k, v := range c.query {
+		clone.query[k] = append([]string(nil), v...)
+	}
+	clone.header = c.header.Clone()
+	if obj, ok := c.body.(map[string]any); ok {
+		body := map[string]any{}
+		for k, v := range obj {
+			body[k] = v
+		}
+		clone.body = body
+	}
+	return clone
+}
+
+// validRequest builds a request for the operation which only contains valid parameters and body.
+func validRequest(spec *specification, method, path string, op *operation) contractRequest {
+	req := contractRequest{
+		method:     strings.ToUpper(method),
+		path:       path,
+		pathParams: map[string]string{},
+		query:      url.Values{},
+		header:     http.Header{},
+	}
+	if len(op.Security) > 0 {
+		req.header.Set(auth.APIKeyHeader, authtest.AdminAPIKey)
+	}
+	for _, param := range op.Parameters {
+		value := param.Example
+		if value == nil {
+			value = spec.validValue(param.Schema)
+		}
+		switch param.In {
+		case "path":
+			req.pathParams[param.Name] = fmt.Sprint(value)
+		case "query":
+			req.query.Set(param.Name, fmt.Sprint(value))
+		}
+	}
+	if op.RequestBody != nil {
+		if media, ok := op.RequestBody.Content["application/json"]; ok {
+			req.body = spec.validValue(media.Schema)
+		}
+	}
+	return req
+}
+
+// successStatus returns the lowest documented 2xx status code of the operation.
+func successStatus(t *testing.T, op *operation) int {
+	var codes []int
+	for code := range op.Responses {
+		status, err := strconv.Atoi(code)
+		require.NoError(t, err)
+		if status >= 200 && status < 300 {
+			codes = append(codes, status)
+		}
+	}
+	require.NotEmpty(t, codes, "operation documents no success response")
+	sort.Ints(codes)
+	return codes[0]
+}
+
+func assertDocumentedResponse(t *testing.T, spec *specification, op *operation, resp *http.Response, expStatus int) {
+	t.Helper()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, expStatus, resp.StatusCode, "body: %s", body)
+	documented, ok := spec.response(op, expStatus)
+	require.True(t, ok, "status %d is not documented", expStatus)
+	media, ok := documented.Content["application/json"]
+	if !ok {
+		return
+	}
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	var decoded any
+	require.NoError(t, json.Unmarshal(body, &decoded), "body: %s", body)
+	assert.NoError(t, spec.validate(media.Schema, decoded, "$"))
+}
+
+func assertStatusDocumented(t *testing.T, op *operation, status int) {
+	t.Helper()
+	_, ok := op.Responses[strconv.Itoa(status)]
+	require.True(t, ok, "operation accepts invalid input but does not document status %d", status)
+}
+
+func TestAPIConformsToSpecification(t *testing.T) {
+	spec, err := loadSpecification(specPath)
+	require.NoError(t, err)
+	require.NotEmpty(t, spec.Paths)
+	server := newServer(t)
+
+	for _, path := range sortedKeys(spec.Paths) {
+		for _, method := range sortedKeys(spec.Paths[path]) {
+			op := spec.Paths[path][method]
+			name := fmt.Sprintf("%s %s", strings.ToUpper(method), path)
+			t.Run(name, func(t *testing.T) {
+				valid := validRequest(spec, method, path, op)
+
+				if op.NotImplemented {
+					resp := valid.do(t, server.URL)
+					assert.Contains(t,
+						[]int{http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented},
+						resp.StatusCode,
+						"operation is marked as not implemented but served",
+					)
+					return
+				}
+
+				t.Run("valid request", func(t *testing.T) {
+					resp := valid.do(t, server.URL)
+					assertDocumentedResponse(t, spec, op, resp, successStatus(t, op))
+				})
+
+				if len(op.Security) > 0 {
+					t.Run("missing credentials", func(t *testing.T) {
+						assertStatusDocumented(t, op, http.StatusUnauthorized)
+						req := valid.clone()
+						req.header.Del(auth.APIKeyHeader)
+						assertDocumentedResponse(t, spec, op, req.do(t, server.URL), http.StatusUnauthorized)
+					})
+				}
+
+				for _, param := range op.Parameters {
+					param := param
+					if param.In == "query" && param.Required {
+						t.Run(fmt.Sprintf("missing query parameter %s", param.Name), func(t *testing.T) {
+							assertStatusDocumented(t, op, http.StatusBadRequest)
+							req := valid.clone()
+							req.query.Del(param.Name)
+							assertDocumentedResponse(t, spec, op, req.do(t, server.URL), http.StatusBadRequest)
+						})
+					}
+					for violation, value := range spec.invalidValues(param.Schema) {
+						value := value
+						t.Run(fmt.Sprintf("%s parameter %s with %s", param.In, param.Name, violation), func(t *testing.T) {
+							assertStatusDocumented(t, op, http.StatusBadRequest)
+							req := valid.clone()
+							switch param.In {
+							case "query":
+								req.query.Set(param.Name, fmt.Sprint(value))
+							case "path":
+								req.pathParams[param.Name] = fmt.Sprint(value)
+							}
+							assertDocumentedResponse(t, spec, op, req.do(t, server.URL), http.StatusBadRequest)
+						})
+					}
+					if param.In == "path" {
+						if _, ok := op.Responses[strconv.Itoa(http.StatusNotFound)]; ok {
+							t.Run(fmt.Sprintf("unknown path parameter %s", param.Name), func(t *testing.T) {
+								req := valid.clone()
+								req.pathParams[param.Name] = "does-not-exist"
+								assertDocumentedResponse(t, spec, op, req.do(t, server.URL), http.StatusNotFound)
+							})
+						}
+					}
+				}
+
+				if op.RequestBody == nil {
+					return
+				}
+				media, ok := op.RequestBody.Content["application/json"]
+				if !ok {
+					return
+				}
+				bodySchema := spec.resolve(media.Schema)
+				for _, property := range bodySchema.Required {
+					property := property
+					t.Run(fmt.Sprintf("missing body property %s", property), func(t *testing.T) {
+						assertStatusDocumented(t, op, http.StatusBadRequest)
+						req := valid.clone()
+						delete(req.body.(map[string]any), property)
+						assertDocumentedResponse(t, spec, op, req.do(t, server.URL), http.StatusBadRequest)
+					})
+				}
+				for _, property := range sortedKeys(bodySchema.Properties) {
+					for violation, value := range spec.invalidValues(bodySchema.Properties[property]) {
+						property, value := property, value
+						t.Run(fmt.Sprintf("body property %s with %s", property, violation), func(t *testing.T) {
+							assertStatusDocumented(t, op, http.StatusBadRequest)
+							req := valid.clone()
+							req.body.(map[string]any)[property] = value
+							assertDocumentedResponse(t, spec, op, req.do(t, server.URL), http.StatusBadRequest)
+						})
+					}
+				}
+			})
+		}
+	}
+}
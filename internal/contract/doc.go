@@ -0,0 +1,6 @@
+// Package contract verifies the running api against the OpenAPI specification in openapi.yml.
+//
+// The package only contains tests. They start the api with the real domain service and in-memory repository, walk
+// every operation of the specification, send valid and invalid requests generated from the schemas and check the
+// returned status codes and response bodies. Operations marked with `x-not-implemented: true` must not be served.
+package contract
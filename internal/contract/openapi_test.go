@@ -0,0 +1,235 @@
+package contract_test
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// specification is the subset of an OpenAPI 3.1 document the contract tests understand.
+type specification struct {
+	Paths      map[string]map[string]*operation `yaml:"paths"`
+	Components struct {
+		Schemas   map[string]*schema   `yaml:"schemas"`
+		Responses map[string]*response `yaml:"responses"`
+	} `yaml:"components"`
+}
+
+type operation struct {
+	Parameters     []parameter           `yaml:"parameters"`
+	RequestBody    *requestBody          `yaml:"requestBody"`
+	Responses      map[string]*response  `yaml:"responses"`
+	Security       []map[string][]string `yaml:"security"`
+	NotImplemented bool                  `yaml:"x-not-implemented"`
+}
+
+type parameter struct {
+	In       string  `yaml:"in"`
+	Name     string  `yaml:"name"`
+	Required bool    `yaml:"required"`
+	Example  any     `yaml:"example"`
+	Schema   *schema `yaml:"schema"`
+}
+
+type requestBody struct {
+	Content map[string]*mediaType `yaml:"content"`
+}
+
+type response struct {
+	Ref     string                `yaml:"$ref"`
+	Content map[string]*mediaType `yaml:"content"`
+}
+
+type mediaType struct {
+	Schema *schema `yaml:"schema"`
+}
+
+type schema struct {
+	Ref        string             `yaml:"$ref"`
+	Type       string             `yaml:"type"`
+	Enum       []any              `yaml:"enum"`
+	Minimum    *float64           `yaml:"minimum"`
+	Maximum    *float64           `yaml:"maximum"`
+	Required   []string           `yaml:"required"`
+	Properties map[string]*schema `yaml:"properties"`
+	Items      *schema            `yaml:"items"`
+	Example    any                `yaml:"example"`
+}
+
+func loadSpecification(path string) (*specification, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var spec specification
+	if err := yaml.Unmarshal(raw, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// resolve follows $ref pointers to the component schemas.
+func (s *specification) resolve(sch *schema) *schema {
+	for sch != nil && sch.Ref != "" {
+		name := strings.TrimPrefix(sch.Ref, "#/components/schemas/")
+		sch = s.Components.Schemas[name]
+	}
+	return sch
+}
+
+// response returns the documented response of the operation for the status code, following $ref pointers to the
+// component responses.
+func (s *specification) response(op *operation, status int) (*response, bool) {
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	for ok && resp.Ref != "" {
+		resp, ok = s.Components.Responses[strings.TrimPrefix(resp.Ref, "#/components/responses/")]
+	}
+	return resp, ok
+}
+
+// validValue generates a value which satisfies the schema. Examples are preferred over generated values.
+func (s *specification) validValue(sch *schema) any {
+	sch = s.resolve(sch)
+	if sch.Example != nil {
+		return sch.Example
+	}
+	if len(sch.Enum) > 0 {
+		return sch.Enum[0]
+	}
+	switch sch.Type {
+	case "object":
+		obj := map[string]any{}
+		for name, prop := range sch.Properties {
+			obj[name] = s.validValue(prop)
+		}
+		return obj
+	case "array":
+		return []any{s.validValue(sch.Items)}
+	case "number", "integer":
+		lo, hi := 0.0, 100.0
+		if sch.Minimum != nil {
+			lo = *sch.Minimum
+		}
+		if sch.Maximum != nil {
+			hi = *sch.Maximum
+		} else if sch.Minimum != nil {
+			hi = lo + 100
+		}
+		return math.Floor((lo + hi) / 2)
+	case "boolean":
+		return true
+	default:
+		return "value"
+	}
+}
+
+// invalidValues generates values violating the schema, keyed by a description of the violation.
+func (s *specification) invalidValues(sch *schema) map[string]any {
+	sch = s.resolve(sch)
+	invalid := map[string]any{}
+	if len(sch.Enum) > 0 {
+		invalid["value outside of enum"] = "not-in-enum"
+	}
+	switch sch.Type {
+	case "number", "integer":
+		invalid["value of wrong type"] = "not-a-number"
+		if sch.Minimum != nil {
+			invalid["value below minimum"] = *sch.Minimum - 1
+		}
+		if sch.Maximum != nil {
+			invalid["value above maximum"] = *sch.Maximum + 1
+		}
+	case "object":
+		invalid["value of wrong type"] = "not-an-object"
+	}
+	return invalid
+}
+
+// validate checks that the decoded json value conforms to the schema. Properties which are not part of the schema are
+// reported as well, since they are undocumented parts of the api.
+func (s *specification) validate(sch *schema, value any, path string) error {
+	sch = s.resolve(sch)
+	if sch == nil {
+		return nil
+	}
+	if len(sch.Enum) > 0 && !containsValue(sch.Enum, value) {
+		return fmt.Errorf("%s: value %v not in enum %v", path, value, sch.Enum)
+	}
+	switch sch.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected object, got %T", path, value)
+		}
+		for _, name := range sch.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: required property %q missing", path, name)
+			}
+		}
+		for _, name := range sortedKeys(obj) {
+			prop, ok := sch.Properties[name]
+			if !ok {
+				return fmt.Errorf("%s: undocumented property %q", path, name)
+			}
+			if err := s.validate(prop, obj[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected array, got %T", path, value)
+		}
+		for i, item := range arr {
+			if err := s.validate(sch.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "number", "integer":
+		num, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("%s: expected %s, got %T", path, sch.Type, value)
+		}
+		if sch.Type == "integer" && num != math.Trunc(num) {
+			return fmt.Errorf("%s: expected integer, got %v", path, num)
+		}
+		if sch.Minimum != nil && num < *sch.Minimum {
+			return fmt.Errorf("%s: %v below minimum %v", path, num, *sch.Minimum)
+		}
+		if sch.Maximum != nil && num > *sch.Maximum {
+			return fmt.Errorf("%s: %v above maximum %v", path, num, *sch.Maximum)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: expected string, got %T", path, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean, got %T", path, value)
+		}
+	}
+	return nil
+}
+
+func containsValue(list []any, value any) bool {
+	for _, v := range list {
+		if fmt.Sprint(v) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"sync"
+)
+
+func NewDataRequestInMemoryRepository() *DataRequestInMemoryRepository {
+	return &DataRequestInMemoryRepository{}
+}
+
+// DataRequestInMemoryRepository saves the audit records of data subject requests in memory. Records can only be
+// added, never changed.
+type DataRequestInMemoryRepository struct {
+	mu       sync.RWMutex
+	requests []entities.DataRequest
+}
+
+// CreateDataRequest appends an audit record.
+func (r *DataRequestInMemoryRepository) CreateDataRequest(ctx context.Context, request entities.DataRequest) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, request)
+	return nil
+}
+
+// GetDataRequests returns all audit records, oldest first.
+func (r *DataRequestInMemoryRepository) GetDataRequests(ctx context.Context) ([]entities.DataRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]entities.DataRequest{}, r.requests...), nil
+}
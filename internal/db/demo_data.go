@@ -1,6 +1,12 @@
 package db
 
-import "customer-partner/internal/entities"
+import (
+	"customer-partner/internal/entities"
+	"time"
+)
+
+// demoInsuranceExpiry is far ahead, so that the demo partners stay vetted.
+var demoInsuranceExpiry = time.Date(2099, 12, 31, 0, 0, 0, 0, time.UTC)
 
 var demoData = []entities.Partner{
 	{
@@ -10,9 +16,29 @@ var demoData = []entities.Partner{
 		Address: entities.Address{
 			Latitude:  48.1360,
 			Longitude: 11.6875,
+			City:      "München",
+			District:  "Trudering-Riem",
+		},
+		OperatingRadius:    100,
+		Rating:             3,
+		VerificationStatus: entities.VerificationVerified,
+		InsuranceExpiresAt: &demoInsuranceExpiry,
+		PriceLists: []entities.PriceList{
+			{
+				Material:             "wood",
+				MinPerSquareMeter:    3500,
+				MaxPerSquareMeter:    5500,
+				MinimumCharge:        50000,
+				TravelSurchargePerKm: 80,
+				FreeTravelDistance:   20,
+			},
+			{
+				Material:          "carpet",
+				MinPerSquareMeter: 1500,
+				MaxPerSquareMeter: 2500,
+				MinimumCharge:     30000,
+			},
 		},
-		OperatingRadius: 100,
-		Rating:          3,
 	},
 	{
 		ID:                  "2",
@@ -21,9 +47,23 @@ var demoData = []entities.Partner{
 		Address: entities.Address{
 			Latitude:  48.1360,
 			Longitude: 11.6875,
+			City:      "München",
+			District:  "Trudering-Riem",
+		},
+		OperatingRadius:    50,
+		Rating:             4,
+		VerificationStatus: entities.VerificationVerified,
+		InsuranceExpiresAt: &demoInsuranceExpiry,
+		TimeZone:           "Europe/Berlin",
+		OpeningHours: []entities.OpeningHours{
+			{Weekday: "monday", Opens: "07:00", Closes: "16:00"},
+			{Weekday: "tuesday", Opens: "07:00", Closes: "16:00"},
+			{Weekday: "wednesday", Opens: "07:00", Closes: "16:00"},
+			{Weekday: "thursday", Opens: "07:00", Closes: "16:00"},
+			{Weekday: "friday", Opens: "07:00", Closes: "14:00"},
+			{Weekday: "saturday", Opens: "08:00", Closes: "12:00"},
 		},
-		OperatingRadius: 50,
-		Rating:          4,
+		Holidays: []string{"2026-12-24", "2026-12-25", "2026-12-26", "2026-12-31", "2027-01-01"},
 	}, {
 		ID:                  "3",
 		Name:                "Wood-hugger Gmbh",
@@ -31,8 +71,29 @@ var demoData = []entities.Partner{
 		Address: entities.Address{
 			Latitude:  48.1360,
 			Longitude: 11.6875,
+			City:      "München",
+			District:  "Trudering-Riem",
+		},
+		OperatingRadius:    50,
+		Rating:             5,
+		VerificationStatus: entities.VerificationVerified,
+		InsuranceExpiresAt: &demoInsuranceExpiry,
+		Certifications: []entities.Certification{
+			{
+				Type:      "FSC Chain of Custody",
+				Issuer:    "Forest Stewardship Council",
+				ExpiresAt: time.Date(2099, 6, 30, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		PriceLists: []entities.PriceList{
+			{
+				Material:             "wood",
+				MinPerSquareMeter:    4500,
+				MaxPerSquareMeter:    6000,
+				MinimumCharge:        80000,
+				TravelSurchargePerKm: 100,
+				FreeTravelDistance:   10,
+			},
 		},
-		OperatingRadius: 50,
-		Rating:          5,
 	},
 }
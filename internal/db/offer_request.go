@@ -0,0 +1,410 @@
+package db
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/privacy"
+	"sync"
+	"time"
+)
+
+// NewOfferRequestInMemoryRepository creates the repository. The events of writes are added to outbox, which may be
+// nil to drop them.
+func NewOfferRequestInMemoryRepository(
+	cipher *privacy.Cipher,
+	outbox *OutboxInMemoryRepository,
+) *OfferRequestInMemoryRepository {
+	return &OfferRequestInMemoryRepository{cipher: cipher, outbox: outbox}
+}
+
+// OfferRequestInMemoryRepository saves offer requests and their quotes in memory. The contact data is only held
+// encrypted.
+type OfferRequestInMemoryRepository struct {
+	mu       sync.RWMutex
+	cipher   *privacy.Cipher
+	outbox   *OutboxInMemoryRepository
+	requests []storedOfferRequest
+	quotes   []entities.Quote
+}
+
+// storedOfferRequest is an offer request as it is held at rest, with encrypted contact data. The blind indexes allow
+// to find the offer requests of a customer.
+type storedOfferRequest struct {
+	ID              string
+	PartnerID       string
+	FloorSize       float64
+	Phone           []byte
+	PhoneIndex      string
+	Email           []byte
+	EmailIndex      string
+	Language        string
+	CreatedAt       time.Time
+	ContactPurgedAt *time.Time
+	LeadID          string
+	Response        string
+	RespondedAt     *time.Time
+	ResponseRank    int
+	CustomerID      string
+	Status          string
+}
+
+// CreateOfferRequest encrypts the contact data and stores a new offer request together with its events.
+func (r *OfferRequestInMemoryRepository) CreateOfferRequest(
+	ctx context.Context,
+	request entities.OfferRequest,
+	events ...entities.Event,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	stored, err := r.seal(request)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, stored)
+	r.outbox.add(events)
+	return nil
+}
+
+// GetOfferRequestByID returns an offer request by an id.
+// Can return entities.ErrRecordNotExist when offer request with given id does not exist.
+func (r *OfferRequestInMemoryRepository) GetOfferRequestByID(
+	ctx context.Context,
+	id string,
+) (entities.OfferRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.OfferRequest{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, stored := range r.requests {
+		if stored.ID == id {
+			return r.open(stored)
+		}
+	}
+	return entities.OfferRequest{}, entities.ErrRecordNotExist
+}
+
+// GetOfferRequestsByPartner returns the offer requests sent to a partner, oldest first.
+func (r *OfferRequestInMemoryRepository) GetOfferRequestsByPartner(
+	ctx context.Context,
+	partnerID string,
+) ([]entities.OfferRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	requests := []entities.OfferRequest{}
+	for _, stored := range r.requests {
+		if stored.PartnerID != partnerID {
+			continue
+		}
+		request, err := r.open(stored)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+// PurgeContactData removes the encrypted contact data of offer requests created before createdBefore.
+func (r *OfferRequestInMemoryRepository) PurgeContactData(
+	ctx context.Context,
+	createdBefore, purgedAt time.Time,
+) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	purged := 0
+	for i := range r.requests {
+		stored := &r.requests[i]
+		if stored.ContactPurgedAt != nil || !stored.CreatedAt.Before(createdBefore) {
+			continue
+		}
+		stored.purge(purgedAt)
+		purged++
+	}
+	return purged, nil
+}
+
+// GetOfferRequestsByContact returns the offer requests whose phone number or email address matches, oldest first.
+func (r *OfferRequestInMemoryRepository) GetOfferRequestsByContact(
+	ctx context.Context,
+	phone, email string,
+) ([]entities.OfferRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	phoneIndex, emailIndex := r.phoneIndex(phone), r.emailIndex(email)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	requests := []entities.OfferRequest{}
+	for _, stored := range r.requests {
+		phoneMatches := phoneIndex != "" && stored.PhoneIndex == phoneIndex
+		emailMatches := emailIndex != "" && stored.EmailIndex == emailIndex
+		if !phoneMatches && !emailMatches {
+			continue
+		}
+		request, err := r.open(stored)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+// GetOfferRequestsByCustomer returns the offer requests of the customer with the given id, oldest first.
+func (r *OfferRequestInMemoryRepository) GetOfferRequestsByCustomer(
+	ctx context.Context,
+	customerID string,
+) ([]entities.OfferRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	requests := []entities.OfferRequest{}
+	for _, stored := range r.requests {
+		if customerID == "" || stored.CustomerID != customerID {
+			continue
+		}
+		request, err := r.open(stored)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+// AnonymizeOfferRequests removes the encrypted contact data and the customer id of the offer requests with the given
+// ids.
+func (r *OfferRequestInMemoryRepository) AnonymizeOfferRequests(
+	ctx context.Context,
+	ids []string,
+	purgedAt time.Time,
+) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	anonymize := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		anonymize[id] = true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	anonymized := 0
+	for i := range r.requests {
+		if anonymize[r.requests[i].ID] {
+			r.requests[i].purge(purgedAt)
+			r.requests[i].CustomerID = ""
+			anonymized++
+		}
+	}
+	return anonymized, nil
+}
+
+// CreateOfferRequests encrypts the contact data and stores the offer requests of a lead together with their events.
+func (r *OfferRequestInMemoryRepository) CreateOfferRequests(
+	ctx context.Context,
+	requests []entities.OfferRequest,
+	events ...entities.Event,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	stored := make([]storedOfferRequest, 0, len(requests))
+	for _, request := range requests {
+		sealed, err := r.seal(request)
+		if err != nil {
+			return err
+		}
+		stored = append(stored, sealed)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, stored...)
+	r.outbox.add(events)
+	return nil
+}
+
+// GetOfferRequestsByLead returns the offer requests of a lead, oldest first.
+func (r *OfferRequestInMemoryRepository) GetOfferRequestsByLead(
+	ctx context.Context,
+	leadID string,
+) ([]entities.OfferRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	requests := []entities.OfferRequest{}
+	for _, stored := range r.requests {
+		if leadID == "" || stored.LeadID != leadID {
+			continue
+		}
+		request, err := r.open(stored)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+// CountLeadsByPartner counts the offer requests of leads sent to a partner since the given time.
+func (r *OfferRequestInMemoryRepository) CountLeadsByPartner(
+	ctx context.Context,
+	partnerID string,
+	since time.Time,
+) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	count := 0
+	for _, stored := range r.requests {
+		if stored.PartnerID == partnerID && stored.LeadID != "" && !stored.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// RespondToOfferRequest stores the response and ranks it after the responses to the same lead given before.
+// Can return entities.ErrRecordNotExist when offer request with given id does not exist and
+// entities.ErrAlreadyResponded when the partner responded before.
+func (r *OfferRequestInMemoryRepository) RespondToOfferRequest(
+	ctx context.Context,
+	id, response string,
+	respondedAt time.Time,
+	events ...entities.Event,
+) (entities.OfferRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.OfferRequest{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	index := r.indexOf(id)
+	if index < 0 {
+		return entities.OfferRequest{}, entities.ErrRecordNotExist
+	}
+	stored := &r.requests[index]
+	if stored.Response != "" {
+		return entities.OfferRequest{}, entities.ErrAlreadyResponded
+	}
+	rank := 1
+	if stored.LeadID != "" {
+		for _, other := range r.requests {
+			if other.LeadID == stored.LeadID && other.Response != "" {
+				rank++
+			}
+		}
+	}
+	stored.Response = response
+	stored.RespondedAt = &respondedAt
+	stored.ResponseRank = rank
+	r.outbox.add(events)
+	return r.open(*stored)
+}
+
+// indexOf returns the index of the offer request with the given id, -1 when it does not exist. The caller must hold
+// the lock.
+func (r *OfferRequestInMemoryRepository) indexOf(id string) int {
+	for i := range r.requests {
+		if r.requests[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *storedOfferRequest) purge(purgedAt time.Time) {
+	s.Phone, s.PhoneIndex, s.Email, s.EmailIndex = nil, "", nil, ""
+	s.ContactPurgedAt = &purgedAt
+}
+
+func (r *OfferRequestInMemoryRepository) phoneIndex(phone string) string {
+	if phone = privacy.NormalizePhone(phone); phone == "" {
+		return ""
+	}
+	return r.cipher.BlindIndex("phone:" + phone)
+}
+
+func (r *OfferRequestInMemoryRepository) emailIndex(email string) string {
+	if email = privacy.NormalizeEmail(email); email == "" {
+		return ""
+	}
+	return r.cipher.BlindIndex("email:" + email)
+}
+
+func (r *OfferRequestInMemoryRepository) seal(request entities.OfferRequest) (storedOfferRequest, error) {
+	phone, err := r.cipher.Encrypt(request.Phone, request.ID+"/phone")
+	if err != nil {
+		return storedOfferRequest{}, err
+	}
+	email, err := r.cipher.Encrypt(request.Email, request.ID+"/email")
+	if err != nil {
+		return storedOfferRequest{}, err
+	}
+	return storedOfferRequest{
+		ID:              request.ID,
+		PartnerID:       request.PartnerID,
+		FloorSize:       request.FloorSize,
+		Language:        request.Language,
+		Phone:           phone,
+		PhoneIndex:      r.phoneIndex(request.Phone),
+		Email:           email,
+		EmailIndex:      r.emailIndex(request.Email),
+		CreatedAt:       request.CreatedAt,
+		ContactPurgedAt: request.ContactPurgedAt,
+		LeadID:          request.LeadID,
+		Response:        request.Response,
+		RespondedAt:     request.RespondedAt,
+		ResponseRank:    request.ResponseRank,
+		CustomerID:      request.CustomerID,
+		Status:          request.Status,
+	}, nil
+}
+
+func (r *OfferRequestInMemoryRepository) open(stored storedOfferRequest) (entities.OfferRequest, error) {
+	phone, err := r.cipher.Decrypt(stored.Phone, stored.ID+"/phone")
+	if err != nil {
+		return entities.OfferRequest{}, err
+	}
+	email, err := r.cipher.Decrypt(stored.Email, stored.ID+"/email")
+	if err != nil {
+		return entities.OfferRequest{}, err
+	}
+	request := entities.OfferRequest{
+		ID:           stored.ID,
+		PartnerID:    stored.PartnerID,
+		FloorSize:    stored.FloorSize,
+		Language:     stored.Language,
+		Phone:        phone,
+		Email:        email,
+		CreatedAt:    stored.CreatedAt,
+		LeadID:       stored.LeadID,
+		Response:     stored.Response,
+		ResponseRank: stored.ResponseRank,
+		CustomerID:   stored.CustomerID,
+		Status:       stored.Status,
+	}
+	if stored.ContactPurgedAt != nil {
+		purgedAt := *stored.ContactPurgedAt
+		request.ContactPurgedAt = &purgedAt
+	}
+	if stored.RespondedAt != nil {
+		respondedAt := *stored.RespondedAt
+		request.RespondedAt = &respondedAt
+	}
+	return request, nil
+}
@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/privacy"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfferRequestInMemoryRepository(t *testing.T) {
+	cipher, err := privacy.NewRandomCipher()
+	require.NoError(t, err)
+	created := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	old := entities.OfferRequest{
+		ID:        "1",
+		PartnerID: "p1",
+		FloorSize: 40,
+		Phone:     "+49 170 1234567",
+		Email:     "jane@example.com",
+		CreatedAt: created,
+	}
+	recent := entities.OfferRequest{ID: "2", PartnerID: "p1", FloorSize: 60, Phone: "0301234", CreatedAt: created.AddDate(0, 1, 0)}
+	other := entities.OfferRequest{
+		ID:         "3",
+		PartnerID:  "p2",
+		FloorSize:  20,
+		Phone:      "0307654",
+		CreatedAt:  created,
+		CustomerID: "sub:max",
+	}
+	ctx := context.Background()
+
+	repo := NewOfferRequestInMemoryRepository(cipher, nil)
+	for _, request := range []entities.OfferRequest{old, recent, other} {
+		require.NoError(t, repo.CreateOfferRequest(ctx, request))
+	}
+
+	t.Run("Holds contact data only encrypted", func(t *testing.T) {
+		stored := repo.requests[0]
+		assert.NotContains(t, string(stored.Phone), "1234567")
+		assert.NotContains(t, string(stored.Email), "jane")
+	})
+
+	t.Run("Returns decrypted offer requests", func(t *testing.T) {
+		actual, err := repo.GetOfferRequestByID(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, old, actual)
+
+		_, err = repo.GetOfferRequestByID(ctx, "4")
+		assert.ErrorIs(t, err, entities.ErrRecordNotExist)
+
+		byPartner, err := repo.GetOfferRequestsByPartner(ctx, "p1")
+		require.NoError(t, err)
+		assert.Equal(t, []entities.OfferRequest{old, recent}, byPartner)
+	})
+
+	t.Run("Finds offer requests by normalized contact data", func(t *testing.T) {
+		byPhone, err := repo.GetOfferRequestsByContact(ctx, "+49 (170) 123-45-67", "")
+		require.NoError(t, err)
+		assert.Equal(t, []entities.OfferRequest{old}, byPhone)
+
+		byEmail, err := repo.GetOfferRequestsByContact(ctx, "", " Jane@Example.com")
+		require.NoError(t, err)
+		assert.Equal(t, []entities.OfferRequest{old}, byEmail)
+
+		both, err := repo.GetOfferRequestsByContact(ctx, "0307654", "jane@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, []entities.OfferRequest{old, other}, both)
+
+		none, err := repo.GetOfferRequestsByContact(ctx, "", "")
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+
+	t.Run("Fails on ciphertext moved to another record", func(t *testing.T) {
+		moved := NewOfferRequestInMemoryRepository(cipher, nil)
+		stored := repo.requests[0]
+		stored.ID = "5"
+		moved.requests = []storedOfferRequest{stored}
+
+		_, err := moved.GetOfferRequestByID(ctx, "5")
+		assert.ErrorIs(t, err, privacy.ErrDecrypt)
+	})
+
+	t.Run("Purges contact data of old offer requests once", func(t *testing.T) {
+		purgedAt := created.AddDate(0, 0, 20)
+		purged, err := repo.PurgeContactData(ctx, created.AddDate(0, 0, 1), purgedAt)
+		require.NoError(t, err)
+		assert.Equal(t, 2, purged)
+
+		actual, err := repo.GetOfferRequestByID(ctx, "1")
+		require.NoError(t, err)
+		assert.Empty(t, actual.Phone)
+		assert.Empty(t, actual.Email)
+		assert.Equal(t, &purgedAt, actual.ContactPurgedAt)
+		assert.Nil(t, repo.requests[0].Phone)
+
+		kept, err := repo.GetOfferRequestByID(ctx, "2")
+		require.NoError(t, err)
+		assert.Equal(t, recent, kept)
+
+		purged, err = repo.PurgeContactData(ctx, created.AddDate(0, 0, 1), purgedAt)
+		require.NoError(t, err)
+		assert.Zero(t, purged)
+	})
+
+	t.Run("Finds offer requests by customer id", func(t *testing.T) {
+		found, err := repo.GetOfferRequestsByCustomer(ctx, "sub:max")
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, other.ID, found[0].ID)
+
+		found, err = repo.GetOfferRequestsByCustomer(ctx, "")
+		require.NoError(t, err)
+		assert.Empty(t, found, "offer requests without account are not matched")
+	})
+
+	t.Run("Anonymizes offer requests by id", func(t *testing.T) {
+		anonymizedAt := created.AddDate(0, 0, 30)
+		anonymized, err := repo.AnonymizeOfferRequests(ctx, []string{"3"}, anonymizedAt)
+		require.NoError(t, err)
+		assert.Equal(t, 1, anonymized)
+
+		actual, err := repo.GetOfferRequestByID(ctx, "3")
+		require.NoError(t, err)
+		assert.Empty(t, actual.Phone)
+		assert.Equal(t, &anonymizedAt, actual.ContactPurgedAt)
+		assert.Empty(t, actual.CustomerID)
+		found, err := repo.GetOfferRequestsByContact(ctx, other.Phone, "")
+		require.NoError(t, err)
+		assert.Empty(t, found)
+		found, err = repo.GetOfferRequestsByCustomer(ctx, other.CustomerID)
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+
+	t.Run("Returns error on canceled context", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := repo.GetOfferRequestsByPartner(canceled, "p1")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestOfferRequestInMemoryRepository_Leads(t *testing.T) {
+	cipher, err := privacy.NewRandomCipher()
+	require.NoError(t, err)
+	ctx := context.Background()
+	created := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	repo := NewOfferRequestInMemoryRepository(cipher, nil)
+	require.NoError(t, repo.CreateOfferRequests(ctx, []entities.OfferRequest{
+		{ID: "1", PartnerID: "p1", Phone: "+49 170 1234567", CreatedAt: created, LeadID: "lead"},
+		{ID: "2", PartnerID: "p2", Phone: "+49 170 1234567", CreatedAt: created, LeadID: "lead"},
+	}))
+	require.NoError(t, repo.CreateOfferRequest(ctx, entities.OfferRequest{
+		ID:        "3",
+		PartnerID: "p1",
+		Phone:     "+49 170 1234567",
+		CreatedAt: created,
+	}))
+
+	requests, err := repo.GetOfferRequestsByLead(ctx, "lead")
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Equal(t, "+49 170 1234567", requests[0].Phone)
+
+	count, err := repo.CountLeadsByPartner(ctx, "p1", created)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "offer requests without lead are not counted")
+	count, err = repo.CountLeadsByPartner(ctx, "p1", created.Add(time.Second))
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	respondedAt := created.Add(time.Hour)
+	second, err := repo.RespondToOfferRequest(ctx, "2", entities.OfferRequestAccepted, respondedAt)
+	require.NoError(t, err)
+	assert.Equal(t, 1, second.ResponseRank)
+	assert.Equal(t, &respondedAt, second.RespondedAt)
+	first, err := repo.RespondToOfferRequest(ctx, "1", entities.OfferRequestDeclined, respondedAt)
+	require.NoError(t, err)
+	assert.Equal(t, 2, first.ResponseRank)
+	single, err := repo.RespondToOfferRequest(ctx, "3", entities.OfferRequestAccepted, respondedAt)
+	require.NoError(t, err)
+	assert.Equal(t, 1, single.ResponseRank)
+
+	_, err = repo.RespondToOfferRequest(ctx, "1", entities.OfferRequestAccepted, respondedAt)
+	assert.ErrorIs(t, err, entities.ErrAlreadyResponded)
+	_, err = repo.RespondToOfferRequest(ctx, "4", entities.OfferRequestAccepted, respondedAt)
+	assert.ErrorIs(t, err, entities.ErrRecordNotExist)
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"sync"
+)
+
+func NewOutboxInMemoryRepository() *OutboxInMemoryRepository {
+	return &OutboxInMemoryRepository{}
+}
+
+// OutboxInMemoryRepository holds the events of the in-memory repositories until they are published. The repositories
+// add the events of a write while holding their own lock, so that a change and its events become visible together.
+type OutboxInMemoryRepository struct {
+	mu     sync.Mutex
+	events []entities.Event
+}
+
+// add appends events to the outbox. It is called by the repositories as part of a write and cannot fail. A nil outbox
+// drops the events.
+func (o *OutboxInMemoryRepository) add(events []entities.Event) {
+	if o == nil || len(events) == 0 {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, event := range events {
+		event.Data = append([]byte(nil), event.Data...)
+		o.events = append(o.events, event)
+	}
+}
+
+// GetPendingEvents returns up to limit events which are not published yet, oldest first.
+func (o *OutboxInMemoryRepository) GetPendingEvents(ctx context.Context, limit int) ([]entities.Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if limit > len(o.events) {
+		limit = len(o.events)
+	}
+	events := make([]entities.Event, 0, limit)
+	for _, event := range o.events[:limit] {
+		event.Data = append([]byte(nil), event.Data...)
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// DeletePendingEvents removes the events of the given types about the aggregate from the outbox.
+func (o *OutboxInMemoryRepository) DeletePendingEvents(ctx context.Context, aggregateID string, types ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	deleted := make(map[string]bool, len(types))
+	for _, eventType := range types {
+		deleted[eventType] = true
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	pending := o.events[:0]
+	for _, event := range o.events {
+		if event.AggregateID != aggregateID || !deleted[event.Type] {
+			pending = append(pending, event)
+		}
+	}
+	o.events = pending
+	return nil
+}
+
+// MarkEventsPublished removes the published events from the outbox. Unknown ids are ignored.
+func (o *OutboxInMemoryRepository) MarkEventsPublished(ctx context.Context, ids []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	published := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		published[id] = true
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	pending := o.events[:0]
+	for _, event := range o.events {
+		if !published[event.ID] {
+			pending = append(pending, event)
+		}
+	}
+	o.events = pending
+	return nil
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxInMemoryRepository(t *testing.T) {
+	ctx := context.Background()
+	outbox := NewOutboxInMemoryRepository()
+	partners := NewPartnerInMemoryRepository(outbox)
+	created := entities.Event{ID: "e1", Type: entities.EventPartnerCreated, AggregateID: "new"}
+	updated := entities.Event{ID: "e2", Type: entities.EventPartnerUpdated, AggregateID: "new"}
+	deleted := entities.Event{ID: "e3", Type: entities.EventPartnerDeleted, AggregateID: "new"}
+
+	require.NoError(t, partners.CreatePartner(ctx, entities.Partner{ID: "new"}, created))
+	require.NoError(t, partners.UpdatePartner(ctx, entities.Partner{ID: "new"}, updated))
+	assert.Equal(t, entities.ErrRecordNotExist, partners.UpdatePartner(ctx, entities.Partner{ID: "unknown"}, updated))
+	require.NoError(t, partners.DeletePartner(ctx, "new", deleted))
+
+	pending, err := outbox.GetPendingEvents(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.Event{created, updated}, pending, "events of failed writes are not stored")
+
+	require.NoError(t, outbox.MarkEventsPublished(ctx, []string{"e1", "e2", "unknown"}))
+	pending, err = outbox.GetPendingEvents(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.Event{deleted}, pending)
+
+	require.NoError(t, outbox.DeletePendingEvents(ctx, "new", entities.EventPartnerUpdated))
+	pending, err = outbox.GetPendingEvents(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.Event{deleted}, pending, "events of other types are kept")
+	require.NoError(t, outbox.DeletePendingEvents(ctx, "other", entities.EventPartnerDeleted))
+	pending, err = outbox.GetPendingEvents(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.Event{deleted}, pending, "events about other aggregates are kept")
+	require.NoError(t, outbox.DeletePendingEvents(ctx, "new", entities.EventPartnerDeleted))
+	pending, err = outbox.GetPendingEvents(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	assert.NotPanics(t, func() {
+		var dropping *OutboxInMemoryRepository
+		dropping.add([]entities.Event{created})
+	}, "a nil outbox drops events")
+}
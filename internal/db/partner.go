@@ -1,41 +1,203 @@
 package db
 
 import (
+	"context"
 	"customer-partner/internal/entities"
+	"sync"
+	"time"
 )
 
-func NewPartnerInMemoryRepository() *PartnerInMemoryRepository {
-	return &PartnerInMemoryRepository{partners: demoData}
+// NewPartnerInMemoryRepository creates the repository with the demo data. The events of writes are added to outbox,
+// which may be nil to drop them.
+func NewPartnerInMemoryRepository(outbox *OutboxInMemoryRepository) *PartnerInMemoryRepository {
+	partners := make([]entities.Partner, 0, len(demoData))
+	for _, partner := range demoData {
+		partners = append(partners, clonePartner(partner))
+	}
+	return &PartnerInMemoryRepository{partners: partners, outbox: outbox}
+}
+
+// NewPartnerInMemoryRepositoryFrom creates the repository with copies of the partners instead of the demo data, e.g.
+// with an export of another instance. The events of writes are added to outbox, which may be nil to drop them.
+func NewPartnerInMemoryRepositoryFrom(partners []entities.Partner, outbox *OutboxInMemoryRepository) *PartnerInMemoryRepository {
+	stored := make([]entities.Partner, 0, len(partners))
+	for _, partner := range partners {
+		stored = append(stored, clonePartner(partner))
+	}
+	return &PartnerInMemoryRepository{partners: stored, outbox: outbox}
 }
 
+// ctxCheckInterval defines after how many partners a running scan checks whether its context is done, like the match
+// of the domain layer.
+const ctxCheckInterval = 256
+
 // PartnerInMemoryRepository saves partners in memory and initialises them with some demo data.
 type PartnerInMemoryRepository struct {
+	mu       sync.RWMutex
 	partners []entities.Partner
+	outbox   *OutboxInMemoryRepository
 }
 
 // GetPartnersByMaterial returns partners filtered by material.
-func (r *PartnerInMemoryRepository) GetPartnersByMaterial(material string) []entities.Partner {
+// Returns the context error when ctx is done before all partners are checked.
+func (r *PartnerInMemoryRepository) GetPartnersByMaterial(ctx context.Context, material string) ([]entities.Partner, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var filtered []entities.Partner
-	for _, partner := range r.partners {
+	for i, partner := range r.partners {
+		if i%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
 		// TODO consider making partner.ExperiencedMaterial a set which would remove the for loop for this check.
 		//    However, this will introduce a data mapping layer to still render it as a list in the api.
 		for _, expMaterial := range partner.ExperiencedMaterial {
 			if expMaterial == material {
-				filtered = append(filtered, partner)
-				continue
+				filtered = append(filtered, clonePartner(partner))
+				break
 			}
 		}
 	}
-	return filtered
+	return filtered, nil
 }
 
 // GetPartnerByID returns a partner by an id.
 // Can return entities.ErrRecordNotExist when partner with given id does not exist.
-func (r *PartnerInMemoryRepository) GetPartnerByID(id string) (entities.Partner, error) {
+func (r *PartnerInMemoryRepository) GetPartnerByID(ctx context.Context, id string) (entities.Partner, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.Partner{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	for _, partner := range r.partners {
 		if partner.ID == id {
-			return partner, nil
+			return clonePartner(partner), nil
 		}
 	}
 	return entities.Partner{}, entities.ErrRecordNotExist
 }
+
+// GetAllPartners returns all partners, including suspended and deleted ones.
+// Returns the context error when ctx is done.
+func (r *PartnerInMemoryRepository) GetAllPartners(ctx context.Context) ([]entities.Partner, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	partners := make([]entities.Partner, 0, len(r.partners))
+	for _, partner := range r.partners {
+		partners = append(partners, clonePartner(partner))
+	}
+	return partners, nil
+}
+
+// GetPartnersWithExpiringCertifications returns the partners having a certification which expires before the given
+// time.
+// Returns the context error when ctx is done before all partners are checked.
+func (r *PartnerInMemoryRepository) GetPartnersWithExpiringCertifications(
+	ctx context.Context,
+	before time.Time,
+) ([]entities.Partner, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var expiring []entities.Partner
+	for _, partner := range r.partners {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, certification := range partner.Certifications {
+			if certification.ExpiresAt.Before(before) {
+				expiring = append(expiring, clonePartner(partner))
+				break
+			}
+		}
+	}
+	return expiring, nil
+}
+
+// CreatePartner stores a new partner together with its events.
+func (r *PartnerInMemoryRepository) CreatePartner(ctx context.Context, partner entities.Partner, events ...entities.Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.partners = append(r.partners, clonePartner(partner))
+	r.outbox.add(events)
+	return nil
+}
+
+// UpdatePartner replaces the stored partner having the same id and stores the events.
+// Can return entities.ErrRecordNotExist when partner with given id does not exist.
+func (r *PartnerInMemoryRepository) UpdatePartner(ctx context.Context, partner entities.Partner, events ...entities.Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.partners {
+		if r.partners[i].ID == partner.ID {
+			r.partners[i] = clonePartner(partner)
+			r.outbox.add(events)
+			return nil
+		}
+	}
+	return entities.ErrRecordNotExist
+}
+
+// DeletePartner removes the partner with the given id and stores the events.
+// Can return entities.ErrRecordNotExist when partner with given id does not exist.
+func (r *PartnerInMemoryRepository) DeletePartner(ctx context.Context, id string, events ...entities.Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.partners {
+		if r.partners[i].ID == id {
+			r.partners = append(r.partners[:i], r.partners[i+1:]...)
+			r.outbox.add(events)
+			return nil
+		}
+	}
+	return entities.ErrRecordNotExist
+}
+
+// Check implements web.HealthChecker. The in-memory repository is always usable as long as ctx is not done.
+func (r *PartnerInMemoryRepository) Check(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// clonePartner copies the slices and pointers of a partner, so that callers cannot change the stored partners.
+func clonePartner(p entities.Partner) entities.Partner {
+	p.ExperiencedMaterial = append([]string(nil), p.ExperiencedMaterial...)
+	p.PriceLists = append([]entities.PriceList(nil), p.PriceLists...)
+	p.OpeningHours = append([]entities.OpeningHours(nil), p.OpeningHours...)
+	p.Holidays = append([]string(nil), p.Holidays...)
+	p.Certifications = append([]entities.Certification(nil), p.Certifications...)
+	for i, certification := range p.Certifications {
+		if certification.FlaggedAt != nil {
+			flaggedAt := *certification.FlaggedAt
+			p.Certifications[i].FlaggedAt = &flaggedAt
+		}
+	}
+	if p.Contact != nil {
+		contact := *p.Contact
+		p.Contact = &contact
+	}
+	if p.InsuranceExpiresAt != nil {
+		expiresAt := *p.InsuranceExpiresAt
+		p.InsuranceExpiresAt = &expiresAt
+	}
+	if p.SuspendedAt != nil {
+		suspendedAt := *p.SuspendedAt
+		p.SuspendedAt = &suspendedAt
+	}
+	if p.DeletedAt != nil {
+		deletedAt := *p.DeletedAt
+		p.DeletedAt = &deletedAt
+	}
+	return p
+}
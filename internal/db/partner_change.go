@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"sync"
+)
+
+func NewPartnerChangeInMemoryRepository() *PartnerChangeInMemoryRepository {
+	return &PartnerChangeInMemoryRepository{changes: map[string][]entities.PartnerChange{}}
+}
+
+// PartnerChangeInMemoryRepository saves the audit trail of partners in memory. Changes can only be appended.
+type PartnerChangeInMemoryRepository struct {
+	mu      sync.RWMutex
+	changes map[string][]entities.PartnerChange
+}
+
+// AppendPartnerChange appends a change to the audit trail of its partner.
+func (r *PartnerChangeInMemoryRepository) AppendPartnerChange(ctx context.Context, change entities.PartnerChange) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	change.Changes = append([]entities.FieldChange(nil), change.Changes...)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes[change.PartnerID] = append(r.changes[change.PartnerID], change)
+	return nil
+}
+
+// RedactPartnerChanges removes the values of all changes of a partner.
+func (r *PartnerChangeInMemoryRepository) RedactPartnerChanges(ctx context.Context, partnerID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, change := range r.changes[partnerID] {
+		redacted := make([]entities.FieldChange, 0, len(change.Changes))
+		for _, fieldChange := range change.Changes {
+			redacted = append(redacted, entities.FieldChange{Field: fieldChange.Field})
+		}
+		r.changes[partnerID][i].Changes = redacted
+		r.changes[partnerID][i].Redacted = true
+	}
+	return nil
+}
+
+// GetPartnerChanges returns the changes of a partner, oldest first.
+func (r *PartnerChangeInMemoryRepository) GetPartnerChanges(
+	ctx context.Context,
+	partnerID string,
+) ([]entities.PartnerChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	changes := make([]entities.PartnerChange, 0, len(r.changes[partnerID]))
+	for _, change := range r.changes[partnerID] {
+		change.Changes = append([]entities.FieldChange(nil), change.Changes...)
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
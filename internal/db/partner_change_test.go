@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartnerChangeInMemoryRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPartnerChangeInMemoryRepository()
+	first := entities.PartnerChange{ID: "c1", PartnerID: "1", Changes: []entities.FieldChange{{Field: "name"}}}
+	second := entities.PartnerChange{ID: "c2", PartnerID: "1"}
+	require.NoError(t, repo.AppendPartnerChange(ctx, first))
+	require.NoError(t, repo.AppendPartnerChange(ctx, second))
+	require.NoError(t, repo.AppendPartnerChange(ctx, entities.PartnerChange{ID: "c3", PartnerID: "2"}))
+
+	changes, err := repo.GetPartnerChanges(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, []entities.PartnerChange{first, second}, changes)
+
+	changes[0].Changes[0].Field = "modified"
+	changes, err = repo.GetPartnerChanges(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "name", changes[0].Changes[0].Field, "stored changes are not modifiable through results")
+
+	changes, err = repo.GetPartnerChanges(ctx, "3")
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestPartnerChangeInMemoryRepository_RedactPartnerChanges(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPartnerChangeInMemoryRepository()
+	change := entities.PartnerChange{ID: "c1", PartnerID: "1", Actor: "apikey:ops", Changes: []entities.FieldChange{
+		{Field: "name", Before: "Floors", After: "Jane Doe Floors"},
+	}}
+	other := entities.PartnerChange{ID: "c2", PartnerID: "2", Changes: []entities.FieldChange{
+		{Field: "name", Before: "Tiles", After: "Tiles & More"},
+	}}
+	require.NoError(t, repo.AppendPartnerChange(ctx, change))
+	require.NoError(t, repo.AppendPartnerChange(ctx, other))
+
+	require.NoError(t, repo.RedactPartnerChanges(ctx, "1"))
+
+	changes, err := repo.GetPartnerChanges(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, []entities.PartnerChange{{
+		ID: "c1", PartnerID: "1", Actor: "apikey:ops", Changes: []entities.FieldChange{{Field: "name"}}, Redacted: true,
+	}}, changes)
+	changes, err = repo.GetPartnerChanges(ctx, "2")
+	require.NoError(t, err)
+	assert.Equal(t, []entities.PartnerChange{other}, changes, "changes of other partners are kept")
+}
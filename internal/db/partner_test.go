@@ -1,10 +1,15 @@
 package db
 
 import (
+	"context"
 	"customer-partner/internal/entities"
+	"customer-partner/internal/seed"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPartnerInMemoryRepository_GetPartnersByMaterial(t *testing.T) {
@@ -39,15 +44,22 @@ func TestPartnerInMemoryRepository_GetPartnersByMaterial(t *testing.T) {
 			expIDs: []string{"234", "345", "567"},
 			expLen: 3,
 		},
+		{
+			name:   "Returns partner listing material twice once",
+			data:   []entities.Partner{{ID: "123", ExperiencedMaterial: []string{"wood", "wood"}}},
+			expIDs: []string{"123"},
+			expLen: 1,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			repo := NewPartnerInMemoryRepository()
+			repo := NewPartnerInMemoryRepository(nil)
 			repo.partners = tt.data
 
-			actual := repo.GetPartnersByMaterial("wood")
+			actual, err := repo.GetPartnersByMaterial(context.Background(), "wood")
 
+			assert.NoError(t, err)
 			assert.Len(t, actual, tt.expLen)
 			for _, partner := range actual {
 				assert.Contains(t, tt.expIDs, partner.ID)
@@ -80,13 +92,128 @@ func TestPartnerInMemoryRepository_GetPartnerByID(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			repo := NewPartnerInMemoryRepository()
+			repo := NewPartnerInMemoryRepository(nil)
 			repo.partners = tt.data
 
-			actual, err := repo.GetPartnerByID("123")
+			actual, err := repo.GetPartnerByID(context.Background(), "123")
 
 			assert.Equal(t, tt.expResult, actual)
 			assert.Equal(t, tt.expErr, err)
 		})
 	}
 }
+
+func TestPartnerInMemoryRepository_GetPartnerByID_Copy(t *testing.T) {
+	flaggedAt := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	expiresAt := flaggedAt.AddDate(0, 1, 0)
+	storedFlaggedAt, storedExpiresAt := flaggedAt, expiresAt
+	repo := NewPartnerInMemoryRepositoryFrom([]entities.Partner{{
+		ID:                 "123",
+		Contact:            &entities.Contact{Email: "info@floors.example"},
+		Certifications:     []entities.Certification{{Type: "FSC", ExpiresAt: expiresAt, FlaggedAt: &storedFlaggedAt}},
+		InsuranceExpiresAt: &storedExpiresAt,
+	}}, nil)
+
+	partner, err := repo.GetPartnerByID(context.Background(), "123")
+	require.NoError(t, err)
+	*partner.Certifications[0].FlaggedAt = flaggedAt.Add(time.Hour)
+	partner.Certifications[0].Type = "Master"
+	partner.Contact.Email = "other@floors.example"
+	*partner.InsuranceExpiresAt = expiresAt.Add(time.Hour)
+
+	stored, err := repo.GetPartnerByID(context.Background(), "123")
+	require.NoError(t, err)
+	assert.Equal(t, flaggedAt, *stored.Certifications[0].FlaggedAt)
+	assert.Equal(t, "FSC", stored.Certifications[0].Type)
+	assert.Equal(t, "info@floors.example", stored.Contact.Email)
+	assert.Equal(t, expiresAt, *stored.InsuranceExpiresAt)
+}
+
+func TestPartnerInMemoryRepository_CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	repo := NewPartnerInMemoryRepository(nil)
+
+	partners, err := repo.GetPartnersByMaterial(ctx, "wood")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, partners)
+
+	_, err = repo.GetPartnerByID(ctx, "1")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = repo.GetAllPartners(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPartnerInMemoryRepository_GetAllPartners(t *testing.T) {
+	repo := NewPartnerInMemoryRepository(nil)
+	repo.partners = []entities.Partner{{ID: "123", ExperiencedMaterial: []string{"wood"}}, {ID: "234"}}
+
+	partners, err := repo.GetAllPartners(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, repo.partners, partners)
+
+	partners[0].ExperiencedMaterial[0] = "tiles"
+	assert.Equal(t, []string{"wood"}, repo.partners[0].ExperiencedMaterial, "returned partners must not share slices")
+}
+
+func TestNewPartnerInMemoryRepositoryFrom(t *testing.T) {
+	partners := []entities.Partner{{ID: "123", ExperiencedMaterial: []string{"wood"}}, {ID: "234"}}
+
+	repo := NewPartnerInMemoryRepositoryFrom(partners, nil)
+
+	assert.Equal(t, partners, repo.partners)
+	partners[0].ExperiencedMaterial[0] = "tiles"
+	assert.Equal(t, []string{"wood"}, repo.partners[0].ExperiencedMaterial, "stored partners must not share slices")
+}
+
+func TestPartnerInMemoryRepository_CreateAndUpdatePartner(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPartnerInMemoryRepository(nil)
+	repo.partners = []entities.Partner{}
+	partner := entities.Partner{ID: "123", Name: "Floors", ExperiencedMaterial: []string{"wood"}, OperatingRadius: 10}
+
+	assert.NoError(t, repo.CreatePartner(ctx, partner))
+	partner.ExperiencedMaterial[0] = "tiles"
+	stored, err := repo.GetPartnerByID(ctx, "123")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"wood"}, stored.ExperiencedMaterial, "stored partner must not share slices with caller")
+
+	stored.OperatingRadius = 20
+	assert.NoError(t, repo.UpdatePartner(ctx, stored))
+	updated, err := repo.GetPartnerByID(ctx, "123")
+	assert.NoError(t, err)
+	assert.Equal(t, 20, updated.OperatingRadius)
+
+	err = repo.UpdatePartner(ctx, entities.Partner{ID: "234"})
+	assert.Equal(t, entities.ErrRecordNotExist, err)
+}
+
+func TestPartnerInMemoryRepository_DeletePartner(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPartnerInMemoryRepository(nil)
+	repo.partners = []entities.Partner{{ID: "123"}, {ID: "234"}}
+
+	assert.NoError(t, repo.DeletePartner(ctx, "123"))
+	_, err := repo.GetPartnerByID(ctx, "123")
+	assert.Equal(t, entities.ErrRecordNotExist, err)
+	assert.Equal(t, []entities.Partner{{ID: "234"}}, repo.partners)
+
+	assert.Equal(t, entities.ErrRecordNotExist, repo.DeletePartner(ctx, "123"))
+}
+
+func BenchmarkPartnerInMemoryRepository_GetPartnersByMaterial(b *testing.B) {
+	for _, size := range []int{1000, 10000, 100000} {
+		repo := NewPartnerInMemoryRepositoryFrom(seed.Partners(seed.Options{Count: size, Seed: 1}), nil)
+		b.Run(fmt.Sprintf("partners=%d", size), func(b *testing.B) {
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.GetPartnersByMaterial(ctx, entities.Materials[i%len(entities.Materials)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"time"
+)
+
+// CreateQuote stores a new quote together with its events. Pending quotes of the offer request are superseded and
+// the offer request is marked as quoted.
+// Can return entities.ErrRecordNotExist when the offer request does not exist and entities.ErrOfferRequestClosed when
+// the partner declined it or the customer ordered.
+func (r *OfferRequestInMemoryRepository) CreateQuote(
+	ctx context.Context,
+	quote entities.Quote,
+	events ...entities.Event,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	index := r.indexOf(quote.OfferRequestID)
+	if index < 0 {
+		return entities.ErrRecordNotExist
+	}
+	request := &r.requests[index]
+	if request.Response == entities.OfferRequestDeclined || request.Status == entities.OfferRequestOrdered {
+		return entities.ErrOfferRequestClosed
+	}
+	for i := range r.quotes {
+		if r.quotes[i].OfferRequestID == quote.OfferRequestID && r.quotes[i].Status == entities.QuotePending {
+			r.quotes[i].Status = entities.QuoteSuperseded
+		}
+	}
+	r.quotes = append(r.quotes, cloneQuote(quote))
+	request.Status = entities.OfferRequestQuoted
+	r.outbox.add(events)
+	return nil
+}
+
+// GetQuoteByID returns a quote by an id.
+// Can return entities.ErrRecordNotExist when quote with given id does not exist.
+func (r *OfferRequestInMemoryRepository) GetQuoteByID(ctx context.Context, id string) (entities.Quote, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.Quote{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, quote := range r.quotes {
+		if quote.ID == id {
+			return cloneQuote(quote), nil
+		}
+	}
+	return entities.Quote{}, entities.ErrRecordNotExist
+}
+
+// GetQuotesByOfferRequest returns the quotes of an offer request, oldest first.
+func (r *OfferRequestInMemoryRepository) GetQuotesByOfferRequest(
+	ctx context.Context,
+	offerRequestID string,
+) ([]entities.Quote, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	quotes := []entities.Quote{}
+	for _, quote := range r.quotes {
+		if quote.OfferRequestID == offerRequestID {
+			quotes = append(quotes, cloneQuote(quote))
+		}
+	}
+	return quotes, nil
+}
+
+// DecideQuote stores the decision of the customer together with its events and advances the offer request: it is
+// ordered when the quote is accepted.
+// Can return entities.ErrRecordNotExist when quote with given id does not exist and entities.ErrQuoteNotPending when
+// the quote is not pending anymore.
+func (r *OfferRequestInMemoryRepository) DecideQuote(
+	ctx context.Context,
+	id, status string,
+	decidedAt time.Time,
+	events ...entities.Event,
+) (entities.Quote, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.Quote{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.quotes {
+		quote := &r.quotes[i]
+		if quote.ID != id {
+			continue
+		}
+		if quote.Status != entities.QuotePending {
+			return entities.Quote{}, entities.ErrQuoteNotPending
+		}
+		quote.Status = status
+		quote.DecidedAt = &decidedAt
+		if index := r.indexOf(quote.OfferRequestID); index >= 0 {
+			r.requests[index].Status = entities.OfferRequestQuoteDeclined
+			if status == entities.QuoteAccepted {
+				r.requests[index].Status = entities.OfferRequestOrdered
+			}
+		}
+		r.outbox.add(events)
+		return cloneQuote(*quote), nil
+	}
+	return entities.Quote{}, entities.ErrRecordNotExist
+}
+
+func cloneQuote(q entities.Quote) entities.Quote {
+	q.LineItems = append([]entities.QuoteLineItem(nil), q.LineItems...)
+	if q.DecidedAt != nil {
+		decidedAt := *q.DecidedAt
+		q.DecidedAt = &decidedAt
+	}
+	return q
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/privacy"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfferRequestInMemoryRepository_Quotes(t *testing.T) {
+	cipher, err := privacy.NewRandomCipher()
+	require.NoError(t, err)
+	created := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	repo := NewOfferRequestInMemoryRepository(cipher, nil)
+	for _, request := range []entities.OfferRequest{
+		{ID: "r1", PartnerID: "p1", FloorSize: 40, Phone: "0301234", CreatedAt: created},
+		{ID: "r2", PartnerID: "p2", FloorSize: 20, Phone: "0307654", CreatedAt: created},
+	} {
+		require.NoError(t, repo.CreateOfferRequest(ctx, request))
+	}
+	quote := func(id string) entities.Quote {
+		return entities.Quote{
+			ID:             id,
+			OfferRequestID: "r1",
+			PartnerID:      "p1",
+			Currency:       "EUR",
+			LineItems:      []entities.QuoteLineItem{{Kind: entities.LineItemLabour, Description: "Laying", Quantity: 1}},
+			Status:         entities.QuotePending,
+			CreatedAt:      created,
+		}
+	}
+
+	t.Run("Rejects quote for unknown offer request", func(t *testing.T) {
+		unknown := quote("q0")
+		unknown.OfferRequestID = "unknown"
+
+		assert.ErrorIs(t, repo.CreateQuote(ctx, unknown), entities.ErrRecordNotExist)
+	})
+
+	t.Run("Marks offer request as quoted and supersedes pending quotes", func(t *testing.T) {
+		require.NoError(t, repo.CreateQuote(ctx, quote("q1")))
+		require.NoError(t, repo.CreateQuote(ctx, quote("q2")))
+
+		quotes, err := repo.GetQuotesByOfferRequest(ctx, "r1")
+		require.NoError(t, err)
+		require.Len(t, quotes, 2)
+		assert.Equal(t, entities.QuoteSuperseded, quotes[0].Status)
+		assert.Equal(t, entities.QuotePending, quotes[1].Status)
+		request, err := repo.GetOfferRequestByID(ctx, "r1")
+		require.NoError(t, err)
+		assert.Equal(t, entities.OfferRequestQuoted, request.Status)
+	})
+
+	t.Run("Rejects decision on superseded quote", func(t *testing.T) {
+		_, err := repo.DecideQuote(ctx, "q1", entities.QuoteAccepted, created)
+
+		assert.ErrorIs(t, err, entities.ErrQuoteNotPending)
+	})
+
+	t.Run("Orders offer request when quote is accepted", func(t *testing.T) {
+		decided, err := repo.DecideQuote(ctx, "q2", entities.QuoteAccepted, created.Add(time.Hour))
+		require.NoError(t, err)
+
+		assert.Equal(t, entities.QuoteAccepted, decided.Status)
+		require.NotNil(t, decided.DecidedAt)
+		assert.Equal(t, created.Add(time.Hour), *decided.DecidedAt)
+		request, err := repo.GetOfferRequestByID(ctx, "r1")
+		require.NoError(t, err)
+		assert.Equal(t, entities.OfferRequestOrdered, request.Status)
+		_, err = repo.DecideQuote(ctx, "q2", entities.QuoteDeclined, created.Add(time.Hour))
+		assert.ErrorIs(t, err, entities.ErrQuoteNotPending)
+	})
+
+	t.Run("Rejects quote for ordered offer request", func(t *testing.T) {
+		assert.ErrorIs(t, repo.CreateQuote(ctx, quote("q3")), entities.ErrOfferRequestClosed)
+	})
+
+	t.Run("Rejects quote for declined offer request", func(t *testing.T) {
+		_, err := repo.RespondToOfferRequest(ctx, "r2", entities.OfferRequestDeclined, created)
+		require.NoError(t, err)
+		declined := quote("q4")
+		declined.OfferRequestID = "r2"
+
+		assert.ErrorIs(t, repo.CreateQuote(ctx, declined), entities.ErrOfferRequestClosed)
+	})
+
+	t.Run("Does not share line items with caller", func(t *testing.T) {
+		stored, err := repo.GetQuoteByID(ctx, "q2")
+		require.NoError(t, err)
+		stored.LineItems[0].Description = "changed"
+
+		again, err := repo.GetQuoteByID(ctx, "q2")
+		require.NoError(t, err)
+		assert.Equal(t, "Laying", again.LineItems[0].Description)
+	})
+}
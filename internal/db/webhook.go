@@ -0,0 +1,214 @@
+package db
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"sort"
+	"sync"
+	"time"
+)
+
+func NewWebhookInMemoryRepository() *WebhookInMemoryRepository {
+	return &WebhookInMemoryRepository{deliveries: map[string]entities.WebhookDelivery{}}
+}
+
+// WebhookInMemoryRepository saves webhook subscriptions and their deliveries in memory.
+type WebhookInMemoryRepository struct {
+	mu            sync.RWMutex
+	subscriptions []entities.WebhookSubscription
+	deliveries    map[string]entities.WebhookDelivery
+}
+
+// CreateWebhookSubscription stores a new subscription.
+func (r *WebhookInMemoryRepository) CreateWebhookSubscription(
+	ctx context.Context,
+	subscription entities.WebhookSubscription,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscriptions = append(r.subscriptions, cloneSubscription(subscription))
+	return nil
+}
+
+// GetWebhookSubscription returns a subscription by an id.
+// Can return entities.ErrRecordNotExist when subscription with given id does not exist.
+func (r *WebhookInMemoryRepository) GetWebhookSubscription(
+	ctx context.Context,
+	id string,
+) (entities.WebhookSubscription, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.WebhookSubscription{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, subscription := range r.subscriptions {
+		if subscription.ID == id {
+			return cloneSubscription(subscription), nil
+		}
+	}
+	return entities.WebhookSubscription{}, entities.ErrRecordNotExist
+}
+
+// GetWebhookSubscriptionsByPartner returns the subscriptions of a partner, oldest first.
+func (r *WebhookInMemoryRepository) GetWebhookSubscriptionsByPartner(
+	ctx context.Context,
+	partnerID string,
+) ([]entities.WebhookSubscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	subscriptions := []entities.WebhookSubscription{}
+	for _, subscription := range r.subscriptions {
+		if subscription.PartnerID == partnerID {
+			subscriptions = append(subscriptions, cloneSubscription(subscription))
+		}
+	}
+	return subscriptions, nil
+}
+
+// DeleteWebhookSubscription removes the subscription with the given id.
+// Can return entities.ErrRecordNotExist when subscription with given id does not exist.
+func (r *WebhookInMemoryRepository) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.subscriptions {
+		if r.subscriptions[i].ID == id {
+			r.subscriptions = append(r.subscriptions[:i], r.subscriptions[i+1:]...)
+			return nil
+		}
+	}
+	return entities.ErrRecordNotExist
+}
+
+// CreateWebhookDelivery stores a new delivery unless a delivery with the same id exists.
+func (r *WebhookInMemoryRepository) CreateWebhookDelivery(ctx context.Context, delivery entities.WebhookDelivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.deliveries[delivery.ID]; !ok {
+		r.deliveries[delivery.ID] = cloneDelivery(delivery)
+	}
+	return nil
+}
+
+// UpdateWebhookDelivery replaces the stored delivery having the same id.
+// Can return entities.ErrRecordNotExist when delivery with given id does not exist.
+func (r *WebhookInMemoryRepository) UpdateWebhookDelivery(ctx context.Context, delivery entities.WebhookDelivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.deliveries[delivery.ID]; !ok {
+		return entities.ErrRecordNotExist
+	}
+	r.deliveries[delivery.ID] = cloneDelivery(delivery)
+	return nil
+}
+
+// GetDueWebhookDeliveries returns up to limit pending deliveries whose next attempt is due at now, oldest first.
+func (r *WebhookInMemoryRepository) GetDueWebhookDeliveries(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]entities.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var due []entities.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == entities.WebhookDeliveryPending &&
+			delivery.NextAttemptAt != nil &&
+			!delivery.NextAttemptAt.After(now) {
+			due = append(due, cloneDelivery(delivery))
+		}
+	}
+	sortDeliveries(due)
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+// GetWebhookDeliveriesByPartner returns the deliveries to a partner, newest first.
+func (r *WebhookInMemoryRepository) GetWebhookDeliveriesByPartner(
+	ctx context.Context,
+	partnerID string,
+) ([]entities.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	deliveries := []entities.WebhookDelivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.PartnerID == partnerID {
+			deliveries = append(deliveries, cloneDelivery(delivery))
+		}
+	}
+	sortDeliveries(deliveries)
+	for i, j := 0, len(deliveries)-1; i < j; i, j = i+1, j-1 {
+		deliveries[i], deliveries[j] = deliveries[j], deliveries[i]
+	}
+	return deliveries, nil
+}
+
+// DeleteWebhooksByPartner removes the subscriptions of a partner and the deliveries to them.
+func (r *WebhookInMemoryRepository) DeleteWebhooksByPartner(ctx context.Context, partnerID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subscriptions := r.subscriptions[:0]
+	for _, subscription := range r.subscriptions {
+		if subscription.PartnerID != partnerID {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	r.subscriptions = subscriptions
+	for id, delivery := range r.deliveries {
+		if delivery.PartnerID == partnerID {
+			delete(r.deliveries, id)
+		}
+	}
+	return nil
+}
+
+// sortDeliveries sorts deliveries by creation time, oldest first. Deliveries created at the same time are sorted by
+// id to keep the order stable.
+func sortDeliveries(deliveries []entities.WebhookDelivery) {
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].CreatedAt.Equal(deliveries[j].CreatedAt) {
+			return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+		}
+		return deliveries[i].ID < deliveries[j].ID
+	})
+}
+
+func cloneSubscription(s entities.WebhookSubscription) entities.WebhookSubscription {
+	s.EventTypes = append([]string(nil), s.EventTypes...)
+	return s
+}
+
+func cloneDelivery(d entities.WebhookDelivery) entities.WebhookDelivery {
+	d.Attempts = append([]entities.WebhookAttempt{}, d.Attempts...)
+	if d.NextAttemptAt != nil {
+		next := *d.NextAttemptAt
+		d.NextAttemptAt = &next
+	}
+	d.Event.Data = append([]byte(nil), d.Event.Data...)
+	return d
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookInMemoryRepository_Deliveries(t *testing.T) {
+	ctx := context.Background()
+	repo := NewWebhookInMemoryRepository()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Minute)
+	first := entities.WebhookDelivery{
+		ID:            "d1",
+		PartnerID:     "1",
+		Status:        entities.WebhookDeliveryPending,
+		Attempts:      []entities.WebhookAttempt{},
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+	}
+	second := entities.WebhookDelivery{
+		ID:            "d2",
+		PartnerID:     "1",
+		Status:        entities.WebhookDeliveryPending,
+		Attempts:      []entities.WebhookAttempt{},
+		NextAttemptAt: &later,
+		CreatedAt:     now.Add(time.Second),
+	}
+	require.NoError(t, repo.CreateWebhookDelivery(ctx, first))
+	require.NoError(t, repo.CreateWebhookDelivery(ctx, second))
+	require.NoError(t, repo.CreateWebhookDelivery(ctx, entities.WebhookDelivery{ID: "d1", Status: "modified"}),
+		"creating a delivery twice keeps the first one")
+
+	due, err := repo.GetDueWebhookDeliveries(ctx, now, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.WebhookDelivery{first}, due)
+
+	due, err = repo.GetDueWebhookDeliveries(ctx, later, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.WebhookDelivery{first}, due, "limit returns the oldest deliveries")
+
+	first.Status = entities.WebhookDeliveryDelivered
+	first.NextAttemptAt = nil
+	first.Attempts = []entities.WebhookAttempt{{At: now, StatusCode: 200}}
+	require.NoError(t, repo.UpdateWebhookDelivery(ctx, first))
+	assert.ErrorIs(t, repo.UpdateWebhookDelivery(ctx, entities.WebhookDelivery{ID: "d3"}), entities.ErrRecordNotExist)
+
+	deliveries, err := repo.GetWebhookDeliveriesByPartner(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, []entities.WebhookDelivery{second, first}, deliveries, "deliveries are returned newest first")
+
+	deliveries[1].Attempts[0].StatusCode = 500
+	deliveries, err = repo.GetWebhookDeliveriesByPartner(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, 200, deliveries[1].Attempts[0].StatusCode, "stored deliveries are not modifiable through results")
+
+	deliveries, err = repo.GetWebhookDeliveriesByPartner(ctx, "2")
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+}
+
+func TestWebhookInMemoryRepository_DeleteWebhooksByPartner(t *testing.T) {
+	ctx := context.Background()
+	repo := NewWebhookInMemoryRepository()
+	for _, partnerID := range []string{"1", "2"} {
+		require.NoError(t, repo.CreateWebhookSubscription(ctx, entities.WebhookSubscription{
+			ID:         "s" + partnerID,
+			PartnerID:  partnerID,
+			EventTypes: []string{entities.EventPartnerUpdated},
+		}))
+		require.NoError(t, repo.CreateWebhookDelivery(ctx, entities.WebhookDelivery{
+			ID:             "d" + partnerID,
+			SubscriptionID: "s" + partnerID,
+			PartnerID:      partnerID,
+			Attempts:       []entities.WebhookAttempt{},
+		}))
+	}
+
+	require.NoError(t, repo.DeleteWebhooksByPartner(ctx, "1"))
+
+	subscriptions, err := repo.GetWebhookSubscriptionsByPartner(ctx, "1")
+	require.NoError(t, err)
+	assert.Empty(t, subscriptions)
+	deliveries, err := repo.GetWebhookDeliveriesByPartner(ctx, "1")
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+	subscriptions, err = repo.GetWebhookSubscriptionsByPartner(ctx, "2")
+	require.NoError(t, err)
+	assert.Len(t, subscriptions, 1, "subscriptions of other partners are kept")
+	deliveries, err = repo.GetWebhookDeliveriesByPartner(ctx, "2")
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 1, "deliveries to other partners are kept")
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"customer-partner/internal/entities"
+	"sync"
+	"time"
+
+	// The time zones of partners are loaded from the embedded database, so that they work on hosts without one.
+	_ "time/tzdata"
+)
+
+// availabilityHorizon is the number of days searched for the next availability of a partner.
+const availabilityHorizon = 366
+
+// locations caches the loaded time zones by name.
+var locations sync.Map
+
+// loadLocation returns the time zone with the IANA name, entities.DefaultTimeZone for an empty name.
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		name = entities.DefaultTimeZone
+	}
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// NextAvailable returns the earliest time at or after from at which the partner is open, in the time zone of the
+// partner. Partners without opening hours are available at from. It reports false when the partner is not open within
+// availabilityHorizon days.
+func NextAvailable(p entities.Partner, from time.Time) (time.Time, bool) {
+	if len(p.OpeningHours) == 0 {
+		return from, true
+	}
+	loc, err := loadLocation(p.TimeZone)
+	if err != nil {
+		return time.Time{}, false
+	}
+	holidays := make(map[string]bool, len(p.Holidays))
+	for _, holiday := range p.Holidays {
+		holidays[holiday] = true
+	}
+	year, month, day := from.In(loc).Date()
+	for i := 0; i < availabilityHorizon; i++ {
+		date := time.Date(year, month, day+i, 0, 0, 0, 0, loc)
+		if holidays[date.Format(entities.DateLayout)] {
+			continue
+		}
+		weekday := weekdayName(date.Weekday())
+		var next time.Time
+		for _, hours := range p.OpeningHours {
+			if hours.Weekday != weekday {
+				continue
+			}
+			opens, closes := clockOn(date, hours.Opens), clockOn(date, hours.Closes)
+			if !closes.After(from) {
+				continue
+			}
+			if opens.Before(from) {
+				opens = from.In(loc)
+			}
+			if next.IsZero() || opens.Before(next) {
+				next = opens
+			}
+		}
+		if !next.IsZero() {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// setAvailability sets whether the partner is open at now and when they are available next.
+func setAvailability(p *entities.Partner, now time.Time) {
+	next, ok := NextAvailable(*p, now)
+	open := ok && next.Equal(now)
+	p.OpenNow = &open
+	p.NextAvailableAt = nil
+	if ok {
+		p.NextAvailableAt = &next
+	}
+}
+
+// filterByAvailability keeps the matches which can start a job at or after from and before until.
+func filterByAvailability(matches []match, from, until time.Time) []match {
+	var available []match
+	for _, m := range matches {
+		if next, ok := NextAvailable(m.partner, from); ok && next.Before(until) {
+			available = append(available, m)
+		}
+	}
+	return available
+}
+
+// weekdayName returns the name of the weekday as used in entities.Weekdays.
+func weekdayName(d time.Weekday) string {
+	// entities.Weekdays starts on Monday, time.Weekday on Sunday.
+	return entities.Weekdays[(int(d)+6)%7]
+}
+
+// clockOn returns the time of the clock, e.g. "08:30", on the date. "24:00" is midnight at the end of the date.
+func clockOn(date time.Time, clock string) time.Time {
+	hour, minute, _ := parseClock(clock)
+	year, month, day := date.Date()
+	return time.Date(year, month, day, hour, minute, 0, 0, date.Location())
+}
+
+// parseClock parses a time of day formatted as "15:04" and reports whether it is valid. "24:00" is valid to end a
+// period at midnight.
+func parseClock(clock string) (hour, minute int, ok bool) {
+	if len(clock) != 5 || clock[2] != ':' {
+		return 0, 0, false
+	}
+	for _, i := range []int{0, 1, 3, 4} {
+		if clock[i] < '0' || clock[i] > '9' {
+			return 0, 0, false
+		}
+	}
+	hour = int(clock[0]-'0')*10 + int(clock[1]-'0')
+	minute = int(clock[3]-'0')*10 + int(clock[4]-'0')
+	if minute > 59 || hour > 24 || hour == 24 && minute != 0 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
@@ -0,0 +1,221 @@
+package domain_test
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/domain/mocks"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// workshop is open on weekdays and Saturday mornings in Berlin and closed on Ascension Day, Thursday 2024-05-09.
+var workshop = entities.Partner{
+	ID:              "workshop",
+	Address:         entities.Address{Latitude: 48.1360, Longitude: 11.6875},
+	OperatingRadius: 50,
+	TimeZone:        "Europe/Berlin",
+	OpeningHours: []entities.OpeningHours{
+		{Weekday: "monday", Opens: "08:00", Closes: "17:00"},
+		{Weekday: "tuesday", Opens: "08:00", Closes: "17:00"},
+		{Weekday: "wednesday", Opens: "08:00", Closes: "17:00"},
+		{Weekday: "thursday", Opens: "08:00", Closes: "17:00"},
+		{Weekday: "friday", Opens: "08:00", Closes: "12:00"},
+		{Weekday: "friday", Opens: "13:00", Closes: "17:00"},
+		{Weekday: "saturday", Opens: "09:00", Closes: "13:00"},
+	},
+	Holidays: []string{"2024-05-09"},
+}
+
+func TestNextAvailable(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	nightShift := entities.Partner{
+		TimeZone:     "America/New_York",
+		OpeningHours: []entities.OpeningHours{{Weekday: "monday", Opens: "20:00", Closes: "24:00"}},
+	}
+	type testCase struct {
+		name    string
+		partner entities.Partner
+		from    time.Time
+		expNext time.Time
+	}
+	tests := []testCase{
+		{
+			name:    "Returns from for partner without opening hours",
+			partner: entities.Partner{},
+			from:    time.Date(2024, 5, 5, 3, 0, 0, 0, time.UTC),
+			expNext: time.Date(2024, 5, 5, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Returns from while open",
+			partner: workshop,
+			from:    time.Date(2024, 5, 6, 10, 0, 0, 0, berlin),
+			expNext: time.Date(2024, 5, 6, 10, 0, 0, 0, berlin),
+		},
+		{
+			name:    "Returns opening time later the same day",
+			partner: workshop,
+			from:    time.Date(2024, 5, 6, 5, 0, 0, 0, time.UTC),
+			expNext: time.Date(2024, 5, 6, 8, 0, 0, 0, berlin),
+		},
+		{
+			name:    "Returns next period after lunch break",
+			partner: workshop,
+			from:    time.Date(2024, 5, 10, 12, 30, 0, 0, berlin),
+			expNext: time.Date(2024, 5, 10, 13, 0, 0, 0, berlin),
+		},
+		{
+			name:    "Returns next day at closing time",
+			partner: workshop,
+			from:    time.Date(2024, 5, 6, 17, 0, 0, 0, berlin),
+			expNext: time.Date(2024, 5, 7, 8, 0, 0, 0, berlin),
+		},
+		{
+			name:    "Skips holidays",
+			partner: workshop,
+			from:    time.Date(2024, 5, 8, 18, 0, 0, 0, berlin),
+			expNext: time.Date(2024, 5, 10, 8, 0, 0, 0, berlin),
+		},
+		{
+			name:    "Skips days without opening hours",
+			partner: workshop,
+			from:    time.Date(2024, 5, 11, 14, 0, 0, 0, berlin),
+			expNext: time.Date(2024, 5, 13, 8, 0, 0, 0, berlin),
+		},
+		{
+			name:    "Uses the time zone of the partner",
+			partner: nightShift,
+			from:    time.Date(2024, 5, 7, 1, 0, 0, 0, time.UTC),
+			expNext: time.Date(2024, 5, 6, 21, 0, 0, 0, newYork),
+		},
+		{
+			name:    "Keeps periods open until midnight",
+			partner: nightShift,
+			from:    time.Date(2024, 5, 6, 23, 59, 0, 0, newYork),
+			expNext: time.Date(2024, 5, 6, 23, 59, 0, 0, newYork),
+		},
+		{
+			name:    "Handles the change to daylight saving time",
+			partner: workshop,
+			from:    time.Date(2024, 3, 30, 14, 0, 0, 0, berlin),
+			expNext: time.Date(2024, 4, 1, 8, 0, 0, 0, berlin),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			next, ok := domain.NextAvailable(tt.partner, tt.from)
+
+			require.True(t, ok)
+			assert.True(t, tt.expNext.Equal(next), "expected %s, got %s", tt.expNext, next)
+		})
+	}
+
+	t.Run("Reports false when closed for a year", func(t *testing.T) {
+		closed := entities.Partner{OpeningHours: []entities.OpeningHours{{Weekday: "monday", Opens: "08:00", Closes: "17:00"}}}
+		for day := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC); day.Year() < 2026; day = day.AddDate(0, 0, 7) {
+			closed.Holidays = append(closed.Holidays, day.Format(entities.DateLayout))
+		}
+
+		_, ok := domain.NextAvailable(closed, time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC))
+
+		assert.False(t, ok)
+	})
+}
+
+func TestPartnerService_GetPartners_Availability(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// Saturday afternoon, the workshop is closed until Monday
+	now := time.Date(2024, 5, 11, 14, 0, 0, 0, berlin)
+	anytime := entities.Partner{
+		ID:              "anytime",
+		Address:         entities.Address{Latitude: 48.1360, Longitude: 11.6875},
+		OperatingRadius: 50,
+	}
+	type testCase struct {
+		name   string
+		opts   domain.GetPartnersOpts
+		expIDs []string
+	}
+	tests := []testCase{
+		{
+			name:   "Returns all partners without window",
+			opts:   domain.GetPartnersOpts{},
+			expIDs: []string{"workshop", "anytime"},
+		},
+		{
+			name:   "Filters partners closed in window",
+			opts:   domain.GetPartnersOpts{AvailableUntil: now.Add(24 * time.Hour)},
+			expIDs: []string{"anytime"},
+		},
+		{
+			name: "Returns partners opening in window",
+			opts: domain.GetPartnersOpts{
+				AvailableFrom:  time.Date(2024, 5, 13, 7, 0, 0, 0, berlin),
+				AvailableUntil: time.Date(2024, 5, 13, 9, 0, 0, 0, berlin),
+			},
+			expIDs: []string{"workshop", "anytime"},
+		},
+		{
+			name: "Starts window at now",
+			opts: domain.GetPartnersOpts{
+				AvailableFrom:  time.Date(2024, 5, 11, 10, 0, 0, 0, berlin),
+				AvailableUntil: time.Date(2024, 5, 11, 16, 0, 0, 0, berlin),
+			},
+			expIDs: []string{"anytime"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.PartnerRepository{}
+			repo.On("GetPartnersByMaterial", mock.Anything, "wood").Return(vetted(workshop, anytime), nil)
+			service := domain.NewPartnerService(repo, logging.Discard()).WithClock(func() time.Time { return now })
+			tt.opts.Material = "wood"
+			tt.opts.CustomerAddressLat, tt.opts.CustomerAddressLong = 48.1351, 11.5820
+
+			actual, err := service.GetPartners(context.Background(), tt.opts)
+
+			require.NoError(t, err)
+			var ids []string
+			for _, partner := range actual {
+				ids = append(ids, partner.ID)
+				require.NotNil(t, partner.OpenNow)
+				require.NotNil(t, partner.NextAvailableAt)
+				switch partner.ID {
+				case "workshop":
+					assert.False(t, *partner.OpenNow)
+					assert.True(t, time.Date(2024, 5, 13, 8, 0, 0, 0, berlin).Equal(*partner.NextAvailableAt))
+				case "anytime":
+					assert.True(t, *partner.OpenNow)
+					assert.True(t, now.Equal(*partner.NextAvailableAt))
+				}
+			}
+			assert.ElementsMatch(t, tt.expIDs, ids)
+		})
+	}
+}
+
+func TestPartnerService_GetPartner_Availability(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	repo := &mocks.PartnerRepository{}
+	repo.On("GetPartnerByID", mock.Anything, "workshop").Return(workshop, nil)
+	service := domain.NewPartnerService(repo, logging.Discard()).
+		WithClock(func() time.Time { return time.Date(2024, 5, 6, 9, 0, 0, 0, berlin) })
+
+	partner, err := service.GetPartner(context.Background(), "workshop")
+
+	require.NoError(t, err)
+	require.NotNil(t, partner.OpenNow)
+	assert.True(t, *partner.OpenNow)
+}
@@ -0,0 +1,45 @@
+package domain_test
+
+import (
+	"context"
+	"customer-partner/internal/db"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/logging"
+	"customer-partner/internal/seed"
+	"fmt"
+	"testing"
+)
+
+// benchmarkSizes are the numbers of partners the matching is benchmarked with, up to beyond the real dataset.
+var benchmarkSizes = []int{1000, 10000, 100000}
+
+// BenchmarkPartnerService_GetPartners matches generated customers against generated partners in the in-memory
+// repository, so that the benchmark covers the whole matching path below the api.
+func BenchmarkPartnerService_GetPartners(b *testing.B) {
+	customers := seed.Customers(seed.Options{Count: 1000, Seed: 2})
+	for _, size := range benchmarkSizes {
+		repo := db.NewPartnerInMemoryRepositoryFrom(seed.Partners(seed.Options{Count: size, Seed: 1}), nil)
+		service := domain.NewPartnerService(repo, logging.Discard())
+		for _, sortBy := range domain.SortOrders {
+			sortBy := sortBy
+			b.Run(fmt.Sprintf("partners=%d/sort=%s", size, sortBy), func(b *testing.B) {
+				ctx := context.Background()
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					customer := customers[i%len(customers)]
+					_, err := service.GetPartners(ctx, domain.GetPartnersOpts{
+						Material:            customer.Material,
+						CustomerAddressLat:  customer.Latitude,
+						CustomerAddressLong: customer.Longitude,
+						FloorSize:           40,
+						SortBy:              sortBy,
+					})
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
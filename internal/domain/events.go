@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"encoding/json"
+	"time"
+)
+
+// Outbox defines an interface which a persistence storage for events must provide. Repositories add the events of a
+// write to the outbox in the same transaction as the write, the relay reads and publishes them.
+type Outbox interface {
+	// GetPendingEvents returns up to limit events which are not published yet, oldest first.
+	GetPendingEvents(ctx context.Context, limit int) ([]entities.Event, error)
+	// MarkEventsPublished removes the events with the given ids from the pending events.
+	MarkEventsPublished(ctx context.Context, ids []string) error
+	// DeletePendingEvents removes the events of the given types about the aggregate which are not published yet.
+	DeletePendingEvents(ctx context.Context, aggregateID string, types ...string) error
+}
+
+// PartnerDeleted is the data of entities.EventPartnerDeleted events. PartnerCreated and PartnerUpdated events carry
+// the partner.
+type PartnerDeleted struct {
+	ID string `json:"id"`
+}
+
+// OfferRequested is the data of entities.EventOfferRequested events. The contact data of the customer does not leave
+// the service with events; consumers fetch it from the api while it is retained.
+type OfferRequested struct {
+	ID        string    `json:"id"`
+	PartnerID string    `json:"partner_id"`
+	FloorSize float64   `json:"floor_size"`
+	LeadID    string    `json:"lead_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OfferResponded is the data of entities.EventOfferResponded events.
+type OfferResponded struct {
+	ID          string    `json:"id"`
+	PartnerID   string    `json:"partner_id"`
+	LeadID      string    `json:"lead_id,omitempty"`
+	Response    string    `json:"response"`
+	RespondedAt time.Time `json:"responded_at"`
+}
+
+// QuoteCreated is the data of entities.EventQuoteCreated events.
+type QuoteCreated struct {
+	ID             string    `json:"id"`
+	OfferRequestID string    `json:"offer_request_id"`
+	PartnerID      string    `json:"partner_id"`
+	Currency       string    `json:"currency"`
+	TotalAmount    int       `json:"total_amount"`
+	ValidUntil     time.Time `json:"valid_until"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// QuoteDecided is the data of entities.EventQuoteAccepted and entities.EventQuoteDeclined events.
+type QuoteDecided struct {
+	ID             string    `json:"id"`
+	OfferRequestID string    `json:"offer_request_id"`
+	PartnerID      string    `json:"partner_id"`
+	Status         string    `json:"status"`
+	DecidedAt      time.Time `json:"decided_at"`
+}
+
+// CertificationExpiring is the data of entities.EventCertificationExpiring events.
+type CertificationExpiring struct {
+	PartnerID string    `json:"partner_id"`
+	Type      string    `json:"type"`
+	Issuer    string    `json:"issuer"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// newEvent returns an event of the given type about the aggregate with data encoded as json.
+func newEvent(eventType, aggregateID string, data any) (entities.Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return entities.Event{}, err
+	}
+	return entities.Event{
+		ID:          newID(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		OccurredAt:  time.Now().UTC(),
+		Data:        raw,
+	}, nil
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Reasons why a partner experienced in the material is not matched.
+const (
+	ExcludedInactive    = "inactive"
+	ExcludedUnvetted    = "unvetted"
+	ExcludedOutOfRadius = "out_of_radius"
+	ExcludedUnavailable = "unavailable"
+)
+
+// MatchExplanation tells how a partner experienced in the material of a match was ranked, or why they were excluded.
+type MatchExplanation struct {
+	// Partner is the partner as matched, with availability and price estimate, when they are ranked.
+	Partner entities.Partner `json:"partner"`
+	// Rank is the position of the partner in the match starting at 1, 0 when they are excluded.
+	Rank int `json:"rank"`
+	// Distance is the distance of the partner to the customer in kilometers.
+	Distance float64 `json:"distance"`
+	// Excluded is one of the Excluded reasons, empty when the partner is ranked.
+	Excluded string `json:"excluded,omitempty"`
+}
+
+// ExplainMatch runs the match of GetPartners and explains it for every partner experienced in the material: the ranked
+// partners in their order, then the excluded partners by distance. It is meant for admins looking into complaints
+// about the ranking.
+// Returns the context error when ctx is done before the match is complete.
+func (s *PartnerService) ExplainMatch(ctx context.Context, opts GetPartnersOpts) ([]MatchExplanation, error) {
+	ctx, span := tracer.Start(ctx, "PartnerService.ExplainMatch", trace.WithAttributes(
+		attribute.String("partner.material", opts.Material),
+	))
+	defer span.End()
+	ranked, err := s.GetPartners(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := s.repository.GetPartnersByMaterial(ctx, opts.Material)
+	if err != nil {
+		return nil, err
+	}
+	ranks := make(map[string]int, len(ranked))
+	for i, partner := range ranked {
+		ranks[partner.ID] = i + 1
+	}
+	now := s.now()
+	explanations := make([]MatchExplanation, 0, len(candidates))
+	for _, candidate := range candidates {
+		explanation := MatchExplanation{
+			Partner: candidate,
+			Rank:    ranks[candidate.ID],
+			Distance: distance(
+				opts.CustomerAddressLat,
+				opts.CustomerAddressLong,
+				candidate.Address.Latitude,
+				candidate.Address.Longitude,
+				"K",
+			),
+		}
+		switch {
+		case explanation.Rank > 0:
+			explanation.Partner = ranked[explanation.Rank-1]
+		case !opts.IncludeInactive && !IsActive(candidate):
+			explanation.Excluded = ExcludedInactive
+		case !opts.IncludeUnverified && !IsVetted(candidate, now):
+			explanation.Excluded = ExcludedUnvetted
+		case explanation.Distance >= float64(candidate.OperatingRadius):
+			explanation.Excluded = ExcludedOutOfRadius
+		default:
+			explanation.Excluded = ExcludedUnavailable
+		}
+		explanations = append(explanations, explanation)
+	}
+	sort.SliceStable(explanations, func(i, j int) bool {
+		a, b := explanations[i], explanations[j]
+		if (a.Rank > 0) != (b.Rank > 0) {
+			return a.Rank > 0
+		}
+		if a.Rank > 0 {
+			return a.Rank < b.Rank
+		}
+		return a.Distance < b.Distance
+	})
+	return explanations, nil
+}
@@ -0,0 +1,97 @@
+package domain_test
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/domain/mocks"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartnerService_ExplainMatch(t *testing.T) {
+	// Sunday, the workshop is closed all day.
+	now := time.Date(2024, 5, 5, 10, 0, 0, 0, time.UTC)
+	suspendedAt := now.Add(-time.Hour)
+	nearby := entities.Address{Latitude: 48.1360, Longitude: 11.6875}
+	best := entities.Partner{ID: "best", Address: nearby, OperatingRadius: 50, Rating: 5}
+	second := entities.Partner{ID: "second", Address: nearby, OperatingRadius: 50, Rating: 3}
+	suspended := entities.Partner{ID: "suspended", Address: nearby, OperatingRadius: 50, SuspendedAt: &suspendedAt}
+	farAway := entities.Partner{
+		ID:              "far-away",
+		Address:         entities.Address{Latitude: 52.5200, Longitude: 13.4050},
+		OperatingRadius: 50,
+	}
+	unverified := entities.Partner{ID: "unverified", Address: nearby, OperatingRadius: 50}
+	candidates := append(vetted(farAway, workshop, second, suspended, best), unverified)
+	opts := domain.GetPartnersOpts{
+		Material:            "wood",
+		CustomerAddressLat:  48.1372,
+		CustomerAddressLong: 11.5756,
+		AvailableUntil:      now.Add(10 * time.Hour),
+	}
+
+	t.Run("Explains ranks and exclusions", func(t *testing.T) {
+		repo := &mocks.PartnerRepository{}
+		repo.On("GetPartnersByMaterial", mock.Anything, "wood").Return(candidates, nil)
+		service := domain.NewPartnerService(repo, logging.Discard()).WithClock(func() time.Time { return now })
+
+		explanations, err := service.ExplainMatch(context.Background(), opts)
+
+		require.NoError(t, err)
+		ids := make([]string, 0, len(explanations))
+		for _, explanation := range explanations {
+			ids = append(ids, explanation.Partner.ID)
+		}
+		assert.Equal(t, []string{"best", "second", "workshop", "suspended", "unverified", "far-away"}, ids)
+		assert.Equal(t, 1, explanations[0].Rank)
+		assert.Empty(t, explanations[0].Excluded)
+		assert.NotNil(t, explanations[0].Partner.OpenNow, "ranked partners must be explained as matched")
+		assert.Equal(t, 2, explanations[1].Rank)
+		for i, expExcluded := range []string{
+			domain.ExcludedUnavailable,
+			domain.ExcludedInactive,
+			domain.ExcludedUnvetted,
+			domain.ExcludedOutOfRadius,
+		} {
+			assert.Zero(t, explanations[i+2].Rank)
+			assert.Equal(t, expExcluded, explanations[i+2].Excluded, explanations[i+2].Partner.ID)
+		}
+		assert.InDelta(t, 8.3, explanations[0].Distance, 0.1)
+		assert.Greater(t, explanations[5].Distance, 500.0)
+	})
+
+	t.Run("Ranks the partners included by the options", func(t *testing.T) {
+		repo := &mocks.PartnerRepository{}
+		repo.On("GetPartnersByMaterial", mock.Anything, "wood").Return(candidates, nil)
+		service := domain.NewPartnerService(repo, logging.Discard()).WithClock(func() time.Time { return now })
+		opts := opts
+		opts.IncludeInactive, opts.IncludeUnverified = true, true
+
+		explanations, err := service.ExplainMatch(context.Background(), opts)
+
+		require.NoError(t, err)
+		for _, explanation := range explanations {
+			if explanation.Partner.ID == "suspended" || explanation.Partner.ID == "unverified" {
+				assert.NotZero(t, explanation.Rank, explanation.Partner.ID)
+			}
+		}
+	})
+
+	t.Run("Returns error of repository", func(t *testing.T) {
+		repoErr := errors.New("unavailable")
+		repo := &mocks.PartnerRepository{}
+		repo.On("GetPartnersByMaterial", mock.Anything, "wood").Return(nil, repoErr)
+		service := domain.NewPartnerService(repo, logging.Discard())
+
+		_, err := service.ExplainMatch(context.Background(), opts)
+
+		assert.ErrorIs(t, err, repoErr)
+	})
+}
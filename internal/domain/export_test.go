@@ -0,0 +1,4 @@
+package domain
+
+// Distance exposes distance to the external tests, which check the ranking of matches by it.
+var Distance = distance
@@ -0,0 +1,280 @@
+package domain
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"customer-partner/internal/privacy"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DataRequestRepository defines an interface which a persistence storage for the audit records of data subject
+// requests must provide.
+type DataRequestRepository interface {
+	CreateDataRequest(ctx context.Context, request entities.DataRequest) error
+	// GetDataRequests returns all audit records, oldest first.
+	GetDataRequests(ctx context.Context) ([]entities.DataRequest, error)
+}
+
+// DataSubject identifies the person a data subject request is about: a customer by phone number, email address or
+// the id of their account, or a partner by id. At least one attribute is required.
+type DataSubject struct {
+	Phone      string
+	Email      string
+	CustomerID string
+	PartnerID  string
+}
+
+// String describes the subject for the audit log. Contact data is masked.
+func (s DataSubject) String() string {
+	var parts []string
+	if s.Phone != "" {
+		parts = append(parts, "phone:"+privacy.MaskPhone(s.Phone))
+	}
+	if s.Email != "" {
+		parts = append(parts, "email:"+privacy.MaskEmail(s.Email))
+	}
+	if s.CustomerID != "" {
+		parts = append(parts, "customer:"+s.CustomerID)
+	}
+	if s.PartnerID != "" {
+		parts = append(parts, "partner:"+s.PartnerID)
+	}
+	return strings.Join(parts, ",")
+}
+
+// DataExport bundles all data the service holds about a data subject. Reviews are not part of it, since the service
+// only stores the aggregated rating of partners.
+type DataExport struct {
+	ExportedAt    time.Time               `json:"exported_at"`
+	OfferRequests []entities.OfferRequest `json:"offer_requests"`
+	Partner       *entities.Partner       `json:"partner,omitempty"`
+}
+
+// DataErasure reports what was erased for a data subject.
+type DataErasure struct {
+	ErasedAt time.Time `json:"erased_at"`
+	// OfferRequests is the number of offer requests whose contact data and customer id were removed. The offer
+	// requests themselves are kept without personal data.
+	OfferRequests  int  `json:"offer_requests"`
+	PartnerDeleted bool `json:"partner_deleted"`
+}
+
+func NewDataRequestService(
+	offerRequests OfferRequestRepository,
+	partners PartnerRepository,
+	partnerChanges PartnerChangeRepository,
+	webhooks WebhookRepository,
+	outbox Outbox,
+	dataRequests DataRequestRepository,
+	logger *slog.Logger,
+) *DataRequestService {
+	return &DataRequestService{
+		offerRequests:  offerRequests,
+		partners:       partners,
+		partnerChanges: partnerChanges,
+		webhooks:       webhooks,
+		outbox:         outbox,
+		dataRequests:   dataRequests,
+		logger:         logger,
+	}
+}
+
+// DataRequestService handles data subject requests under the GDPR. Every export and erasure is recorded with the
+// actor who performed it.
+type DataRequestService struct {
+	offerRequests  OfferRequestRepository
+	partners       PartnerRepository
+	partnerChanges PartnerChangeRepository
+	webhooks       WebhookRepository
+	outbox         Outbox
+	dataRequests   DataRequestRepository
+	logger         *slog.Logger
+}
+
+// Export collects all data about the subject on behalf of actor.
+// Can return a *ValidationError when the subject is empty and entities.ErrRecordNotExist when the partner does not
+// exist.
+func (s *DataRequestService) Export(ctx context.Context, subject DataSubject, actor string) (DataExport, error) {
+	ctx, span := tracer.Start(ctx, "DataRequestService.Export", trace.WithAttributes(
+		attribute.String("partner.id", subject.PartnerID),
+	))
+	defer span.End()
+	if err := validateDataSubject(subject); err != nil {
+		return DataExport{}, err
+	}
+	export := DataExport{ExportedAt: time.Now().UTC().Truncate(time.Second), OfferRequests: []entities.OfferRequest{}}
+	if subject.PartnerID != "" {
+		partner, err := s.partners.GetPartnerByID(ctx, subject.PartnerID)
+		if err != nil {
+			return DataExport{}, err
+		}
+		export.Partner = &partner
+	}
+	requests, err := s.offerRequestsOf(ctx, subject)
+	if err != nil {
+		return DataExport{}, err
+	}
+	export.OfferRequests = requests
+	err = s.record(ctx, entities.DataRequest{
+		Action:        entities.DataRequestExport,
+		Subject:       subject.String(),
+		Actor:         actor,
+		PerformedAt:   export.ExportedAt,
+		OfferRequests: len(export.OfferRequests),
+		Partner:       export.Partner != nil,
+	})
+	if err != nil {
+		return DataExport{}, err
+	}
+	return export, nil
+}
+
+// Erase removes all personal data about the subject on behalf of actor: the contact data and customer id of the offer
+// requests of a customer, and a partner as described at erasePartner. Every step can be repeated, so that an erasure
+// which failed part way is completed by retrying it. Events about the partner which were published before cannot be
+// recalled; receivers learn about the erasure from the PartnerDeleted event and have to erase their copies.
+// Can return a *ValidationError when the subject is empty and entities.ErrRecordNotExist when the partner neither
+// exists nor existed.
+func (s *DataRequestService) Erase(ctx context.Context, subject DataSubject, actor string) (DataErasure, error) {
+	ctx, span := tracer.Start(ctx, "DataRequestService.Erase", trace.WithAttributes(
+		attribute.String("partner.id", subject.PartnerID),
+	))
+	defer span.End()
+	if err := validateDataSubject(subject); err != nil {
+		return DataErasure{}, err
+	}
+	erasure := DataErasure{ErasedAt: time.Now().UTC().Truncate(time.Second)}
+	if subject.PartnerID != "" {
+		if err := s.erasePartner(ctx, subject.PartnerID); err != nil {
+			return DataErasure{}, err
+		}
+		erasure.PartnerDeleted = true
+	}
+	requests, err := s.offerRequestsOf(ctx, subject)
+	if err != nil {
+		return DataErasure{}, err
+	}
+	if len(requests) > 0 {
+		ids := make([]string, 0, len(requests))
+		for _, request := range requests {
+			ids = append(ids, request.ID)
+		}
+		if erasure.OfferRequests, err = s.offerRequests.AnonymizeOfferRequests(ctx, ids, erasure.ErasedAt); err != nil {
+			return DataErasure{}, err
+		}
+	}
+	err = s.record(ctx, entities.DataRequest{
+		Action:        entities.DataRequestErasure,
+		Subject:       subject.String(),
+		Actor:         actor,
+		PerformedAt:   erasure.ErasedAt,
+		OfferRequests: erasure.OfferRequests,
+		Partner:       erasure.PartnerDeleted,
+	})
+	if err != nil {
+		return DataErasure{}, err
+	}
+	return erasure, nil
+}
+
+// erasePartner deletes the partner and the personal data about them kept elsewhere: the values of their audit trail,
+// including the deletion itself, their events which are not published yet and carry the partner, and their webhook
+// subscriptions and deliveries, whose payloads carry the partner as well. A partner which is already deleted but has
+// an audit trail is erased again, so that an erasure which failed after the deletion can be retried.
+// Can return entities.ErrRecordNotExist when the partner neither exists nor existed.
+func (s *DataRequestService) erasePartner(ctx context.Context, id string) error {
+	event, err := newEvent(entities.EventPartnerDeleted, id, PartnerDeleted{ID: id})
+	if err != nil {
+		return err
+	}
+	if err := s.partners.DeletePartner(ctx, id, event); errors.Is(err, entities.ErrRecordNotExist) {
+		changes, historyErr := s.partnerChanges.GetPartnerChanges(ctx, id)
+		if historyErr != nil {
+			return historyErr
+		}
+		if len(changes) == 0 {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	err = s.outbox.DeletePendingEvents(ctx, id, entities.EventPartnerCreated, entities.EventPartnerUpdated)
+	if err != nil {
+		return err
+	}
+	if err := s.webhooks.DeleteWebhooksByPartner(ctx, id); err != nil {
+		return err
+	}
+	return s.partnerChanges.RedactPartnerChanges(ctx, id)
+}
+
+// offerRequestsOf returns the offer requests of the customer identified by the subject, matched by contact data or
+// customer id.
+func (s *DataRequestService) offerRequestsOf(
+	ctx context.Context,
+	subject DataSubject,
+) ([]entities.OfferRequest, error) {
+	requests := []entities.OfferRequest{}
+	if subject.Phone != "" || subject.Email != "" {
+		byContact, err := s.offerRequests.GetOfferRequestsByContact(ctx, subject.Phone, subject.Email)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, byContact...)
+	}
+	if subject.CustomerID != "" {
+		byCustomer, err := s.offerRequests.GetOfferRequestsByCustomer(ctx, subject.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+		found := make(map[string]bool, len(requests))
+		for _, request := range requests {
+			found[request.ID] = true
+		}
+		for _, request := range byCustomer {
+			if !found[request.ID] {
+				requests = append(requests, request)
+			}
+		}
+	}
+	return requests, nil
+}
+
+// GetDataRequests returns the audit records of all data subject requests, oldest first.
+func (s *DataRequestService) GetDataRequests(ctx context.Context) ([]entities.DataRequest, error) {
+	ctx, span := tracer.Start(ctx, "DataRequestService.GetDataRequests")
+	defer span.End()
+	return s.dataRequests.GetDataRequests(ctx)
+}
+
+// record stores the audit record of a performed data request. The action already happened, so a failure is logged
+// with the record to allow recording it by hand.
+func (s *DataRequestService) record(ctx context.Context, request entities.DataRequest) error {
+	request.ID = newID()
+	logger := logging.FromContextOr(ctx, s.logger).With(
+		"data_request_id", request.ID,
+		"action", request.Action,
+		"subject", request.Subject,
+		"actor", request.Actor,
+	)
+	if err := s.dataRequests.CreateDataRequest(ctx, request); err != nil {
+		logger.Error("recording data request failed", "error", err)
+		return err
+	}
+	logger.Info("data request performed")
+	return nil
+}
+
+func validateDataSubject(subject DataSubject) error {
+	if subject.Phone == "" && subject.Email == "" && subject.CustomerID == "" && subject.PartnerID == "" {
+		return &ValidationError{Field: "subject", Reason: "phone, email, customer_id or partner_id required"}
+	}
+	return nil
+}
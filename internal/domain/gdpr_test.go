@@ -0,0 +1,219 @@
+package domain_test
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/domain/mocks"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockery --name DataRequestRepository
+//go:generate mockery --name Outbox
+
+func TestDataRequestService_Export(t *testing.T) {
+	request := entities.OfferRequest{ID: "abc", PartnerID: "1", Phone: "+49 170 1234567"}
+	partner := entities.Partner{ID: "1", Name: "Floors"}
+
+	t.Run("Exports offer requests and partner and records the export", func(t *testing.T) {
+		offerRequests := &mocks.OfferRequestRepository{}
+		offerRequests.On("GetOfferRequestsByContact", mock.Anything, "+49 170 1234567", "").
+			Return([]entities.OfferRequest{request}, nil)
+		partners := &mocks.PartnerRepository{}
+		partners.On("GetPartnerByID", mock.Anything, "1").Return(partner, nil)
+		dataRequests := &mocks.DataRequestRepository{}
+		dataRequests.On("CreateDataRequest", mock.Anything, mock.MatchedBy(func(r entities.DataRequest) bool {
+			return r.ID != "" &&
+				r.Action == entities.DataRequestExport &&
+				r.Subject == "phone:+** *** *****67,partner:1" &&
+				r.Actor == "apikey:legal" &&
+				r.OfferRequests == 1 &&
+				r.Partner
+		})).Return(nil)
+		service := domain.NewDataRequestService(offerRequests, partners, nil, nil, nil, dataRequests, logging.Discard())
+		subject := domain.DataSubject{Phone: request.Phone, PartnerID: "1"}
+
+		export, err := service.Export(context.Background(), subject, "apikey:legal")
+
+		require.NoError(t, err)
+		assert.Equal(t, []entities.OfferRequest{request}, export.OfferRequests)
+		assert.Equal(t, &partner, export.Partner)
+		dataRequests.AssertExpectations(t)
+	})
+
+	t.Run("Exports offer requests of a customer account", func(t *testing.T) {
+		offerRequests := &mocks.OfferRequestRepository{}
+		offerRequests.On("GetOfferRequestsByCustomer", mock.Anything, "sub:jane").
+			Return([]entities.OfferRequest{request}, nil)
+		dataRequests := &mocks.DataRequestRepository{}
+		dataRequests.On("CreateDataRequest", mock.Anything, mock.MatchedBy(func(r entities.DataRequest) bool {
+			return r.Subject == "customer:sub:jane" && r.OfferRequests == 1 && !r.Partner
+		})).Return(nil)
+		service := domain.NewDataRequestService(offerRequests, nil, nil, nil, nil, dataRequests, logging.Discard())
+
+		export, err := service.Export(context.Background(), domain.DataSubject{CustomerID: "sub:jane"}, "apikey:legal")
+
+		require.NoError(t, err)
+		assert.Equal(t, []entities.OfferRequest{request}, export.OfferRequests)
+		dataRequests.AssertExpectations(t)
+	})
+
+	t.Run("Rejects empty subject", func(t *testing.T) {
+		service := domain.NewDataRequestService(nil, nil, nil, nil, nil, nil, logging.Discard())
+
+		_, err := service.Export(context.Background(), domain.DataSubject{}, "apikey:legal")
+
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("Returns ErrRecordNotExist for unknown partner without recording", func(t *testing.T) {
+		partners := &mocks.PartnerRepository{}
+		partners.On("GetPartnerByID", mock.Anything, "9").Return(entities.Partner{}, entities.ErrRecordNotExist)
+		dataRequests := &mocks.DataRequestRepository{}
+		service := domain.NewDataRequestService(nil, partners, nil, nil, nil, dataRequests, logging.Discard())
+
+		_, err := service.Export(context.Background(), domain.DataSubject{PartnerID: "9"}, "apikey:legal")
+
+		assert.ErrorIs(t, err, entities.ErrRecordNotExist)
+		dataRequests.AssertExpectations(t)
+	})
+}
+
+func TestDataRequestService_Erase(t *testing.T) {
+	// partnerMocks returns the repositories of an erasure of partner "1" whose steps all succeed.
+	partnerMocks := func(deleteErr error) (
+		*mocks.PartnerRepository,
+		*mocks.PartnerChangeRepository,
+		*mocks.WebhookRepository,
+		*mocks.Outbox,
+	) {
+		partners := &mocks.PartnerRepository{}
+		partners.On("DeletePartner", mock.Anything, "1", mock.MatchedBy(func(e entities.Event) bool {
+			return e.Type == entities.EventPartnerDeleted && e.AggregateID == "1" && string(e.Data) == `{"id":"1"}`
+		})).Return(deleteErr)
+		partnerChanges := &mocks.PartnerChangeRepository{}
+		partnerChanges.On("RedactPartnerChanges", mock.Anything, "1").Return(nil)
+		webhooks := &mocks.WebhookRepository{}
+		webhooks.On("DeleteWebhooksByPartner", mock.Anything, "1").Return(nil)
+		outbox := &mocks.Outbox{}
+		outbox.On("DeletePendingEvents", mock.Anything, "1", entities.EventPartnerCreated, entities.EventPartnerUpdated).
+			Return(nil)
+		return partners, partnerChanges, webhooks, outbox
+	}
+
+	t.Run("Anonymizes offer requests of the customer and records the erasure", func(t *testing.T) {
+		offerRequests := &mocks.OfferRequestRepository{}
+		offerRequests.On("GetOfferRequestsByContact", mock.Anything, "", "jane@example.com").
+			Return([]entities.OfferRequest{{ID: "abc"}, {ID: "bcd"}}, nil)
+		offerRequests.On("GetOfferRequestsByCustomer", mock.Anything, "sub:jane").
+			Return([]entities.OfferRequest{{ID: "bcd"}, {ID: "cde"}}, nil)
+		offerRequests.On("AnonymizeOfferRequests", mock.Anything, []string{"abc", "bcd", "cde"}, mock.Anything).
+			Return(3, nil)
+		dataRequests := &mocks.DataRequestRepository{}
+		dataRequests.On("CreateDataRequest", mock.Anything, mock.MatchedBy(func(r entities.DataRequest) bool {
+			return r.Action == entities.DataRequestErasure &&
+				r.Subject == "email:j***@example.com,customer:sub:jane" &&
+				r.OfferRequests == 3
+		})).Return(nil)
+		service := domain.NewDataRequestService(offerRequests, nil, nil, nil, nil, dataRequests, logging.Discard())
+		subject := domain.DataSubject{Email: "jane@example.com", CustomerID: "sub:jane"}
+
+		erasure, err := service.Erase(context.Background(), subject, "sub:admin")
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, erasure.OfferRequests)
+		assert.False(t, erasure.PartnerDeleted)
+		offerRequests.AssertExpectations(t)
+		dataRequests.AssertExpectations(t)
+	})
+
+	t.Run("Deletes partner with their webhooks, pending events and the values of their history", func(t *testing.T) {
+		partners, partnerChanges, webhooks, outbox := partnerMocks(nil)
+		dataRequests := &mocks.DataRequestRepository{}
+		dataRequests.On("CreateDataRequest", mock.Anything, mock.Anything).Return(nil)
+		service := domain.NewDataRequestService(
+			&mocks.OfferRequestRepository{}, partners, partnerChanges, webhooks, outbox, dataRequests, logging.Discard(),
+		)
+
+		erasure, err := service.Erase(context.Background(), domain.DataSubject{PartnerID: "1"}, "sub:admin")
+
+		require.NoError(t, err)
+		assert.True(t, erasure.PartnerDeleted)
+		partners.AssertExpectations(t)
+		partnerChanges.AssertExpectations(t)
+		webhooks.AssertExpectations(t)
+		outbox.AssertExpectations(t)
+		dataRequests.AssertExpectations(t)
+	})
+
+	t.Run("Completes the erasure of a partner which was deleted before", func(t *testing.T) {
+		partners, partnerChanges, webhooks, outbox := partnerMocks(entities.ErrRecordNotExist)
+		partnerChanges.On("GetPartnerChanges", mock.Anything, "1").
+			Return([]entities.PartnerChange{{ID: "c1", PartnerID: "1", Action: entities.PartnerDeleted}}, nil)
+		dataRequests := &mocks.DataRequestRepository{}
+		dataRequests.On("CreateDataRequest", mock.Anything, mock.Anything).Return(nil)
+		service := domain.NewDataRequestService(
+			&mocks.OfferRequestRepository{}, partners, partnerChanges, webhooks, outbox, dataRequests, logging.Discard(),
+		)
+
+		erasure, err := service.Erase(context.Background(), domain.DataSubject{PartnerID: "1"}, "sub:admin")
+
+		require.NoError(t, err)
+		assert.True(t, erasure.PartnerDeleted)
+		partnerChanges.AssertExpectations(t)
+		webhooks.AssertExpectations(t)
+		outbox.AssertExpectations(t)
+		dataRequests.AssertExpectations(t)
+	})
+
+	t.Run("Returns ErrRecordNotExist for unknown partner without recording", func(t *testing.T) {
+		partners, _, webhooks, outbox := partnerMocks(entities.ErrRecordNotExist)
+		partnerChanges := &mocks.PartnerChangeRepository{}
+		partnerChanges.On("GetPartnerChanges", mock.Anything, "1").Return([]entities.PartnerChange{}, nil)
+		dataRequests := &mocks.DataRequestRepository{}
+		service := domain.NewDataRequestService(
+			&mocks.OfferRequestRepository{}, partners, partnerChanges, webhooks, outbox, dataRequests, logging.Discard(),
+		)
+
+		_, err := service.Erase(context.Background(), domain.DataSubject{PartnerID: "1"}, "sub:admin")
+
+		assert.ErrorIs(t, err, entities.ErrRecordNotExist)
+		webhooks.AssertNotCalled(t, "DeleteWebhooksByPartner", mock.Anything, mock.Anything)
+		dataRequests.AssertExpectations(t)
+	})
+
+	t.Run("Returns error when the history cannot be redacted", func(t *testing.T) {
+		partners, _, webhooks, outbox := partnerMocks(nil)
+		partnerChanges := &mocks.PartnerChangeRepository{}
+		partnerChanges.On("RedactPartnerChanges", mock.Anything, "1").Return(errors.New("disk full"))
+		dataRequests := &mocks.DataRequestRepository{}
+		service := domain.NewDataRequestService(
+			&mocks.OfferRequestRepository{}, partners, partnerChanges, webhooks, outbox, dataRequests, logging.Discard(),
+		)
+
+		_, err := service.Erase(context.Background(), domain.DataSubject{PartnerID: "1"}, "sub:admin")
+
+		assert.EqualError(t, err, "disk full")
+		dataRequests.AssertExpectations(t)
+	})
+
+	t.Run("Returns error when the audit record cannot be stored", func(t *testing.T) {
+		partners, partnerChanges, webhooks, outbox := partnerMocks(nil)
+		dataRequests := &mocks.DataRequestRepository{}
+		dataRequests.On("CreateDataRequest", mock.Anything, mock.Anything).Return(errors.New("disk full"))
+		service := domain.NewDataRequestService(
+			&mocks.OfferRequestRepository{}, partners, partnerChanges, webhooks, outbox, dataRequests, logging.Discard(),
+		)
+
+		_, err := service.Erase(context.Background(), domain.DataSubject{PartnerID: "1"}, "sub:admin")
+
+		assert.EqualError(t, err, "disk full")
+	})
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func NewPartnerHistoryService(partners PartnerRepository, changes PartnerChangeRepository) *PartnerHistoryService {
+	return &PartnerHistoryService{partners: partners, changes: changes}
+}
+
+// PartnerHistoryService provides the audit trail of partners.
+type PartnerHistoryService struct {
+	partners PartnerRepository
+	changes  PartnerChangeRepository
+}
+
+// GetPartnerHistory returns the changes of a partner, oldest first. The history of deleted partners is kept.
+// Can return entities.ErrRecordNotExist when partner with given id neither exists nor has a history.
+func (s *PartnerHistoryService) GetPartnerHistory(ctx context.Context, id string) ([]entities.PartnerChange, error) {
+	ctx, span := tracer.Start(ctx, "PartnerHistoryService.GetPartnerHistory", trace.WithAttributes(
+		attribute.String("partner.id", id),
+	))
+	defer span.End()
+	changes, err := s.changes.GetPartnerChanges(ctx, id)
+	if err != nil || len(changes) > 0 {
+		return changes, err
+	}
+	if _, err := s.partners.GetPartnerByID(ctx, id); err != nil {
+		if errors.Is(err, entities.ErrRecordNotExist) {
+			return nil, entities.ErrRecordNotExist
+		}
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,72 @@
+package domain_test
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/domain/mocks"
+	"customer-partner/internal/entities"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+//go:generate mockery --name PartnerChangeRepository
+
+func TestPartnerHistoryService_GetPartnerHistory(t *testing.T) {
+	change := entities.PartnerChange{ID: "c1", PartnerID: "1", Action: entities.PartnerUpdated}
+	errStorage := errors.New("storage failed")
+	type testCase struct {
+		name       string
+		changes    []entities.PartnerChange
+		changesErr error
+		lookup     bool
+		partnerErr error
+		expChanges []entities.PartnerChange
+		expErr     error
+	}
+	tests := []testCase{
+		{
+			name:       "Returns changes without looking up partner, e.g. when it was deleted",
+			changes:    []entities.PartnerChange{change},
+			expChanges: []entities.PartnerChange{change},
+		},
+		{
+			name:       "Returns empty history of existing partner",
+			changes:    []entities.PartnerChange{},
+			lookup:     true,
+			expChanges: []entities.PartnerChange{},
+		},
+		{
+			name:       "Returns ErrRecordNotExist for unknown partner",
+			changes:    []entities.PartnerChange{},
+			lookup:     true,
+			partnerErr: entities.ErrRecordNotExist,
+			expErr:     entities.ErrRecordNotExist,
+		},
+		{
+			name:       "Returns error of change repository",
+			changesErr: errStorage,
+			expErr:     errStorage,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			changes := &mocks.PartnerChangeRepository{}
+			changes.On("GetPartnerChanges", mock.Anything, "1").Return(tt.changes, tt.changesErr)
+			partners := &mocks.PartnerRepository{}
+			if tt.lookup {
+				partners.On("GetPartnerByID", mock.Anything, "1").Return(entities.Partner{ID: "1"}, tt.partnerErr)
+			}
+			service := domain.NewPartnerHistoryService(partners, changes)
+
+			history, err := service.GetPartnerHistory(context.Background(), "1")
+
+			assert.ErrorIs(t, err, tt.expErr)
+			assert.Equal(t, tt.expChanges, history)
+			partners.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrNoPartnerAvailable is returned when no partner matches a lead or all matching partners are excluded.
+var ErrNoPartnerAvailable = errors.New("no partner available")
+
+// PartnerMatcher finds the partners matching a customer, best match first. It is implemented by PartnerService.
+type PartnerMatcher interface {
+	GetPartners(ctx context.Context, opts GetPartnersOpts) ([]entities.Partner, error)
+}
+
+// LeadRequest is the request of a customer for offers from the best matching partners.
+type LeadRequest struct {
+	GetPartnersOpts
+	FloorSize float64
+	Phone     string
+	Email     string
+	Language  string
+	// CustomerID is the subject of the customer with an account, empty for customers without account.
+	CustomerID string
+	// Partners is the number of partners the lead is dispatched to. It is limited by the maximum of the service,
+	// which is also used when Partners is 0.
+	Partners int
+}
+
+func NewLeadService(
+	repository OfferRequestRepository,
+	matcher PartnerMatcher,
+	maxPartners int,
+	logger *slog.Logger,
+) *LeadService {
+	return &LeadService{repository: repository, matcher: matcher, maxPartners: maxPartners, logger: logger, now: time.Now}
+}
+
+// LeadService dispatches requests of customers to the best matching partners. Partners who reached their daily lead
+// cap and partners who declined the customer before are left out.
+type LeadService struct {
+	// mu serializes the dispatch of leads, so that concurrent leads do not exceed the daily lead caps. The caps are
+	// only kept per instance of the service.
+	mu          sync.Mutex
+	repository  OfferRequestRepository
+	matcher     PartnerMatcher
+	maxPartners int
+	logger      *slog.Logger
+	now         func() time.Time
+}
+
+// CreateLead validates the request and creates an offer request for each of the best matching partners which are
+// available. The offer requests share the id of the lead.
+// Can return a *ValidationError when the request is invalid and ErrNoPartnerAvailable when no partner is available.
+func (s *LeadService) CreateLead(ctx context.Context, request LeadRequest) (entities.Lead, error) {
+	ctx, span := tracer.Start(ctx, "LeadService.CreateLead", trace.WithAttributes(
+		attribute.String("partner.material", request.Material),
+	))
+	defer span.End()
+	if err := s.validateLeadRequest(request); err != nil {
+		return entities.Lead{}, err
+	}
+	partners := request.Partners
+	if partners == 0 {
+		partners = s.maxPartners
+	}
+	matches, err := s.matcher.GetPartners(ctx, request.GetPartnersOpts)
+	if err != nil {
+		return entities.Lead{}, err
+	}
+	declined, err := s.declinedPartners(ctx, request.Phone, request.Email)
+	if err != nil {
+		return entities.Lead{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now().UTC()
+	lead := entities.Lead{ID: newID(), CreatedAt: now.Truncate(time.Second)}
+	var events []entities.Event
+	for _, partner := range matches {
+		if len(lead.OfferRequests) == partners {
+			break
+		}
+		if declined[partner.ID] {
+			continue
+		}
+		if partner.DailyLeadCap > 0 {
+			count, err := s.repository.CountLeadsByPartner(ctx, partner.ID, now.Truncate(24*time.Hour))
+			if err != nil {
+				return entities.Lead{}, err
+			}
+			if count >= partner.DailyLeadCap {
+				continue
+			}
+		}
+		offerRequest := entities.OfferRequest{
+			ID:         newID(),
+			PartnerID:  partner.ID,
+			FloorSize:  request.FloorSize,
+			Phone:      request.Phone,
+			Email:      request.Email,
+			Language:   request.Language,
+			CreatedAt:  lead.CreatedAt,
+			LeadID:     lead.ID,
+			CustomerID: request.CustomerID,
+		}
+		event, err := newEvent(entities.EventOfferRequested, offerRequest.ID, OfferRequested{
+			ID:        offerRequest.ID,
+			PartnerID: offerRequest.PartnerID,
+			FloorSize: offerRequest.FloorSize,
+			LeadID:    lead.ID,
+			CreatedAt: offerRequest.CreatedAt,
+		})
+		if err != nil {
+			return entities.Lead{}, err
+		}
+		lead.OfferRequests = append(lead.OfferRequests, offerRequest)
+		events = append(events, event)
+	}
+	span.SetAttributes(
+		attribute.Int("lead.matches", len(matches)),
+		attribute.Int("lead.partners", len(lead.OfferRequests)),
+	)
+	if len(lead.OfferRequests) == 0 {
+		return entities.Lead{}, ErrNoPartnerAvailable
+	}
+	if err := s.repository.CreateOfferRequests(ctx, lead.OfferRequests, events...); err != nil {
+		return entities.Lead{}, err
+	}
+	logging.FromContextOr(ctx, s.logger).Info("lead created",
+		"lead_id", lead.ID,
+		"matches", len(matches),
+		"partners", len(lead.OfferRequests),
+	)
+	return lead, nil
+}
+
+// GetLead finds a lead by its id.
+// Can return entities.ErrRecordNotExist when lead with given id does not exist.
+func (s *LeadService) GetLead(ctx context.Context, id string) (entities.Lead, error) {
+	ctx, span := tracer.Start(ctx, "LeadService.GetLead", trace.WithAttributes(attribute.String("lead.id", id)))
+	defer span.End()
+	requests, err := s.repository.GetOfferRequestsByLead(ctx, id)
+	if err != nil {
+		return entities.Lead{}, err
+	}
+	if len(requests) == 0 {
+		return entities.Lead{}, entities.ErrRecordNotExist
+	}
+	return entities.Lead{ID: id, CreatedAt: requests[0].CreatedAt, OfferRequests: requests}, nil
+}
+
+// declinedPartners returns the ids of the partners who declined an offer request of the customer before.
+func (s *LeadService) declinedPartners(ctx context.Context, phone, email string) (map[string]bool, error) {
+	requests, err := s.repository.GetOfferRequestsByContact(ctx, phone, email)
+	if err != nil {
+		return nil, err
+	}
+	declined := map[string]bool{}
+	for _, request := range requests {
+		if request.Response == entities.OfferRequestDeclined {
+			declined[request.PartnerID] = true
+		}
+	}
+	return declined, nil
+}
+
+// validateLeadRequest checks the request. It returns a *ValidationError for the first invalid attribute.
+func (s *LeadService) validateLeadRequest(r LeadRequest) error {
+	if !isMaterial(r.Material) {
+		return &ValidationError{Field: "material", Reason: fmt.Sprintf("unknown material %q", r.Material)}
+	}
+	if r.CustomerAddressLat < -90 || r.CustomerAddressLat > 90 {
+		return &ValidationError{Field: "address", Reason: "latitude out of range"}
+	}
+	if r.CustomerAddressLong < -180 || r.CustomerAddressLong > 180 {
+		return &ValidationError{Field: "address", Reason: "longitude out of range"}
+	}
+	if r.Partners < 0 || r.Partners > s.maxPartners {
+		return &ValidationError{Field: "partners", Reason: fmt.Sprintf("must be between 1 and %d", s.maxPartners)}
+	}
+	return validateOfferRequestDetails(entities.OfferRequest{
+		FloorSize: r.FloorSize,
+		Phone:     r.Phone,
+		Email:     r.Email,
+		Language:  r.Language,
+	})
+}
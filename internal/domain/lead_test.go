@@ -0,0 +1,120 @@
+package domain_test
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/domain/mocks"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockery --name PartnerMatcher
+
+func TestLeadService_CreateLead(t *testing.T) {
+	input := domain.LeadRequest{
+		GetPartnersOpts: domain.GetPartnersOpts{Material: "wood", CustomerAddressLat: 48.1, CustomerAddressLong: 11.6},
+		FloorSize:       42.5,
+		Phone:           "+49 170 1234567",
+		Partners:        2,
+	}
+	matches := []entities.Partner{
+		{ID: "declined"},
+		{ID: "1"},
+		{ID: "capped", DailyLeadCap: 2},
+		{ID: "2", DailyLeadCap: 2},
+		{ID: "3"},
+	}
+
+	t.Run("Dispatches to the best available matches", func(t *testing.T) {
+		matcher := &mocks.PartnerMatcher{}
+		matcher.On("GetPartners", mock.Anything, input.GetPartnersOpts).Return(matches, nil)
+		repo := &mocks.OfferRequestRepository{}
+		repo.On("GetOfferRequestsByContact", mock.Anything, input.Phone, "").Return([]entities.OfferRequest{
+			{PartnerID: "declined", Response: entities.OfferRequestDeclined},
+			{PartnerID: "1", Response: entities.OfferRequestAccepted},
+		}, nil)
+		repo.On("CountLeadsByPartner", mock.Anything, "capped", mock.Anything).Return(2, nil)
+		repo.On("CountLeadsByPartner", mock.Anything, "2", mock.Anything).Return(1, nil)
+		repo.On("CreateOfferRequests", mock.Anything, mock.MatchedBy(func(requests []entities.OfferRequest) bool {
+			return len(requests) == 2 &&
+				requests[0].PartnerID == "1" &&
+				requests[1].PartnerID == "2" &&
+				requests[0].LeadID != "" &&
+				requests[0].LeadID == requests[1].LeadID &&
+				requests[0].ID != requests[1].ID
+		}), mock.MatchedBy(isEvent(entities.EventOfferRequested)), mock.MatchedBy(isEvent(entities.EventOfferRequested))).
+			Return(nil)
+		service := domain.NewLeadService(repo, matcher, 3, logging.Discard())
+
+		lead, err := service.CreateLead(context.Background(), input)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, lead.ID)
+		require.Len(t, lead.OfferRequests, 2)
+		assert.Equal(t, lead.ID, lead.OfferRequests[0].LeadID)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Returns ErrNoPartnerAvailable when all matches are excluded", func(t *testing.T) {
+		matcher := &mocks.PartnerMatcher{}
+		matcher.On("GetPartners", mock.Anything, input.GetPartnersOpts).Return(matches[:1], nil)
+		repo := &mocks.OfferRequestRepository{}
+		repo.On("GetOfferRequestsByContact", mock.Anything, input.Phone, "").Return([]entities.OfferRequest{
+			{PartnerID: "declined", Response: entities.OfferRequestDeclined},
+		}, nil)
+		service := domain.NewLeadService(repo, matcher, 3, logging.Discard())
+
+		_, err := service.CreateLead(context.Background(), input)
+
+		assert.ErrorIs(t, err, domain.ErrNoPartnerAvailable)
+	})
+
+	type testCase struct {
+		name     string
+		modify   func(r *domain.LeadRequest)
+		expField string
+	}
+	tests := []testCase{
+		{name: "Rejects unknown material", modify: func(r *domain.LeadRequest) { r.Material = "glass" }, expField: "material"},
+		{name: "Rejects latitude out of range", modify: func(r *domain.LeadRequest) { r.CustomerAddressLat = 91 }, expField: "address"},
+		{name: "Rejects more partners than allowed", modify: func(r *domain.LeadRequest) { r.Partners = 4 }, expField: "partners"},
+		{name: "Rejects invalid phone", modify: func(r *domain.LeadRequest) { r.Phone = "call me" }, expField: "phone"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			request := input
+			tt.modify(&request)
+			service := domain.NewLeadService(&mocks.OfferRequestRepository{}, &mocks.PartnerMatcher{}, 3, logging.Discard())
+
+			_, err := service.CreateLead(context.Background(), request)
+
+			var validationErr *domain.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expField, validationErr.Field)
+		})
+	}
+}
+
+func TestLeadService_GetLead(t *testing.T) {
+	repo := &mocks.OfferRequestRepository{}
+	repo.On("GetOfferRequestsByLead", mock.Anything, "lead").Return([]entities.OfferRequest{{ID: "r1", LeadID: "lead"}}, nil)
+	repo.On("GetOfferRequestsByLead", mock.Anything, "unknown").Return([]entities.OfferRequest{}, nil)
+	service := domain.NewLeadService(repo, &mocks.PartnerMatcher{}, 3, logging.Discard())
+
+	lead, err := service.GetLead(context.Background(), "lead")
+	require.NoError(t, err)
+	assert.Equal(t, entities.Lead{ID: "lead", OfferRequests: []entities.OfferRequest{{ID: "r1", LeadID: "lead"}}}, lead)
+
+	_, err = service.GetLead(context.Background(), "unknown")
+	assert.ErrorIs(t, err, entities.ErrRecordNotExist)
+}
+
+func isEvent(eventType string) func(entities.Event) bool {
+	return func(e entities.Event) bool { return e.Type == eventType }
+}
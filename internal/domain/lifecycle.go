@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// IsActive reports whether the partner is neither suspended nor deleted. Inactive partners are kept, but not shown to
+// customers.
+func IsActive(p entities.Partner) bool {
+	return p.SuspendedAt == nil && p.DeletedAt == nil
+}
+
+// filterActive keeps the partners which are active.
+func filterActive(partners []entities.Partner) []entities.Partner {
+	active := make([]entities.Partner, 0, len(partners))
+	for _, partner := range partners {
+		if IsActive(partner) {
+			active = append(active, partner)
+		}
+	}
+	return active
+}
+
+// SuspendPartner suspends the partner for the reason, so that they are not shown to customers until they are
+// reinstated. Suspending a suspended partner replaces the reason.
+// Can return a *ValidationError when the reason is invalid, entities.ErrRecordNotExist when partner with given id
+// does not exist and entities.ErrPartnerDeleted when the partner was deleted.
+func (s *PartnerService) SuspendPartner(ctx context.Context, id, reason string) (entities.Partner, error) {
+	ctx, span := tracer.Start(ctx, "PartnerService.SuspendPartner", trace.WithAttributes(attribute.String("partner.id", id)))
+	defer span.End()
+	if err := validateReason(reason); err != nil {
+		return entities.Partner{}, err
+	}
+	partner, err := s.undeletedPartner(ctx, id)
+	if err != nil {
+		return entities.Partner{}, err
+	}
+	suspendedAt := s.now().UTC().Truncate(time.Second)
+	partner.SuspendedAt, partner.SuspensionReason = &suspendedAt, reason
+	if err := s.storeLifecycle(ctx, partner, entities.EventPartnerUpdated, partner); err != nil {
+		return entities.Partner{}, err
+	}
+	logging.FromContextOr(ctx, s.logger).Info("partner suspended", "partner_id", id)
+	return partner, nil
+}
+
+// VerificationSuspensionReason is the reason of suspensions caused by the verification status suspended.
+const VerificationSuspensionReason = "Verification suspended"
+
+// suspendByVerification maps the verification status suspended to the suspension fields, so that the partner is
+// hidden and listed like any suspended partner. Partners suspended before keep their reason.
+func (s *PartnerService) suspendByVerification(partner *entities.Partner) {
+	if partner.VerificationStatus != entities.VerificationSuspended || partner.SuspendedAt != nil {
+		return
+	}
+	suspendedAt := s.now().UTC().Truncate(time.Second)
+	partner.SuspendedAt, partner.SuspensionReason = &suspendedAt, VerificationSuspensionReason
+}
+
+// ReinstatePartner lifts the suspension of the partner. Reinstating a partner who is not suspended changes nothing.
+// A suspended verification status is reset to pending, so that the partner has to be verified again.
+// Can return entities.ErrRecordNotExist when partner with given id does not exist and entities.ErrPartnerDeleted when
+// the partner was deleted.
+func (s *PartnerService) ReinstatePartner(ctx context.Context, id string) (entities.Partner, error) {
+	ctx, span := tracer.Start(ctx, "PartnerService.ReinstatePartner", trace.WithAttributes(attribute.String("partner.id", id)))
+	defer span.End()
+	partner, err := s.undeletedPartner(ctx, id)
+	if err != nil || partner.SuspendedAt == nil {
+		return partner, err
+	}
+	partner.SuspendedAt, partner.SuspensionReason = nil, ""
+	if partner.VerificationStatus == entities.VerificationSuspended {
+		partner.VerificationStatus = entities.VerificationPending
+	}
+	if err := s.storeLifecycle(ctx, partner, entities.EventPartnerUpdated, partner); err != nil {
+		return entities.Partner{}, err
+	}
+	logging.FromContextOr(ctx, s.logger).Info("partner reinstated", "partner_id", id)
+	return partner, nil
+}
+
+// DeletePartner marks the partner as deleted for the reason. The partner is kept, so that the offer requests and
+// reviews referencing them stay intact, but is only shown to admins and cannot be changed anymore. Consumers of the
+// entities.EventPartnerDeleted event treat the partner as gone.
+// Can return a *ValidationError when the reason is invalid, entities.ErrRecordNotExist when partner with given id
+// does not exist and entities.ErrPartnerDeleted when the partner was deleted before.
+func (s *PartnerService) DeletePartner(ctx context.Context, id, reason string) error {
+	ctx, span := tracer.Start(ctx, "PartnerService.DeletePartner", trace.WithAttributes(attribute.String("partner.id", id)))
+	defer span.End()
+	if err := validateReason(reason); err != nil {
+		return err
+	}
+	partner, err := s.undeletedPartner(ctx, id)
+	if err != nil {
+		return err
+	}
+	deletedAt := s.now().UTC().Truncate(time.Second)
+	partner.DeletedAt, partner.DeletionReason = &deletedAt, reason
+	if err := s.storeLifecycle(ctx, partner, entities.EventPartnerDeleted, PartnerDeleted{ID: id}); err != nil {
+		return err
+	}
+	logging.FromContextOr(ctx, s.logger).Info("partner deleted", "partner_id", id)
+	return nil
+}
+
+// undeletedPartner returns the stored partner with the id.
+// Can return entities.ErrRecordNotExist when partner with given id does not exist and entities.ErrPartnerDeleted when
+// the partner was deleted.
+func (s *PartnerService) undeletedPartner(ctx context.Context, id string) (entities.Partner, error) {
+	partner, err := s.repository.GetPartnerByID(ctx, id)
+	if err != nil {
+		return entities.Partner{}, err
+	}
+	if partner.DeletedAt != nil {
+		return entities.Partner{}, entities.ErrPartnerDeleted
+	}
+	return partner, nil
+}
+
+// storeLifecycle stores the partner with an event of the given type and data.
+func (s *PartnerService) storeLifecycle(ctx context.Context, partner entities.Partner, eventType string, data any) error {
+	event, err := newEvent(eventType, partner.ID, data)
+	if err != nil {
+		return err
+	}
+	return s.repository.UpdatePartner(ctx, partner, event)
+}
@@ -0,0 +1,267 @@
+package domain_test
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/domain/mocks"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartnerService_GetPartners_Inactive(t *testing.T) {
+	address := entities.Address{Latitude: 48.1360, Longitude: 11.6875}
+	changedAt := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	partners := vetted(
+		entities.Partner{ID: "active", Address: address, OperatingRadius: 50},
+		entities.Partner{ID: "suspended", Address: address, OperatingRadius: 50, SuspendedAt: &changedAt},
+		entities.Partner{ID: "deleted", Address: address, OperatingRadius: 50, DeletedAt: &changedAt},
+	)
+	type testCase struct {
+		name            string
+		includeInactive bool
+		expIDs          []string
+	}
+	tests := []testCase{
+		{name: "Excludes inactive partners", expIDs: []string{"active"}},
+		{name: "Includes inactive partners for admins", includeInactive: true, expIDs: []string{"active", "suspended", "deleted"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.PartnerRepository{}
+			repo.On("GetPartnersByMaterial", mock.Anything, "wood").Return(partners, nil)
+			service := domain.NewPartnerService(repo, logging.Discard())
+
+			actual, err := service.GetPartners(context.Background(), domain.GetPartnersOpts{
+				Material:            "wood",
+				CustomerAddressLat:  48.1351,
+				CustomerAddressLong: 11.5820,
+				IncludeInactive:     tt.includeInactive,
+			})
+
+			require.NoError(t, err)
+			var ids []string
+			for _, partner := range actual {
+				ids = append(ids, partner.ID)
+			}
+			assert.ElementsMatch(t, tt.expIDs, ids)
+		})
+	}
+}
+
+func TestPartnerService_SuspendPartner(t *testing.T) {
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	deletedAt := now.Add(-time.Hour)
+	type testCase struct {
+		name      string
+		reason    string
+		stored    entities.Partner
+		getErr    error
+		expUpdate bool
+		expErr    error
+		expField  string
+	}
+	tests := []testCase{
+		{name: "Suspends partner", reason: "Complaints", stored: entities.Partner{ID: "1"}, expUpdate: true},
+		{name: "Rejects empty reason", reason: " ", expField: "reason"},
+		{name: "Rejects long reason", reason: strings.Repeat("x", 501), expField: "reason"},
+		{name: "Returns error for unknown partner", reason: "Complaints", getErr: entities.ErrRecordNotExist, expErr: entities.ErrRecordNotExist},
+		{
+			name:   "Returns error for deleted partner",
+			reason: "Complaints",
+			stored: entities.Partner{ID: "1", DeletedAt: &deletedAt},
+			expErr: entities.ErrPartnerDeleted,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.PartnerRepository{}
+			repo.On("GetPartnerByID", mock.Anything, "1").Return(tt.stored, tt.getErr).Maybe()
+			var stored entities.Partner
+			if tt.expUpdate {
+				repo.On("UpdatePartner", mock.Anything, mock.AnythingOfType("entities.Partner"), mock.Anything).
+					Run(func(args mock.Arguments) { stored = args.Get(1).(entities.Partner) }).
+					Return(nil)
+			}
+			service := domain.NewPartnerService(repo, logging.Discard()).WithClock(func() time.Time { return now })
+
+			partner, err := service.SuspendPartner(context.Background(), "1", tt.reason)
+
+			repo.AssertExpectations(t)
+			if tt.expField != "" {
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.expField, validationErr.Field)
+				return
+			}
+			if tt.expErr != nil {
+				assert.ErrorIs(t, err, tt.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, partner.SuspendedAt)
+			assert.Equal(t, now, *partner.SuspendedAt)
+			assert.Equal(t, tt.reason, partner.SuspensionReason)
+			assert.Equal(t, partner, stored)
+		})
+	}
+}
+
+func TestPartnerService_ReinstatePartner(t *testing.T) {
+	suspendedAt := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Lifts suspension", func(t *testing.T) {
+		repo := &mocks.PartnerRepository{}
+		repo.On("GetPartnerByID", mock.Anything, "1").
+			Return(entities.Partner{ID: "1", SuspendedAt: &suspendedAt, SuspensionReason: "Complaints"}, nil)
+		repo.On("UpdatePartner", mock.Anything, entities.Partner{ID: "1"}, mock.Anything).Return(nil)
+		service := domain.NewPartnerService(repo, logging.Discard())
+
+		partner, err := service.ReinstatePartner(context.Background(), "1")
+
+		require.NoError(t, err)
+		assert.Equal(t, entities.Partner{ID: "1"}, partner)
+		repo.AssertExpectations(t)
+	})
+	t.Run("Resets suspended verification to pending", func(t *testing.T) {
+		repo := &mocks.PartnerRepository{}
+		repo.On("GetPartnerByID", mock.Anything, "1").Return(entities.Partner{
+			ID:                 "1",
+			VerificationStatus: entities.VerificationSuspended,
+			SuspendedAt:        &suspendedAt,
+			SuspensionReason:   domain.VerificationSuspensionReason,
+		}, nil)
+		expected := entities.Partner{ID: "1", VerificationStatus: entities.VerificationPending}
+		repo.On("UpdatePartner", mock.Anything, expected, mock.Anything).Return(nil)
+		service := domain.NewPartnerService(repo, logging.Discard())
+
+		partner, err := service.ReinstatePartner(context.Background(), "1")
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, partner)
+		repo.AssertExpectations(t)
+	})
+	t.Run("Keeps active partner unchanged", func(t *testing.T) {
+		repo := &mocks.PartnerRepository{}
+		repo.On("GetPartnerByID", mock.Anything, "1").Return(entities.Partner{ID: "1"}, nil)
+		service := domain.NewPartnerService(repo, logging.Discard())
+
+		partner, err := service.ReinstatePartner(context.Background(), "1")
+
+		require.NoError(t, err)
+		assert.Equal(t, entities.Partner{ID: "1"}, partner)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestPartnerService_DeletePartner(t *testing.T) {
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Keeps partner and marks it as deleted", func(t *testing.T) {
+		repo := &mocks.PartnerRepository{}
+		repo.On("GetPartnerByID", mock.Anything, "1").Return(entities.Partner{ID: "1", Name: "Floors"}, nil)
+		var stored entities.Partner
+		var event entities.Event
+		repo.On("UpdatePartner", mock.Anything, mock.AnythingOfType("entities.Partner"), mock.Anything).
+			Run(func(args mock.Arguments) {
+				stored = args.Get(1).(entities.Partner)
+				event = args.Get(2).(entities.Event)
+			}).
+			Return(nil)
+		service := domain.NewPartnerService(repo, logging.Discard()).WithClock(func() time.Time { return now })
+
+		err := service.DeletePartner(context.Background(), "1", "Business closed")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Floors", stored.Name)
+		require.NotNil(t, stored.DeletedAt)
+		assert.Equal(t, now, *stored.DeletedAt)
+		assert.Equal(t, "Business closed", stored.DeletionReason)
+		assert.Equal(t, entities.EventPartnerDeleted, event.Type)
+		var data domain.PartnerDeleted
+		require.NoError(t, json.Unmarshal(event.Data, &data))
+		assert.Equal(t, domain.PartnerDeleted{ID: "1"}, data)
+	})
+	t.Run("Returns error for deleted partner", func(t *testing.T) {
+		deletedAt := now.Add(-time.Hour)
+		repo := &mocks.PartnerRepository{}
+		repo.On("GetPartnerByID", mock.Anything, "1").Return(entities.Partner{ID: "1", DeletedAt: &deletedAt}, nil)
+		service := domain.NewPartnerService(repo, logging.Discard())
+
+		err := service.DeletePartner(context.Background(), "1", "Business closed")
+
+		assert.ErrorIs(t, err, entities.ErrPartnerDeleted)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestPartnerService_UpdatePartner_Deleted(t *testing.T) {
+	deletedAt := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	repo := &mocks.PartnerRepository{}
+	service := domain.NewPartnerService(repo, logging.Discard())
+
+	_, err := service.UpdatePartner(context.Background(), entities.Partner{ID: "1", DeletedAt: &deletedAt})
+
+	assert.ErrorIs(t, err, entities.ErrPartnerDeleted)
+	repo.AssertExpectations(t)
+}
+
+func TestPartnerService_UpdatePartner_SuspendedVerification(t *testing.T) {
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	suspendedAt := now.Add(-time.Hour)
+	partner := entities.Partner{
+		ID:                  "1",
+		Name:                "Floors",
+		ExperiencedMaterial: []string{"wood"},
+		OperatingRadius:     10,
+		VerificationStatus:  entities.VerificationSuspended,
+	}
+	type testCase struct {
+		name           string
+		suspendedAt    *time.Time
+		reason         string
+		expSuspendedAt time.Time
+		expReason      string
+	}
+	tests := []testCase{
+		{name: "Suspends partner", expSuspendedAt: now, expReason: domain.VerificationSuspensionReason},
+		{
+			name:           "Keeps earlier suspension",
+			suspendedAt:    &suspendedAt,
+			reason:         "Complaints",
+			expSuspendedAt: suspendedAt,
+			expReason:      "Complaints",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.PartnerRepository{}
+			var stored entities.Partner
+			repo.On("UpdatePartner", mock.Anything, mock.AnythingOfType("entities.Partner"), mock.Anything).
+				Run(func(args mock.Arguments) { stored = args.Get(1).(entities.Partner) }).
+				Return(nil)
+			service := domain.NewPartnerService(repo, logging.Discard()).WithClock(func() time.Time { return now })
+			changed := partner
+			changed.SuspendedAt, changed.SuspensionReason = tt.suspendedAt, tt.reason
+
+			updated, err := service.UpdatePartner(context.Background(), changed)
+
+			require.NoError(t, err)
+			require.NotNil(t, stored.SuspendedAt)
+			assert.Equal(t, tt.expSuspendedAt, *stored.SuspendedAt)
+			assert.Equal(t, tt.expReason, stored.SuspensionReason)
+			assert.Equal(t, stored, updated)
+			assert.False(t, domain.IsActive(updated))
+		})
+	}
+}
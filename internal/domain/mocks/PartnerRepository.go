@@ -3,7 +3,9 @@
 package mocks
 
 import (
+	context "context"
 	entities "customer-partner/internal/entities"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,20 +15,108 @@ type PartnerRepository struct {
 	mock.Mock
 }
 
-// GetPartnerByID provides a mock function with given fields: id
-func (_m *PartnerRepository) GetPartnerByID(id string) (entities.Partner, error) {
-	ret := _m.Called(id)
+// CreatePartner provides a mock function with given fields: ctx, partner, events
+func (_m *PartnerRepository) CreatePartner(ctx context.Context, partner entities.Partner, events ...entities.Event) error {
+	_va := make([]interface{}, len(events))
+	for _i := range events {
+		_va[_i] = events[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, partner)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Partner, ...entities.Event) error); ok {
+		r0 = rf(ctx, partner, events...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePartner provides a mock function with given fields: ctx, id, events
+func (_m *PartnerRepository) DeletePartner(ctx context.Context, id string, events ...entities.Event) error {
+	_va := make([]interface{}, len(events))
+	for _i := range events {
+		_va[_i] = events[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, id)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...entities.Event) error); ok {
+		r0 = rf(ctx, id, events...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllPartners provides a mock function with given fields: ctx
+func (_m *PartnerRepository) GetAllPartners(ctx context.Context) ([]entities.Partner, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entities.Partner
+	if rf, ok := ret.Get(0).(func(context.Context) []entities.Partner); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Partner)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPartnerByID provides a mock function with given fields: ctx, id
+func (_m *PartnerRepository) GetPartnerByID(ctx context.Context, id string) (entities.Partner, error) {
+	ret := _m.Called(ctx, id)
 
 	var r0 entities.Partner
-	if rf, ok := ret.Get(0).(func(string) entities.Partner); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, string) entities.Partner); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Get(0).(entities.Partner)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPartnersByMaterial provides a mock function with given fields: ctx, material
+func (_m *PartnerRepository) GetPartnersByMaterial(ctx context.Context, material string) ([]entities.Partner, error) {
+	ret := _m.Called(ctx, material)
+
+	var r0 []entities.Partner
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entities.Partner); ok {
+		r0 = rf(ctx, material)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Partner)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, material)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -34,19 +124,47 @@ func (_m *PartnerRepository) GetPartnerByID(id string) (entities.Partner, error)
 	return r0, r1
 }
 
-// GetPartnersByMaterial provides a mock function with given fields: material
-func (_m *PartnerRepository) GetPartnersByMaterial(material string) []entities.Partner {
-	ret := _m.Called(material)
+// GetPartnersWithExpiringCertifications provides a mock function with given fields: ctx, before
+func (_m *PartnerRepository) GetPartnersWithExpiringCertifications(ctx context.Context, before time.Time) ([]entities.Partner, error) {
+	ret := _m.Called(ctx, before)
 
 	var r0 []entities.Partner
-	if rf, ok := ret.Get(0).(func(string) []entities.Partner); ok {
-		r0 = rf(material)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []entities.Partner); ok {
+		r0 = rf(ctx, before)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entities.Partner)
 		}
 	}
 
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePartner provides a mock function with given fields: ctx, partner, events
+func (_m *PartnerRepository) UpdatePartner(ctx context.Context, partner entities.Partner, events ...entities.Event) error {
+	_va := make([]interface{}, len(events))
+	for _i := range events {
+		_va[_i] = events[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, partner)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Partner, ...entities.Event) error); ok {
+		r0 = rf(ctx, partner, events...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
 	return r0
 }
 
@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OfferRequestRepository defines an interface which a persistence storage for offer requests must provide.
+// Implementations are responsible for encrypting the contact data at rest.
+type OfferRequestRepository interface {
+	// CreateOfferRequest stores a new offer request. The events are added to the outbox in the same transaction.
+	CreateOfferRequest(ctx context.Context, request entities.OfferRequest, events ...entities.Event) error
+	// GetOfferRequestByID can return entities.ErrRecordNotExist when offer request with given id does not exist.
+	GetOfferRequestByID(ctx context.Context, id string) (entities.OfferRequest, error)
+	// GetOfferRequestsByPartner returns the offer requests sent to a partner, oldest first.
+	GetOfferRequestsByPartner(ctx context.Context, partnerID string) ([]entities.OfferRequest, error)
+	// PurgeContactData removes phone and email of all offer requests created before createdBefore, marks them as
+	// purged at purgedAt and returns the number of purged offer requests.
+	PurgeContactData(ctx context.Context, createdBefore, purgedAt time.Time) (int, error)
+	// GetOfferRequestsByContact returns the offer requests of the customer with the given phone number or email
+	// address. Empty values match nothing.
+	GetOfferRequestsByContact(ctx context.Context, phone, email string) ([]entities.OfferRequest, error)
+	// GetOfferRequestsByCustomer returns the offer requests the customer with the given id created with an account,
+	// oldest first.
+	GetOfferRequestsByCustomer(ctx context.Context, customerID string) ([]entities.OfferRequest, error)
+	// AnonymizeOfferRequests removes phone, email and customer id of the offer requests with the given ids, marks
+	// them as purged at purgedAt and returns the number of anonymized offer requests.
+	AnonymizeOfferRequests(ctx context.Context, ids []string, purgedAt time.Time) (int, error)
+	// CreateOfferRequests stores the offer requests of a lead at once. The events are added to the outbox in the same
+	// transaction.
+	CreateOfferRequests(ctx context.Context, requests []entities.OfferRequest, events ...entities.Event) error
+	// GetOfferRequestsByLead returns the offer requests of a lead, in the order they were created.
+	GetOfferRequestsByLead(ctx context.Context, leadID string) ([]entities.OfferRequest, error)
+	// CountLeadsByPartner returns the number of offer requests of leads sent to a partner since the given time.
+	CountLeadsByPartner(ctx context.Context, partnerID string, since time.Time) (int, error)
+	// RespondToOfferRequest stores the response of the partner and ranks it among the responses to the lead. The
+	// events are added to the outbox in the same transaction.
+	// Can return entities.ErrRecordNotExist when offer request with given id does not exist and
+	// entities.ErrAlreadyResponded when the partner responded before.
+	RespondToOfferRequest(
+		ctx context.Context,
+		id, response string,
+		respondedAt time.Time,
+		events ...entities.Event,
+	) (entities.OfferRequest, error)
+}
+
+func NewOfferRequestService(
+	repository OfferRequestRepository,
+	partners PartnerRepository,
+	retention time.Duration,
+	logger *slog.Logger,
+) *OfferRequestService {
+	return &OfferRequestService{repository: repository, partners: partners, retention: retention, logger: logger}
+}
+
+// OfferRequestService implements the domain logic of offer requests. The contact data of customers is only kept for
+// the retention period.
+type OfferRequestService struct {
+	repository OfferRequestRepository
+	partners   PartnerRepository
+	retention  time.Duration
+	logger     *slog.Logger
+}
+
+// CreateOfferRequest validates and stores a new offer request for an existing partner. The offer request is assigned
+// a new id and the time of creation.
+// Can return a *ValidationError when the offer request is invalid or the partner does not exist or is inactive.
+func (s *OfferRequestService) CreateOfferRequest(
+	ctx context.Context,
+	request entities.OfferRequest,
+) (entities.OfferRequest, error) {
+	ctx, span := tracer.Start(ctx, "OfferRequestService.CreateOfferRequest", trace.WithAttributes(
+		attribute.String("partner.id", request.PartnerID),
+	))
+	defer span.End()
+	if err := ValidateOfferRequest(request); err != nil {
+		return entities.OfferRequest{}, err
+	}
+	partner, err := s.partners.GetPartnerByID(ctx, request.PartnerID)
+	if errors.Is(err, entities.ErrRecordNotExist) {
+		return entities.OfferRequest{}, &ValidationError{Field: "partner_id", Reason: "unknown partner"}
+	}
+	if err != nil {
+		return entities.OfferRequest{}, err
+	}
+	if !IsActive(partner) {
+		return entities.OfferRequest{}, &ValidationError{Field: "partner_id", Reason: "inactive partner"}
+	}
+	request.ID = newID()
+	request.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	request.ContactPurgedAt = nil
+	event, err := newEvent(entities.EventOfferRequested, request.ID, OfferRequested{
+		ID:        request.ID,
+		PartnerID: request.PartnerID,
+		FloorSize: request.FloorSize,
+		CreatedAt: request.CreatedAt,
+	})
+	if err != nil {
+		return entities.OfferRequest{}, err
+	}
+	if err := s.repository.CreateOfferRequest(ctx, request, event); err != nil {
+		return entities.OfferRequest{}, err
+	}
+	logging.FromContextOr(ctx, s.logger).Info("offer request created", "offer_request", request)
+	return request, nil
+}
+
+// GetOfferRequest finds an offer request by its id.
+// Can return entities.ErrRecordNotExist when offer request with given id does not exist.
+func (s *OfferRequestService) GetOfferRequest(ctx context.Context, id string) (entities.OfferRequest, error) {
+	ctx, span := tracer.Start(ctx, "OfferRequestService.GetOfferRequest", trace.WithAttributes(
+		attribute.String("offer_request.id", id),
+	))
+	defer span.End()
+	return s.repository.GetOfferRequestByID(ctx, id)
+}
+
+// GetOfferRequestsByPartner returns the offer requests sent to a partner, oldest first.
+func (s *OfferRequestService) GetOfferRequestsByPartner(
+	ctx context.Context,
+	partnerID string,
+) ([]entities.OfferRequest, error) {
+	ctx, span := tracer.Start(ctx, "OfferRequestService.GetOfferRequestsByPartner", trace.WithAttributes(
+		attribute.String("partner.id", partnerID),
+	))
+	defer span.End()
+	return s.repository.GetOfferRequestsByPartner(ctx, partnerID)
+}
+
+// RespondToOfferRequest stores whether the partner accepts or declines the offer request. Partners respond only
+// once. Customers are not dispatched to partners who declined them before.
+// Can return a *ValidationError when the response is unknown, entities.ErrRecordNotExist when offer request with given
+// id does not exist and entities.ErrAlreadyResponded when the partner responded before.
+func (s *OfferRequestService) RespondToOfferRequest(
+	ctx context.Context,
+	id, response string,
+) (entities.OfferRequest, error) {
+	ctx, span := tracer.Start(ctx, "OfferRequestService.RespondToOfferRequest", trace.WithAttributes(
+		attribute.String("offer_request.id", id),
+		attribute.String("offer_request.response", response),
+	))
+	defer span.End()
+	if response != entities.OfferRequestAccepted && response != entities.OfferRequestDeclined {
+		return entities.OfferRequest{}, &ValidationError{
+			Field:  "response",
+			Reason: fmt.Sprintf("unknown response %q", response),
+		}
+	}
+	request, err := s.repository.GetOfferRequestByID(ctx, id)
+	if err != nil {
+		return entities.OfferRequest{}, err
+	}
+	respondedAt := time.Now().UTC().Truncate(time.Second)
+	event, err := newEvent(entities.EventOfferResponded, id, OfferResponded{
+		ID:          id,
+		PartnerID:   request.PartnerID,
+		LeadID:      request.LeadID,
+		Response:    response,
+		RespondedAt: respondedAt,
+	})
+	if err != nil {
+		return entities.OfferRequest{}, err
+	}
+	request, err = s.repository.RespondToOfferRequest(ctx, id, response, respondedAt, event)
+	if err != nil {
+		return entities.OfferRequest{}, err
+	}
+	logging.FromContextOr(ctx, s.logger).Info("offer request responded",
+		"offer_request", request,
+		"response", response,
+		"response_rank", request.ResponseRank,
+	)
+	return request, nil
+}
+
+// PurgeExpiredContactData removes the contact data of offer requests older than the retention period. It is run
+// periodically.
+func (s *OfferRequestService) PurgeExpiredContactData(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracer.Start(ctx, "OfferRequestService.PurgeExpiredContactData")
+	defer span.End()
+	purged, err := s.repository.PurgeContactData(ctx, now.Add(-s.retention), now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	span.SetAttributes(attribute.Int("offer_request.purged", purged))
+	if purged > 0 {
+		logging.FromContextOr(ctx, s.logger).Info("purged contact data of offer requests",
+			"count", purged,
+			"retention", s.retention,
+		)
+	}
+	return purged, nil
+}
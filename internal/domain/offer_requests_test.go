@@ -0,0 +1,181 @@
+package domain_test
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/domain/mocks"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockery --name OfferRequestRepository
+
+func TestValidateOfferRequest(t *testing.T) {
+	valid := entities.OfferRequest{PartnerID: "1", FloorSize: 42.5, Phone: "+49 (170) 123-4567", Email: "jane@example.com"}
+	type testCase struct {
+		name     string
+		modify   func(r *entities.OfferRequest)
+		expField string
+	}
+	tests := []testCase{
+		{name: "Accepts valid offer request", modify: func(r *entities.OfferRequest) {}},
+		{name: "Accepts missing email", modify: func(r *entities.OfferRequest) { r.Email = "" }},
+		{name: "Rejects empty partner id", modify: func(r *entities.OfferRequest) { r.PartnerID = " " }, expField: "partner_id"},
+		{name: "Rejects zero floor size", modify: func(r *entities.OfferRequest) { r.FloorSize = 0 }, expField: "floor_size"},
+		{name: "Rejects huge floor size", modify: func(r *entities.OfferRequest) { r.FloorSize = 1e6 }, expField: "floor_size"},
+		{name: "Rejects missing phone", modify: func(r *entities.OfferRequest) { r.Phone = "" }, expField: "phone"},
+		{name: "Rejects letters in phone", modify: func(r *entities.OfferRequest) { r.Phone = "call me" }, expField: "phone"},
+		{name: "Rejects short phone", modify: func(r *entities.OfferRequest) { r.Phone = "12345" }, expField: "phone"},
+		{name: "Rejects invalid email", modify: func(r *entities.OfferRequest) { r.Email = "jane" }, expField: "email"},
+		{name: "Rejects email with name", modify: func(r *entities.OfferRequest) { r.Email = "Jane <jane@example.com>" }, expField: "email"},
+		{name: "Accepts known language", modify: func(r *entities.OfferRequest) { r.Language = "en" }},
+		{name: "Rejects unknown language", modify: func(r *entities.OfferRequest) { r.Language = "xx" }, expField: "language"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			request := valid
+			tt.modify(&request)
+
+			err := domain.ValidateOfferRequest(request)
+
+			if tt.expField == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *domain.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expField, validationErr.Field)
+			if request.Phone != "" {
+				assert.NotContains(t, err.Error(), request.Phone)
+			}
+		})
+	}
+}
+
+func TestOfferRequestService_CreateOfferRequest(t *testing.T) {
+	input := entities.OfferRequest{PartnerID: "1", FloorSize: 42.5, Phone: "+49 170 1234567"}
+
+	t.Run("Stores offer request with id and creation time", func(t *testing.T) {
+		partners := &mocks.PartnerRepository{}
+		partners.On("GetPartnerByID", mock.Anything, "1").Return(entities.Partner{ID: "1"}, nil)
+		repo := &mocks.OfferRequestRepository{}
+		repo.On("CreateOfferRequest", mock.Anything, mock.MatchedBy(func(r entities.OfferRequest) bool {
+			return r.ID != "" && !r.CreatedAt.IsZero() && r.Phone == input.Phone
+		}), mock.MatchedBy(func(e entities.Event) bool {
+			var data domain.OfferRequested
+			return e.Type == entities.EventOfferRequested &&
+				json.Unmarshal(e.Data, &data) == nil &&
+				data.ID == e.AggregateID &&
+				data.PartnerID == "1" &&
+				!strings.Contains(string(e.Data), "170")
+		})).Return(nil)
+		service := domain.NewOfferRequestService(repo, partners, time.Hour, logging.Discard())
+
+		created, err := service.CreateOfferRequest(context.Background(), input)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, created.ID)
+		assert.WithinDuration(t, time.Now(), created.CreatedAt, time.Minute)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects unknown partner", func(t *testing.T) {
+		partners := &mocks.PartnerRepository{}
+		partners.On("GetPartnerByID", mock.Anything, "1").Return(entities.Partner{}, entities.ErrRecordNotExist)
+		service := domain.NewOfferRequestService(&mocks.OfferRequestRepository{}, partners, time.Hour, logging.Discard())
+
+		_, err := service.CreateOfferRequest(context.Background(), input)
+
+		var validationErr *domain.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "partner_id", validationErr.Field)
+	})
+
+	t.Run("Rejects suspended partner", func(t *testing.T) {
+		suspendedAt := time.Now()
+		partners := &mocks.PartnerRepository{}
+		partners.On("GetPartnerByID", mock.Anything, "1").Return(entities.Partner{ID: "1", SuspendedAt: &suspendedAt}, nil)
+		service := domain.NewOfferRequestService(&mocks.OfferRequestRepository{}, partners, time.Hour, logging.Discard())
+
+		_, err := service.CreateOfferRequest(context.Background(), input)
+
+		var validationErr *domain.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "partner_id", validationErr.Field)
+	})
+
+	t.Run("Passes on repository errors", func(t *testing.T) {
+		partners := &mocks.PartnerRepository{}
+		partners.On("GetPartnerByID", mock.Anything, "1").Return(entities.Partner{ID: "1"}, nil)
+		repo := &mocks.OfferRequestRepository{}
+		repo.On("CreateOfferRequest", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("disk full"))
+		service := domain.NewOfferRequestService(repo, partners, time.Hour, logging.Discard())
+
+		_, err := service.CreateOfferRequest(context.Background(), input)
+
+		assert.EqualError(t, err, "disk full")
+	})
+}
+
+func TestOfferRequestService_PurgeExpiredContactData(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := &mocks.OfferRequestRepository{}
+	repo.On("PurgeContactData", mock.Anything, now.Add(-30*24*time.Hour), now).Return(3, nil)
+	service := domain.NewOfferRequestService(repo, &mocks.PartnerRepository{}, 30*24*time.Hour, logging.Discard())
+
+	purged, err := service.PurgeExpiredContactData(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, purged)
+	repo.AssertExpectations(t)
+}
+
+func TestOfferRequestService_RespondToOfferRequest(t *testing.T) {
+	t.Run("Stores the response with an event", func(t *testing.T) {
+		repo := &mocks.OfferRequestRepository{}
+		repo.On("GetOfferRequestByID", mock.Anything, "r1").
+			Return(entities.OfferRequest{ID: "r1", PartnerID: "1", LeadID: "lead"}, nil)
+		repo.On("RespondToOfferRequest", mock.Anything, "r1", entities.OfferRequestDeclined, mock.Anything,
+			mock.MatchedBy(func(e entities.Event) bool {
+				var data domain.OfferResponded
+				return e.Type == entities.EventOfferResponded &&
+					json.Unmarshal(e.Data, &data) == nil &&
+					data.PartnerID == "1" &&
+					data.LeadID == "lead" &&
+					data.Response == entities.OfferRequestDeclined
+			})).
+			Return(entities.OfferRequest{ID: "r1", Response: entities.OfferRequestDeclined, ResponseRank: 1}, nil)
+		service := domain.NewOfferRequestService(repo, &mocks.PartnerRepository{}, time.Hour, logging.Discard())
+
+		request, err := service.RespondToOfferRequest(context.Background(), "r1", entities.OfferRequestDeclined)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, request.ResponseRank)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects unknown response", func(t *testing.T) {
+		service := domain.NewOfferRequestService(
+			&mocks.OfferRequestRepository{},
+			&mocks.PartnerRepository{},
+			time.Hour,
+			logging.Discard(),
+		)
+
+		_, err := service.RespondToOfferRequest(context.Background(), "r1", "maybe")
+
+		var validationErr *domain.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "response", validationErr.Field)
+	})
+}
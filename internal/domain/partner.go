@@ -1,9 +1,20 @@
 package domain
 
 import (
+	"context"
+	"crypto/rand"
 	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"encoding/hex"
+	"log/slog"
 	"math"
 	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type match struct {
@@ -18,8 +29,8 @@ func (rd byRatingAndDistance) Len() int {
 	return len(rd)
 }
 func (rd byRatingAndDistance) Less(i, j int) bool {
-	if rd[i].partner.Rating > rd[j].partner.Rating {
-		return true
+	if rd[i].partner.Rating != rd[j].partner.Rating {
+		return rd[i].partner.Rating > rd[j].partner.Rating
 	}
 	return rd[i].distance < rd[j].distance
 }
@@ -32,49 +43,236 @@ type GetPartnersOpts struct {
 	Material            string
 	CustomerAddressLong float64
 	CustomerAddressLat  float64
+	// FloorSize is the size of the floor in square meters. Matches get a price estimate when it is set.
+	FloorSize float64
+	// SortBy is one of SortOrders, empty for SortByRating.
+	SortBy string
+	// AvailableFrom and AvailableUntil are the window in which the customer wants the job to start. Only partners
+	// available in the window match when AvailableUntil is set, AvailableFrom defaults to now.
+	AvailableFrom  time.Time
+	AvailableUntil time.Time
+	// IncludeUnverified also matches partners who are not vetted, see IsVetted. It is meant for admins.
+	IncludeUnverified bool
+	// IncludeInactive also matches partners who are suspended or deleted, see IsActive. It is meant for admins.
+	IncludeInactive bool
 }
 
 // PartnerRepository defines an interface which a persistence storage must provide.
 type PartnerRepository interface {
-	GetPartnersByMaterial(material string) []entities.Partner
-	GetPartnerByID(id string) (entities.Partner, error)
+	GetPartnersByMaterial(ctx context.Context, material string) ([]entities.Partner, error)
+	GetPartnerByID(ctx context.Context, id string) (entities.Partner, error)
+	// GetAllPartners returns all stored partners, including suspended and deleted ones.
+	GetAllPartners(ctx context.Context) ([]entities.Partner, error)
+	// GetPartnersWithExpiringCertifications returns the partners having a certification which expires before the
+	// given time.
+	GetPartnersWithExpiringCertifications(ctx context.Context, before time.Time) ([]entities.Partner, error)
+	// CreatePartner stores a new partner. The events are added to the outbox in the same transaction.
+	CreatePartner(ctx context.Context, partner entities.Partner, events ...entities.Event) error
+	// UpdatePartner replaces a stored partner. The events are added to the outbox in the same transaction.
+	// Can return entities.ErrRecordNotExist when partner with given id does not exist.
+	UpdatePartner(ctx context.Context, partner entities.Partner, events ...entities.Event) error
+	// DeletePartner removes a partner, e.g. to erase their personal data. Partners deleted by admins are kept and only
+	// marked as deleted with UpdatePartner. The events are added to the outbox in the same transaction.
+	// Can return entities.ErrRecordNotExist when partner with given id does not exist.
+	DeletePartner(ctx context.Context, id string, events ...entities.Event) error
+}
+
+// PartnerChangeRepository defines an interface which a persistence storage for the audit trail of partners must
+// provide. Changes can only be appended, never modified or removed, except for redacting them on erasures.
+type PartnerChangeRepository interface {
+	AppendPartnerChange(ctx context.Context, change entities.PartnerChange) error
+	// GetPartnerChanges returns the changes of a partner, oldest first.
+	GetPartnerChanges(ctx context.Context, partnerID string) ([]entities.PartnerChange, error)
+	// RedactPartnerChanges removes the values before and after all changes of a partner and marks the changes as
+	// redacted. Which attributes were changed by whom and when is kept.
+	RedactPartnerChanges(ctx context.Context, partnerID string) error
 }
 
-func NewPartnerService(repository PartnerRepository) *PartnerService {
-	return &PartnerService{repository: repository}
+// ctxCheckInterval defines after how many partners a running match checks whether its context is done.
+const ctxCheckInterval = 256
+
+var tracer = otel.Tracer("customer-partner/internal/domain")
+
+func NewPartnerService(repository PartnerRepository, logger *slog.Logger) *PartnerService {
+	return &PartnerService{repository: repository, logger: logger, now: time.Now}
 }
 
 // PartnerService implements the domain logic of the partner domain.
 type PartnerService struct {
 	repository PartnerRepository
+	logger     *slog.Logger
+	now        func() time.Time
 }
 
-// GetPartners retrieves the partners from the persistence storage and sorts them after best match. Partners not in
-// operating radius are sorted out.
-func (s *PartnerService) GetPartners(opts GetPartnersOpts) []entities.Partner {
-	partners := s.repository.GetPartnersByMaterial(opts.Material)
-	matches := convertPartnersToMatchesAndFilterByOperatingRadius(
+// WithClock replaces the clock the availability of partners is calculated with, e.g. by a fixed time in tests.
+func (s *PartnerService) WithClock(now func() time.Time) *PartnerService {
+	s.now = now
+	return s
+}
+
+// GetPartners retrieves the partners from the persistence storage and sorts them after best match. Partners who are
+// inactive unless opts.IncludeInactive is set, partners who are not vetted unless opts.IncludeUnverified is set,
+// partners not in operating radius, and partners not available in the window of opts when it is set, are sorted out.
+// Partners with a price list for the material get a price estimate when opts.FloorSize is set. All matches tell
+// whether they are open now and when they are available next.
+// Returns the context error when ctx is done before the match is complete.
+func (s *PartnerService) GetPartners(ctx context.Context, opts GetPartnersOpts) ([]entities.Partner, error) {
+	ctx, span := tracer.Start(ctx, "PartnerService.GetPartners", trace.WithAttributes(
+		attribute.String("partner.material", opts.Material),
+	))
+	defer span.End()
+
+	partners, err := s.repository.GetPartnersByMaterial(ctx, opts.Material)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	now := s.now()
+	if !opts.IncludeInactive {
+		partners = filterActive(partners)
+	}
+	if !opts.IncludeUnverified {
+		partners = filterVetted(partners, now)
+	}
+
+	_, matchSpan := tracer.Start(ctx, "match", trace.WithAttributes(attribute.Int("match.candidates", len(partners))))
+	matches, err := convertPartnersToMatchesAndFilterByOperatingRadius(
+		ctx,
 		partners,
 		opts.CustomerAddressLat,
 		opts.CustomerAddressLong,
 	)
+	matchSpan.SetAttributes(attribute.Int("match.matches", len(matches)))
+	matchSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if !opts.AvailableUntil.IsZero() {
+		from := opts.AvailableFrom
+		if from.Before(now) {
+			from = now
+		}
+		matches = filterByAvailability(matches, from, opts.AvailableUntil)
+	}
+	for i := range matches {
+		setAvailability(&matches[i].partner, now)
+	}
+	if opts.FloorSize > 0 {
+		addPriceEstimates(matches, opts.Material, opts.FloorSize)
+	}
+
+	_, sortSpan := tracer.Start(ctx, "sort", trace.WithAttributes(attribute.String("sort.by", opts.SortBy)))
 	sort.Sort(byRatingAndDistance(matches))
-	return convertMatchesToPartners(matches)
+	if opts.SortBy == SortByPrice {
+		sortByPrice(matches)
+	}
+	sortSpan.End()
+
+	logging.FromContextOr(ctx, s.logger).Debug("matched partners",
+		"material", opts.Material,
+		"candidates", len(partners),
+		"matches", len(matches),
+	)
+	return convertMatchesToPartners(matches), nil
 }
 
-// GetPartner finds a partner by its id.
+// GetPartner finds a partner by its id, including suspended and deleted partners. The partner tells whether they are
+// open now and when they are available next.
 // Can return entities.ErrRecordNotExist when partner with given id does not exist.
-func (s *PartnerService) GetPartner(id string) (entities.Partner, error) {
-	return s.repository.GetPartnerByID(id)
+func (s *PartnerService) GetPartner(ctx context.Context, id string) (entities.Partner, error) {
+	ctx, span := tracer.Start(ctx, "PartnerService.GetPartner", trace.WithAttributes(attribute.String("partner.id", id)))
+	defer span.End()
+	partner, err := s.repository.GetPartnerByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return entities.Partner{}, err
+	}
+	setAvailability(&partner, s.now())
+	return partner, nil
+}
+
+// CreatePartner validates and stores a new partner. The partner is assigned a new id and is pending verification
+// unless a verification status is given. A suspended verification suspends the partner.
+// Can return a *ValidationError when the partner is invalid.
+func (s *PartnerService) CreatePartner(ctx context.Context, partner entities.Partner) (entities.Partner, error) {
+	ctx, span := tracer.Start(ctx, "PartnerService.CreatePartner")
+	defer span.End()
+	partner.OpenNow, partner.NextAvailableAt = nil, nil
+	if partner.VerificationStatus == "" {
+		partner.VerificationStatus = entities.VerificationPending
+	}
+	if err := ValidatePartner(partner); err != nil {
+		return entities.Partner{}, err
+	}
+	s.suspendByVerification(&partner)
+	partner.ID = newID()
+	event, err := newEvent(entities.EventPartnerCreated, partner.ID, partner)
+	if err != nil {
+		return entities.Partner{}, err
+	}
+	if err := s.repository.CreatePartner(ctx, partner, event); err != nil {
+		return entities.Partner{}, err
+	}
+	logging.FromContextOr(ctx, s.logger).Info("partner created", "partner_id", partner.ID)
+	return partner, nil
+}
+
+// UpdatePartner validates and stores the changed partner. A partner without verification status is pending
+// verification, a suspended verification suspends the partner.
+// Can return a *ValidationError when the partner is invalid, entities.ErrRecordNotExist when partner with given id
+// does not exist and entities.ErrPartnerDeleted when the partner was deleted.
+func (s *PartnerService) UpdatePartner(ctx context.Context, partner entities.Partner) (entities.Partner, error) {
+	ctx, span := tracer.Start(ctx, "PartnerService.UpdatePartner", trace.WithAttributes(
+		attribute.String("partner.id", partner.ID),
+	))
+	defer span.End()
+	if partner.DeletedAt != nil {
+		return entities.Partner{}, entities.ErrPartnerDeleted
+	}
+	partner.OpenNow, partner.NextAvailableAt = nil, nil
+	if partner.VerificationStatus == "" {
+		partner.VerificationStatus = entities.VerificationPending
+	}
+	if err := ValidatePartner(partner); err != nil {
+		return entities.Partner{}, err
+	}
+	s.suspendByVerification(&partner)
+	event, err := newEvent(entities.EventPartnerUpdated, partner.ID, partner)
+	if err != nil {
+		return entities.Partner{}, err
+	}
+	if err := s.repository.UpdatePartner(ctx, partner, event); err != nil {
+		return entities.Partner{}, err
+	}
+	logging.FromContextOr(ctx, s.logger).Info("partner updated", "partner_id", partner.ID)
+	return partner, nil
+}
+
+// newID returns a random id for a new entity.
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
 }
 
 func convertPartnersToMatchesAndFilterByOperatingRadius(
+	ctx context.Context,
 	partners []entities.Partner,
 	customerLat float64,
 	customerLong float64,
-) []match {
+) ([]match, error) {
 	var matches []match
-	for _, partner := range partners {
+	for i, partner := range partners {
+		if i%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
 		d := distance(
 			customerLat,
 			customerLong,
@@ -90,11 +288,11 @@ func convertPartnersToMatchesAndFilterByOperatingRadius(
 			matches = append(matches, match)
 		}
 	}
-	return matches
+	return matches, nil
 }
 
 func convertMatchesToPartners(matches []match) []entities.Partner {
-	var partners []entities.Partner
+	partners := make([]entities.Partner, 0, len(matches))
 	for _, m := range matches {
 		partners = append(partners, m.partner)
 	}
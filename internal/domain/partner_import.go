@@ -0,0 +1,276 @@
+package domain
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MaxImportRows limits the number of rows of an import, sales sends a few hundred partners at a time.
+const MaxImportRows = 10000
+
+// ImportRow is a partner read from a row of an import file. Line is the line of the row in the file, Err is set when
+// the row could not be read. Omitted names the attributes the file leaves out by the columns of CSV files, e.g.
+// "rating" or "email", existing partners keep their values of them.
+type ImportRow struct {
+	Line    int
+	Partner entities.Partner
+	Omitted []string
+	Err     error
+}
+
+// ImportError reports a row which was not imported. Field is empty when the row as a whole is invalid.
+type ImportError struct {
+	Line       int    `json:"line"`
+	ExternalID string `json:"external_id,omitempty"`
+	Field      string `json:"field,omitempty"`
+	Reason     string `json:"reason"`
+}
+
+// ImportResult reports the outcome of an import. In a dry run the counts tell what an import would have done.
+type ImportResult struct {
+	DryRun    bool          `json:"dry_run"`
+	Created   int           `json:"created"`
+	Updated   int           `json:"updated"`
+	Unchanged int           `json:"unchanged"`
+	Failed    int           `json:"failed"`
+	Errors    []ImportError `json:"errors"`
+}
+
+// GetAllPartners returns all partners ordered by id, including suspended and deleted partners. It is meant for exports
+// by admins.
+func (s *PartnerService) GetAllPartners(ctx context.Context) ([]entities.Partner, error) {
+	ctx, span := tracer.Start(ctx, "PartnerService.GetAllPartners")
+	defer span.End()
+	partners, err := s.repository.GetAllPartners(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(partners, func(i, j int) bool { return partners[i].ID < partners[j].ID })
+	return partners, nil
+}
+
+// ImportPartners creates or updates the partners of the rows, matched by their external id. Rows without external id
+// update the partner with their id, if that partner has no external id either, so that exports of partners created
+// through the api can be imported again. The rows are the source of truth for the attributes they give, except that
+// the id, the suspension, the flags of certifications and, when a row has none, the verification status of existing
+// partners are kept, as well as the attributes the file omits. New partners are pending verification
+// unless a verification status is given. Rows which cannot be read, lack an external id, repeat an external id, refer
+// to a deleted partner or are invalid are reported as failed, all other rows are imported. Nothing is stored in a dry
+// run.
+// Can return a *ValidationError when there are more than MaxImportRows rows. Errors of the repository abort the import,
+// the result then reports the rows imported before.
+func (s *PartnerService) ImportPartners(ctx context.Context, rows []ImportRow, dryRun bool) (ImportResult, error) {
+	ctx, span := tracer.Start(ctx, "PartnerService.ImportPartners", trace.WithAttributes(
+		attribute.Int("import.rows", len(rows)),
+		attribute.Bool("import.dry_run", dryRun),
+	))
+	defer span.End()
+	result := ImportResult{DryRun: dryRun, Errors: []ImportError{}}
+	if len(rows) > MaxImportRows {
+		return result, &ValidationError{Field: "rows", Reason: fmt.Sprintf("at most %d rows allowed", MaxImportRows)}
+	}
+	stored, err := s.repository.GetAllPartners(ctx)
+	if err != nil {
+		return result, err
+	}
+	byExternalID := make(map[string]entities.Partner, len(stored))
+	byID := map[string]entities.Partner{}
+	for _, partner := range stored {
+		if partner.ExternalID != "" {
+			byExternalID[partner.ExternalID] = partner
+		} else {
+			byID[partner.ID] = partner
+		}
+	}
+	firstLines := map[string]int{}
+	for _, row := range rows {
+		fail := func(err error) {
+			result.Failed++
+			result.Errors = append(result.Errors, newImportError(row, err))
+		}
+		if row.Err != nil {
+			fail(row.Err)
+			continue
+		}
+		partner := row.Partner
+		partner.ExternalID = strings.TrimSpace(partner.ExternalID)
+		key := partner.ExternalID
+		existing, exists := byExternalID[key]
+		if key == "" {
+			existing, exists = byID[strings.TrimSpace(partner.ID)]
+			if !exists {
+				fail(&ValidationError{Field: "external_id", Reason: "must not be empty"})
+				continue
+			}
+			key = "id:" + existing.ID
+		}
+		if line, ok := firstLines[key]; ok {
+			fail(&ValidationError{Field: "external_id", Reason: fmt.Sprintf("duplicate of line %d", line)})
+			continue
+		}
+		firstLines[key] = row.Line
+		if exists && existing.DeletedAt != nil {
+			fail(entities.ErrPartnerDeleted)
+			continue
+		}
+		partner = importedPartner(partner, row.Omitted, existing, exists)
+		if err := ValidatePartner(partner); err != nil {
+			fail(err)
+			continue
+		}
+		if exists && samePartner(partner, existing) {
+			result.Unchanged++
+			continue
+		}
+		if !dryRun {
+			if exists {
+				_, err = s.UpdatePartner(ctx, partner)
+			} else {
+				_, err = s.CreatePartner(ctx, partner)
+			}
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				fail(err)
+				continue
+			}
+			if err != nil {
+				return result, err
+			}
+		}
+		if exists {
+			result.Updated++
+		} else {
+			result.Created++
+		}
+	}
+	span.SetAttributes(
+		attribute.Int("import.created", result.Created),
+		attribute.Int("import.updated", result.Updated),
+		attribute.Int("import.failed", result.Failed),
+	)
+	logging.FromContextOr(ctx, s.logger).Info("partners imported",
+		"dry_run", dryRun,
+		"created", result.Created,
+		"updated", result.Updated,
+		"unchanged", result.Unchanged,
+		"failed", result.Failed,
+	)
+	return result, nil
+}
+
+// importedPartner returns the partner of a row as it is stored. Attributes which are never imported or which the file
+// omits are taken from the existing partner, if it exists.
+func importedPartner(
+	partner entities.Partner,
+	omitted []string,
+	existing entities.Partner,
+	exists bool,
+) entities.Partner {
+	partner.ID = ""
+	partner.OpenNow, partner.NextAvailableAt, partner.PriceEstimate = nil, nil, nil
+	partner.SuspendedAt, partner.SuspensionReason = nil, ""
+	partner.DeletedAt, partner.DeletionReason = nil, ""
+	if exists {
+		partner.ID = existing.ID
+		partner.SuspendedAt, partner.SuspensionReason = existing.SuspendedAt, existing.SuspensionReason
+		partner = keepOmitted(partner, existing, omitted)
+		if partner.Certifications != nil {
+			partner.Certifications = KeepCertificationFlags(partner.Certifications, existing.Certifications)
+		}
+		if partner.VerificationStatus == "" {
+			partner.VerificationStatus = existing.VerificationStatus
+		}
+	}
+	if partner.VerificationStatus == "" {
+		partner.VerificationStatus = entities.VerificationPending
+	}
+	return partner
+}
+
+// keepOmitted returns the partner with the values of the existing partner for the omitted attributes.
+func keepOmitted(partner, existing entities.Partner, omitted []string) entities.Partner {
+	contact := entities.Contact{}
+	if partner.Contact != nil {
+		contact = *partner.Contact
+	}
+	var existingContact entities.Contact
+	if existing.Contact != nil {
+		existingContact = *existing.Contact
+	}
+	for _, attribute := range omitted {
+		switch attribute {
+		case "external_id":
+			partner.ExternalID = existing.ExternalID
+		case "name":
+			partner.Name = existing.Name
+		case "experienced_material":
+			partner.ExperiencedMaterial = existing.ExperiencedMaterial
+		case "latitude":
+			partner.Address.Latitude = existing.Address.Latitude
+		case "longitude":
+			partner.Address.Longitude = existing.Address.Longitude
+		case "city":
+			partner.Address.City = existing.Address.City
+		case "district":
+			partner.Address.District = existing.Address.District
+		case "operating_radius":
+			partner.OperatingRadius = existing.OperatingRadius
+		case "rating":
+			partner.Rating = existing.Rating
+		case "daily_lead_cap":
+			partner.DailyLeadCap = existing.DailyLeadCap
+		case "email":
+			contact.Email = existingContact.Email
+		case "phone":
+			contact.Phone = existingContact.Phone
+		case "language":
+			contact.Language = existingContact.Language
+		case "time_zone":
+			partner.TimeZone = existing.TimeZone
+		case "verification_status":
+			partner.VerificationStatus = existing.VerificationStatus
+		case "insurance_expires_at":
+			partner.InsuranceExpiresAt = existing.InsuranceExpiresAt
+		case "price_lists":
+			partner.PriceLists = existing.PriceLists
+		case "opening_hours":
+			partner.OpeningHours = existing.OpeningHours
+		case "holidays":
+			partner.Holidays = existing.Holidays
+		case "certifications":
+			partner.Certifications = existing.Certifications
+		}
+	}
+	partner.Contact = nil
+	if contact != (entities.Contact{}) {
+		partner.Contact = &contact
+	}
+	return partner
+}
+
+// samePartner reports whether the partners have the same attributes. Comparing the json representation treats empty
+// and missing lists alike.
+func samePartner(a, b entities.Partner) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(aJSON) == string(bJSON)
+}
+
+// newImportError describes why the row was not imported.
+func newImportError(row ImportRow, err error) ImportError {
+	importErr := ImportError{Line: row.Line, ExternalID: strings.TrimSpace(row.Partner.ExternalID), Reason: err.Error()}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		importErr.Field, importErr.Reason = validationErr.Field, validationErr.Reason
+	}
+	return importErr
+}
@@ -0,0 +1,190 @@
+package domain_test
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/domain/mocks"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func importedPartner(externalID string) entities.Partner {
+	return entities.Partner{
+		ExternalID:          externalID,
+		Name:                "Floors",
+		ExperiencedMaterial: []string{"wood"},
+		Address:             entities.Address{Latitude: 48.1, Longitude: 11.6},
+		OperatingRadius:     10,
+	}
+}
+
+func TestPartnerService_ImportPartners(t *testing.T) {
+	changedAt := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	unchanged := importedPartner("SF-3")
+	unchanged.ID, unchanged.VerificationStatus = "3", entities.VerificationVerified
+	suspended := importedPartner("SF-2")
+	suspended.ID, suspended.VerificationStatus = "2", entities.VerificationVerified
+	suspended.SuspendedAt, suspended.SuspensionReason = &changedAt, "Complaints"
+	deleted := importedPartner("SF-4")
+	deleted.ID, deleted.DeletedAt = "4", &changedAt
+	stored := []entities.Partner{{ID: "5"}, suspended, unchanged, deleted}
+
+	renamed := importedPartner("SF-2")
+	renamed.Name = "Floors & More"
+	invalid := importedPartner("SF-6")
+	invalid.OperatingRadius = 0
+	rows := []domain.ImportRow{
+		{Line: 2, Partner: importedPartner(" SF-1 ")},
+		{Line: 3, Partner: renamed},
+		{Line: 4, Partner: importedPartner("SF-3")},
+		{Line: 5, Partner: importedPartner("SF-4")},
+		{Line: 6, Partner: importedPartner("SF-1")},
+		{Line: 7, Partner: importedPartner("")},
+		{Line: 8, Partner: invalid},
+		{Line: 9, Err: &domain.ValidationError{Field: "latitude", Reason: "not a number"}},
+	}
+	expErrors := []domain.ImportError{
+		{Line: 5, ExternalID: "SF-4", Reason: entities.ErrPartnerDeleted.Error()},
+		{Line: 6, ExternalID: "SF-1", Field: "external_id", Reason: "duplicate of line 2"},
+		{Line: 7, Field: "external_id", Reason: "must not be empty"},
+		{Line: 8, ExternalID: "SF-6", Field: "operating_radius", Reason: "must be positive"},
+		{Line: 9, Field: "latitude", Reason: "not a number"},
+	}
+
+	t.Run("Creates and updates partners by external id", func(t *testing.T) {
+		repo := &mocks.PartnerRepository{}
+		repo.On("GetAllPartners", mock.Anything).Return(stored, nil)
+		var created, updated entities.Partner
+		repo.On("CreatePartner", mock.Anything, mock.AnythingOfType("entities.Partner"), mock.Anything).
+			Run(func(args mock.Arguments) { created = args.Get(1).(entities.Partner) }).
+			Return(nil)
+		repo.On("UpdatePartner", mock.Anything, mock.AnythingOfType("entities.Partner"), mock.Anything).
+			Run(func(args mock.Arguments) { updated = args.Get(1).(entities.Partner) }).
+			Return(nil)
+		service := domain.NewPartnerService(repo, logging.Discard())
+
+		result, err := service.ImportPartners(context.Background(), rows, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ImportResult{Created: 1, Updated: 1, Unchanged: 1, Failed: 5, Errors: expErrors}, result)
+		assert.Equal(t, "SF-1", created.ExternalID)
+		assert.NotEmpty(t, created.ID)
+		assert.Equal(t, entities.VerificationPending, created.VerificationStatus)
+		expUpdated := suspended
+		expUpdated.Name = "Floors & More"
+		assert.Equal(t, expUpdated, updated, "id, suspension and verification status are kept")
+		repo.AssertExpectations(t)
+	})
+	t.Run("Keeps the attributes omitted by the file", func(t *testing.T) {
+		expiresAt := time.Date(2099, 12, 31, 0, 0, 0, 0, time.UTC)
+		existing := importedPartner("SF-7")
+		existing.ID, existing.VerificationStatus, existing.Rating = "7", entities.VerificationVerified, 4
+		existing.Contact = &entities.Contact{Email: "info@floors.example", Phone: "+49 89 123456"}
+		existing.PriceLists = []entities.PriceList{{Material: "wood", MinPerSquareMeter: 3000, MaxPerSquareMeter: 4500}}
+		existing.InsuranceExpiresAt = &expiresAt
+		existing.Certifications = []entities.Certification{{Type: "Meister", Issuer: "HWK", ExpiresAt: expiresAt}}
+		row := importedPartner("SF-7")
+		row.OperatingRadius = 30
+		row.Contact = &entities.Contact{Phone: "+49 89 654321"}
+		omitted := []string{"id", "rating", "daily_lead_cap", "email", "language", "time_zone", "verification_status",
+			"insurance_expires_at", "price_lists", "opening_hours", "holidays", "certifications"}
+		repo := &mocks.PartnerRepository{}
+		repo.On("GetAllPartners", mock.Anything).Return([]entities.Partner{existing}, nil)
+		var updated entities.Partner
+		repo.On("UpdatePartner", mock.Anything, mock.AnythingOfType("entities.Partner"), mock.Anything).
+			Run(func(args mock.Arguments) { updated = args.Get(1).(entities.Partner) }).
+			Return(nil)
+		service := domain.NewPartnerService(repo, logging.Discard())
+
+		result, err := service.ImportPartners(context.Background(), []domain.ImportRow{
+			{Line: 2, Partner: row, Omitted: omitted},
+		}, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Updated)
+		expUpdated := existing
+		expUpdated.OperatingRadius = 30
+		expUpdated.Contact = &entities.Contact{Email: "info@floors.example", Phone: "+49 89 654321"}
+		assert.Equal(t, expUpdated, updated)
+		assert.True(t, domain.IsVetted(updated, time.Now()), "the partner is still vetted")
+	})
+	t.Run("Updates partners without external id by their id", func(t *testing.T) {
+		existing := importedPartner("")
+		existing.ID, existing.VerificationStatus = "8", entities.VerificationVerified
+		row := existing
+		row.Name = "Floors & More"
+		repo := &mocks.PartnerRepository{}
+		repo.On("GetAllPartners", mock.Anything).Return([]entities.Partner{existing}, nil)
+		var updated entities.Partner
+		repo.On("UpdatePartner", mock.Anything, mock.AnythingOfType("entities.Partner"), mock.Anything).
+			Run(func(args mock.Arguments) { updated = args.Get(1).(entities.Partner) }).
+			Return(nil)
+		service := domain.NewPartnerService(repo, logging.Discard())
+
+		result, err := service.ImportPartners(context.Background(), []domain.ImportRow{
+			{Line: 2, Partner: row},
+			{Line: 3, Partner: row},
+			{Line: 4, Partner: importedPartner("")},
+		}, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ImportResult{Updated: 1, Failed: 2, Errors: []domain.ImportError{
+			{Line: 3, Field: "external_id", Reason: "duplicate of line 2"},
+			{Line: 4, Field: "external_id", Reason: "must not be empty"},
+		}}, result)
+		assert.Equal(t, row, updated)
+	})
+	t.Run("Stores nothing in a dry run", func(t *testing.T) {
+		repo := &mocks.PartnerRepository{}
+		repo.On("GetAllPartners", mock.Anything).Return(stored, nil)
+		service := domain.NewPartnerService(repo, logging.Discard())
+
+		result, err := service.ImportPartners(context.Background(), rows, true)
+
+		require.NoError(t, err)
+		expResult := domain.ImportResult{DryRun: true, Created: 1, Updated: 1, Unchanged: 1, Failed: 5, Errors: expErrors}
+		assert.Equal(t, expResult, result)
+		repo.AssertExpectations(t)
+	})
+	t.Run("Rejects too many rows", func(t *testing.T) {
+		repo := &mocks.PartnerRepository{}
+		service := domain.NewPartnerService(repo, logging.Discard())
+
+		_, err := service.ImportPartners(context.Background(), make([]domain.ImportRow, domain.MaxImportRows+1), false)
+
+		var validationErr *domain.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "rows", validationErr.Field)
+		repo.AssertExpectations(t)
+	})
+	t.Run("Aborts on repository error", func(t *testing.T) {
+		repoErr := errors.New("connection lost")
+		repo := &mocks.PartnerRepository{}
+		repo.On("GetAllPartners", mock.Anything).Return(stored, nil)
+		repo.On("CreatePartner", mock.Anything, mock.Anything, mock.Anything).Return(repoErr)
+		service := domain.NewPartnerService(repo, logging.Discard())
+
+		_, err := service.ImportPartners(context.Background(), rows, false)
+
+		assert.ErrorIs(t, err, repoErr)
+		repo.AssertNotCalled(t, "UpdatePartner", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestPartnerService_GetAllPartners(t *testing.T) {
+	repo := &mocks.PartnerRepository{}
+	repo.On("GetAllPartners", mock.Anything).Return([]entities.Partner{{ID: "2"}, {ID: "1"}}, nil)
+	service := domain.NewPartnerService(repo, logging.Discard())
+
+	partners, err := service.GetAllPartners(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []entities.Partner{{ID: "1"}, {ID: "2"}}, partners)
+}
@@ -1,12 +1,21 @@
 package domain_test
 
 import (
+	"context"
+	"customer-partner/internal/db"
 	"customer-partner/internal/domain"
 	"customer-partner/internal/domain/mocks"
 	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"customer-partner/internal/seed"
+	"encoding/json"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 //go:generate mockery --name PartnerRepository
@@ -139,17 +148,49 @@ func TestPartnerService_GetPartners(t *testing.T) {
 			expLen: 3,
 			expIDs: []string{"345", "234", "123"},
 		},
+		{
+			name: "Returns higher rated partner before closer partner",
+			opts: domain.GetPartnersOpts{
+				Material:            "wood",
+				CustomerAddressLat:  48.3535,
+				CustomerAddressLong: 11.7812,
+			},
+			repoReturn: []entities.Partner{
+				{
+					ID:              "near",
+					Address:         entities.Address{Latitude: 48.3535, Longitude: 11.7812},
+					OperatingRadius: 50,
+					Rating:          3,
+				},
+				{
+					ID:              "far",
+					Address:         entities.Address{Latitude: 48.2186, Longitude: 11.6236},
+					OperatingRadius: 50,
+					Rating:          5,
+				},
+				{
+					ID:              "between",
+					Address:         entities.Address{Latitude: 48.4021, Longitude: 11.7511},
+					OperatingRadius: 50,
+					Rating:          4,
+				},
+			},
+			expLen: 3,
+			expIDs: []string{"far", "between", "near"},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			repo := &mocks.PartnerRepository{}
-			repo.On("GetPartnersByMaterial", tt.opts.Material).Return(tt.repoReturn)
-			service := domain.NewPartnerService(repo)
+			ctx := context.Background()
+			repo.On("GetPartnersByMaterial", mock.Anything, tt.opts.Material).Return(vetted(tt.repoReturn...), nil)
+			service := domain.NewPartnerService(repo, logging.Discard())
 
-			actual := service.GetPartners(tt.opts)
+			actual, err := service.GetPartners(ctx, tt.opts)
 
 			repo.AssertExpectations(t)
+			assert.NoError(t, err)
 			assert.Len(t, actual, tt.expLen)
 			for i, expID := range tt.expIDs {
 				assert.Equal(t, expID, actual[i].ID)
@@ -157,3 +198,293 @@ func TestPartnerService_GetPartners(t *testing.T) {
 		})
 	}
 }
+
+func TestPartnerService_GetPartners_SeedRanking(t *testing.T) {
+	repo := db.NewPartnerInMemoryRepositoryFrom(seed.Partners(seed.Options{Count: 2000, Seed: 3}), nil)
+	service := domain.NewPartnerService(repo, logging.Discard())
+	ctx := context.Background()
+
+	for _, customer := range seed.Customers(seed.Options{Count: 50, Seed: 4}) {
+		opts := domain.GetPartnersOpts{
+			Material:            customer.Material,
+			CustomerAddressLat:  customer.Latitude,
+			CustomerAddressLong: customer.Longitude,
+		}
+		partners, err := service.GetPartners(ctx, opts)
+		require.NoError(t, err)
+		require.NotEmpty(t, partners, "customers near seeded cities must be matched")
+
+		distanceTo := func(p entities.Partner) float64 {
+			return domain.Distance(customer.Latitude, customer.Longitude, p.Address.Latitude, p.Address.Longitude, "K")
+		}
+		for i := 1; i < len(partners); i++ {
+			prev, next := partners[i-1], partners[i]
+			require.GreaterOrEqual(t, prev.Rating, next.Rating, "ratings must not increase, customer %+v", customer)
+			if prev.Rating == next.Rating {
+				require.LessOrEqual(t, distanceTo(prev), distanceTo(next),
+					"distance must grow within rating %d, customer %+v", prev.Rating, customer)
+			}
+		}
+	}
+}
+
+func TestPartnerService_GetPartners_PriceEstimate(t *testing.T) {
+	partners := []entities.Partner{
+		{
+			ID:              "345",
+			Address:         entities.Address{Latitude: 48.2186, Longitude: 11.6236},
+			OperatingRadius: 20,
+			Rating:          3,
+			PriceLists:      []entities.PriceList{{Material: "carpet", MinPerSquareMeter: 1000, MaxPerSquareMeter: 1000}},
+		},
+		{
+			ID:              "234",
+			Address:         entities.Address{Latitude: 48.2186, Longitude: 11.6236},
+			OperatingRadius: 20,
+			Rating:          4,
+			PriceLists: []entities.PriceList{{
+				Material:             "wood",
+				MinPerSquareMeter:    2000,
+				MaxPerSquareMeter:    2500,
+				MinimumCharge:        120000,
+				TravelSurchargePerKm: 50,
+				FreeTravelDistance:   20,
+			}},
+		},
+		{
+			ID:              "123",
+			Address:         entities.Address{Latitude: 48.4021, Longitude: 11.7511},
+			OperatingRadius: 10,
+			Rating:          5,
+			PriceLists: []entities.PriceList{{
+				Material:             "wood",
+				MinPerSquareMeter:    3000,
+				MaxPerSquareMeter:    4000,
+				TravelSurchargePerKm: 100,
+				FreeTravelDistance:   2,
+			}},
+		},
+	}
+	type testCase struct {
+		name         string
+		opts         domain.GetPartnersOpts
+		expIDs       []string
+		expEstimates map[string]*entities.PriceEstimate
+	}
+	tests := []testCase{
+		{
+			name: "Returns no estimates without floor size",
+			opts: domain.GetPartnersOpts{
+				Material:            "wood",
+				CustomerAddressLat:  48.3535,
+				CustomerAddressLong: 11.7812,
+			},
+			expIDs: []string{"123", "234", "345"},
+		},
+		{
+			name: "Estimates price with minimum charge and travel surcharge",
+			opts: domain.GetPartnersOpts{
+				Material:            "wood",
+				CustomerAddressLat:  48.3535,
+				CustomerAddressLong: 11.7812,
+				FloorSize:           50,
+			},
+			expIDs: []string{"123", "234", "345"},
+			expEstimates: map[string]*entities.PriceEstimate{
+				// 5.8 km away, 4 started kilometers beyond the free distance
+				"123": {Currency: "EUR", Min: 150400, Max: 200400},
+				"234": {Currency: "EUR", Min: 120000, Max: 125000},
+			},
+		},
+		{
+			name: "Sorts by price with partners without estimate last",
+			opts: domain.GetPartnersOpts{
+				Material:            "wood",
+				CustomerAddressLat:  48.3535,
+				CustomerAddressLong: 11.7812,
+				FloorSize:           50,
+				SortBy:              domain.SortByPrice,
+			},
+			expIDs: []string{"234", "123", "345"},
+			expEstimates: map[string]*entities.PriceEstimate{
+				"123": {Currency: "EUR", Min: 150400, Max: 200400},
+				"234": {Currency: "EUR", Min: 120000, Max: 125000},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.PartnerRepository{}
+			repo.On("GetPartnersByMaterial", mock.Anything, "wood").Return(vetted(partners...), nil)
+			service := domain.NewPartnerService(repo, logging.Discard())
+
+			actual, err := service.GetPartners(context.Background(), tt.opts)
+
+			repo.AssertExpectations(t)
+			assert.NoError(t, err)
+			var ids []string
+			for _, partner := range actual {
+				ids = append(ids, partner.ID)
+				assert.Equal(t, tt.expEstimates[partner.ID], partner.PriceEstimate, partner.ID)
+			}
+			assert.Equal(t, tt.expIDs, ids)
+		})
+	}
+}
+
+func TestPartnerService_GetPartners_Errors(t *testing.T) {
+	repoErr := errors.New("connection lost")
+	type testCase struct {
+		name       string
+		ctx        func() context.Context
+		repoReturn []entities.Partner
+		repoErr    error
+		expErr     error
+	}
+	tests := []testCase{
+		{
+			name:    "Returns repository error",
+			ctx:     context.Background,
+			repoErr: repoErr,
+			expErr:  repoErr,
+		},
+		{
+			name: "Returns context error when canceled",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			repoReturn: []entities.Partner{{ID: "123", OperatingRadius: 10}},
+			expErr:     context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.PartnerRepository{}
+			repo.On("GetPartnersByMaterial", mock.Anything, "wood").Return(vetted(tt.repoReturn...), tt.repoErr)
+			service := domain.NewPartnerService(repo, logging.Discard())
+
+			actual, err := service.GetPartners(tt.ctx(), domain.GetPartnersOpts{Material: "wood"})
+
+			repo.AssertExpectations(t)
+			assert.ErrorIs(t, err, tt.expErr)
+			assert.Nil(t, actual)
+		})
+	}
+}
+
+func TestValidatePartner(t *testing.T) {
+	valid := entities.Partner{
+		Name:                "Floors",
+		ExperiencedMaterial: []string{"wood", "tiles"},
+		Address:             entities.Address{Latitude: 48.1, Longitude: 11.6},
+		OperatingRadius:     10,
+		Rating:              5,
+	}
+	priceList := entities.PriceList{Material: "wood", MinPerSquareMeter: 3000, MaxPerSquareMeter: 4000}
+	invertedRange := entities.PriceList{Material: "wood", MinPerSquareMeter: 4000, MaxPerSquareMeter: 3000}
+	morning := entities.OpeningHours{Weekday: "monday", Opens: "08:00", Closes: "12:00"}
+	overnight := entities.OpeningHours{Weekday: "monday", Opens: "22:00", Closes: "06:00"}
+	untilMidnight := entities.OpeningHours{Weekday: "sunday", Opens: "20:00", Closes: "24:00"}
+	certification := entities.Certification{Type: "Master", Issuer: "HWK", ExpiresAt: time.Now().AddDate(1, 0, 0)}
+	anonymous := entities.Certification{Type: "Master", ExpiresAt: time.Now().AddDate(1, 0, 0)}
+	undated := entities.Certification{Type: "Master", Issuer: "HWK"}
+	abbreviated := entities.OpeningHours{Weekday: "mon", Opens: "08:00", Closes: "12:00"}
+	unpadded := entities.OpeningHours{Weekday: "monday", Opens: "8:00", Closes: "12:00"}
+	type testCase struct {
+		name     string
+		change   func(p *entities.Partner)
+		expField string
+	}
+	tests := []testCase{
+		{name: "Accepts valid partner", change: func(p *entities.Partner) {}},
+		{name: "Rejects empty name", change: func(p *entities.Partner) { p.Name = " " }, expField: "name"},
+		{name: "Rejects no materials", change: func(p *entities.Partner) { p.ExperiencedMaterial = nil }, expField: "experienced_material"},
+		{name: "Rejects unknown material", change: func(p *entities.Partner) { p.ExperiencedMaterial = []string{"glass"} }, expField: "experienced_material"},
+		{name: "Rejects duplicate material", change: func(p *entities.Partner) { p.ExperiencedMaterial = []string{"wood", "wood"} }, expField: "experienced_material"},
+		{name: "Rejects latitude out of range", change: func(p *entities.Partner) { p.Address.Latitude = 91 }, expField: "address"},
+		{name: "Rejects longitude out of range", change: func(p *entities.Partner) { p.Address.Longitude = -181 }, expField: "address"},
+		{name: "Rejects zero radius", change: func(p *entities.Partner) { p.OperatingRadius = 0 }, expField: "operating_radius"},
+		{name: "Rejects rating above maximum", change: func(p *entities.Partner) { p.Rating = 6 }, expField: "rating"},
+		{name: "Accepts contact", change: func(p *entities.Partner) { p.Contact = &entities.Contact{Email: "info@floors.example", Language: "en"} }},
+		{name: "Rejects empty contact", change: func(p *entities.Partner) { p.Contact = &entities.Contact{Language: "en"} }, expField: "contact"},
+		{name: "Rejects contact with invalid phone", change: func(p *entities.Partner) { p.Contact = &entities.Contact{Phone: "call us"} }, expField: "contact"},
+		{name: "Accepts price list", change: func(p *entities.Partner) { p.PriceLists = []entities.PriceList{priceList} }},
+		{name: "Rejects price list for material not experienced", change: func(p *entities.Partner) { p.PriceLists = []entities.PriceList{{Material: "carpet"}} }, expField: "price_lists"},
+		{name: "Rejects duplicate price list", change: func(p *entities.Partner) { p.PriceLists = []entities.PriceList{{Material: "wood"}, {Material: "wood"}} }, expField: "price_lists"},
+		{name: "Rejects negative price", change: func(p *entities.Partner) { p.PriceLists = []entities.PriceList{{Material: "wood", MinimumCharge: -1}} }, expField: "price_lists"},
+		{name: "Rejects price range with max below min", change: func(p *entities.Partner) { p.PriceLists = []entities.PriceList{invertedRange} }, expField: "price_lists"},
+		{name: "Accepts time zone", change: func(p *entities.Partner) { p.TimeZone = "America/New_York" }},
+		{name: "Rejects unknown time zone", change: func(p *entities.Partner) { p.TimeZone = "Mars/Olympus" }, expField: "time_zone"},
+		{name: "Rejects local time zone", change: func(p *entities.Partner) { p.TimeZone = "Local" }, expField: "time_zone"},
+		{name: "Accepts opening hours", change: func(p *entities.Partner) { p.OpeningHours = []entities.OpeningHours{morning, untilMidnight} }},
+		{name: "Rejects unknown weekday", change: func(p *entities.Partner) { p.OpeningHours = []entities.OpeningHours{abbreviated} }, expField: "opening_hours"},
+		{name: "Rejects malformed time", change: func(p *entities.Partner) { p.OpeningHours = []entities.OpeningHours{unpadded} }, expField: "opening_hours"},
+		{name: "Rejects closing before opening", change: func(p *entities.Partner) { p.OpeningHours = []entities.OpeningHours{overnight} }, expField: "opening_hours"},
+		{name: "Accepts holidays", change: func(p *entities.Partner) { p.Holidays = []string{"2024-12-24"} }},
+		{name: "Rejects malformed holiday", change: func(p *entities.Partner) { p.Holidays = []string{"24.12.2024"} }, expField: "holidays"},
+		{name: "Accepts verification status", change: func(p *entities.Partner) { p.VerificationStatus = entities.VerificationSuspended }},
+		{name: "Rejects unknown verification status", change: func(p *entities.Partner) { p.VerificationStatus = "approved" }, expField: "verification_status"},
+		{name: "Accepts certification", change: func(p *entities.Partner) { p.Certifications = []entities.Certification{certification} }},
+		{name: "Rejects certification without issuer", change: func(p *entities.Partner) { p.Certifications = []entities.Certification{anonymous} }, expField: "certifications"},
+		{name: "Rejects certification without expiry", change: func(p *entities.Partner) { p.Certifications = []entities.Certification{undated} }, expField: "certifications"},
+		{name: "Rejects contact with unknown language", change: func(p *entities.Partner) { p.Contact = &entities.Contact{Phone: "+49 89 1234567", Language: "xx"} }, expField: "contact"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			partner := valid
+			partner.ExperiencedMaterial = append([]string(nil), valid.ExperiencedMaterial...)
+			tt.change(&partner)
+
+			err := domain.ValidatePartner(partner)
+
+			if tt.expField == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *domain.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expField, validationErr.Field)
+		})
+	}
+}
+
+func TestPartnerService_CreateAndUpdatePartner(t *testing.T) {
+	partner := entities.Partner{
+		Name:                "Floors",
+		ExperiencedMaterial: []string{"wood"},
+		OperatingRadius:     10,
+	}
+	repo := &mocks.PartnerRepository{}
+	repo.On("CreatePartner", mock.Anything, mock.MatchedBy(func(p entities.Partner) bool {
+		return p.ID != "" && p.Name == "Floors"
+	}), mock.MatchedBy(func(e entities.Event) bool {
+		var data entities.Partner
+		return e.ID != "" && e.Type == entities.EventPartnerCreated && e.AggregateID != "" &&
+			json.Unmarshal(e.Data, &data) == nil && data.ID == e.AggregateID && data.Name == "Floors"
+	})).Return(nil)
+	repo.On("UpdatePartner", mock.Anything, mock.MatchedBy(func(p entities.Partner) bool {
+		return p.ID == "123"
+	}), mock.MatchedBy(func(e entities.Event) bool {
+		return e.Type == entities.EventPartnerUpdated && e.AggregateID == "123"
+	})).Return(entities.ErrRecordNotExist)
+	service := domain.NewPartnerService(repo, logging.Discard())
+
+	created, err := service.CreatePartner(context.Background(), partner)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+
+	partner.ID = "123"
+	_, err = service.UpdatePartner(context.Background(), partner)
+	assert.ErrorIs(t, err, entities.ErrRecordNotExist)
+
+	partner.Name = ""
+	_, err = service.UpdatePartner(context.Background(), partner)
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	repo.AssertExpectations(t)
+}
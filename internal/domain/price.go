@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"customer-partner/internal/entities"
+	"math"
+	"sort"
+)
+
+// Orders of the partners returned by PartnerService.GetPartners.
+const (
+	// SortByRating orders by rating first and distance second. It is the default.
+	SortByRating = "rating"
+	// SortByPrice orders by the lower bound of the price estimate. Partners without estimate come last, ties keep the
+	// order by rating.
+	SortByPrice = "price"
+)
+
+// SortOrders lists the supported orders of the partners.
+var SortOrders = []string{SortByRating, SortByPrice}
+
+// estimatePrice estimates the price range of a floor of floorSize square meters for a customer distance kilometers
+// away. The price per square meter is applied first, then the minimum charge and last the travel surcharge.
+func estimatePrice(list entities.PriceList, floorSize float64, distance float64) entities.PriceEstimate {
+	travel := 0
+	if beyond := distance - float64(list.FreeTravelDistance); beyond > 0 {
+		travel = int(math.Ceil(beyond)) * list.TravelSurchargePerKm
+	}
+	price := func(perSquareMeter int) int {
+		amount := int(math.Round(floorSize * float64(perSquareMeter)))
+		if amount < list.MinimumCharge {
+			amount = list.MinimumCharge
+		}
+		return amount + travel
+	}
+	return entities.PriceEstimate{
+		Currency: entities.Currency,
+		Min:      price(list.MinPerSquareMeter),
+		Max:      price(list.MaxPerSquareMeter),
+	}
+}
+
+// addPriceEstimates sets the price estimate of every match whose partner has a price list for the material.
+func addPriceEstimates(matches []match, material string, floorSize float64) {
+	for i := range matches {
+		for _, list := range matches[i].partner.PriceLists {
+			if list.Material == material {
+				estimate := estimatePrice(list, floorSize, matches[i].distance)
+				matches[i].partner.PriceEstimate = &estimate
+				break
+			}
+		}
+	}
+}
+
+// sortByPrice orders matches sorted by rating after the lower bound of their price estimate.
+func sortByPrice(matches []match) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i].partner.PriceEstimate, matches[j].partner.PriceEstimate
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.Min < b.Min
+	})
+}
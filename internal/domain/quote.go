@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QuoteRepository defines an interface which a persistence storage for quotes must provide. Quotes advance the status
+// of their offer request, so they are stored in the same transaction as the offer request.
+type QuoteRepository interface {
+	// CreateQuote stores a new quote, supersedes the pending quotes of the offer request and marks the offer request
+	// as quoted. The events are added to the outbox in the same transaction.
+	// Can return entities.ErrRecordNotExist when the offer request does not exist and entities.ErrOfferRequestClosed
+	// when the partner declined it or the customer ordered.
+	CreateQuote(ctx context.Context, quote entities.Quote, events ...entities.Event) error
+	// GetQuoteByID can return entities.ErrRecordNotExist when quote with given id does not exist.
+	GetQuoteByID(ctx context.Context, id string) (entities.Quote, error)
+	// GetQuotesByOfferRequest returns the quotes of an offer request, oldest first.
+	GetQuotesByOfferRequest(ctx context.Context, offerRequestID string) ([]entities.Quote, error)
+	// DecideQuote sets the status of a pending quote to entities.QuoteAccepted or entities.QuoteDeclined and advances
+	// the offer request. The events are added to the outbox in the same transaction.
+	// Can return entities.ErrRecordNotExist when quote with given id does not exist and entities.ErrQuoteNotPending
+	// when the quote is not pending anymore.
+	DecideQuote(
+		ctx context.Context,
+		id, status string,
+		decidedAt time.Time,
+		events ...entities.Event,
+	) (entities.Quote, error)
+}
+
+func NewQuoteService(repository QuoteRepository, offerRequests OfferRequestRepository, logger *slog.Logger) *QuoteService {
+	return &QuoteService{repository: repository, offerRequests: offerRequests, logger: logger, now: time.Now}
+}
+
+// QuoteService implements the domain logic of quotes: partners quote offer requests sent to them and customers accept
+// or decline the quotes.
+type QuoteService struct {
+	repository    QuoteRepository
+	offerRequests OfferRequestRepository
+	logger        *slog.Logger
+	now           func() time.Time
+}
+
+// CreateQuote validates and stores a new quote for an offer request. The quote is assigned a new id, the partner of
+// the offer request and the time of creation, and its amounts are calculated from the line items. A pending quote
+// sent before is superseded.
+// Can return a *ValidationError when the quote is invalid, entities.ErrRecordNotExist when the offer request does not
+// exist and entities.ErrOfferRequestClosed when the partner declined it or the customer ordered.
+func (s *QuoteService) CreateQuote(ctx context.Context, quote entities.Quote) (entities.Quote, error) {
+	ctx, span := tracer.Start(ctx, "QuoteService.CreateQuote", trace.WithAttributes(
+		attribute.String("offer_request.id", quote.OfferRequestID),
+	))
+	defer span.End()
+	now := s.now().UTC()
+	if err := ValidateQuote(quote, now); err != nil {
+		return entities.Quote{}, err
+	}
+	request, err := s.offerRequests.GetOfferRequestByID(ctx, quote.OfferRequestID)
+	if err != nil {
+		return entities.Quote{}, err
+	}
+	quote.ID = newID()
+	quote.PartnerID = request.PartnerID
+	quote.Status = entities.QuotePending
+	quote.CreatedAt = now.Truncate(time.Second)
+	quote.DecidedAt = nil
+	calculateQuote(&quote)
+	event, err := newEvent(entities.EventQuoteCreated, quote.ID, QuoteCreated{
+		ID:             quote.ID,
+		OfferRequestID: quote.OfferRequestID,
+		PartnerID:      quote.PartnerID,
+		Currency:       quote.Currency,
+		TotalAmount:    quote.TotalAmount,
+		ValidUntil:     quote.ValidUntil,
+		CreatedAt:      quote.CreatedAt,
+	})
+	if err != nil {
+		return entities.Quote{}, err
+	}
+	if err := s.repository.CreateQuote(ctx, quote, event); err != nil {
+		return entities.Quote{}, err
+	}
+	logging.FromContextOr(ctx, s.logger).Info("quote created",
+		"quote_id", quote.ID,
+		"offer_request_id", quote.OfferRequestID,
+		"partner_id", quote.PartnerID,
+	)
+	return quote, nil
+}
+
+// GetQuote finds a quote by its id. Pending quotes whose validity is over are returned as expired.
+// Can return entities.ErrRecordNotExist when quote with given id does not exist.
+func (s *QuoteService) GetQuote(ctx context.Context, id string) (entities.Quote, error) {
+	ctx, span := tracer.Start(ctx, "QuoteService.GetQuote", trace.WithAttributes(attribute.String("quote.id", id)))
+	defer span.End()
+	quote, err := s.repository.GetQuoteByID(ctx, id)
+	if err != nil {
+		return entities.Quote{}, err
+	}
+	return expireQuote(quote, s.now()), nil
+}
+
+// GetQuotesByOfferRequest returns the quotes of an offer request, oldest first. Pending quotes whose validity is over
+// are returned as expired.
+func (s *QuoteService) GetQuotesByOfferRequest(ctx context.Context, offerRequestID string) ([]entities.Quote, error) {
+	ctx, span := tracer.Start(ctx, "QuoteService.GetQuotesByOfferRequest", trace.WithAttributes(
+		attribute.String("offer_request.id", offerRequestID),
+	))
+	defer span.End()
+	quotes, err := s.repository.GetQuotesByOfferRequest(ctx, offerRequestID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	for i := range quotes {
+		quotes[i] = expireQuote(quotes[i], now)
+	}
+	return quotes, nil
+}
+
+// DecideQuote stores whether the customer accepts or declines a pending quote. Accepting a quote orders the offer
+// request.
+// Can return a *ValidationError when the decision is unknown, entities.ErrRecordNotExist when quote with given id does
+// not exist and entities.ErrQuoteNotPending when the quote was decided, superseded or expired.
+func (s *QuoteService) DecideQuote(ctx context.Context, id, decision string) (entities.Quote, error) {
+	ctx, span := tracer.Start(ctx, "QuoteService.DecideQuote", trace.WithAttributes(
+		attribute.String("quote.id", id),
+		attribute.String("quote.decision", decision),
+	))
+	defer span.End()
+	eventType := entities.EventQuoteAccepted
+	switch decision {
+	case entities.QuoteAccepted:
+	case entities.QuoteDeclined:
+		eventType = entities.EventQuoteDeclined
+	default:
+		return entities.Quote{}, &ValidationError{Field: "decision", Reason: fmt.Sprintf("unknown decision %q", decision)}
+	}
+	quote, err := s.repository.GetQuoteByID(ctx, id)
+	if err != nil {
+		return entities.Quote{}, err
+	}
+	now := s.now().UTC()
+	if expireQuote(quote, now).Status != entities.QuotePending {
+		return entities.Quote{}, entities.ErrQuoteNotPending
+	}
+	decidedAt := now.Truncate(time.Second)
+	event, err := newEvent(eventType, id, QuoteDecided{
+		ID:             id,
+		OfferRequestID: quote.OfferRequestID,
+		PartnerID:      quote.PartnerID,
+		Status:         decision,
+		DecidedAt:      decidedAt,
+	})
+	if err != nil {
+		return entities.Quote{}, err
+	}
+	quote, err = s.repository.DecideQuote(ctx, id, decision, decidedAt, event)
+	if err != nil {
+		return entities.Quote{}, err
+	}
+	logging.FromContextOr(ctx, s.logger).Info("quote decided",
+		"quote_id", id,
+		"offer_request_id", quote.OfferRequestID,
+		"decision", decision,
+	)
+	return quote, nil
+}
+
+// calculateQuote sets the amounts of the line items and the totals of the quote. Amounts are rounded half away from
+// zero to the minor unit.
+func calculateQuote(q *entities.Quote) {
+	q.NetAmount = 0
+	for i := range q.LineItems {
+		item := &q.LineItems[i]
+		item.Amount = int(math.Round(item.Quantity * float64(item.UnitPrice)))
+		q.NetAmount += item.Amount
+	}
+	q.VATAmount = int(math.Round(float64(q.NetAmount) * float64(q.VATRate) / 10000))
+	q.TotalAmount = q.NetAmount + q.VATAmount
+}
+
+// expireQuote returns the quote as expired when it is pending and its validity is over at now.
+func expireQuote(q entities.Quote, now time.Time) entities.Quote {
+	if q.Status == entities.QuotePending && now.After(q.ValidUntil) {
+		q.Status = entities.QuoteExpired
+	}
+	return q
+}
@@ -0,0 +1,225 @@
+package domain_test
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/domain/mocks"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockery --name QuoteRepository
+
+func validQuote() entities.Quote {
+	return entities.Quote{
+		OfferRequestID: "r1",
+		Currency:       "EUR",
+		LineItems: []entities.QuoteLineItem{
+			{Kind: entities.LineItemMaterial, Description: "Oak parquet", Quantity: 42.5, Unit: "m²", UnitPrice: 3999},
+			{Kind: entities.LineItemLabour, Description: "Laying", Quantity: 16, Unit: "h", UnitPrice: 5500},
+		},
+		VATRate:    1900,
+		ValidUntil: time.Now().Add(14 * 24 * time.Hour),
+	}
+}
+
+func TestValidateQuote(t *testing.T) {
+	type testCase struct {
+		name     string
+		modify   func(q *entities.Quote)
+		expField string
+	}
+	tests := []testCase{
+		{name: "Accepts valid quote", modify: func(q *entities.Quote) {}},
+		{name: "Rejects unknown currency", modify: func(q *entities.Quote) { q.Currency = "eur" }, expField: "currency"},
+		{name: "Rejects no line items", modify: func(q *entities.Quote) { q.LineItems = nil }, expField: "line_items"},
+		{name: "Rejects unknown kind", modify: func(q *entities.Quote) { q.LineItems[0].Kind = "tip" }, expField: "line_items"},
+		{name: "Rejects empty description", modify: func(q *entities.Quote) { q.LineItems[0].Description = " " }, expField: "line_items"},
+		{name: "Rejects zero quantity", modify: func(q *entities.Quote) { q.LineItems[0].Quantity = 0 }, expField: "line_items"},
+		{name: "Rejects negative unit price", modify: func(q *entities.Quote) { q.LineItems[0].UnitPrice = -1 }, expField: "line_items"},
+		{name: "Accepts zero VAT", modify: func(q *entities.Quote) { q.VATRate = 0 }},
+		{name: "Rejects VAT above 100 %", modify: func(q *entities.Quote) { q.VATRate = 10001 }, expField: "vat_rate"},
+		{name: "Rejects validity in the past", modify: func(q *entities.Quote) { q.ValidUntil = time.Now().Add(-time.Minute) }, expField: "valid_until"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			quote := validQuote()
+			tt.modify(&quote)
+
+			err := domain.ValidateQuote(quote, time.Now())
+
+			if tt.expField == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *domain.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expField, validationErr.Field)
+		})
+	}
+}
+
+func TestQuoteService_CreateQuote(t *testing.T) {
+	t.Run("Calculates the amounts and stores the quote with an event", func(t *testing.T) {
+		repo := &mocks.QuoteRepository{}
+		offerRequests := &mocks.OfferRequestRepository{}
+		offerRequests.On("GetOfferRequestByID", mock.Anything, "r1").
+			Return(entities.OfferRequest{ID: "r1", PartnerID: "1"}, nil)
+		repo.On("CreateQuote", mock.Anything, mock.AnythingOfType("entities.Quote"),
+			mock.MatchedBy(func(e entities.Event) bool {
+				var data domain.QuoteCreated
+				return e.Type == entities.EventQuoteCreated &&
+					json.Unmarshal(e.Data, &data) == nil &&
+					data.PartnerID == "1" &&
+					data.TotalAmount == 306970
+			})).
+			Return(nil)
+		service := domain.NewQuoteService(repo, offerRequests, logging.Discard())
+
+		quote, err := service.CreateQuote(context.Background(), validQuote())
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, quote.ID)
+		assert.Equal(t, "1", quote.PartnerID)
+		assert.Equal(t, entities.QuotePending, quote.Status)
+		// 42.5 m² at 39.99 are 1699.575, rounded to 1699.58
+		assert.Equal(t, 169958, quote.LineItems[0].Amount)
+		assert.Equal(t, 88000, quote.LineItems[1].Amount)
+		assert.Equal(t, 257958, quote.NetAmount)
+		assert.Equal(t, 49012, quote.VATAmount)
+		assert.Equal(t, 306970, quote.TotalAmount)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Returns error of unknown offer request", func(t *testing.T) {
+		offerRequests := &mocks.OfferRequestRepository{}
+		offerRequests.On("GetOfferRequestByID", mock.Anything, "r1").
+			Return(entities.OfferRequest{}, entities.ErrRecordNotExist)
+		service := domain.NewQuoteService(&mocks.QuoteRepository{}, offerRequests, logging.Discard())
+
+		_, err := service.CreateQuote(context.Background(), validQuote())
+
+		assert.ErrorIs(t, err, entities.ErrRecordNotExist)
+	})
+
+	t.Run("Returns error of closed offer request", func(t *testing.T) {
+		repo := &mocks.QuoteRepository{}
+		offerRequests := &mocks.OfferRequestRepository{}
+		offerRequests.On("GetOfferRequestByID", mock.Anything, "r1").
+			Return(entities.OfferRequest{ID: "r1", PartnerID: "1"}, nil)
+		repo.On("CreateQuote", mock.Anything, mock.Anything, mock.Anything).Return(entities.ErrOfferRequestClosed)
+		service := domain.NewQuoteService(repo, offerRequests, logging.Discard())
+
+		_, err := service.CreateQuote(context.Background(), validQuote())
+
+		assert.ErrorIs(t, err, entities.ErrOfferRequestClosed)
+	})
+}
+
+func TestQuoteService_GetQuotesByOfferRequest(t *testing.T) {
+	repo := &mocks.QuoteRepository{}
+	repo.On("GetQuotesByOfferRequest", mock.Anything, "r1").Return([]entities.Quote{
+		{ID: "q1", Status: entities.QuotePending, ValidUntil: time.Now().Add(-time.Hour)},
+		{ID: "q2", Status: entities.QuotePending, ValidUntil: time.Now().Add(time.Hour)},
+		{ID: "q3", Status: entities.QuoteDeclined, ValidUntil: time.Now().Add(-time.Hour)},
+	}, nil)
+	service := domain.NewQuoteService(repo, &mocks.OfferRequestRepository{}, logging.Discard())
+
+	quotes, err := service.GetQuotesByOfferRequest(context.Background(), "r1")
+
+	require.NoError(t, err)
+	require.Len(t, quotes, 3)
+	assert.Equal(t, entities.QuoteExpired, quotes[0].Status)
+	assert.Equal(t, entities.QuotePending, quotes[1].Status)
+	assert.Equal(t, entities.QuoteDeclined, quotes[2].Status)
+}
+
+func TestQuoteService_DecideQuote(t *testing.T) {
+	type testCase struct {
+		name         string
+		decision     string
+		stored       entities.Quote
+		expEventType string
+		expErr       error
+		expField     string
+	}
+	pending := entities.Quote{
+		ID:             "q1",
+		OfferRequestID: "r1",
+		PartnerID:      "1",
+		Status:         entities.QuotePending,
+		ValidUntil:     time.Now().Add(time.Hour),
+	}
+	expired := pending
+	expired.ValidUntil = time.Now().Add(-time.Hour)
+	tests := []testCase{
+		{
+			name:         "Accepts pending quote",
+			decision:     entities.QuoteAccepted,
+			stored:       pending,
+			expEventType: entities.EventQuoteAccepted,
+		},
+		{
+			name:         "Declines pending quote",
+			decision:     entities.QuoteDeclined,
+			stored:       pending,
+			expEventType: entities.EventQuoteDeclined,
+		},
+		{
+			name:     "Rejects expired quote",
+			decision: entities.QuoteAccepted,
+			stored:   expired,
+			expErr:   entities.ErrQuoteNotPending,
+		},
+		{
+			name:     "Rejects unknown decision",
+			decision: entities.QuoteSuperseded,
+			stored:   pending,
+			expField: "decision",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.QuoteRepository{}
+			repo.On("GetQuoteByID", mock.Anything, "q1").Return(tt.stored, nil)
+			if tt.expEventType != "" {
+				decided := tt.stored
+				decided.Status = tt.decision
+				repo.On("DecideQuote", mock.Anything, "q1", tt.decision, mock.Anything,
+					mock.MatchedBy(func(e entities.Event) bool {
+						var data domain.QuoteDecided
+						return e.Type == tt.expEventType &&
+							json.Unmarshal(e.Data, &data) == nil &&
+							data.PartnerID == "1" &&
+							data.Status == tt.decision
+					})).
+					Return(decided, nil)
+			}
+			service := domain.NewQuoteService(repo, &mocks.OfferRequestRepository{}, logging.Discard())
+
+			quote, err := service.DecideQuote(context.Background(), "q1", tt.decision)
+
+			switch {
+			case tt.expField != "":
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.expField, validationErr.Field)
+			case tt.expErr != nil:
+				assert.ErrorIs(t, err, tt.expErr)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.decision, quote.Status)
+				repo.AssertExpectations(t)
+			}
+		})
+	}
+}
@@ -0,0 +1,367 @@
+package domain
+
+import (
+	"customer-partner/internal/entities"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// MinFloorSize and MaxFloorSize bound the floor size of offer requests in square meters.
+const (
+	MinFloorSize = 1
+	MaxFloorSize = 100000
+)
+
+const (
+	minRating = 0
+	maxRating = 5
+	// maxLineItems, maxQuantity and maxUnitPrice bound the line items of quotes, so that amounts cannot overflow.
+	maxLineItems = 100
+	maxQuantity  = 1000000
+	maxUnitPrice = 100000000
+	// maxVATRate is the highest rate of the value added tax in basis points.
+	maxVATRate     = 10000
+	maxNotesLength = 2000
+	// maxOpeningHours and maxHolidays bound the availability of partners, four periods per weekday and a year of
+	// holidays.
+	maxOpeningHours = 28
+	maxHolidays     = 366
+	// maxCertifications and maxCertificationText bound the certifications of partners.
+	maxCertifications    = 20
+	maxCertificationText = 200
+	// maxExternalIDLength bounds the ids partners are imported with.
+	maxExternalIDLength = 100
+	// maxReasonLength bounds the reasons of suspensions and deletions of partners.
+	maxReasonLength = 500
+	// minPhoneDigits and maxPhoneDigits bound the number of digits of a phone number, E.164 allows up to 15.
+	minPhoneDigits = 6
+	maxPhoneDigits = 15
+)
+
+// ValidationError reports an attribute of an entity which violates a domain rule.
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+// ValidatePartner checks the attributes of a partner. It returns a *ValidationError for the first invalid attribute.
+func ValidatePartner(p entities.Partner) error {
+	if len(p.ExternalID) > maxExternalIDLength {
+		return &ValidationError{
+			Field:  "external_id",
+			Reason: fmt.Sprintf("must be at most %d bytes", maxExternalIDLength),
+		}
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		return &ValidationError{Field: "name", Reason: "must not be empty"}
+	}
+	if len(p.ExperiencedMaterial) == 0 {
+		return &ValidationError{Field: "experienced_material", Reason: "must not be empty"}
+	}
+	seen := map[string]bool{}
+	for _, material := range p.ExperiencedMaterial {
+		if !isMaterial(material) {
+			return &ValidationError{Field: "experienced_material", Reason: fmt.Sprintf("unknown material %q", material)}
+		}
+		if seen[material] {
+			return &ValidationError{Field: "experienced_material", Reason: fmt.Sprintf("duplicate material %q", material)}
+		}
+		seen[material] = true
+	}
+	if p.Address.Latitude < -90 || p.Address.Latitude > 90 {
+		return &ValidationError{Field: "address", Reason: "latitude out of range"}
+	}
+	if p.Address.Longitude < -180 || p.Address.Longitude > 180 {
+		return &ValidationError{Field: "address", Reason: "longitude out of range"}
+	}
+	if p.OperatingRadius < 1 {
+		return &ValidationError{Field: "operating_radius", Reason: "must be positive"}
+	}
+	if p.Rating < minRating || p.Rating > maxRating {
+		return &ValidationError{Field: "rating", Reason: fmt.Sprintf("must be between %d and %d", minRating, maxRating)}
+	}
+	if p.DailyLeadCap < 0 {
+		return &ValidationError{Field: "daily_lead_cap", Reason: "must not be negative"}
+	}
+	if err := validatePriceLists(p.PriceLists, seen); err != nil {
+		return err
+	}
+	if err := validateAvailability(p); err != nil {
+		return err
+	}
+	// Services store partners without verification status as pending.
+	if p.VerificationStatus != "" && !isVerificationStatus(p.VerificationStatus) {
+		return &ValidationError{
+			Field:  "verification_status",
+			Reason: fmt.Sprintf("unknown verification status %q", p.VerificationStatus),
+		}
+	}
+	if err := validateCertifications(p.Certifications); err != nil {
+		return err
+	}
+	if p.Contact != nil {
+		if p.Contact.Email == "" && p.Contact.Phone == "" {
+			return &ValidationError{Field: "contact", Reason: "email or phone required"}
+		}
+		if p.Contact.Email != "" && !isEmail(p.Contact.Email) {
+			return &ValidationError{Field: "contact", Reason: "not an email address"}
+		}
+		if p.Contact.Phone != "" && !isPhone(p.Contact.Phone) {
+			return &ValidationError{Field: "contact", Reason: "not a phone number"}
+		}
+		if p.Contact.Language != "" && !isLanguage(p.Contact.Language) {
+			return &ValidationError{Field: "contact", Reason: fmt.Sprintf("unknown language %q", p.Contact.Language)}
+		}
+	}
+	return nil
+}
+
+// validatePriceLists checks the price lists of a partner, experienced contains the experienced materials.
+func validatePriceLists(lists []entities.PriceList, experienced map[string]bool) error {
+	priced := map[string]bool{}
+	for _, list := range lists {
+		if !experienced[list.Material] {
+			return &ValidationError{
+				Field:  "price_lists",
+				Reason: fmt.Sprintf("material %q is not experienced", list.Material),
+			}
+		}
+		if priced[list.Material] {
+			return &ValidationError{Field: "price_lists", Reason: fmt.Sprintf("duplicate material %q", list.Material)}
+		}
+		priced[list.Material] = true
+		if list.MinPerSquareMeter < 0 || list.MinimumCharge < 0 || list.TravelSurchargePerKm < 0 ||
+			list.FreeTravelDistance < 0 {
+			return &ValidationError{Field: "price_lists", Reason: "prices and distances must not be negative"}
+		}
+		if list.MaxPerSquareMeter < list.MinPerSquareMeter {
+			return &ValidationError{
+				Field:  "price_lists",
+				Reason: "max_per_square_meter must not be less than min_per_square_meter",
+			}
+		}
+	}
+	return nil
+}
+
+// validateAvailability checks the time zone, the opening hours and the holidays of a partner.
+func validateAvailability(p entities.Partner) error {
+	if p.TimeZone == "Local" {
+		return &ValidationError{Field: "time_zone", Reason: "must be an IANA time zone"}
+	}
+	if _, err := loadLocation(p.TimeZone); err != nil {
+		return &ValidationError{Field: "time_zone", Reason: fmt.Sprintf("unknown time zone %q", p.TimeZone)}
+	}
+	if len(p.OpeningHours) > maxOpeningHours {
+		return &ValidationError{Field: "opening_hours", Reason: fmt.Sprintf("at most %d periods allowed", maxOpeningHours)}
+	}
+	for _, hours := range p.OpeningHours {
+		if !isWeekday(hours.Weekday) {
+			return &ValidationError{Field: "opening_hours", Reason: fmt.Sprintf("unknown weekday %q", hours.Weekday)}
+		}
+		opensHour, opensMinute, opensOK := parseClock(hours.Opens)
+		closesHour, closesMinute, closesOK := parseClock(hours.Closes)
+		if !opensOK || !closesOK {
+			return &ValidationError{Field: "opening_hours", Reason: "opens and closes must be formatted as 15:04"}
+		}
+		if opensHour*60+opensMinute >= closesHour*60+closesMinute {
+			return &ValidationError{Field: "opening_hours", Reason: "opens must be before closes"}
+		}
+	}
+	if len(p.Holidays) > maxHolidays {
+		return &ValidationError{Field: "holidays", Reason: fmt.Sprintf("at most %d holidays allowed", maxHolidays)}
+	}
+	for _, holiday := range p.Holidays {
+		if _, err := time.Parse(entities.DateLayout, holiday); err != nil {
+			return &ValidationError{Field: "holidays", Reason: fmt.Sprintf("%q is not a date", holiday)}
+		}
+	}
+	return nil
+}
+
+// validateCertifications checks the metadata of the certifications of a partner.
+func validateCertifications(certifications []entities.Certification) error {
+	if len(certifications) > maxCertifications {
+		return &ValidationError{
+			Field:  "certifications",
+			Reason: fmt.Sprintf("at most %d certifications allowed", maxCertifications),
+		}
+	}
+	for _, certification := range certifications {
+		if strings.TrimSpace(certification.Type) == "" || strings.TrimSpace(certification.Issuer) == "" {
+			return &ValidationError{Field: "certifications", Reason: "type and issuer must not be empty"}
+		}
+		if len(certification.Type) > maxCertificationText || len(certification.Issuer) > maxCertificationText {
+			return &ValidationError{
+				Field:  "certifications",
+				Reason: fmt.Sprintf("type and issuer must not be longer than %d characters", maxCertificationText),
+			}
+		}
+		if certification.ExpiresAt.IsZero() {
+			return &ValidationError{Field: "certifications", Reason: "expires_at must be set"}
+		}
+	}
+	return nil
+}
+
+// validateReason checks the reason of a suspension or deletion of a partner.
+func validateReason(reason string) error {
+	if strings.TrimSpace(reason) == "" {
+		return &ValidationError{Field: "reason", Reason: "must not be empty"}
+	}
+	if len(reason) > maxReasonLength {
+		return &ValidationError{Field: "reason", Reason: fmt.Sprintf("must be at most %d bytes", maxReasonLength)}
+	}
+	return nil
+}
+
+// ValidateOfferRequest checks the attributes of an offer request. It returns a *ValidationError for the first invalid
+// attribute. The error never contains the contact data.
+func ValidateOfferRequest(r entities.OfferRequest) error {
+	if strings.TrimSpace(r.PartnerID) == "" {
+		return &ValidationError{Field: "partner_id", Reason: "must not be empty"}
+	}
+	return validateOfferRequestDetails(r)
+}
+
+// validateOfferRequestDetails checks the attributes of an offer request which do not depend on the partner.
+func validateOfferRequestDetails(r entities.OfferRequest) error {
+	if r.FloorSize < MinFloorSize || r.FloorSize > MaxFloorSize {
+		return &ValidationError{
+			Field:  "floor_size",
+			Reason: fmt.Sprintf("must be between %d and %d", MinFloorSize, MaxFloorSize),
+		}
+	}
+	if !isPhone(r.Phone) {
+		return &ValidationError{Field: "phone", Reason: "not a phone number"}
+	}
+	if r.Email != "" && !isEmail(r.Email) {
+		return &ValidationError{Field: "email", Reason: "not an email address"}
+	}
+	if r.Language != "" && !isLanguage(r.Language) {
+		return &ValidationError{Field: "language", Reason: fmt.Sprintf("unknown language %q", r.Language)}
+	}
+	return nil
+}
+
+// ValidateQuote checks the attributes of a quote given at now. It returns a *ValidationError for the first invalid
+// attribute.
+func ValidateQuote(q entities.Quote, now time.Time) error {
+	if !isCurrency(q.Currency) {
+		return &ValidationError{Field: "currency", Reason: fmt.Sprintf("unknown currency %q", q.Currency)}
+	}
+	if len(q.LineItems) == 0 || len(q.LineItems) > maxLineItems {
+		return &ValidationError{Field: "line_items", Reason: fmt.Sprintf("must be between 1 and %d", maxLineItems)}
+	}
+	for _, item := range q.LineItems {
+		if !isLineItemKind(item.Kind) {
+			return &ValidationError{Field: "line_items", Reason: fmt.Sprintf("unknown kind %q", item.Kind)}
+		}
+		if strings.TrimSpace(item.Description) == "" {
+			return &ValidationError{Field: "line_items", Reason: "description must not be empty"}
+		}
+		if item.Quantity <= 0 || item.Quantity > maxQuantity {
+			return &ValidationError{
+				Field:  "line_items",
+				Reason: fmt.Sprintf("quantity must be positive and at most %d", maxQuantity),
+			}
+		}
+		if item.UnitPrice < 0 || item.UnitPrice > maxUnitPrice {
+			return &ValidationError{
+				Field:  "line_items",
+				Reason: fmt.Sprintf("unit price must be between 0 and %d", maxUnitPrice),
+			}
+		}
+	}
+	if q.VATRate < 0 || q.VATRate > maxVATRate {
+		return &ValidationError{Field: "vat_rate", Reason: fmt.Sprintf("must be between 0 and %d", maxVATRate)}
+	}
+	if !q.ValidUntil.After(now) {
+		return &ValidationError{Field: "valid_until", Reason: "must be in the future"}
+	}
+	if len(q.Notes) > maxNotesLength {
+		return &ValidationError{Field: "notes", Reason: fmt.Sprintf("must be at most %d bytes", maxNotesLength)}
+	}
+	return nil
+}
+
+// isEmail accepts a plain email address without display name.
+func isEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
+}
+
+// isPhone accepts digits separated by spaces, dashes, slashes and parentheses with an optional leading '+'.
+func isPhone(phone string) bool {
+	digits := 0
+	for i, c := range phone {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '+' && i == 0, c == ' ', c == '-', c == '/', c == '(', c == ')':
+		default:
+			return false
+		}
+	}
+	return digits >= minPhoneDigits && digits <= maxPhoneDigits
+}
+
+func isLanguage(language string) bool {
+	for _, l := range entities.Languages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+func isCurrency(currency string) bool {
+	for _, c := range entities.Currencies {
+		if c == currency {
+			return true
+		}
+	}
+	return false
+}
+
+func isLineItemKind(kind string) bool {
+	for _, k := range entities.LineItemKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func isMaterial(material string) bool {
+	for _, m := range entities.Materials {
+		if m == material {
+			return true
+		}
+	}
+	return false
+}
+
+func isWeekday(weekday string) bool {
+	for _, d := range entities.Weekdays {
+		if d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func isVerificationStatus(status string) bool {
+	for _, s := range entities.VerificationStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
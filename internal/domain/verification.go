@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// CertificationExpiryWarning is how long before their expiry certifications are flagged.
+const CertificationExpiryWarning = 30 * 24 * time.Hour
+
+// IsVetted reports whether customers may be shown the partner at now: the partner is verified and insured, and none of
+// their certifications expired.
+func IsVetted(p entities.Partner, now time.Time) bool {
+	if p.VerificationStatus != entities.VerificationVerified {
+		return false
+	}
+	if p.InsuranceExpiresAt == nil || !p.InsuranceExpiresAt.After(now) {
+		return false
+	}
+	for _, certification := range p.Certifications {
+		if !certification.ExpiresAt.After(now) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterVetted keeps the partners which are vetted at now.
+func filterVetted(partners []entities.Partner, now time.Time) []entities.Partner {
+	vetted := make([]entities.Partner, 0, len(partners))
+	for _, partner := range partners {
+		if IsVetted(partner, now) {
+			vetted = append(vetted, partner)
+		}
+	}
+	return vetted
+}
+
+// KeepCertificationFlags returns the certifications with the flags of the matching stored certifications. Flags sent
+// by clients are ignored, so that an expiring certification is neither flagged twice nor hidden from the flagging job.
+func KeepCertificationFlags(certifications, stored []entities.Certification) []entities.Certification {
+	kept := make([]entities.Certification, 0, len(certifications))
+	for _, certification := range certifications {
+		certification.FlaggedAt = nil
+		for _, s := range stored {
+			if s.Type == certification.Type && s.Issuer == certification.Issuer && s.ExpiresAt.Equal(certification.ExpiresAt) {
+				certification.FlaggedAt = s.FlaggedAt
+				break
+			}
+		}
+		kept = append(kept, certification)
+	}
+	return kept
+}
+
+// FlagExpiringCertifications flags the certifications of all partners which expire within CertificationExpiryWarning
+// after now and publishes an entities.EventCertificationExpiring event for each of them. Deleted partners are skipped.
+// A certification is flagged once per expiry, so a renewed certification is flagged again before its new expiry. It is
+// run periodically and returns the number of flagged certifications.
+func (s *PartnerService) FlagExpiringCertifications(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracer.Start(ctx, "PartnerService.FlagExpiringCertifications")
+	defer span.End()
+	now = now.UTC()
+	partners, err := s.repository.GetPartnersWithExpiringCertifications(ctx, now.Add(CertificationExpiryWarning))
+	if err != nil {
+		return 0, err
+	}
+	flagged := 0
+	for _, partner := range partners {
+		if partner.DeletedAt != nil {
+			continue
+		}
+		var events []entities.Event
+		for i := range partner.Certifications {
+			certification := &partner.Certifications[i]
+			warnFrom := certification.ExpiresAt.Add(-CertificationExpiryWarning)
+			if now.Before(warnFrom) || certification.FlaggedAt != nil && !certification.FlaggedAt.Before(warnFrom) {
+				continue
+			}
+			flaggedAt := now.Truncate(time.Second)
+			certification.FlaggedAt = &flaggedAt
+			event, err := newEvent(entities.EventCertificationExpiring, partner.ID, CertificationExpiring{
+				PartnerID: partner.ID,
+				Type:      certification.Type,
+				Issuer:    certification.Issuer,
+				ExpiresAt: certification.ExpiresAt,
+			})
+			if err != nil {
+				return flagged, err
+			}
+			events = append(events, event)
+		}
+		if len(events) == 0 {
+			continue
+		}
+		err := s.repository.UpdatePartner(ctx, partner, events...)
+		if errors.Is(err, entities.ErrRecordNotExist) {
+			continue
+		}
+		if err != nil {
+			return flagged, err
+		}
+		flagged += len(events)
+		logging.FromContextOr(ctx, s.logger).Info("flagged expiring certifications",
+			"partner_id", partner.ID,
+			"count", len(events),
+		)
+	}
+	span.SetAttributes(attribute.Int("certification.flagged", flagged))
+	return flagged, nil
+}
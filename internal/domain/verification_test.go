@@ -0,0 +1,159 @@
+package domain_test
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/domain/mocks"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// insuredUntil is the end of the insurance of partners returned by vetted.
+var insuredUntil = time.Date(2099, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// vetted returns the partners as verified and insured, so that they are shown to customers.
+func vetted(partners ...entities.Partner) []entities.Partner {
+	vetted := make([]entities.Partner, 0, len(partners))
+	for _, partner := range partners {
+		partner.VerificationStatus = entities.VerificationVerified
+		partner.InsuranceExpiresAt = &insuredUntil
+		vetted = append(vetted, partner)
+	}
+	return vetted
+}
+
+func TestIsVetted(t *testing.T) {
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Hour)
+	type testCase struct {
+		name   string
+		change func(p *entities.Partner)
+		exp    bool
+	}
+	tests := []testCase{
+		{name: "Accepts verified and insured partner", change: func(p *entities.Partner) {}, exp: true},
+		{name: "Rejects pending partner", change: func(p *entities.Partner) { p.VerificationStatus = entities.VerificationPending }},
+		{name: "Rejects suspended partner", change: func(p *entities.Partner) { p.VerificationStatus = entities.VerificationSuspended }},
+		{name: "Rejects partner without insurance", change: func(p *entities.Partner) { p.InsuranceExpiresAt = nil }},
+		{name: "Rejects partner with expired insurance", change: func(p *entities.Partner) { p.InsuranceExpiresAt = &expired }},
+		{
+			name: "Accepts valid certification",
+			change: func(p *entities.Partner) {
+				p.Certifications = []entities.Certification{{Type: "Master", Issuer: "HWK", ExpiresAt: now.Add(time.Hour)}}
+			},
+			exp: true,
+		},
+		{
+			name: "Rejects expired certification",
+			change: func(p *entities.Partner) {
+				p.Certifications = []entities.Certification{{Type: "Master", Issuer: "HWK", ExpiresAt: expired}}
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			partner := vetted(entities.Partner{ID: "1"})[0]
+			tt.change(&partner)
+
+			assert.Equal(t, tt.exp, domain.IsVetted(partner, now))
+		})
+	}
+}
+
+func TestPartnerService_GetPartners_Verification(t *testing.T) {
+	address := entities.Address{Latitude: 48.1360, Longitude: 11.6875}
+	partners := append(
+		vetted(entities.Partner{ID: "vetted", Address: address, OperatingRadius: 50}),
+		entities.Partner{ID: "pending", Address: address, OperatingRadius: 50, VerificationStatus: entities.VerificationPending},
+	)
+	type testCase struct {
+		name              string
+		includeUnverified bool
+		expIDs            []string
+	}
+	tests := []testCase{
+		{name: "Excludes partners who are not vetted", expIDs: []string{"vetted"}},
+		{name: "Includes partners who are not vetted for admins", includeUnverified: true, expIDs: []string{"vetted", "pending"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.PartnerRepository{}
+			repo.On("GetPartnersByMaterial", mock.Anything, "wood").Return(partners, nil)
+			service := domain.NewPartnerService(repo, logging.Discard())
+
+			actual, err := service.GetPartners(context.Background(), domain.GetPartnersOpts{
+				Material:            "wood",
+				CustomerAddressLat:  48.1351,
+				CustomerAddressLong: 11.5820,
+				IncludeUnverified:   tt.includeUnverified,
+			})
+
+			require.NoError(t, err)
+			var ids []string
+			for _, partner := range actual {
+				ids = append(ids, partner.ID)
+			}
+			assert.ElementsMatch(t, tt.expIDs, ids)
+		})
+	}
+}
+
+func TestPartnerService_FlagExpiringCertifications(t *testing.T) {
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	flaggedBefore := now.Add(-24 * time.Hour)
+	renewedFlag := now.Add(-300 * 24 * time.Hour)
+	partner := entities.Partner{
+		ID: "1",
+		Certifications: []entities.Certification{
+			// expires in 10 days and was not flagged yet
+			{Type: "Master", Issuer: "HWK", ExpiresAt: now.Add(10 * 24 * time.Hour)},
+			// expires in 20 days and was flagged yesterday
+			{Type: "Insulation", Issuer: "TÜV", ExpiresAt: now.Add(20 * 24 * time.Hour), FlaggedAt: &flaggedBefore},
+			// expires in 20 days and was flagged before its previous expiry
+			{Type: "Asbestos", Issuer: "BG Bau", ExpiresAt: now.Add(20 * 24 * time.Hour), FlaggedAt: &renewedFlag},
+			// expires in 40 days
+			{Type: "FSC", Issuer: "FSC", ExpiresAt: now.Add(40 * 24 * time.Hour)},
+		},
+	}
+	repo := &mocks.PartnerRepository{}
+	repo.On("GetPartnersWithExpiringCertifications", mock.Anything, now.Add(domain.CertificationExpiryWarning)).
+		Return([]entities.Partner{partner}, nil)
+	var stored entities.Partner
+	var events []entities.Event
+	repo.On("UpdatePartner", mock.Anything, mock.AnythingOfType("entities.Partner"), mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(1).(entities.Partner)
+			events = []entities.Event{args.Get(2).(entities.Event), args.Get(3).(entities.Event)}
+		}).
+		Return(nil)
+	service := domain.NewPartnerService(repo, logging.Discard())
+
+	flagged, err := service.FlagExpiringCertifications(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, flagged)
+	repo.AssertExpectations(t)
+	require.NotNil(t, stored.Certifications[0].FlaggedAt)
+	assert.Equal(t, now, *stored.Certifications[0].FlaggedAt)
+	assert.Equal(t, flaggedBefore, *stored.Certifications[1].FlaggedAt)
+	assert.Equal(t, now, *stored.Certifications[2].FlaggedAt)
+	assert.Nil(t, stored.Certifications[3].FlaggedAt)
+	var data domain.CertificationExpiring
+	require.NoError(t, json.Unmarshal(events[0].Data, &data))
+	assert.Equal(t, entities.EventCertificationExpiring, events[0].Type)
+	assert.Equal(t, domain.CertificationExpiring{
+		PartnerID: "1",
+		Type:      "Master",
+		Issuer:    "HWK",
+		ExpiresAt: now.Add(10 * 24 * time.Hour),
+	}, data)
+}
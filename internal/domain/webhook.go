@@ -0,0 +1,219 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"encoding/base64"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxWebhookSubscriptions limits the number of subscriptions per partner.
+const maxWebhookSubscriptions = 10
+
+// WebhookRepository defines an interface which a persistence storage for webhook subscriptions and deliveries must
+// provide.
+type WebhookRepository interface {
+	CreateWebhookSubscription(ctx context.Context, subscription entities.WebhookSubscription) error
+	// GetWebhookSubscription can return entities.ErrRecordNotExist when subscription with given id does not exist.
+	GetWebhookSubscription(ctx context.Context, id string) (entities.WebhookSubscription, error)
+	// GetWebhookSubscriptionsByPartner returns the subscriptions of a partner, oldest first.
+	GetWebhookSubscriptionsByPartner(ctx context.Context, partnerID string) ([]entities.WebhookSubscription, error)
+	// DeleteWebhookSubscription can return entities.ErrRecordNotExist when subscription with given id does not exist.
+	DeleteWebhookSubscription(ctx context.Context, id string) error
+	// CreateWebhookDelivery stores a new delivery. A delivery with the same id is kept, so that events published
+	// twice are only delivered once.
+	CreateWebhookDelivery(ctx context.Context, delivery entities.WebhookDelivery) error
+	// UpdateWebhookDelivery can return entities.ErrRecordNotExist when delivery with given id does not exist.
+	UpdateWebhookDelivery(ctx context.Context, delivery entities.WebhookDelivery) error
+	// GetDueWebhookDeliveries returns up to limit pending deliveries whose next attempt is due at now, oldest first.
+	GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]entities.WebhookDelivery, error)
+	// GetWebhookDeliveriesByPartner returns the deliveries to a partner, newest first.
+	GetWebhookDeliveriesByPartner(ctx context.Context, partnerID string) ([]entities.WebhookDelivery, error)
+	// DeleteWebhooksByPartner removes the subscriptions of a partner and the deliveries to them.
+	DeleteWebhooksByPartner(ctx context.Context, partnerID string) error
+}
+
+func NewWebhookService(
+	repository WebhookRepository,
+	partners PartnerRepository,
+	allowInsecure bool,
+	logger *slog.Logger,
+) *WebhookService {
+	return &WebhookService{repository: repository, partners: partners, allowInsecure: allowInsecure, logger: logger}
+}
+
+// WebhookService manages the webhook subscriptions of partners. Subscriptions must use https unless allowInsecure is
+// set, e.g. for local development.
+type WebhookService struct {
+	repository    WebhookRepository
+	partners      PartnerRepository
+	allowInsecure bool
+	logger        *slog.Logger
+}
+
+// CreateWebhookSubscription validates and stores a new subscription of an existing partner. The subscription is
+// assigned a new id and a random secret, which is only returned here.
+// Can return a *ValidationError when the subscription is invalid or the partner has too many subscriptions and
+// entities.ErrRecordNotExist when the partner does not exist.
+func (s *WebhookService) CreateWebhookSubscription(
+	ctx context.Context,
+	subscription entities.WebhookSubscription,
+) (entities.WebhookSubscription, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.CreateWebhookSubscription", trace.WithAttributes(
+		attribute.String("partner.id", subscription.PartnerID),
+	))
+	defer span.End()
+	if err := ValidateWebhookSubscription(subscription, s.allowInsecure); err != nil {
+		return entities.WebhookSubscription{}, err
+	}
+	if _, err := s.partners.GetPartnerByID(ctx, subscription.PartnerID); err != nil {
+		return entities.WebhookSubscription{}, err
+	}
+	existing, err := s.repository.GetWebhookSubscriptionsByPartner(ctx, subscription.PartnerID)
+	if err != nil {
+		return entities.WebhookSubscription{}, err
+	}
+	if len(existing) >= maxWebhookSubscriptions {
+		return entities.WebhookSubscription{}, &ValidationError{Field: "url", Reason: "too many subscriptions"}
+	}
+	subscription.ID = newID()
+	subscription.Secret = newSecret()
+	subscription.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	if err := s.repository.CreateWebhookSubscription(ctx, subscription); err != nil {
+		return entities.WebhookSubscription{}, err
+	}
+	logging.FromContextOr(ctx, s.logger).Info("webhook subscription created",
+		"partner_id", subscription.PartnerID,
+		"webhook_id", subscription.ID,
+	)
+	return subscription, nil
+}
+
+// GetWebhookSubscriptions returns the subscriptions of a partner without their secrets.
+func (s *WebhookService) GetWebhookSubscriptions(
+	ctx context.Context,
+	partnerID string,
+) ([]entities.WebhookSubscription, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.GetWebhookSubscriptions", trace.WithAttributes(
+		attribute.String("partner.id", partnerID),
+	))
+	defer span.End()
+	subscriptions, err := s.repository.GetWebhookSubscriptionsByPartner(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	return subscriptions, nil
+}
+
+// DeleteWebhookSubscription removes a subscription of a partner. Pending deliveries of the subscription end up in the
+// dead letters.
+// Can return entities.ErrRecordNotExist when the partner has no subscription with given id.
+func (s *WebhookService) DeleteWebhookSubscription(ctx context.Context, partnerID, id string) error {
+	ctx, span := tracer.Start(ctx, "WebhookService.DeleteWebhookSubscription", trace.WithAttributes(
+		attribute.String("partner.id", partnerID),
+	))
+	defer span.End()
+	subscription, err := s.repository.GetWebhookSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	if subscription.PartnerID != partnerID {
+		return entities.ErrRecordNotExist
+	}
+	if err := s.repository.DeleteWebhookSubscription(ctx, id); err != nil {
+		return err
+	}
+	logging.FromContextOr(ctx, s.logger).Info("webhook subscription deleted", "partner_id", partnerID, "webhook_id", id)
+	return nil
+}
+
+// GetWebhookDeliveries returns the deliveries to a partner, newest first. With status only deliveries in this
+// status are returned, e.g. entities.WebhookDeliveryDeadLetter.
+func (s *WebhookService) GetWebhookDeliveries(
+	ctx context.Context,
+	partnerID string,
+	status string,
+) ([]entities.WebhookDelivery, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.GetWebhookDeliveries", trace.WithAttributes(
+		attribute.String("partner.id", partnerID),
+	))
+	defer span.End()
+	deliveries, err := s.repository.GetWebhookDeliveriesByPartner(ctx, partnerID)
+	if err != nil || status == "" {
+		return deliveries, err
+	}
+	filtered := []entities.WebhookDelivery{}
+	for _, delivery := range deliveries {
+		if delivery.Status == status {
+			filtered = append(filtered, delivery)
+		}
+	}
+	return filtered, nil
+}
+
+// ValidateWebhookSubscription checks the attributes of a subscription. It returns a *ValidationError for the first
+// invalid attribute. Urls of internal hosts are rejected, host names resolving to internal addresses are refused when
+// the dispatcher connects.
+func ValidateWebhookSubscription(s entities.WebhookSubscription, allowInsecure bool) error {
+	u, err := url.Parse(s.URL)
+	if err != nil || u.Host == "" || u.User != nil || (u.Scheme != "https" && (u.Scheme != "http" || !allowInsecure)) {
+		return &ValidationError{Field: "url", Reason: "must be an absolute https url without credentials"}
+	}
+	if !publicHost(u.Hostname()) {
+		return &ValidationError{Field: "url", Reason: "must not be a loopback, private or link-local address"}
+	}
+	if len(s.EventTypes) == 0 {
+		return &ValidationError{Field: "event_types", Reason: "must not be empty"}
+	}
+	for _, eventType := range s.EventTypes {
+		if !isWebhookEventType(eventType) {
+			return &ValidationError{Field: "event_types", Reason: "unknown event type " + eventType}
+		}
+	}
+	return nil
+}
+
+// PublicAddress reports whether webhooks may be delivered to ip. Loopback, private, link-local, multicast and
+// unspecified addresses belong to the network of the service, which partners must not reach through webhooks.
+func PublicAddress(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsUnspecified()
+}
+
+// publicHost reports whether the host of a url may be public. Names other than localhost are resolved only when
+// connecting.
+func publicHost(host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return PublicAddress(ip)
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	return host != "localhost" && !strings.HasSuffix(host, ".localhost")
+}
+
+func isWebhookEventType(eventType string) bool {
+	for _, t := range entities.WebhookEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// newSecret returns a random secret for signing webhook payloads.
+func newSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(b)
+}
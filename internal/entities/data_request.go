@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// Actions of data requests.
+const (
+	DataRequestExport  = "export"
+	DataRequestErasure = "erasure"
+)
+
+// DataRequest is the audit record of a data subject request under the GDPR, i.e. an export or erasure of all data
+// about a customer or partner. Subject only describes the data subject with masked contact data.
+type DataRequest struct {
+	ID          string    `json:"id"`
+	Action      string    `json:"action"`
+	Subject     string    `json:"subject"`
+	Actor       string    `json:"actor"`
+	PerformedAt time.Time `json:"performed_at"`
+	// OfferRequests is the number of offer requests exported or anonymized.
+	OfferRequests int `json:"offer_requests"`
+	// Partner reports whether a partner record was exported or deleted.
+	Partner bool `json:"partner"`
+}
@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Types of domain events.
+const (
+	EventPartnerCreated = "PartnerCreated"
+	EventPartnerUpdated = "PartnerUpdated"
+	EventPartnerDeleted = "PartnerDeleted"
+	EventOfferRequested = "OfferRequested"
+	EventOfferResponded = "OfferResponded"
+	EventQuoteCreated   = "QuoteCreated"
+	EventQuoteAccepted  = "QuoteAccepted"
+	EventQuoteDeclined  = "QuoteDeclined"
+	// EventCertificationExpiring warns that a certification of a partner expires soon.
+	EventCertificationExpiring = "CertificationExpiring"
+)
+
+// Event notifies downstream systems about a change. Events of the same aggregate are published in the order they
+// occurred, but may be published more than once, so consumers should deduplicate by ID.
+type Event struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// AggregateID is the id of the partner, offer request or quote the event is about.
+	AggregateID string          `json:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Data        json.RawMessage `json:"data"`
+}
@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// ErrAlreadyResponded is returned when a partner responds to an offer request a second time.
+var ErrAlreadyResponded = errors.New("offer request already responded")
+
+// Responses of partners to offer requests.
+const (
+	OfferRequestAccepted = "accepted"
+	OfferRequestDeclined = "declined"
+)
+
+// OfferRequest is the request of a customer for an offer of a partner. Phone and Email are personal data of the
+// customer: they are encrypted at rest and removed when the retention period is over.
+type OfferRequest struct {
+	ID        string  `json:"id"`
+	PartnerID string  `json:"partner_id"`
+	FloorSize float64 `json:"floor_size"`
+	Phone     string  `json:"phone,omitempty"`
+	Email     string  `json:"email,omitempty"`
+	// Language is the language the customer is notified in, empty for the default language.
+	Language  string    `json:"language,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// ContactPurgedAt is the time the contact data was removed, nil while it is kept.
+	ContactPurgedAt *time.Time `json:"contact_purged_at,omitempty"`
+	// LeadID groups the offer requests which were dispatched together to the best matching partners.
+	LeadID string `json:"lead_id,omitempty"`
+	// Response is the answer of the partner, empty until the partner responded.
+	Response    string     `json:"response,omitempty"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+	// ResponseRank is the position of the response among the responses to the lead, 1 for the partner who
+	// responded first. It is 1 for every response to an offer request without lead.
+	ResponseRank int `json:"response_rank,omitempty"`
+	// CustomerID is the subject of the customer who created the offer request with an account, empty for customers
+	// without account. Only that customer can decide on the quotes.
+	CustomerID string `json:"customer_id,omitempty"`
+	// Status is advanced by the quotes of the partner, empty until the partner sent a quote.
+	Status string `json:"status,omitempty"`
+}
+
+// Lead is a request of a customer dispatched to several partners at once, with an offer request per partner.
+type Lead struct {
+	ID            string         `json:"id"`
+	CreatedAt     time.Time      `json:"created_at"`
+	OfferRequests []OfferRequest `json:"offer_requests"`
+}
+
+// LogValue implements slog.LogValuer. The contact data is left out, so that it never ends up in logs.
+func (r OfferRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", r.ID),
+		slog.String("partner_id", r.PartnerID),
+		slog.Float64("floor_size", r.FloorSize),
+		slog.Time("created_at", r.CreatedAt),
+		slog.String("lead_id", r.LeadID),
+	)
+}
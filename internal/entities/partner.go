@@ -1,19 +1,137 @@
 package entities
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var ErrRecordNotExist = errors.New("record not exist")
 
+// ErrPartnerDeleted is returned when a partner was deleted. Deleted partners are kept for the offer requests and
+// reviews referencing them, but cannot be changed anymore.
+var ErrPartnerDeleted = errors.New("partner deleted")
+
+// Materials lists the floor materials partners can be experienced in.
+var Materials = []string{"wood", "carpet", "tiles"}
+
+// Currency is the currency of all prices. Amounts are given in its minor unit, i.e. cents.
+const Currency = "EUR"
+
+// Languages lists the languages customers and partners can be notified in. The first one is the default.
+var Languages = []string{"de", "en"}
+
+// Weekdays lists the days of opening hours, starting on Monday.
+var Weekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// DefaultTimeZone is the time zone of partners who do not set one.
+const DefaultTimeZone = "Europe/Berlin"
+
+// Verification statuses of partners. Only verified partners are shown to customers.
+const (
+	VerificationPending   = "pending"
+	VerificationVerified  = "verified"
+	VerificationSuspended = "suspended"
+)
+
+// VerificationStatuses lists the verification statuses of partners.
+var VerificationStatuses = []string{VerificationPending, VerificationVerified, VerificationSuspended}
+
+// DateLayout is the layout of dates without time, e.g. of holidays.
+const DateLayout = "2006-01-02"
+
 type Address struct {
 	Latitude  float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
+	City      string  `json:"city,omitempty"`
+	District  string  `json:"district,omitempty"`
 }
 
 type Partner struct {
-	ID                  string   `json:"id"`
+	ID string `json:"id"`
+	// ExternalID identifies the partner in the systems of sales, empty for partners who were not imported.
+	ExternalID          string   `json:"external_id,omitempty"`
 	Name                string   `json:"name"`
 	ExperiencedMaterial []string `json:"experienced_material"`
 	Address             Address  `json:"address"`
 	OperatingRadius     int      `json:"operating_radius"`
 	Rating              int      `json:"rating"`
+	// DailyLeadCap limits the number of leads routed to the partner per day, 0 for no limit.
+	DailyLeadCap int `json:"daily_lead_cap,omitempty"`
+	// Contact is where the partner is notified about offer requests, nil when the partner is not notified.
+	Contact *Contact `json:"contact,omitempty"`
+	// PriceLists are the prices the partner publishes, at most one per experienced material.
+	PriceLists []PriceList `json:"price_lists,omitempty"`
+	// PriceEstimate is the estimated price of the job of a customer. It is only set on matches for a floor size and
+	// never stored.
+	PriceEstimate *PriceEstimate `json:"price_estimate,omitempty"`
+	// TimeZone is the IANA time zone of the opening hours and holidays, empty for DefaultTimeZone.
+	TimeZone string `json:"time_zone,omitempty"`
+	// OpeningHours are the weekly hours in which the partner starts jobs. Partners without opening hours are always
+	// available.
+	OpeningHours []OpeningHours `json:"opening_hours,omitempty"`
+	// Holidays are the dates in DateLayout on which the partner is closed, in the time zone of the partner.
+	Holidays []string `json:"holidays,omitempty"`
+	// OpenNow and NextAvailableAt tell whether the partner is open and when they can start a job next. They are only
+	// set on responses and never stored, NextAvailableAt is nil when the partner is closed for the next year.
+	OpenNow         *bool      `json:"open_now,omitempty"`
+	NextAvailableAt *time.Time `json:"next_available_at,omitempty"`
+	// VerificationStatus is one of VerificationStatuses, set by admins after vetting the partner.
+	VerificationStatus string `json:"verification_status,omitempty"`
+	// Certifications are the certificates of the partner, e.g. of a master craftsman.
+	Certifications []Certification `json:"certifications,omitempty"`
+	// InsuranceExpiresAt is the end of the liability insurance of the partner, nil when no insurance is known.
+	InsuranceExpiresAt *time.Time `json:"insurance_expires_at,omitempty"`
+	// SuspendedAt is when an admin suspended the partner, nil when the partner is not suspended. Suspended partners are
+	// not shown to customers until they are reinstated.
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
+	// DeletedAt is when an admin deleted the partner, nil when the partner is not deleted. Deleted partners are kept,
+	// so that offer requests and reviews referencing them stay intact, but they are only shown to admins.
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+	DeletionReason string     `json:"deletion_reason,omitempty"`
+}
+
+// Certification is the metadata of a certificate a partner uploaded.
+type Certification struct {
+	Type      string    `json:"type"`
+	Issuer    string    `json:"issuer"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// FlaggedAt is the time the certification was flagged as expiring soon, nil before.
+	FlaggedAt *time.Time `json:"flagged_at,omitempty"`
+}
+
+// OpeningHours is a period of a weekday in which a partner is open. Opens and Closes are local times like "08:00",
+// Closes is "24:00" for periods lasting until midnight.
+type OpeningHours struct {
+	Weekday string `json:"weekday"`
+	Opens   string `json:"opens"`
+	Closes  string `json:"closes"`
+}
+
+// PriceList is what a partner charges for a floor of a material. All amounts are in cents.
+type PriceList struct {
+	Material string `json:"material"`
+	// MinPerSquareMeter and MaxPerSquareMeter bound the price per square meter, depending on the condition of the
+	// floor.
+	MinPerSquareMeter int `json:"min_per_square_meter"`
+	MaxPerSquareMeter int `json:"max_per_square_meter"`
+	// MinimumCharge is the least the partner charges for a job.
+	MinimumCharge int `json:"minimum_charge,omitempty"`
+	// TravelSurchargePerKm is charged for every started kilometer the customer lives beyond FreeTravelDistance.
+	TravelSurchargePerKm int `json:"travel_surcharge_per_km,omitempty"`
+	FreeTravelDistance   int `json:"free_travel_distance,omitempty"`
+}
+
+// PriceEstimate is the estimated price range of a job. Min and Max are in the minor unit of Currency.
+type PriceEstimate struct {
+	Currency string `json:"currency"`
+	Min      int    `json:"min"`
+	Max      int    `json:"max"`
+}
+
+// Contact is the business contact data of a partner. It is not shown to the public.
+type Contact struct {
+	Email    string `json:"email,omitempty"`
+	Phone    string `json:"phone,omitempty"`
+	Language string `json:"language,omitempty"`
 }
@@ -0,0 +1,32 @@
+package entities
+
+import "time"
+
+// Actions of partner changes.
+const (
+	PartnerCreated = "create"
+	PartnerUpdated = "update"
+	PartnerDeleted = "delete"
+)
+
+// PartnerChange is an entry of the audit trail of a partner: who changed which attributes when.
+type PartnerChange struct {
+	ID        string        `json:"id"`
+	PartnerID string        `json:"partner_id"`
+	Action    string        `json:"action"`
+	Actor     string        `json:"actor"`
+	RequestID string        `json:"request_id,omitempty"`
+	ChangedAt time.Time     `json:"changed_at"`
+	Changes   []FieldChange `json:"changes"`
+	// Redacted is set when the values of the changes were removed, because the personal data of the partner was
+	// erased.
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// FieldChange is the value of an attribute before and after a change. Before is nil for created partners, After is
+// nil for deleted partners.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
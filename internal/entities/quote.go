@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrOfferRequestClosed is returned when a partner quotes an offer request they declined or the customer already
+	// ordered.
+	ErrOfferRequestClosed = errors.New("offer request closed")
+	// ErrQuoteNotPending is returned when a customer decides on a quote which is decided, superseded or expired.
+	ErrQuoteNotPending = errors.New("quote not pending")
+)
+
+// Currencies lists the currencies quotes can be given in. All of them have cents as minor unit.
+var Currencies = []string{"EUR", "CHF", "GBP"}
+
+// Kinds of the line items of quotes.
+const (
+	LineItemMaterial = "material"
+	LineItemLabour   = "labour"
+	LineItemRemoval  = "removal"
+	LineItemOther    = "other"
+)
+
+// LineItemKinds lists the kinds of the line items of quotes.
+var LineItemKinds = []string{LineItemMaterial, LineItemLabour, LineItemRemoval, LineItemOther}
+
+// Statuses of quotes.
+const (
+	QuotePending  = "pending"
+	QuoteAccepted = "accepted"
+	QuoteDeclined = "declined"
+	// QuoteSuperseded marks a pending quote which the partner replaced by a newer one.
+	QuoteSuperseded = "superseded"
+	// QuoteExpired marks a pending quote whose validity is over. It is never stored.
+	QuoteExpired = "expired"
+)
+
+// Statuses of offer requests, advanced by quotes. Offer requests without quote have no status.
+const (
+	OfferRequestQuoted        = "quoted"
+	OfferRequestOrdered       = "ordered"
+	OfferRequestQuoteDeclined = "quote_declined"
+)
+
+// Quote is the offer of a partner for an offer request. Amounts are in the minor unit of the currency, i.e. cents.
+type Quote struct {
+	ID             string          `json:"id"`
+	OfferRequestID string          `json:"offer_request_id"`
+	PartnerID      string          `json:"partner_id"`
+	Currency       string          `json:"currency"`
+	LineItems      []QuoteLineItem `json:"line_items"`
+	// VATRate is the rate of the value added tax in basis points, e.g. 1900 for 19 %.
+	VATRate     int `json:"vat_rate"`
+	NetAmount   int `json:"net_amount"`
+	VATAmount   int `json:"vat_amount"`
+	TotalAmount int `json:"total_amount"`
+	// ValidUntil is the time until the customer can accept the quote.
+	ValidUntil time.Time `json:"valid_until"`
+	Notes      string    `json:"notes,omitempty"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	// DecidedAt is the time the customer accepted or declined the quote, nil before.
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+}
+
+// QuoteLineItem is a position of a quote. Amount is the quantity times the unit price, rounded to the minor unit.
+type QuoteLineItem struct {
+	Kind        string  `json:"kind"`
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	// Unit of the quantity, e.g. "m²" or "h".
+	Unit      string `json:"unit,omitempty"`
+	UnitPrice int    `json:"unit_price"`
+	Amount    int    `json:"amount"`
+}
@@ -0,0 +1,54 @@
+package entities
+
+import "time"
+
+// WebhookEventTypes lists the event types partners can subscribe to. Partners only receive events about themselves.
+var WebhookEventTypes = []string{
+	EventOfferRequested,
+	EventPartnerUpdated,
+	EventPartnerDeleted,
+	EventQuoteAccepted,
+	EventQuoteDeclined,
+	EventCertificationExpiring,
+}
+
+// Statuses of webhook deliveries.
+const (
+	WebhookDeliveryPending    = "pending"
+	WebhookDeliveryDelivered  = "delivered"
+	WebhookDeliveryDeadLetter = "dead_letter"
+)
+
+// WebhookSubscription is a url of a partner which receives the events of the given types. The payloads are signed
+// with the secret.
+type WebhookSubscription struct {
+	ID         string    `json:"id"`
+	PartnerID  string    `json:"partner_id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret,omitempty"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WebhookDelivery is the delivery of an event to a subscription. It is retried until it succeeds or the attempts are
+// exhausted, which moves it to the dead letters.
+type WebhookDelivery struct {
+	ID             string           `json:"id"`
+	SubscriptionID string           `json:"subscription_id"`
+	PartnerID      string           `json:"partner_id"`
+	EventID        string           `json:"event_id"`
+	EventType      string           `json:"event_type"`
+	Status         string           `json:"status"`
+	Attempts       []WebhookAttempt `json:"attempts"`
+	NextAttemptAt  *time.Time       `json:"next_attempt_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	// Event is the payload of the delivery.
+	Event Event `json:"-"`
+}
+
+// WebhookAttempt is a single request of a delivery. StatusCode is 0 when no response was received.
+type WebhookAttempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code"`
+	Error      string    `json:"error,omitempty"`
+}
@@ -0,0 +1,31 @@
+package events
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+)
+
+func NewChannelPublisher(buffer int) *ChannelPublisher {
+	return &ChannelPublisher{events: make(chan entities.Event, buffer)}
+}
+
+// ChannelPublisher hands events to consumers within the process.
+type ChannelPublisher struct {
+	events chan entities.Event
+}
+
+// Publish sends the event to the channel. It blocks while the buffer is full until a consumer receives the event or
+// ctx is done.
+func (p *ChannelPublisher) Publish(ctx context.Context, event entities.Event) error {
+	select {
+	case p.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Events returns the channel the events are delivered to.
+func (p *ChannelPublisher) Events() <-chan entities.Event {
+	return p.events
+}
@@ -0,0 +1,46 @@
+package events
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// OpenFilePublisher opens the file at path for appending and creates it when it does not exist.
+func OpenFilePublisher(path string) (*FilePublisher, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &FilePublisher{file: f}, nil
+}
+
+// FilePublisher appends events as newline delimited json to a file, e.g. for a log shipper to pick them up.
+type FilePublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// Publish writes the event as a single line and syncs it to disk.
+func (p *FilePublisher) Publish(ctx context.Context, event entities.Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return p.file.Sync()
+}
+
+// Close closes the file.
+func (p *FilePublisher) Close() error {
+	return p.file.Close()
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"customer-partner/internal/entities"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Headers of the requests sent by HTTPPublisher.
+const (
+	EventIDHeader   = "X-Event-ID"
+	EventTypeHeader = "X-Event-Type"
+)
+
+// httpTimeout limits the time a single delivery of HTTPPublisher may take.
+const httpTimeout = 10 * time.Second
+
+// NewHTTPPublisher creates a publisher posting to url. client may be nil to use a client with a timeout of 10
+// seconds.
+func NewHTTPPublisher(url string, client *http.Client) *HTTPPublisher {
+	if client == nil {
+		client = &http.Client{Timeout: httpTimeout}
+	}
+	return &HTTPPublisher{url: url, client: client}
+}
+
+// HTTPPublisher posts every event as json to a webhook. Any 2xx status acknowledges the event.
+type HTTPPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func (p *HTTPPublisher) Publish(ctx context.Context, event entities.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, event.ID)
+	req.Header.Set(EventTypeHeader, event.Type)
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("publishing event %s: unexpected status %d", event.ID, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Package events publishes the domain events stored in the outbox to downstream systems.
+package events
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"log/slog"
+)
+
+// batchSize limits the number of events the relay reads from the outbox at once.
+const batchSize = 100
+
+// Publisher delivers events to downstream systems. Publish returns an error when the event may not have been
+// delivered; it is published again later then.
+type Publisher interface {
+	Publish(ctx context.Context, event entities.Event) error
+}
+
+func NewRelay(outbox domain.Outbox, publisher Publisher, logger *slog.Logger) *Relay {
+	return &Relay{outbox: outbox, publisher: publisher, logger: logger}
+}
+
+// Relay moves events from the outbox to a publisher. Events are delivered at least once and in the order they were
+// stored: when publishing an event fails, the relay stops and tries again with the same event on the next run.
+type Relay struct {
+	outbox    domain.Outbox
+	publisher Publisher
+	logger    *slog.Logger
+}
+
+// Run publishes all pending events. It is run periodically.
+func (r *Relay) Run(ctx context.Context) error {
+	for {
+		events, err := r.outbox.GetPendingEvents(ctx, batchSize)
+		if err != nil || len(events) == 0 {
+			return err
+		}
+		published := make([]string, 0, len(events))
+		for _, event := range events {
+			if err = r.publisher.Publish(ctx, event); err != nil {
+				break
+			}
+			published = append(published, event.ID)
+		}
+		if len(published) > 0 {
+			if markErr := r.outbox.MarkEventsPublished(ctx, published); markErr != nil {
+				return markErr
+			}
+			logging.FromContextOr(ctx, r.logger).Debug("published events", "count", len(published))
+		}
+		if err != nil || len(events) < batchSize {
+			return err
+		}
+	}
+}
+
+// Publishers delivers events to several publishers in turn. When one fails, the event is published again to all of
+// them later, so the publishers see it more than once.
+type Publishers []Publisher
+
+func (p Publishers) Publish(ctx context.Context, event entities.Event) error {
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
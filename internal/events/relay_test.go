@@ -0,0 +1,137 @@
+package events_test
+
+import (
+	"context"
+	"customer-partner/internal/db"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/events"
+	"customer-partner/internal/logging"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingPublisher fails from the given call on.
+type failingPublisher struct {
+	next    events.Publisher
+	calls   int
+	failsAt int
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, event entities.Event) error {
+	p.calls++
+	if p.calls >= p.failsAt {
+		return errors.New("sink unavailable")
+	}
+	return p.next.Publish(ctx, event)
+}
+
+// createPartners writes n partners through the repository, which adds a PartnerCreated event per partner to the
+// outbox.
+func createPartners(t *testing.T, outbox *db.OutboxInMemoryRepository, n int) {
+	repo := db.NewPartnerInMemoryRepository(outbox)
+	for i := 0; i < n; i++ {
+		id := fmt.Sprint("p", i)
+		event := entities.Event{ID: "e" + id, Type: entities.EventPartnerCreated, AggregateID: id, Data: []byte(`{}`)}
+		require.NoError(t, repo.CreatePartner(context.Background(), entities.Partner{ID: id}, event))
+	}
+}
+
+func receive(ch <-chan entities.Event, n int) []string {
+	ids := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		ids = append(ids, (<-ch).ID)
+	}
+	return ids
+}
+
+func TestRelay_Run(t *testing.T) {
+	ctx := context.Background()
+	outbox := db.NewOutboxInMemoryRepository()
+	createPartners(t, outbox, 250)
+	publisher := events.NewChannelPublisher(250)
+	relay := events.NewRelay(outbox, publisher, logging.Discard())
+
+	require.NoError(t, relay.Run(ctx))
+
+	ids := receive(publisher.Events(), 250)
+	assert.Equal(t, "ep0", ids[0])
+	assert.Equal(t, "ep249", ids[249])
+	pending, err := outbox.GetPendingEvents(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestRelay_Run_PublisherFails(t *testing.T) {
+	ctx := context.Background()
+	outbox := db.NewOutboxInMemoryRepository()
+	createPartners(t, outbox, 3)
+	channel := events.NewChannelPublisher(3)
+	relay := events.NewRelay(outbox, &failingPublisher{next: channel, failsAt: 2}, logging.Discard())
+
+	assert.EqualError(t, relay.Run(ctx), "sink unavailable")
+
+	assert.Equal(t, []string{"ep0"}, receive(channel.Events(), 1))
+	pending, err := outbox.GetPendingEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2, "failed and later events stay in the outbox in order")
+	assert.Equal(t, "ep1", pending[0].ID)
+
+	relay = events.NewRelay(outbox, channel, logging.Discard())
+	require.NoError(t, relay.Run(ctx))
+	assert.Equal(t, []string{"ep1", "ep2"}, receive(channel.Events(), 2))
+}
+
+func TestFilePublisher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	publisher, err := events.OpenFilePublisher(path)
+	require.NoError(t, err)
+	event := entities.Event{ID: "e1", Type: entities.EventPartnerDeleted, AggregateID: "1", Data: []byte(`{"id":"1"}`)}
+
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	require.NoError(t, publisher.Close())
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(raw), "\n"), "\n")
+	require.Len(t, lines, 2)
+	var decoded entities.Event
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &decoded))
+	assert.Equal(t, "e1", decoded.ID)
+	assert.JSONEq(t, `{"id":"1"}`, string(decoded.Data))
+}
+
+func TestHTTPPublisher(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusAccepted)
+	var received atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event entities.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		received.Store(r.Header.Get(events.EventTypeHeader) + " " + r.Header.Get(events.EventIDHeader) + " " + event.ID)
+		w.WriteHeader(int(status.Load()))
+	}))
+	t.Cleanup(server.Close)
+	publisher := events.NewHTTPPublisher(server.URL, server.Client())
+	event := entities.Event{ID: "e1", Type: entities.EventOfferRequested, AggregateID: "o1", Data: []byte(`{}`)}
+
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	assert.Equal(t, "OfferRequested e1 e1", received.Load())
+
+	status.Store(http.StatusServiceUnavailable)
+	assert.EqualError(t, publisher.Publish(context.Background(), event), "publishing event e1: unexpected status 503")
+}
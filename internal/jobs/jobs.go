@@ -0,0 +1,41 @@
+// Package jobs runs maintenance tasks periodically in the background.
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Job is a task run periodically.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Start runs the job immediately and then every interval until ctx is done. A failed run is logged and the job is
+// tried again in the next interval. The returned channel is closed when the job stopped.
+func Start(ctx context.Context, logger *slog.Logger, job Job) <-chan struct{} {
+	done := make(chan struct{})
+	logger = logger.With("job", job.Name)
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(job.Interval)
+		defer ticker.Stop()
+		for {
+			start := time.Now()
+			if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+				logger.Error("job failed", "error", err)
+			} else {
+				logger.Debug("job completed", "duration", time.Since(start))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return done
+}
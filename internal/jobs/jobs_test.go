@@ -0,0 +1,34 @@
+package jobs_test
+
+import (
+	"context"
+	"customer-partner/internal/jobs"
+	"customer-partner/internal/logging"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs atomic.Int32
+	done := jobs.Start(ctx, logging.Discard(), jobs.Job{
+		Name:     "test",
+		Interval: time.Millisecond,
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return errors.New("failing runs are retried")
+		},
+	})
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job did not stop after ctx was canceled")
+	}
+}
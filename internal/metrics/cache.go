@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"customer-partner/internal/cache"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MatchCache is the cache of matches observed by ObserveMatchCache.
+type MatchCache interface {
+	Stats() cache.Stats
+}
+
+// ObserveMatchCache registers the statistics of the match cache. They are read from the cache on every scrape, so
+// that the cache does not depend on the metrics.
+func (m *Metrics) ObserveMatchCache(c MatchCache) {
+	counter := func(name, help string, labels prometheus.Labels, value func(cache.Stats) uint64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        name,
+			Help:        help,
+			ConstLabels: labels,
+		}, func() float64 { return float64(value(c.Stats())) })
+	}
+	m.registry.MustRegister(
+		counter("match_cache_requests_total", "Number of matches looked up in the cache by result.",
+			prometheus.Labels{"result": "hit"}, func(s cache.Stats) uint64 { return s.Hits }),
+		counter("match_cache_requests_total", "Number of matches looked up in the cache by result.",
+			prometheus.Labels{"result": "miss"}, func(s cache.Stats) uint64 { return s.Misses }),
+		counter("match_cache_evictions_total", "Number of matches evicted from the cache because it was full.",
+			nil, func(s cache.Stats) uint64 { return s.Evictions }),
+		counter("match_cache_invalidations_total", "Number of times the cache was cleared because partners changed.",
+			nil, func(s cache.Stats) uint64 { return s.Invalidations }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "match_cache_entries",
+			Help:      "Number of matches in the cache.",
+		}, func() float64 { return float64(c.Stats().Entries) }),
+	)
+}
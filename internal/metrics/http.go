@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute labels requests no route is registered for. It keeps the cardinality of the route label bounded.
+const unmatchedRoute = "unmatched"
+
+// Middleware records the number and latency of requests. route maps a request to the pattern of the route serving
+// it, so that e.g. all requests for single partners share one label.
+func (m *Metrics) Middleware(route func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			pattern := route(r)
+			if pattern == "" {
+				pattern = unmatchedRoute
+			}
+			status := strconv.Itoa(rec.status)
+			m.httpRequests.WithLabelValues(pattern, r.Method, status).Inc()
+			m.httpRequestDuration.WithLabelValues(pattern, r.Method, status).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// statusRecorder records the status code written to a http.ResponseWriter.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap allows http.ResponseController to reach the underlying http.ResponseWriter.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
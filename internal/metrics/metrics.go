@@ -0,0 +1,88 @@
+// Package metrics exposes Prometheus metrics of the service. The http layer is instrumented by a middleware, the
+// repository and the service by decorators, so that their implementations stay free of instrumentation code.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "customer_partner"
+
+// Metrics holds all collectors of the service.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+
+	matchResultSize         prometheus.Histogram
+	matchCandidatesScanned  prometheus.Counter
+	matchCandidatesReturned prometheus.Counter
+
+	repositoryDuration *prometheus.HistogramVec
+}
+
+// New creates the collectors and registers them, together with the Go runtime and process collectors, at a new
+// registry.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of handled http requests by route, method and status code.",
+		}, []string{"route", "method", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of handled http requests by route, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		matchResultSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "match_result_size",
+			Help:      "Number of partners returned by a single match.",
+			Buckets:   []float64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500},
+		}),
+		matchCandidatesScanned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "match_candidates_scanned_total",
+			Help:      "Number of partners loaded from the repository to be checked by a match.",
+		}),
+		matchCandidatesReturned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "match_candidates_returned_total",
+			Help:      "Number of partners returned to customers by a match.",
+		}),
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_call_duration_seconds",
+			Help:      "Latency of partner repository calls by method and outcome.",
+			Buckets:   []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+		}, []string{"method", "outcome"}),
+	}
+	m.registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.matchResultSize,
+		m.matchCandidatesScanned,
+		m.matchCandidatesReturned,
+		m.repositoryDuration,
+	)
+	return m
+}
+
+// Registry returns the registry holding all collectors. It allows other packages to register their own collectors.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler returns the http.Handler serving the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
@@ -0,0 +1,124 @@
+package metrics_test
+
+import (
+	"context"
+	"customer-partner/internal/cache"
+	"customer-partner/internal/domain"
+	domainmocks "customer-partner/internal/domain/mocks"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/metrics"
+	webmocks "customer-partner/internal/web/mocks"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_Middleware(t *testing.T) {
+	m := metrics.New()
+	route := func(r *http.Request) string {
+		if strings.HasPrefix(r.URL.Path, "/partners/") {
+			return "/partners/"
+		}
+		return ""
+	}
+	handler := m.Middleware(route)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/partners/404" {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+
+	for _, path := range []string{"/partners/1", "/partners/2", "/partners/404", "/unknown"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	expected := `
+# HELP customer_partner_http_requests_total Number of handled http requests by route, method and status code.
+# TYPE customer_partner_http_requests_total counter
+customer_partner_http_requests_total{method="GET",route="/partners/",status="200"} 2
+customer_partner_http_requests_total{method="GET",route="/partners/",status="404"} 1
+customer_partner_http_requests_total{method="GET",route="unmatched",status="200"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(m.Registry(), strings.NewReader(expected),
+		"customer_partner_http_requests_total"))
+}
+
+func TestInstrumentedDecorators(t *testing.T) {
+	m := metrics.New()
+	repo := &domainmocks.PartnerRepository{}
+	ctx := context.Background()
+	repo.On("GetPartnersByMaterial", ctx, "wood").Return([]entities.Partner{{ID: "1"}, {ID: "2"}, {ID: "3"}}, nil)
+	repo.On("GetPartnerByID", ctx, "4").Return(entities.Partner{}, entities.ErrRecordNotExist)
+	service := &webmocks.PartnerService{}
+	opts := domain.GetPartnersOpts{Material: "wood"}
+	service.On("GetPartners", ctx, opts).Return([]entities.Partner{{ID: "1"}}, nil)
+
+	instrumentedRepo := metrics.NewInstrumentedPartnerRepository(repo, m)
+	instrumentedService := metrics.NewInstrumentedPartnerService(service, m)
+	partners, err := instrumentedRepo.GetPartnersByMaterial(ctx, "wood")
+	assert.NoError(t, err)
+	assert.Len(t, partners, 3)
+	_, err = instrumentedRepo.GetPartnerByID(ctx, "4")
+	assert.ErrorIs(t, err, entities.ErrRecordNotExist)
+	partners, err = instrumentedService.GetPartners(ctx, opts)
+	assert.NoError(t, err)
+	assert.Len(t, partners, 1)
+
+	repo.AssertExpectations(t)
+	service.AssertExpectations(t)
+	expected := `
+# HELP customer_partner_match_candidates_returned_total Number of partners returned to customers by a match.
+# TYPE customer_partner_match_candidates_returned_total counter
+customer_partner_match_candidates_returned_total 1
+# HELP customer_partner_match_candidates_scanned_total Number of partners loaded from the repository to be checked by a match.
+# TYPE customer_partner_match_candidates_scanned_total counter
+customer_partner_match_candidates_scanned_total 3
+`
+	assert.NoError(t, testutil.GatherAndCompare(m.Registry(), strings.NewReader(expected),
+		"customer_partner_match_candidates_returned_total",
+		"customer_partner_match_candidates_scanned_total",
+	))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.Registry(), "customer_partner_repository_call_duration_seconds"))
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `customer_partner_repository_call_duration_seconds_count{method="GetPartnerByID",outcome="not_found"} 1`)
+	assert.Contains(t, string(body), "customer_partner_match_result_size_bucket")
+}
+
+type matchCache struct {
+	stats cache.Stats
+}
+
+func (c matchCache) Stats() cache.Stats {
+	return c.stats
+}
+
+func TestMetrics_ObserveMatchCache(t *testing.T) {
+	m := metrics.New()
+
+	m.ObserveMatchCache(matchCache{cache.Stats{Hits: 7, Misses: 3, Evictions: 1, Invalidations: 2, Entries: 2}})
+
+	expected := `
+# HELP customer_partner_match_cache_entries Number of matches in the cache.
+# TYPE customer_partner_match_cache_entries gauge
+customer_partner_match_cache_entries 2
+# HELP customer_partner_match_cache_requests_total Number of matches looked up in the cache by result.
+# TYPE customer_partner_match_cache_requests_total counter
+customer_partner_match_cache_requests_total{result="hit"} 7
+customer_partner_match_cache_requests_total{result="miss"} 3
+`
+	assert.NoError(t, testutil.GatherAndCompare(m.Registry(), strings.NewReader(expected),
+		"customer_partner_match_cache_entries",
+		"customer_partner_match_cache_requests_total",
+	))
+}
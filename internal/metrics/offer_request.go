@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"time"
+)
+
+func NewInstrumentedOfferRequestRepository(
+	next domain.OfferRequestRepository,
+	m *Metrics,
+) *InstrumentedOfferRequestRepository {
+	return &InstrumentedOfferRequestRepository{next: next, metrics: m}
+}
+
+// InstrumentedOfferRequestRepository records the latency of repository calls.
+type InstrumentedOfferRequestRepository struct {
+	next    domain.OfferRequestRepository
+	metrics *Metrics
+}
+
+func (r *InstrumentedOfferRequestRepository) CreateOfferRequest(
+	ctx context.Context,
+	request entities.OfferRequest,
+	events ...entities.Event,
+) error {
+	start := time.Now()
+	err := r.next.CreateOfferRequest(ctx, request, events...)
+	r.observe("CreateOfferRequest", start, err)
+	return err
+}
+
+func (r *InstrumentedOfferRequestRepository) GetOfferRequestByID(ctx context.Context, id string) (entities.OfferRequest, error) {
+	start := time.Now()
+	request, err := r.next.GetOfferRequestByID(ctx, id)
+	r.observe("GetOfferRequestByID", start, err)
+	return request, err
+}
+
+func (r *InstrumentedOfferRequestRepository) GetOfferRequestsByPartner(ctx context.Context, partnerID string) ([]entities.OfferRequest, error) {
+	start := time.Now()
+	requests, err := r.next.GetOfferRequestsByPartner(ctx, partnerID)
+	r.observe("GetOfferRequestsByPartner", start, err)
+	return requests, err
+}
+
+func (r *InstrumentedOfferRequestRepository) PurgeContactData(ctx context.Context, createdBefore, purgedAt time.Time) (int, error) {
+	start := time.Now()
+	purged, err := r.next.PurgeContactData(ctx, createdBefore, purgedAt)
+	r.observe("PurgeContactData", start, err)
+	return purged, err
+}
+
+func (r *InstrumentedOfferRequestRepository) GetOfferRequestsByContact(ctx context.Context, phone, email string) ([]entities.OfferRequest, error) {
+	start := time.Now()
+	requests, err := r.next.GetOfferRequestsByContact(ctx, phone, email)
+	r.observe("GetOfferRequestsByContact", start, err)
+	return requests, err
+}
+
+func (r *InstrumentedOfferRequestRepository) GetOfferRequestsByCustomer(
+	ctx context.Context,
+	customerID string,
+) ([]entities.OfferRequest, error) {
+	start := time.Now()
+	requests, err := r.next.GetOfferRequestsByCustomer(ctx, customerID)
+	r.observe("GetOfferRequestsByCustomer", start, err)
+	return requests, err
+}
+
+func (r *InstrumentedOfferRequestRepository) AnonymizeOfferRequests(ctx context.Context, ids []string, purgedAt time.Time) (int, error) {
+	start := time.Now()
+	anonymized, err := r.next.AnonymizeOfferRequests(ctx, ids, purgedAt)
+	r.observe("AnonymizeOfferRequests", start, err)
+	return anonymized, err
+}
+
+func (r *InstrumentedOfferRequestRepository) CreateOfferRequests(
+	ctx context.Context,
+	requests []entities.OfferRequest,
+	events ...entities.Event,
+) error {
+	start := time.Now()
+	err := r.next.CreateOfferRequests(ctx, requests, events...)
+	r.observe("CreateOfferRequests", start, err)
+	return err
+}
+
+func (r *InstrumentedOfferRequestRepository) GetOfferRequestsByLead(ctx context.Context, leadID string) ([]entities.OfferRequest, error) {
+	start := time.Now()
+	requests, err := r.next.GetOfferRequestsByLead(ctx, leadID)
+	r.observe("GetOfferRequestsByLead", start, err)
+	return requests, err
+}
+
+func (r *InstrumentedOfferRequestRepository) CountLeadsByPartner(ctx context.Context, partnerID string, since time.Time) (int, error) {
+	start := time.Now()
+	count, err := r.next.CountLeadsByPartner(ctx, partnerID, since)
+	r.observe("CountLeadsByPartner", start, err)
+	return count, err
+}
+
+func (r *InstrumentedOfferRequestRepository) RespondToOfferRequest(
+	ctx context.Context,
+	id, response string,
+	respondedAt time.Time,
+	events ...entities.Event,
+) (entities.OfferRequest, error) {
+	start := time.Now()
+	request, err := r.next.RespondToOfferRequest(ctx, id, response, respondedAt, events...)
+	r.observe("RespondToOfferRequest", start, err)
+	return request, err
+}
+
+func (r *InstrumentedOfferRequestRepository) observe(method string, start time.Time, err error) {
+	r.metrics.repositoryDuration.WithLabelValues(method, outcome(err)).Observe(time.Since(start).Seconds())
+}
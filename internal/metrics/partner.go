@@ -0,0 +1,158 @@
+package metrics
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"errors"
+	"time"
+)
+
+// PartnerService is the service interface decorated by InstrumentedPartnerService. It matches web.PartnerService.
+type PartnerService interface {
+	GetPartners(ctx context.Context, opts domain.GetPartnersOpts) ([]entities.Partner, error)
+	GetPartner(ctx context.Context, id string) (entities.Partner, error)
+	CreatePartner(ctx context.Context, partner entities.Partner) (entities.Partner, error)
+	UpdatePartner(ctx context.Context, partner entities.Partner) (entities.Partner, error)
+	SuspendPartner(ctx context.Context, id, reason string) (entities.Partner, error)
+	ReinstatePartner(ctx context.Context, id string) (entities.Partner, error)
+	DeletePartner(ctx context.Context, id, reason string) error
+	GetAllPartners(ctx context.Context) ([]entities.Partner, error)
+	ImportPartners(ctx context.Context, rows []domain.ImportRow, dryRun bool) (domain.ImportResult, error)
+}
+
+func NewInstrumentedPartnerService(next PartnerService, m *Metrics) *InstrumentedPartnerService {
+	return &InstrumentedPartnerService{next: next, metrics: m}
+}
+
+// InstrumentedPartnerService records the size of match results before passing them on.
+type InstrumentedPartnerService struct {
+	next    PartnerService
+	metrics *Metrics
+}
+
+func (s *InstrumentedPartnerService) GetPartners(ctx context.Context, opts domain.GetPartnersOpts) ([]entities.Partner, error) {
+	partners, err := s.next.GetPartners(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	s.metrics.matchResultSize.Observe(float64(len(partners)))
+	s.metrics.matchCandidatesReturned.Add(float64(len(partners)))
+	return partners, nil
+}
+
+func (s *InstrumentedPartnerService) GetPartner(ctx context.Context, id string) (entities.Partner, error) {
+	return s.next.GetPartner(ctx, id)
+}
+
+func (s *InstrumentedPartnerService) CreatePartner(ctx context.Context, partner entities.Partner) (entities.Partner, error) {
+	return s.next.CreatePartner(ctx, partner)
+}
+
+func (s *InstrumentedPartnerService) UpdatePartner(ctx context.Context, partner entities.Partner) (entities.Partner, error) {
+	return s.next.UpdatePartner(ctx, partner)
+}
+
+func (s *InstrumentedPartnerService) SuspendPartner(ctx context.Context, id, reason string) (entities.Partner, error) {
+	return s.next.SuspendPartner(ctx, id, reason)
+}
+
+func (s *InstrumentedPartnerService) ReinstatePartner(ctx context.Context, id string) (entities.Partner, error) {
+	return s.next.ReinstatePartner(ctx, id)
+}
+
+func (s *InstrumentedPartnerService) DeletePartner(ctx context.Context, id, reason string) error {
+	return s.next.DeletePartner(ctx, id, reason)
+}
+
+func (s *InstrumentedPartnerService) GetAllPartners(ctx context.Context) ([]entities.Partner, error) {
+	return s.next.GetAllPartners(ctx)
+}
+
+func (s *InstrumentedPartnerService) ImportPartners(
+	ctx context.Context,
+	rows []domain.ImportRow,
+	dryRun bool,
+) (domain.ImportResult, error) {
+	return s.next.ImportPartners(ctx, rows, dryRun)
+}
+
+func NewInstrumentedPartnerRepository(next domain.PartnerRepository, m *Metrics) *InstrumentedPartnerRepository {
+	return &InstrumentedPartnerRepository{next: next, metrics: m}
+}
+
+// InstrumentedPartnerRepository records the latency of repository calls and the number of partners loaded as match
+// candidates.
+type InstrumentedPartnerRepository struct {
+	next    domain.PartnerRepository
+	metrics *Metrics
+}
+
+func (r *InstrumentedPartnerRepository) GetPartnersByMaterial(ctx context.Context, material string) ([]entities.Partner, error) {
+	start := time.Now()
+	partners, err := r.next.GetPartnersByMaterial(ctx, material)
+	r.observe("GetPartnersByMaterial", start, err)
+	r.metrics.matchCandidatesScanned.Add(float64(len(partners)))
+	return partners, err
+}
+
+func (r *InstrumentedPartnerRepository) GetPartnerByID(ctx context.Context, id string) (entities.Partner, error) {
+	start := time.Now()
+	partner, err := r.next.GetPartnerByID(ctx, id)
+	r.observe("GetPartnerByID", start, err)
+	return partner, err
+}
+
+func (r *InstrumentedPartnerRepository) GetAllPartners(ctx context.Context) ([]entities.Partner, error) {
+	start := time.Now()
+	partners, err := r.next.GetAllPartners(ctx)
+	r.observe("GetAllPartners", start, err)
+	return partners, err
+}
+
+func (r *InstrumentedPartnerRepository) GetPartnersWithExpiringCertifications(
+	ctx context.Context,
+	before time.Time,
+) ([]entities.Partner, error) {
+	start := time.Now()
+	partners, err := r.next.GetPartnersWithExpiringCertifications(ctx, before)
+	r.observe("GetPartnersWithExpiringCertifications", start, err)
+	return partners, err
+}
+
+func (r *InstrumentedPartnerRepository) CreatePartner(ctx context.Context, partner entities.Partner, events ...entities.Event) error {
+	start := time.Now()
+	err := r.next.CreatePartner(ctx, partner, events...)
+	r.observe("CreatePartner", start, err)
+	return err
+}
+
+func (r *InstrumentedPartnerRepository) UpdatePartner(ctx context.Context, partner entities.Partner, events ...entities.Event) error {
+	start := time.Now()
+	err := r.next.UpdatePartner(ctx, partner, events...)
+	r.observe("UpdatePartner", start, err)
+	return err
+}
+
+func (r *InstrumentedPartnerRepository) DeletePartner(ctx context.Context, id string, events ...entities.Event) error {
+	start := time.Now()
+	err := r.next.DeletePartner(ctx, id, events...)
+	r.observe("DeletePartner", start, err)
+	return err
+}
+
+func (r *InstrumentedPartnerRepository) observe(method string, start time.Time, err error) {
+	r.metrics.repositoryDuration.WithLabelValues(method, outcome(err)).Observe(time.Since(start).Seconds())
+}
+
+// outcome reduces an error to a label value. A missing record is an expected outcome and not counted as error.
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, entities.ErrRecordNotExist):
+		return "not_found"
+	default:
+		return "error"
+	}
+}
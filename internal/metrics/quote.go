@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"time"
+)
+
+func NewInstrumentedQuoteRepository(next domain.QuoteRepository, m *Metrics) *InstrumentedQuoteRepository {
+	return &InstrumentedQuoteRepository{next: next, metrics: m}
+}
+
+// InstrumentedQuoteRepository records the latency of repository calls.
+type InstrumentedQuoteRepository struct {
+	next    domain.QuoteRepository
+	metrics *Metrics
+}
+
+func (r *InstrumentedQuoteRepository) CreateQuote(
+	ctx context.Context,
+	quote entities.Quote,
+	events ...entities.Event,
+) error {
+	start := time.Now()
+	err := r.next.CreateQuote(ctx, quote, events...)
+	r.observe("CreateQuote", start, err)
+	return err
+}
+
+func (r *InstrumentedQuoteRepository) GetQuoteByID(ctx context.Context, id string) (entities.Quote, error) {
+	start := time.Now()
+	quote, err := r.next.GetQuoteByID(ctx, id)
+	r.observe("GetQuoteByID", start, err)
+	return quote, err
+}
+
+func (r *InstrumentedQuoteRepository) GetQuotesByOfferRequest(ctx context.Context, offerRequestID string) ([]entities.Quote, error) {
+	start := time.Now()
+	quotes, err := r.next.GetQuotesByOfferRequest(ctx, offerRequestID)
+	r.observe("GetQuotesByOfferRequest", start, err)
+	return quotes, err
+}
+
+func (r *InstrumentedQuoteRepository) DecideQuote(
+	ctx context.Context,
+	id, status string,
+	decidedAt time.Time,
+	events ...entities.Event,
+) (entities.Quote, error) {
+	start := time.Now()
+	quote, err := r.next.DecideQuote(ctx, id, status, decidedAt, events...)
+	r.observe("DecideQuote", start, err)
+	return quote, err
+}
+
+func (r *InstrumentedQuoteRepository) observe(method string, start time.Time, err error) {
+	r.metrics.repositoryDuration.WithLabelValues(method, outcome(err)).Observe(time.Since(start).Seconds())
+}
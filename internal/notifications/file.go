@@ -0,0 +1,44 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// OpenFileNotifier opens the file at path for appending and creates it when it does not exist.
+func OpenFileNotifier(path string) (*FileNotifier, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileNotifier{file: f}, nil
+}
+
+// FileNotifier appends messages as newline delimited json to a file instead of sending them. It serves local
+// development and tests on any channel.
+type FileNotifier struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// Notify writes the message as a single line.
+func (n *FileNotifier) Notify(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	line, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err = n.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the file.
+func (n *FileNotifier) Close() error {
+	return n.file.Close()
+}
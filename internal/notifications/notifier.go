@@ -0,0 +1,61 @@
+// Package notifications informs customers and partners about offer requests by email and SMS. Messages are rendered
+// from templates in the language of the recipient and sent in the background with retries.
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// Channel is the way a message reaches its recipient.
+type Channel string
+
+const (
+	ChannelEmail Channel = "email"
+	ChannelSMS   Channel = "sms"
+)
+
+// ErrChannelNotConfigured is returned when a message is sent over a channel without provider.
+var ErrChannelNotConfigured = errors.New("notification channel not configured")
+
+// Message is a notification for a single recipient. To is an email address or a phone number depending on the
+// channel, Subject is only used for emails.
+type Message struct {
+	Channel  Channel `json:"channel"`
+	To       string  `json:"to"`
+	Subject  string  `json:"subject,omitempty"`
+	Body     string  `json:"body"`
+	Template string  `json:"template"`
+}
+
+// LogValue implements slog.LogValuer. The recipient and the text are left out, since they contain personal data.
+func (m Message) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("channel", string(m.Channel)),
+		slog.String("template", m.Template),
+	)
+}
+
+// Notifier sends messages through a provider. Notify returns an error when the message may not have been sent; it is
+// sent again later then.
+type Notifier interface {
+	Notify(ctx context.Context, message Message) error
+}
+
+// Channels sends messages with the notifier of their channel. Channels without notifier are disabled.
+type Channels map[Channel]Notifier
+
+// Enabled reports whether messages can be sent over the channel.
+func (c Channels) Enabled(channel Channel) bool {
+	return c[channel] != nil
+}
+
+func (c Channels) Notify(ctx context.Context, message Message) error {
+	notifier := c[message.Channel]
+	if notifier == nil {
+		return fmt.Errorf("%w: %s", ErrChannelNotConfigured, message.Channel)
+	}
+	return notifier.Notify(ctx, message)
+}
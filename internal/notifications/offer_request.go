@@ -0,0 +1,132 @@
+package notifications
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/events"
+	"customer-partner/internal/logging"
+	"encoding/json"
+	"errors"
+	"log/slog"
+)
+
+// OfferRequestData is passed to the templates about offer requests.
+type OfferRequestData struct {
+	OfferRequestID string
+	PartnerName    string
+	FloorSize      float64
+	CustomerPhone  string
+	CustomerEmail  string
+}
+
+func NewOfferRequestNotifier(
+	offerRequests domain.OfferRequestRepository,
+	partners domain.PartnerRepository,
+	templates *Templates,
+	channels Channels,
+	queue *Queue,
+	logger *slog.Logger,
+) *OfferRequestNotifier {
+	return &OfferRequestNotifier{
+		offerRequests: offerRequests,
+		partners:      partners,
+		templates:     templates,
+		channels:      channels,
+		queue:         queue,
+		logger:        logger,
+	}
+}
+
+// OfferRequestNotifier confirms new offer requests to the customer and notifies the partner. It is an
+// events.Publisher for the relay. Events carry no contact data, so it is read from the offer request.
+type OfferRequestNotifier struct {
+	offerRequests domain.OfferRequestRepository
+	partners      domain.PartnerRepository
+	templates     *Templates
+	channels      Channels
+	queue         *Queue
+	logger        *slog.Logger
+}
+
+var _ events.Publisher = (*OfferRequestNotifier)(nil)
+
+// Publish enqueues the messages about an OfferRequested event. The customer gets an SMS and, when given, an email.
+// The partner gets an email and an SMS to the contact data they provided. Messages over disabled channels are left
+// out. Offer requests whose contact data is gone by now are not notified.
+func (n *OfferRequestNotifier) Publish(ctx context.Context, event entities.Event) error {
+	if event.Type != entities.EventOfferRequested {
+		return nil
+	}
+	var data domain.OfferRequested
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+	logger := logging.FromContextOr(ctx, n.logger).With("offer_request_id", data.ID)
+	request, err := n.offerRequests.GetOfferRequestByID(ctx, data.ID)
+	if errors.Is(err, entities.ErrRecordNotExist) {
+		logger.Debug("offer request not notified, it does not exist anymore")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if request.ContactPurgedAt != nil {
+		logger.Debug("offer request not notified, its contact data is purged")
+		return nil
+	}
+	partner, err := n.partners.GetPartnerByID(ctx, request.PartnerID)
+	if errors.Is(err, entities.ErrRecordNotExist) {
+		logger.Debug("offer request not notified, its partner does not exist anymore")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	templateData := OfferRequestData{
+		OfferRequestID: request.ID,
+		PartnerName:    partner.Name,
+		FloorSize:      request.FloorSize,
+		CustomerPhone:  request.Phone,
+		CustomerEmail:  request.Email,
+	}
+	recipients := []recipient{
+		{ChannelSMS, request.Phone, request.Language, TemplateOfferRequestedCustomer},
+		{ChannelEmail, request.Email, request.Language, TemplateOfferRequestedCustomer},
+	}
+	if contact := partner.Contact; contact != nil {
+		recipients = append(recipients,
+			recipient{ChannelEmail, contact.Email, contact.Language, TemplateOfferRequestedPartner},
+			recipient{ChannelSMS, contact.Phone, contact.Language, TemplateOfferRequestedPartner},
+		)
+	}
+	var messages []Message
+	for _, r := range recipients {
+		if r.to == "" || !n.channels.Enabled(r.channel) {
+			continue
+		}
+		message, err := n.templates.Render(r.language, r.template, r.channel, templateData)
+		if err != nil {
+			return err
+		}
+		message.To = r.to
+		messages = append(messages, message)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	if err := n.queue.Enqueue(messages...); err != nil {
+		return err
+	}
+	logger.Debug("notifications enqueued", "count", len(messages))
+	return nil
+}
+
+// recipient describes a message to render.
+type recipient struct {
+	channel  Channel
+	to       string
+	language string
+	template string
+}
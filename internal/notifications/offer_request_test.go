@@ -0,0 +1,118 @@
+package notifications
+
+import (
+	"context"
+	"customer-partner/internal/db"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"customer-partner/internal/privacy"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfferRequestNotifier(t *testing.T) {
+	ctx := context.Background()
+	partners := db.NewPartnerInMemoryRepository(nil)
+	require.NoError(t, partners.CreatePartner(ctx, entities.Partner{
+		ID:      "p1",
+		Name:    "Parkett Paradies",
+		Contact: &entities.Contact{Email: "info@parkett.example", Language: "en"},
+	}))
+	require.NoError(t, partners.CreatePartner(ctx, entities.Partner{ID: "p2", Name: "Floors"}))
+	cipher, err := privacy.NewRandomCipher()
+	require.NoError(t, err)
+	offerRequests := db.NewOfferRequestInMemoryRepository(cipher, nil)
+	purgedAt := time.Now()
+	for _, request := range []entities.OfferRequest{
+		{ID: "r1", PartnerID: "p1", FloorSize: 42.5, Phone: "+49 170 1234567", Email: "jane@example.com"},
+		{ID: "r2", PartnerID: "p2", FloorSize: 10, Phone: "+49 170 7654321", Language: "en"},
+		{ID: "r3", PartnerID: "p1", FloorSize: 10, ContactPurgedAt: &purgedAt},
+	} {
+		require.NoError(t, offerRequests.CreateOfferRequest(ctx, request))
+	}
+	templates, err := LoadTemplates(DefaultTemplates)
+	require.NoError(t, err)
+
+	type testCase struct {
+		name        string
+		event       entities.Event
+		channels    Channels
+		expMessages []Message
+	}
+	tests := []testCase{
+		{
+			name:     "Notifies customer and partner",
+			event:    offerRequested(t, "r1"),
+			channels: Channels{ChannelEmail: &recorder{}, ChannelSMS: &recorder{}},
+			expMessages: []Message{
+				{Channel: ChannelSMS, To: "+49 170 1234567", Template: TemplateOfferRequestedCustomer},
+				{Channel: ChannelEmail, To: "jane@example.com", Template: TemplateOfferRequestedCustomer},
+				{Channel: ChannelEmail, To: "info@parkett.example", Template: TemplateOfferRequestedPartner},
+			},
+		},
+		{
+			name:     "Leaves out disabled channels",
+			event:    offerRequested(t, "r1"),
+			channels: Channels{ChannelEmail: &recorder{}},
+			expMessages: []Message{
+				{Channel: ChannelEmail, To: "jane@example.com", Template: TemplateOfferRequestedCustomer},
+				{Channel: ChannelEmail, To: "info@parkett.example", Template: TemplateOfferRequestedPartner},
+			},
+		},
+		{
+			name:     "Notifies only customer when partner has no contact",
+			event:    offerRequested(t, "r2"),
+			channels: Channels{ChannelEmail: &recorder{}, ChannelSMS: &recorder{}},
+			expMessages: []Message{
+				{Channel: ChannelSMS, To: "+49 170 7654321", Template: TemplateOfferRequestedCustomer},
+			},
+		},
+		{
+			name:     "Skips offer requests with purged contact data",
+			event:    offerRequested(t, "r3"),
+			channels: Channels{ChannelEmail: &recorder{}, ChannelSMS: &recorder{}},
+		},
+		{
+			name:     "Skips unknown offer requests",
+			event:    offerRequested(t, "r4"),
+			channels: Channels{ChannelEmail: &recorder{}, ChannelSMS: &recorder{}},
+		},
+		{
+			name:     "Ignores other events",
+			event:    entities.Event{ID: "e1", Type: entities.EventPartnerUpdated, AggregateID: "p1"},
+			channels: Channels{ChannelEmail: &recorder{}, ChannelSMS: &recorder{}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			queue := NewQueue(tt.channels, DefaultQueueConfig, logging.Discard())
+			notifier := NewOfferRequestNotifier(offerRequests, partners, templates, tt.channels, queue,
+				logging.Discard())
+
+			require.NoError(t, notifier.Publish(ctx, tt.event))
+			require.NoError(t, queue.Send(ctx))
+
+			var sent []Message
+			for _, channel := range []Channel{ChannelSMS, ChannelEmail} {
+				if r, ok := tt.channels[channel].(*recorder); ok {
+					for _, message := range r.sent {
+						assert.NotEmpty(t, message.Body)
+						sent = append(sent, Message{Channel: message.Channel, To: message.To, Template: message.Template})
+					}
+				}
+			}
+			assert.ElementsMatch(t, tt.expMessages, sent)
+		})
+	}
+}
+
+func offerRequested(t *testing.T, id string) entities.Event {
+	data, err := json.Marshal(map[string]any{"id": id})
+	require.NoError(t, err)
+	return entities.Event{ID: "e-" + id, Type: entities.EventOfferRequested, AggregateID: id, Data: data}
+}
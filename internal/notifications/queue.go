@@ -0,0 +1,144 @@
+package notifications
+
+import (
+	"context"
+	"customer-partner/internal/logging"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrQueueFull is returned when messages are enqueued while the queue is at its capacity.
+var ErrQueueFull = errors.New("notification queue full")
+
+// QueueConfig controls the attempts to send messages.
+type QueueConfig struct {
+	// Capacity limits the number of messages waiting to be sent.
+	Capacity int
+	// MaxAttempts is the number of attempts after which a message is given up.
+	MaxAttempts int
+	// InitialBackoff is the delay after the first failed attempt. It doubles with every further attempt up to
+	// MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Timeout limits the time of a single attempt.
+	Timeout time.Duration
+}
+
+// DefaultQueueConfig retries a message for about half an hour.
+var DefaultQueueConfig = QueueConfig{
+	Capacity:       10000,
+	MaxAttempts:    10,
+	InitialBackoff: 5 * time.Second,
+	MaxBackoff:     10 * time.Minute,
+	Timeout:        10 * time.Second,
+}
+
+func NewQueue(notifier Notifier, cfg QueueConfig, logger *slog.Logger) *Queue {
+	return &Queue{notifier: notifier, cfg: cfg, logger: logger, now: time.Now}
+}
+
+// Queue sends messages in the background, so that slow providers do not hold up the caller. Messages which fail are
+// retried with exponential backoff. The queue is held in memory: messages still waiting on shutdown are lost.
+type Queue struct {
+	mu       sync.Mutex
+	pending  []queuedMessage
+	notifier Notifier
+	cfg      QueueConfig
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// queuedMessage is a message waiting for its next attempt.
+type queuedMessage struct {
+	message  Message
+	attempts int
+	next     time.Time
+}
+
+// Enqueue adds the messages to the queue to be sent by the next Send. Either all or none of the messages are added.
+func (q *Queue) Enqueue(messages ...Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending)+len(messages) > q.cfg.Capacity {
+		return ErrQueueFull
+	}
+	now := q.now()
+	for _, message := range messages {
+		q.pending = append(q.pending, queuedMessage{message: message, next: now})
+	}
+	return nil
+}
+
+// Len returns the number of messages waiting to be sent.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// Send attempts all messages which are due. It is run periodically.
+func (q *Queue) Send(ctx context.Context) error {
+	logger := logging.FromContextOr(ctx, q.logger)
+	for _, queued := range q.takeDue() {
+		attemptCtx, cancel := context.WithTimeout(ctx, q.cfg.Timeout)
+		err := q.notifier.Notify(attemptCtx, queued.message)
+		cancel()
+		if err == nil {
+			logger.Debug("notification sent", "message", queued.message)
+			continue
+		}
+		if ctx.Err() != nil {
+			// The attempt was cut short, it does not count.
+			q.requeue(queued)
+			continue
+		}
+		queued.attempts++
+		if queued.attempts >= q.cfg.MaxAttempts {
+			logger.Error("notification given up", "message", queued.message, "attempts", queued.attempts, "error", err)
+			continue
+		}
+		logger.Warn("sending notification failed", "message", queued.message, "attempts", queued.attempts,
+			"error", err)
+		queued.next = q.now().Add(q.backoff(queued.attempts))
+		q.requeue(queued)
+	}
+	return ctx.Err()
+}
+
+func (q *Queue) requeue(queued queuedMessage) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = append(q.pending, queued)
+}
+
+// takeDue removes the messages which are due from the queue, so that concurrent runs do not send them twice.
+func (q *Queue) takeDue() []queuedMessage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := q.now()
+	var due []queuedMessage
+	waiting := q.pending[:0]
+	for _, queued := range q.pending {
+		if queued.next.After(now) {
+			waiting = append(waiting, queued)
+		} else {
+			due = append(due, queued)
+		}
+	}
+	q.pending = waiting
+	return due
+}
+
+// backoff returns the delay after the given number of failed attempts.
+func (q *Queue) backoff(attempts int) time.Duration {
+	backoff := q.cfg.InitialBackoff
+	for i := 1; i < attempts && backoff < q.cfg.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > q.cfg.MaxBackoff {
+		return q.cfg.MaxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,84 @@
+package notifications
+
+import (
+	"context"
+	"customer-partner/internal/logging"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a Notifier which records the messages and fails while err is set.
+type recorder struct {
+	mu       sync.Mutex
+	err      error
+	attempts int
+	sent     []Message
+}
+
+func (r *recorder) Notify(ctx context.Context, message Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts++
+	if r.err != nil {
+		return r.err
+	}
+	r.sent = append(r.sent, message)
+	return nil
+}
+
+func TestQueue(t *testing.T) {
+	ctx := context.Background()
+	notifier := &recorder{err: errors.New("gateway down")}
+	queue := NewQueue(notifier, QueueConfig{
+		Capacity:       2,
+		MaxAttempts:    3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		Timeout:        time.Second,
+	}, logging.Discard())
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	queue.now = func() time.Time { return now }
+	message := Message{Channel: ChannelSMS, To: "+49 170 1234567", Body: "hello"}
+
+	require.NoError(t, queue.Enqueue(message))
+	assert.ErrorIs(t, queue.Enqueue(message, message), ErrQueueFull, "messages are added all or none")
+	require.NoError(t, queue.Send(ctx))
+	assert.Equal(t, 1, notifier.attempts)
+
+	require.NoError(t, queue.Send(ctx))
+	assert.Equal(t, 1, notifier.attempts, "failed messages wait for the backoff")
+
+	notifier.err = nil
+	now = now.Add(time.Second)
+	require.NoError(t, queue.Send(ctx))
+	assert.Equal(t, []Message{message}, notifier.sent)
+	assert.Zero(t, queue.Len())
+}
+
+func TestQueue_GivesUp(t *testing.T) {
+	ctx := context.Background()
+	notifier := &recorder{err: errors.New("gateway down")}
+	queue := NewQueue(notifier, QueueConfig{
+		Capacity:       1,
+		MaxAttempts:    3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		Timeout:        time.Second,
+	}, logging.Discard())
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	queue.now = func() time.Time { return now }
+	require.NoError(t, queue.Enqueue(Message{Channel: ChannelEmail}))
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, queue.Send(ctx))
+		now = now.Add(time.Minute)
+	}
+
+	assert.Equal(t, 3, notifier.attempts)
+	assert.Zero(t, queue.Len())
+}
@@ -0,0 +1,62 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// smsTimeout limits the time a single request of SMSGatewayNotifier may take.
+const smsTimeout = 10 * time.Second
+
+// NewSMSGatewayNotifier creates a notifier posting to the gateway at url. token is sent as bearer token when it is
+// not empty. client may be nil to use a client with a timeout of 10 seconds.
+func NewSMSGatewayNotifier(url, token string, client *http.Client) *SMSGatewayNotifier {
+	if client == nil {
+		client = &http.Client{Timeout: smsTimeout}
+	}
+	return &SMSGatewayNotifier{url: url, token: token, client: client}
+}
+
+// SMSGatewayNotifier sends text messages through a generic HTTP gateway. Every message is posted as json object with
+// the attributes "to" and "text", any 2xx status acknowledges it.
+type SMSGatewayNotifier struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// smsRequest is the request body of the gateway.
+type smsRequest struct {
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
+
+func (n *SMSGatewayNotifier) Notify(ctx context.Context, message Message) error {
+	body, err := json.Marshal(smsRequest{To: message.To, Text: message.Body})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sending sms: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMSGatewayNotifier(t *testing.T) {
+	type testCase struct {
+		name      string
+		status    int
+		expErr    bool
+		expAuth   string
+		withToken bool
+	}
+	tests := []testCase{
+		{name: "Posts the message", status: http.StatusAccepted, withToken: true, expAuth: "Bearer secret"},
+		{name: "Posts without token", status: http.StatusOK},
+		{name: "Fails on error status", status: http.StatusBadGateway, expErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var received smsRequest
+			var auth string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				auth = r.Header.Get("Authorization")
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+			token := ""
+			if tt.withToken {
+				token = "secret"
+			}
+			notifier := NewSMSGatewayNotifier(server.URL, token, server.Client())
+
+			err := notifier.Notify(context.Background(), Message{
+				Channel: ChannelSMS,
+				To:      "+49 170 1234567",
+				Body:    "hello",
+			})
+
+			if tt.expErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, smsRequest{To: "+49 170 1234567", Text: "hello"}, received)
+			assert.Equal(t, tt.expAuth, auth)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package notifications
+
+import (
+	"bytes"
+	"customer-partner/internal/entities"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// Names of the templates.
+const (
+	TemplateOfferRequestedCustomer = "offer_requested_customer"
+	TemplateOfferRequestedPartner  = "offer_requested_partner"
+)
+
+//go:embed templates
+var embedded embed.FS
+
+// DefaultTemplates contains the templates shipped with the service.
+var DefaultTemplates, _ = fs.Sub(embedded, "templates")
+
+// LoadTemplates parses the templates in fsys, which holds a directory per language with a file "<name>.tmpl" per
+// template, e.g. "en/offer_requested_customer.tmpl". Every template defines the blocks "subject", "email" and "sms"
+// and must exist in the default language.
+func LoadTemplates(fsys fs.FS) (*Templates, error) {
+	files, err := fs.Glob(fsys, "*/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	t := &Templates{templates: map[string]*template.Template{}}
+	for _, file := range files {
+		parsed, err := template.ParseFS(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range []string{"subject", string(ChannelEmail), string(ChannelSMS)} {
+			if parsed.Lookup(block) == nil {
+				return nil, fmt.Errorf("template %s: missing block %q", file, block)
+			}
+		}
+		t.templates[strings.TrimSuffix(file, ".tmpl")] = parsed
+	}
+	for _, name := range []string{TemplateOfferRequestedCustomer, TemplateOfferRequestedPartner} {
+		if _, ok := t.templates[path.Join(defaultLanguage(), name)]; !ok {
+			return nil, fmt.Errorf("template %s missing in language %s", name, defaultLanguage())
+		}
+	}
+	return t, nil
+}
+
+// Templates renders messages in the language of the recipient.
+type Templates struct {
+	templates map[string]*template.Template
+}
+
+// Render renders the template with the given name for the channel. Templates missing in the language are rendered
+// in the default language.
+func (t *Templates) Render(language, name string, channel Channel, data any) (Message, error) {
+	tmpl, ok := t.templates[path.Join(language, name)]
+	if !ok {
+		tmpl, ok = t.templates[path.Join(defaultLanguage(), name)]
+	}
+	if !ok {
+		return Message{}, fmt.Errorf("unknown template %s", name)
+	}
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, err
+	}
+	if err := tmpl.ExecuteTemplate(&body, string(channel), data); err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Channel: channel,
+		// Line breaks in the subject would start new headers of the email.
+		Subject:  strings.Join(strings.Fields(subject.String()), " "),
+		Body:     strings.TrimSpace(body.String()),
+		Template: name,
+	}, nil
+}
+
+func defaultLanguage() string {
+	return entities.Languages[0]
+}
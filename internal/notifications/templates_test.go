@@ -0,0 +1,104 @@
+package notifications
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplates_Render(t *testing.T) {
+	templates, err := LoadTemplates(DefaultTemplates)
+	require.NoError(t, err)
+	data := OfferRequestData{
+		OfferRequestID: "r1",
+		PartnerName:    "Parkett Paradies",
+		FloorSize:      42.5,
+		CustomerPhone:  "+49 170 1234567",
+		CustomerEmail:  "jane@example.com",
+	}
+	type testCase struct {
+		name       string
+		language   string
+		template   string
+		channel    Channel
+		expSubject string
+		expBody    []string
+	}
+	tests := []testCase{
+		{
+			name:       "Renders customer email in english",
+			language:   "en",
+			template:   TemplateOfferRequestedCustomer,
+			channel:    ChannelEmail,
+			expSubject: "Your offer request to Parkett Paradies",
+			expBody:    []string{"received your offer request for 42.5 m²", "Your request number: r1"},
+		},
+		{
+			name:       "Renders partner sms in german",
+			language:   "de",
+			template:   TemplateOfferRequestedPartner,
+			channel:    ChannelSMS,
+			expSubject: "Neue Angebotsanfrage für 42.5 m²",
+			expBody:    []string{"Telefon +49 170 1234567"},
+		},
+		{
+			name:       "Falls back to the default language",
+			language:   "fr",
+			template:   TemplateOfferRequestedCustomer,
+			channel:    ChannelSMS,
+			expSubject: "Ihre Angebotsanfrage bei Parkett Paradies",
+			expBody:    []string{"hat Ihre Angebotsanfrage für 42.5 m² erhalten"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := templates.Render(tt.language, tt.template, tt.channel, data)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.channel, message.Channel)
+			assert.Equal(t, tt.template, message.Template)
+			assert.Equal(t, tt.expSubject, message.Subject)
+			for _, expected := range tt.expBody {
+				assert.Contains(t, message.Body, expected)
+			}
+		})
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	block := `{{define "subject"}}{{.PartnerName}}
+Bcc: everyone@example.com{{end}}{{define "email"}}email{{end}}{{define "sms"}}sms{{end}}`
+
+	t.Run("Keeps subjects on a single line", func(t *testing.T) {
+		templates, err := LoadTemplates(fstest.MapFS{
+			"de/offer_requested_customer.tmpl": {Data: []byte(block)},
+			"de/offer_requested_partner.tmpl":  {Data: []byte(block)},
+		})
+		require.NoError(t, err)
+
+		message, err := templates.Render("de", TemplateOfferRequestedCustomer, ChannelEmail, OfferRequestData{
+			PartnerName: "Floors",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Floors Bcc: everyone@example.com", message.Subject)
+	})
+
+	t.Run("Rejects templates without all blocks", func(t *testing.T) {
+		_, err := LoadTemplates(fstest.MapFS{
+			"de/offer_requested_customer.tmpl": {Data: []byte(`{{define "subject"}}subject{{end}}`)},
+		})
+		assert.ErrorContains(t, err, "missing block")
+	})
+
+	t.Run("Rejects templates missing in the default language", func(t *testing.T) {
+		_, err := LoadTemplates(fstest.MapFS{
+			"en/offer_requested_customer.tmpl": {Data: []byte(block)},
+			"en/offer_requested_partner.tmpl":  {Data: []byte(block)},
+		})
+		assert.ErrorContains(t, err, "missing in language de")
+	})
+}
@@ -0,0 +1,358 @@
+package partnerio
+
+import (
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Columns are the columns of CSV files in the order they are written. Files may order them differently and leave out
+// optional columns, imports keep the values of existing partners for the columns left out. Lists of materials and
+// holidays are separated by listSeparator, price lists, opening hours and certifications are given as json.
+var Columns = []string{
+	"id",
+	"external_id",
+	"name",
+	"experienced_material",
+	"latitude",
+	"longitude",
+	"city",
+	"district",
+	"operating_radius",
+	"rating",
+	"daily_lead_cap",
+	"email",
+	"phone",
+	"language",
+	"time_zone",
+	"verification_status",
+	"insurance_expires_at",
+	"price_lists",
+	"opening_hours",
+	"holidays",
+	"certifications",
+	"suspended_at",
+	"suspension_reason",
+	"deleted_at",
+	"deletion_reason",
+}
+
+// requiredColumns must be present in every CSV file.
+var requiredColumns = []string{
+	"external_id",
+	"name",
+	"experienced_material",
+	"latitude",
+	"longitude",
+	"operating_radius",
+}
+
+// listSeparator separates the items of list cells.
+const listSeparator = ";"
+
+// byteOrderMark starts CSV files saved by some spreadsheet applications.
+const byteOrderMark = "\uFEFF"
+
+func readCSV(r io.Reader) ([]domain.ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading header: %v", ErrInvalidFile, err)
+	}
+	columns, err := parseHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	omitted := omittedColumns(columns)
+	var rows []domain.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		line, _ := reader.FieldPos(0)
+		if errors.Is(err, csv.ErrFieldCount) {
+			rows = append(rows, domain.ImportRow{
+				Line: line,
+				Err:  fmt.Errorf("expected %d cells, got %d", len(header), len(record)),
+			})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		cells := make(map[string]string, len(columns))
+		for i, column := range columns {
+			cells[column] = strings.TrimSpace(record[i])
+		}
+		partner, err := parseRecord(cells)
+		rows = append(rows, domain.ImportRow{Line: line, Partner: partner, Omitted: omitted, Err: err})
+	}
+}
+
+// parseHeader returns the names of the columns, in the order of the file.
+// Can return ErrInvalidFile when a column is unknown, repeated or a required column is missing.
+func parseHeader(header []string) ([]string, error) {
+	columns := make([]string, 0, len(header))
+	seen := map[string]bool{}
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, byteOrderMark)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !isColumn(name) {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidFile, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidFile, name)
+		}
+		seen[name] = true
+		columns = append(columns, name)
+	}
+	for _, name := range requiredColumns {
+		if !seen[name] {
+			return nil, fmt.Errorf("%w: column %q missing", ErrInvalidFile, name)
+		}
+	}
+	return columns, nil
+}
+
+// parseRecord returns the partner of the cells of a row by column. Columns which are not imported are read as well,
+// imports ignore them.
+// Can return a *domain.ValidationError naming the column of a cell which is empty but required or cannot be parsed.
+func parseRecord(cells map[string]string) (entities.Partner, error) {
+	for _, column := range requiredColumns {
+		if cells[column] == "" {
+			return entities.Partner{ExternalID: cells["external_id"]}, &domain.ValidationError{
+				Field:  column,
+				Reason: "must not be empty",
+			}
+		}
+	}
+	partner := entities.Partner{
+		ID:                  cells["id"],
+		ExternalID:          cells["external_id"],
+		Name:                cells["name"],
+		ExperiencedMaterial: splitList(cells["experienced_material"]),
+		Address:             entities.Address{City: cells["city"], District: cells["district"]},
+		TimeZone:            cells["time_zone"],
+		VerificationStatus:  cells["verification_status"],
+		Holidays:            splitList(cells["holidays"]),
+		SuspensionReason:    cells["suspension_reason"],
+		DeletionReason:      cells["deletion_reason"],
+	}
+	var err error
+	if partner.Address.Latitude, err = parseFloat(cells, "latitude"); err != nil {
+		return partner, err
+	}
+	if partner.Address.Longitude, err = parseFloat(cells, "longitude"); err != nil {
+		return partner, err
+	}
+	if partner.OperatingRadius, err = parseInt(cells, "operating_radius"); err != nil {
+		return partner, err
+	}
+	if partner.Rating, err = parseInt(cells, "rating"); err != nil {
+		return partner, err
+	}
+	if partner.DailyLeadCap, err = parseInt(cells, "daily_lead_cap"); err != nil {
+		return partner, err
+	}
+	if cells["email"] != "" || cells["phone"] != "" || cells["language"] != "" {
+		partner.Contact = &entities.Contact{Email: cells["email"], Phone: cells["phone"], Language: cells["language"]}
+	}
+	if partner.InsuranceExpiresAt, err = parseTime(cells, "insurance_expires_at"); err != nil {
+		return partner, err
+	}
+	if partner.SuspendedAt, err = parseTime(cells, "suspended_at"); err != nil {
+		return partner, err
+	}
+	if partner.DeletedAt, err = parseTime(cells, "deleted_at"); err != nil {
+		return partner, err
+	}
+	if err := parseJSON(cells, "price_lists", &partner.PriceLists); err != nil {
+		return partner, err
+	}
+	if err := parseJSON(cells, "opening_hours", &partner.OpeningHours); err != nil {
+		return partner, err
+	}
+	if err := parseJSON(cells, "certifications", &partner.Certifications); err != nil {
+		return partner, err
+	}
+	return partner, nil
+}
+
+func writeCSV(w io.Writer, partners []entities.Partner) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(Columns); err != nil {
+		return err
+	}
+	for _, partner := range partners {
+		record, err := formatRecord(partner)
+		if err != nil {
+			return err
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatRecord returns the cells of the partner in the order of Columns.
+func formatRecord(p entities.Partner) ([]string, error) {
+	var contact entities.Contact
+	if p.Contact != nil {
+		contact = *p.Contact
+	}
+	priceLists, err := formatJSON(p.PriceLists)
+	if err != nil {
+		return nil, err
+	}
+	openingHours, err := formatJSON(p.OpeningHours)
+	if err != nil {
+		return nil, err
+	}
+	certifications, err := formatJSON(p.Certifications)
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		p.ID,
+		p.ExternalID,
+		p.Name,
+		strings.Join(p.ExperiencedMaterial, listSeparator),
+		strconv.FormatFloat(p.Address.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(p.Address.Longitude, 'f', -1, 64),
+		p.Address.City,
+		p.Address.District,
+		strconv.Itoa(p.OperatingRadius),
+		strconv.Itoa(p.Rating),
+		strconv.Itoa(p.DailyLeadCap),
+		contact.Email,
+		contact.Phone,
+		contact.Language,
+		p.TimeZone,
+		p.VerificationStatus,
+		formatTime(p.InsuranceExpiresAt),
+		priceLists,
+		openingHours,
+		strings.Join(p.Holidays, listSeparator),
+		certifications,
+		formatTime(p.SuspendedAt),
+		p.SuspensionReason,
+		formatTime(p.DeletedAt),
+		p.DeletionReason,
+	}, nil
+}
+
+// splitList returns the trimmed items of a list cell, nil for an empty cell.
+func splitList(cell string) []string {
+	if cell == "" {
+		return nil
+	}
+	items := strings.Split(cell, listSeparator)
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
+
+// parseFloat parses the number in the cell of the column, 0 for an empty cell.
+func parseFloat(cells map[string]string, column string) (float64, error) {
+	if cells[column] == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(cells[column], 64)
+	if err != nil {
+		return 0, &domain.ValidationError{Field: column, Reason: "not a number"}
+	}
+	return f, nil
+}
+
+// parseInt parses the integer in the cell of the column, 0 for an empty cell.
+func parseInt(cells map[string]string, column string) (int, error) {
+	if cells[column] == "" {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(cells[column])
+	if err != nil {
+		return 0, &domain.ValidationError{Field: column, Reason: "not an integer"}
+	}
+	return i, nil
+}
+
+// parseTime parses the RFC 3339 time in the cell of the column, nil for an empty cell.
+func parseTime(cells map[string]string, column string) (*time.Time, error) {
+	if cells[column] == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, cells[column])
+	if err != nil {
+		return nil, &domain.ValidationError{Field: column, Reason: "not an RFC 3339 time"}
+	}
+	t = t.UTC()
+	return &t, nil
+}
+
+// parseJSON decodes the json in the cell of the column into v, leaving v unchanged for an empty cell.
+func parseJSON(cells map[string]string, column string, v any) error {
+	if cells[column] == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(cells[column]), v); err != nil {
+		return &domain.ValidationError{Field: column, Reason: "invalid json"}
+	}
+	return nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// formatJSON encodes a list as json, an empty string for an empty list.
+func formatJSON[T any](list []T) (string, error) {
+	if len(list) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(list)
+	return string(b), err
+}
+
+// omittedColumns returns the Columns which are not given, nil when all are.
+func omittedColumns(given []string) []string {
+	var omitted []string
+	for _, column := range Columns {
+		found := false
+		for _, name := range given {
+			found = found || name == column
+		}
+		if !found {
+			omitted = append(omitted, column)
+		}
+	}
+	return omitted
+}
+
+func isColumn(name string) bool {
+	for _, column := range Columns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
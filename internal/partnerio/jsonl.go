@@ -0,0 +1,70 @@
+package partnerio
+
+import (
+	"bufio"
+	"bytes"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// maxLineSize limits the size of a line of JSON Lines files.
+const maxLineSize = 1 << 20
+
+// keyColumns are the columns of CSV files given by the objects of JSON Lines files. Objects are given as a whole.
+var keyColumns = map[string][]string{
+	"address": {"latitude", "longitude", "city", "district"},
+	"contact": {"email", "phone", "language"},
+}
+
+func readJSONL(r io.Reader) ([]domain.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	var rows []domain.ImportRow
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.DisallowUnknownFields()
+		var partner entities.Partner
+		err := decoder.Decode(&partner)
+		if err != nil {
+			err = fmt.Errorf("invalid json: %v", err)
+		}
+		rows = append(rows, domain.ImportRow{Line: line, Partner: partner, Omitted: omittedKeys(scanner.Bytes()), Err: err})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	return rows, nil
+}
+
+// omittedKeys returns the Columns which the keys of the json object on the line do not give, nil when all are.
+func omittedKeys(line []byte) []string {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(line, &object); err != nil {
+		return nil
+	}
+	var given []string
+	for key := range object {
+		if columns, ok := keyColumns[key]; ok {
+			given = append(given, columns...)
+		} else {
+			given = append(given, key)
+		}
+	}
+	return omittedColumns(given)
+}
+
+func writeJSONL(w io.Writer, partners []entities.Partner) error {
+	encoder := json.NewEncoder(w)
+	for _, partner := range partners {
+		if err := encoder.Encode(partner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Package partnerio reads and writes partners in the file formats of bulk imports and exports: CSV, as used in the
+// spreadsheets of sales, and JSON Lines with one entities.Partner per line.
+package partnerio
+
+import (
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Formats of import and export files.
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// Formats lists the supported file formats.
+var Formats = []string{FormatCSV, FormatJSONL}
+
+// ErrInvalidFile is returned when a file cannot be read as a whole, e.g. because of an unknown column. Errors of single
+// rows are reported on the rows instead.
+var ErrInvalidFile = errors.New("invalid file")
+
+// Read reads the partners of a file in the format. Every partner is returned as a row with its line in the file, rows
+// which cannot be read carry the error instead. Empty files have no rows.
+// Can return ErrInvalidFile when the file cannot be read as a whole.
+func Read(r io.Reader, format string) ([]domain.ImportRow, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r)
+	case FormatJSONL:
+		return readJSONL(r)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// Write writes the partners to a file in the format. Files written can be read again with all attributes which are
+// stored, imports ignore the attributes which are not imported, like the id.
+func Write(w io.Writer, format string, partners []entities.Partner) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, partners)
+	case FormatJSONL:
+		return writeJSONL(w, partners)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// ContentType returns the media type of files in the format.
+func ContentType(format string) string {
+	if format == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// FormatOf returns the format of a file by the extension of its path, or an empty string for unknown extensions.
+func FormatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,206 @@
+package partnerio_test
+
+import (
+	"bytes"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/partnerio"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	insuranceExpiresAt := time.Date(2099, 12, 31, 0, 0, 0, 0, time.UTC)
+	suspendedAt := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	partner := entities.Partner{
+		ID:                  "1",
+		ExternalID:          "SF-1001",
+		Name:                "Floors, Tiles & More",
+		ExperiencedMaterial: []string{"wood", "tiles"},
+		Address:             entities.Address{Latitude: 48.1351, Longitude: 11.582, City: "München", District: "Maxvorstadt"},
+		OperatingRadius:     25,
+		Rating:              4,
+		DailyLeadCap:        10,
+		Contact:             &entities.Contact{Email: "info@floors.example", Phone: "+49 89 123456", Language: "de"},
+		PriceLists:          []entities.PriceList{{Material: "wood", MinPerSquareMeter: 3000, MaxPerSquareMeter: 4500}},
+		TimeZone:            "Europe/Berlin",
+		OpeningHours:        []entities.OpeningHours{{Weekday: "monday", Opens: "08:00", Closes: "17:00"}},
+		Holidays:            []string{"2099-12-24", "2099-12-25"},
+		VerificationStatus:  entities.VerificationVerified,
+		Certifications: []entities.Certification{
+			{Type: "Parkettlegermeister", Issuer: "Handwerkskammer München", ExpiresAt: insuranceExpiresAt},
+		},
+		InsuranceExpiresAt: &insuranceExpiresAt,
+		SuspendedAt:        &suspendedAt,
+		SuspensionReason:   "Complaints",
+	}
+	type testCase struct {
+		format   string
+		expLines []int
+	}
+	tests := []testCase{
+		{format: partnerio.FormatCSV, expLines: []int{2, 3}},
+		{format: partnerio.FormatJSONL, expLines: []int{1, 2}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.format, func(t *testing.T) {
+			var file bytes.Buffer
+			require.NoError(t, partnerio.Write(&file, tt.format, []entities.Partner{partner, {ExternalID: "SF-1002"}}))
+
+			rows, err := partnerio.Read(&file, tt.format)
+
+			require.NoError(t, err)
+			require.Len(t, rows, 2)
+			assert.Equal(t, tt.expLines[0], rows[0].Line)
+			assert.Equal(t, partner, rows[0].Partner)
+			assert.NoError(t, rows[0].Err)
+			assert.Equal(t, tt.expLines[1], rows[1].Line)
+			assert.Equal(t, "SF-1002", rows[1].Partner.ExternalID)
+		})
+	}
+}
+
+func TestRead_CSV(t *testing.T) {
+	type testCase struct {
+		name       string
+		file       string
+		expRows    int
+		expLine    int
+		expField   string
+		expErr     string
+		expFileErr bool
+	}
+	header := "external_id,name,experienced_material,latitude,longitude,operating_radius\n"
+	tests := []testCase{
+		{name: "Reads empty file", file: ""},
+		{name: "Reads header only", file: header},
+		{
+			name:    "Reads columns in any order",
+			file:    "name,external_id,latitude,longitude,operating_radius,experienced_material\nFloors,SF-1,48.1,11.6,10,wood\n",
+			expRows: 1,
+		},
+		{name: "Ignores byte order mark", file: "\uFEFF" + header + "SF-1,Floors,wood,48.1,11.6,10\n", expRows: 1},
+		{name: "Rejects unknown column", file: "external_id,name,shoe_size\n", expFileErr: true},
+		{name: "Rejects duplicate column", file: "external_id,name,name\n", expFileErr: true},
+		{name: "Rejects missing required column", file: "external_id,name\n", expFileErr: true},
+		{name: "Reports cell which is not a number", file: header + "SF-1,Floors,wood,north,11.6,10\n", expRows: 1, expLine: 2, expField: "latitude"},
+		{name: "Reports empty required cell", file: header + "SF-1,Floors,wood,48.1,11.6,\n", expRows: 1, expLine: 2, expField: "operating_radius"},
+		{name: "Reports wrong number of cells", file: header + "SF-1,Floors,wood\n", expRows: 1, expLine: 2, expErr: "expected 6 cells, got 3"},
+		{
+			name:     "Reports line of row after quoted line break",
+			file:     header + "SF-1,\"Floors\nand More\",wood,48.1,11.6,10\nSF-2,Floors,wood,48.1,11.6,x\n",
+			expRows:  2,
+			expLine:  4,
+			expField: "operating_radius",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := partnerio.Read(strings.NewReader(tt.file), partnerio.FormatCSV)
+
+			if tt.expFileErr {
+				assert.ErrorIs(t, err, partnerio.ErrInvalidFile)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, rows, tt.expRows)
+			if tt.expLine == 0 {
+				for _, row := range rows {
+					assert.NoError(t, row.Err)
+				}
+				return
+			}
+			row := rows[len(rows)-1]
+			assert.Equal(t, tt.expLine, row.Line)
+			if tt.expField != "" {
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, row.Err, &validationErr)
+				assert.Equal(t, tt.expField, validationErr.Field)
+			}
+			if tt.expErr != "" {
+				assert.EqualError(t, row.Err, tt.expErr)
+			}
+		})
+	}
+}
+
+func TestRead_JSONL(t *testing.T) {
+	file := `{"external_id":"SF-1","name":"Floors"}
+
+{"external_id":"SF-2","shoe_size":42}
+{"external_id":
+`
+
+	rows, err := partnerio.Read(strings.NewReader(file), partnerio.FormatJSONL)
+
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, 1, rows[0].Line)
+	assert.Equal(t, entities.Partner{ExternalID: "SF-1", Name: "Floors"}, rows[0].Partner)
+	assert.NoError(t, rows[0].Err)
+	assert.Equal(t, 3, rows[1].Line)
+	assert.ErrorContains(t, rows[1].Err, "shoe_size")
+	assert.Equal(t, 4, rows[2].Line)
+	assert.Error(t, rows[2].Err)
+}
+
+func TestRead_Omitted(t *testing.T) {
+	header := "external_id,name,experienced_material,latitude,longitude,operating_radius,email\n"
+	type testCase struct {
+		name       string
+		format     string
+		file       string
+		expOmitted []string
+	}
+	tests := []testCase{
+		{
+			name:   "CSV without optional columns",
+			format: partnerio.FormatCSV,
+			file:   header + "SF-1,Floors,wood,48.1,11.6,10,\n",
+			expOmitted: []string{
+				"id", "city", "district", "rating", "daily_lead_cap", "phone", "language", "time_zone",
+				"verification_status", "insurance_expires_at", "price_lists", "opening_hours", "holidays",
+				"certifications", "suspended_at", "suspension_reason", "deleted_at", "deletion_reason",
+			},
+		},
+		{
+			name:       "CSV with all columns",
+			format:     partnerio.FormatCSV,
+			file:       strings.Join(partnerio.Columns, ",") + "\n" + strings.Repeat(",", len(partnerio.Columns)-1) + "\n",
+			expOmitted: nil,
+		},
+		{
+			name:   "JSON Lines with objects",
+			format: partnerio.FormatJSONL,
+			file:   `{"id":"1","external_id":"SF-1","name":"Floors","address":{"latitude":48.1},"contact":null,"rating":4}`,
+			expOmitted: []string{
+				"experienced_material", "operating_radius", "daily_lead_cap", "time_zone", "verification_status",
+				"insurance_expires_at", "price_lists", "opening_hours", "holidays", "certifications", "suspended_at",
+				"suspension_reason", "deleted_at", "deletion_reason",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := partnerio.Read(strings.NewReader(tt.file), tt.format)
+
+			require.NoError(t, err)
+			require.Len(t, rows, 1)
+			assert.Equal(t, tt.expOmitted, rows[0].Omitted)
+		})
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	assert.Equal(t, partnerio.FormatCSV, partnerio.FormatOf("partners.CSV"))
+	assert.Equal(t, partnerio.FormatJSONL, partnerio.FormatOf("partners.jsonl"))
+	assert.Equal(t, partnerio.FormatJSONL, partnerio.FormatOf("partners.ndjson"))
+	assert.Equal(t, "", partnerio.FormatOf("partners.xlsx"))
+}
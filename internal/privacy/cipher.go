@@ -0,0 +1,118 @@
+package privacy
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeySize is the size of the keys used by Cipher, which encrypts with AES-256.
+const KeySize = 32
+
+// cipherVersion prefixes every ciphertext, so that the format can change without breaking stored data.
+const cipherVersion = 1
+
+// ErrDecrypt is returned when a ciphertext was not created with the key of the Cipher or was modified.
+var ErrDecrypt = errors.New("decrypting personal data failed")
+
+// ParseKey decodes a base64 encoded key of KeySize bytes.
+func ParseKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("decoding key: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// LoadKey reads a base64 encoded key from the file at path.
+func LoadKey(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKey(string(raw))
+}
+
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("blind index"))
+	return &Cipher{aead: aead, indexKey: mac.Sum(nil)}, nil
+}
+
+// NewRandomCipher creates a Cipher with a random key. Data encrypted by it cannot be decrypted after a restart.
+func NewRandomCipher() (*Cipher, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return NewCipher(key)
+}
+
+// Cipher encrypts personal data for storage with AES-256-GCM. Every value is bound to a context, e.g. the id of its
+// record and the name of its attribute, so that encrypted values cannot be moved between records unnoticed.
+type Cipher struct {
+	aead     cipher.AEAD
+	indexKey []byte
+}
+
+// Encrypt encrypts plaintext bound to associated data, e.g. "<record id>/phone". An empty plaintext is returned as nil,
+// so that removed data leaves nothing behind.
+func (c *Cipher) Encrypt(plaintext, associated string) ([]byte, error) {
+	if plaintext == "" {
+		return nil, nil
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte{cipherVersion}, nonce...)
+	return c.aead.Seal(out, nonce, []byte(plaintext), []byte(associated)), nil
+}
+
+// Decrypt decrypts a ciphertext created by Encrypt with the same associated data. Nil is returned as empty string.
+// Returns ErrDecrypt when the ciphertext was created with another key or associated data or was modified.
+func (c *Cipher) Decrypt(ciphertext []byte, associated string) (string, error) {
+	if len(ciphertext) == 0 {
+		return "", nil
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(ciphertext) < 1+nonceSize || ciphertext[0] != cipherVersion {
+		return "", ErrDecrypt
+	}
+	nonce, sealed := ciphertext[1:1+nonceSize], ciphertext[1+nonceSize:]
+	plaintext, err := c.aead.Open(nil, nonce, sealed, []byte(associated))
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(plaintext), nil
+}
+
+// BlindIndex returns a keyed hash of value, which allows to find records by encrypted data without decrypting all of
+// them. Values should be normalized, e.g. with NormalizePhone, so that different spellings share the same index.
+func (c *Cipher) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,87 @@
+package privacy_test
+
+import (
+	"bytes"
+	"customer-partner/internal/privacy"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipher(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, privacy.KeySize)
+	c, err := privacy.NewCipher(key)
+	require.NoError(t, err)
+
+	t.Run("Decrypts what it encrypted", func(t *testing.T) {
+		ciphertext, err := c.Encrypt("+49 170 1234567", "1/phone")
+		require.NoError(t, err)
+		assert.NotContains(t, string(ciphertext), "1234567")
+
+		plaintext, err := c.Decrypt(ciphertext, "1/phone")
+		require.NoError(t, err)
+		assert.Equal(t, "+49 170 1234567", plaintext)
+	})
+
+	t.Run("Encrypts the same value differently", func(t *testing.T) {
+		a, _ := c.Encrypt("+49 170 1234567", "1/phone")
+		b, _ := c.Encrypt("+49 170 1234567", "1/phone")
+		assert.NotEqual(t, a, b)
+	})
+
+	t.Run("Leaves empty values empty", func(t *testing.T) {
+		ciphertext, err := c.Encrypt("", "1/email")
+		require.NoError(t, err)
+		assert.Nil(t, ciphertext)
+		plaintext, err := c.Decrypt(nil, "1/email")
+		require.NoError(t, err)
+		assert.Empty(t, plaintext)
+	})
+
+	t.Run("Rejects other associated data, keys and modified ciphertexts", func(t *testing.T) {
+		ciphertext, _ := c.Encrypt("+49 170 1234567", "1/phone")
+		_, err := c.Decrypt(ciphertext, "2/phone")
+		assert.ErrorIs(t, err, privacy.ErrDecrypt)
+
+		other, _ := privacy.NewCipher(bytes.Repeat([]byte{2}, privacy.KeySize))
+		_, err = other.Decrypt(ciphertext, "1/phone")
+		assert.ErrorIs(t, err, privacy.ErrDecrypt)
+
+		ciphertext[len(ciphertext)-1] ^= 1
+		_, err = c.Decrypt(ciphertext, "1/phone")
+		assert.ErrorIs(t, err, privacy.ErrDecrypt)
+	})
+}
+
+func TestCipher_BlindIndex(t *testing.T) {
+	c, _ := privacy.NewCipher(bytes.Repeat([]byte{1}, privacy.KeySize))
+	other, _ := privacy.NewCipher(bytes.Repeat([]byte{2}, privacy.KeySize))
+
+	assert.Equal(t, c.BlindIndex("phone:+491701234567"), c.BlindIndex("phone:+491701234567"))
+	assert.NotEqual(t, c.BlindIndex("phone:+491701234567"), c.BlindIndex("phone:+491701234568"))
+	assert.NotEqual(t, c.BlindIndex("phone:+491701234567"), other.BlindIndex("phone:+491701234567"))
+}
+
+func TestParseKey(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, privacy.KeySize)
+
+	parsed, err := privacy.ParseKey(base64.StdEncoding.EncodeToString(key) + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, key, parsed)
+
+	_, err = privacy.ParseKey(base64.StdEncoding.EncodeToString(key[:16]))
+	assert.Error(t, err)
+	_, err = privacy.ParseKey("not base64")
+	assert.Error(t, err)
+}
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "+** *** *****67", privacy.MaskPhone("+49 170 1234567"))
+	assert.Equal(t, "****-**89", privacy.MaskPhone("0301-2389"))
+	assert.Equal(t, "j***@example.com", privacy.MaskEmail("jane.doe@example.com"))
+	assert.Equal(t, "***", privacy.MaskEmail("invalid"))
+	assert.Equal(t, "+491701234567", privacy.NormalizePhone(" +49 (170) 123-45/67"))
+	assert.Equal(t, "jane@example.com", privacy.NormalizeEmail(" Jane@Example.COM "))
+}
@@ -0,0 +1,56 @@
+// Package privacy protects personal data of partners from being disclosed to the public.
+package privacy
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"customer-partner/internal/entities"
+	"encoding/binary"
+	"math"
+)
+
+// DefaultDecimals is the number of decimals obfuscated coordinates are rounded to. Two decimals are about 1.1 km in
+// latitude and 0.75 km in longitude in Germany.
+const DefaultDecimals = 2
+
+// NewObfuscator creates an Obfuscator rounding coordinates to decimals. The key keeps the offsets secret; it must be
+// the same on all instances and across restarts, or clients may average the locations they get.
+func NewObfuscator(key []byte, decimals int) *Obfuscator {
+	return &Obfuscator{key: key, scale: math.Pow10(decimals)}
+}
+
+// NewRandomObfuscator creates an Obfuscator with a random key, which is only stable during the lifetime of the
+// process.
+func NewRandomObfuscator(decimals int) (*Obfuscator, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return NewObfuscator(key, decimals), nil
+}
+
+// Obfuscator blurs the home coordinates of partners. A coordinate is moved by an offset of up to half a grid cell,
+// derived from the partner id with HMAC-SHA256, and then rounded to the grid. The result is within one grid cell of
+// the exact location, the same for every request and, without the key, reveals nothing about the exact location
+// within its cell.
+type Obfuscator struct {
+	key   []byte
+	scale float64
+}
+
+// Obfuscate returns the address of the partner with blurred coordinates. City and district are kept.
+func (o *Obfuscator) Obfuscate(partnerID string, address entities.Address) entities.Address {
+	mac := hmac.New(sha256.New, o.key)
+	mac.Write([]byte(partnerID))
+	sum := mac.Sum(nil)
+	address.Latitude = math.Max(-90, math.Min(90, o.blur(address.Latitude, sum[0:8])))
+	address.Longitude = math.Max(-180, math.Min(180, o.blur(address.Longitude, sum[8:16])))
+	return address
+}
+
+// blur moves v by an offset in [-0.5, 0.5) grid cells taken from seed and rounds it to the grid.
+func (o *Obfuscator) blur(v float64, seed []byte) float64 {
+	offset := float64(binary.BigEndian.Uint64(seed))/math.MaxUint64 - 0.5
+	return math.Round(v*o.scale+offset) / o.scale
+}
@@ -0,0 +1,49 @@
+package privacy_test
+
+import (
+	"customer-partner/internal/entities"
+	"customer-partner/internal/privacy"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObfuscator_Obfuscate(t *testing.T) {
+	o := privacy.NewObfuscator([]byte("secret"), privacy.DefaultDecimals)
+	exact := entities.Address{Latitude: 48.13743, Longitude: 11.57549, City: "München", District: "Altstadt"}
+
+	t.Run("Is deterministic per partner", func(t *testing.T) {
+		assert.Equal(t, o.Obfuscate("1", exact), o.Obfuscate("1", exact))
+	})
+
+	t.Run("Keeps city and district and stays within a grid cell", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			blurred := o.Obfuscate(fmt.Sprint(i), exact)
+			assert.Equal(t, exact.City, blurred.City)
+			assert.Equal(t, exact.District, blurred.District)
+			assert.LessOrEqual(t, math.Abs(blurred.Latitude-exact.Latitude), 0.01)
+			assert.LessOrEqual(t, math.Abs(blurred.Longitude-exact.Longitude), 0.01)
+			assert.Equal(t, math.Round(blurred.Latitude*100)/100, blurred.Latitude)
+			assert.Equal(t, math.Round(blurred.Longitude*100)/100, blurred.Longitude)
+		}
+	})
+
+	t.Run("Depends on the key", func(t *testing.T) {
+		other := privacy.NewObfuscator([]byte("other"), privacy.DefaultDecimals)
+		differs := false
+		for i := 0; i < 20 && !differs; i++ {
+			differs = o.Obfuscate(fmt.Sprint(i), exact) != other.Obfuscate(fmt.Sprint(i), exact)
+		}
+		assert.True(t, differs)
+	})
+
+	t.Run("Stays within valid ranges", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			blurred := o.Obfuscate(fmt.Sprint(i), entities.Address{Latitude: 90, Longitude: -180})
+			assert.LessOrEqual(t, blurred.Latitude, 90.0)
+			assert.GreaterOrEqual(t, blurred.Longitude, -180.0)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package privacy
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// visibleDigits is the number of trailing digits MaskPhone leaves readable.
+const visibleDigits = 2
+
+// MaskPhone replaces all but the last two digits of a phone number with '*'. The format of the number is kept, e.g.
+// "+49 170 1234567" becomes "+** *** *****67".
+func MaskPhone(phone string) string {
+	digits := 0
+	for _, c := range phone {
+		if c >= '0' && c <= '9' {
+			digits++
+		}
+	}
+	var b strings.Builder
+	for _, c := range phone {
+		if c >= '0' && c <= '9' {
+			digits--
+			if digits >= visibleDigits {
+				c = '*'
+			}
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// MaskEmail keeps the first character of the local part and the domain of an email address, e.g.
+// "jane.doe@example.com" becomes "j***@example.com".
+func MaskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return "***"
+	}
+	_, size := utf8.DecodeRuneInString(local)
+	return local[:size] + "***@" + domain
+}
+
+// NormalizePhone removes all characters but digits and a leading '+' from a phone number.
+func NormalizePhone(phone string) string {
+	var b strings.Builder
+	for i, c := range strings.TrimSpace(phone) {
+		if c >= '0' && c <= '9' || c == '+' && i == 0 {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// NormalizeEmail trims and lower cases an email address.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
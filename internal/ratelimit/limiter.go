@@ -0,0 +1,160 @@
+// Package ratelimit limits the number of requests clients may send per route with token buckets.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit allows Requests per Period with bursts of up to Burst requests.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s:%d", l.Requests, l.Period, l.Burst)
+}
+
+// ParseLimit parses a limit in the form "requests/period[:burst]", e.g. "60/1m:20". The burst defaults to requests.
+func ParseLimit(s string) (Limit, error) {
+	rate, burst, hasBurst := strings.Cut(s, ":")
+	requests, period, ok := strings.Cut(rate, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("limit %q: expected requests/period", s)
+	}
+	var l Limit
+	var err error
+	if l.Requests, err = strconv.Atoi(requests); err != nil || l.Requests < 1 {
+		return Limit{}, fmt.Errorf("limit %q: invalid number of requests", s)
+	}
+	if l.Period, err = time.ParseDuration(period); err != nil || l.Period <= 0 {
+		return Limit{}, fmt.Errorf("limit %q: invalid period", s)
+	}
+	l.Burst = l.Requests
+	if hasBurst {
+		if l.Burst, err = strconv.Atoi(burst); err != nil || l.Burst < 1 {
+			return Limit{}, fmt.Errorf("limit %q: invalid burst", s)
+		}
+	}
+	return l, nil
+}
+
+// Result is the decision about a single request.
+type Result struct {
+	Allowed bool
+	// Limit is the maximum number of requests the client may send at once.
+	Limit int
+	// Remaining is the number of requests the client may still send at once.
+	Remaining int
+	// RetryAfter is the time until the next request is allowed. It is zero for allowed requests.
+	RetryAfter time.Duration
+	// Reset is the time until the full limit is available again.
+	Reset time.Duration
+}
+
+// Limiter decides whether a request of the client identified by key is allowed. It is implemented in-process by
+// MemoryLimiter; implementations backed by a shared store allow limits across several instances.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+	// Check returns the result Allow would return without taking a token.
+	Check(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// sweepInterval defines how often MemoryLimiter removes buckets of idle clients.
+const sweepInterval = time.Minute
+
+// idleTimeout is the time after which the bucket of a client without requests is removed even if it is not full yet,
+// e.g. because the limit refills over hours. Such clients start over with a full bucket.
+const idleTimeout = 10 * time.Minute
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{buckets: map[string]*bucket{}, now: time.Now}
+}
+
+// MemoryLimiter keeps a token bucket per key in memory. Buckets which are full again or idle for idleTimeout are
+// removed periodically, so that memory is only held for active clients.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+// Allow takes a token from the bucket of key. The request is allowed when a token was available.
+func (m *MemoryLimiter) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	return m.take(key, limit, true), nil
+}
+
+// Check returns whether a token is available in the bucket of key without taking it.
+func (m *MemoryLimiter) Check(_ context.Context, key string, limit Limit) (Result, error) {
+	return m.take(key, limit, false), nil
+}
+
+func (m *MemoryLimiter) take(key string, limit Limit, consume bool) Result {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok || b.limit != limit {
+		if !consume {
+			return Result{Allowed: true, Limit: limit.Burst, Remaining: limit.Burst}
+		}
+		b = &bucket{tokens: float64(limit.Burst), updated: now, limit: limit}
+		m.buckets[key] = b
+	}
+	b.refill(now)
+
+	res := Result{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		if consume {
+			b.tokens--
+		}
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration((1 - b.tokens) / limit.rate() * float64(time.Second))
+	}
+	res.Remaining = int(math.Floor(b.tokens))
+	res.Reset = time.Duration((float64(limit.Burst) - b.tokens) / limit.rate() * float64(time.Second))
+	return res
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.rate())
+		b.updated = now
+	}
+}
+
+func (m *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, b := range m.buckets {
+		idle := now.Sub(b.updated) >= idleTimeout
+		b.refill(now)
+		if idle || b.tokens >= float64(b.limit.Burst) {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package ratelimit
+
+import (
+	"context"
+	"customer-partner/internal/auth"
+	"customer-partner/internal/auth/authtest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLimiter_Allow(t *testing.T) {
+	now := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return now }
+	limit := Limit{Requests: 60, Period: time.Minute, Burst: 2}
+	ctx := context.Background()
+
+	res, err := limiter.Allow(ctx, "a", limit)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second}, res)
+	res, _ = limiter.Allow(ctx, "a", limit)
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: 2 * time.Second}, res)
+	res, _ = limiter.Allow(ctx, "a", limit)
+	assert.Equal(t, Result{Allowed: false, Limit: 2, Remaining: 0, RetryAfter: time.Second, Reset: 2 * time.Second}, res)
+
+	res, _ = limiter.Check(ctx, "b", limit)
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 2}, res, "checks do not take tokens")
+	res, _ = limiter.Allow(ctx, "b", limit)
+	assert.True(t, res.Allowed, "buckets are separated by key")
+	res, _ = limiter.Check(ctx, "a", limit)
+	assert.False(t, res.Allowed)
+
+	now = now.Add(500 * time.Millisecond)
+	res, _ = limiter.Allow(ctx, "a", limit)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+	now = now.Add(500 * time.Millisecond)
+	res, _ = limiter.Allow(ctx, "a", limit)
+	assert.True(t, res.Allowed, "bucket is refilled over time")
+
+	now = now.Add(2 * sweepInterval)
+	_, _ = limiter.Allow(ctx, "c", limit)
+	assert.Len(t, limiter.buckets, 1, "full buckets of idle clients are removed")
+
+	slow := Limit{Requests: 1, Period: time.Hour, Burst: 1}
+	_, _ = limiter.Allow(ctx, "d", slow)
+	now = now.Add(sweepInterval)
+	_, _ = limiter.Allow(ctx, "c", limit)
+	assert.Len(t, limiter.buckets, 2, "buckets of recent clients are kept")
+	now = now.Add(idleTimeout)
+	_, _ = limiter.Allow(ctx, "c", limit)
+	assert.Len(t, limiter.buckets, 1, "buckets of idle clients are removed before they are full")
+}
+
+func TestParsePolicy(t *testing.T) {
+	defaults := Policy{Default: Limit{Requests: 1, Period: time.Second, Burst: 1}}
+
+	policy, err := ParsePolicy("*=120/1m:30, /partners=30/1m", defaults)
+	require.NoError(t, err)
+	assert.Equal(t, Limit{Requests: 120, Period: time.Minute, Burst: 30}, policy.limit("/partners/"))
+	assert.Equal(t, Limit{Requests: 30, Period: time.Minute, Burst: 30}, policy.limit("/partners"))
+
+	for _, invalid := range []string{"/partners", "/partners=30", "/partners=0/1m", "/partners=30/x", "/partners=30/1m:-1"} {
+		_, err := ParsePolicy(invalid, defaults)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestEnforcer_Middleware(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	policy := Policy{
+		Default: Limit{Requests: 100, Period: time.Minute, Burst: 100},
+		Routes:  map[string]Limit{"/partners": {Requests: 1, Period: time.Minute, Burst: 1}},
+	}
+	route := func(r *http.Request) string { return r.URL.Path }
+	handler := NewEnforcer(NewMemoryLimiter(), policy).Middleware(route)(
+		keys.Authenticator(t).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})),
+	)
+	send := func(path string, header http.Header, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header = header
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send("/partners", http.Header{}, "10.0.0.1:1234")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", rec.Header().Get("RateLimit-Reset"))
+
+	rec = send("/partners", http.Header{}, "10.0.0.1:5678")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "clients are identified by address without port")
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, send("/partners/1", http.Header{}, "10.0.0.1:1234").Code, "routes have own limits")
+	assert.Equal(t, http.StatusOK, send("/partners", http.Header{}, "10.0.0.2:1234").Code)
+	apiKey := http.Header{}
+	apiKey.Set(auth.APIKeyHeader, authtest.AdminAPIKey)
+	assert.Equal(t, http.StatusOK, send("/partners", apiKey, "10.0.0.1:1234").Code, "api keys have own buckets")
+	token := http.Header{}
+	token.Set("Authorization", authtest.Bearer(keys.CustomerToken(t, "c")))
+	assert.Equal(t, http.StatusOK, send("/partners", token, "10.0.0.1:1234").Code, "tokens have own buckets")
+	assert.Equal(t, http.StatusTooManyRequests, send("/partners", token, "10.0.0.3:1234").Code)
+
+}
+
+func TestEnforcer_Middleware_FailedAuthentication(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	policy := Policy{Default: Limit{Requests: 2, Period: time.Minute, Burst: 2}}
+	route := func(r *http.Request) string { return r.URL.Path }
+	handler := NewEnforcer(NewMemoryLimiter(), policy).Middleware(route)(
+		keys.Authenticator(t).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})),
+	)
+	send := func(path string, key string, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(auth.APIKeyHeader, key)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, send("/partners", "guessed", "10.0.0.1:1234").Code)
+	assert.Equal(t, http.StatusUnauthorized, send("/leads", "guessed again", "10.0.0.1:1234").Code)
+	rec := send("/offer_requests", "guessed once more", "10.0.0.1:1234")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "failed authentications count per address across routes")
+	assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusTooManyRequests, send("/partners", authtest.AdminAPIKey, "10.0.0.1:1234").Code,
+		"credentials are not checked once the address is out of failed attempts")
+	assert.Equal(t, http.StatusOK, send("/partners", authtest.AdminAPIKey, "10.0.0.2:1234").Code)
+}
+
+func TestEnforcer_ClientAddr(t *testing.T) {
+	type testCase struct {
+		name           string
+		trustedProxies int
+		forwardedFor   []string
+		expAddr        string
+	}
+	tests := []testCase{
+		{name: "Ignores header without trusted proxies", forwardedFor: []string{"203.0.113.7"}, expAddr: "10.0.0.1"},
+		{name: "Uses address of connection without header", trustedProxies: 1, expAddr: "10.0.0.1"},
+		{
+			name:           "Uses address appended by proxy",
+			trustedProxies: 1,
+			forwardedFor:   []string{"198.51.100.1, 203.0.113.7"},
+			expAddr:        "203.0.113.7",
+		},
+		{
+			name:           "Uses address appended by outermost proxy",
+			trustedProxies: 2,
+			forwardedFor:   []string{"198.51.100.1, 203.0.113.7", "10.0.0.9"},
+			expAddr:        "203.0.113.7",
+		},
+		{
+			name:           "Uses leftmost address of header with fewer addresses than proxies",
+			trustedProxies: 3,
+			forwardedFor:   []string{"203.0.113.7,10.0.0.9"},
+			expAddr:        "203.0.113.7",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			enforcer := NewEnforcer(NewMemoryLimiter(), Policy{TrustedProxies: tt.trustedProxies})
+			req := httptest.NewRequest(http.MethodGet, "/partners", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			for _, value := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+
+			assert.Equal(t, tt.expAddr, enforcer.clientAddr(req))
+		})
+	}
+}
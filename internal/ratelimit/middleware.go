@@ -0,0 +1,210 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"customer-partner/internal/auth"
+	"customer-partner/internal/logging"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy assigns limits to routes. Routes without an own limit share the default limit.
+type Policy struct {
+	Default Limit
+	Routes  map[string]Limit
+	// TrustedProxies is the number of proxies in front of the service which append the address they received the
+	// request from to the X-Forwarded-For header. Anonymous clients are identified by the address the outermost of
+	// them saw, i.e. the TrustedProxies-th address from the right, instead of the address of the connection. Addresses
+	// further left are set by clients and not trusted. Zero ignores the header.
+	TrustedProxies int
+}
+
+// ParsePolicy parses a comma separated list of "route=limit" pairs, e.g. "*=120/1m:30,/partners=30/1m:10". The route
+// "*" sets the default limit, the limits are parsed by ParseLimit.
+func ParsePolicy(s string, defaults Policy) (Policy, error) {
+	policy := Policy{Default: defaults.Default, Routes: map[string]Limit{}, TrustedProxies: defaults.TrustedProxies}
+	for route, limit := range defaults.Routes {
+		policy.Routes[route] = limit
+	}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return Policy{}, fmt.Errorf("rate limit %q: expected route=limit", entry)
+		}
+		limit, err := ParseLimit(value)
+		if err != nil {
+			return Policy{}, err
+		}
+		if route == "*" {
+			policy.Default = limit
+			continue
+		}
+		policy.Routes[route] = limit
+	}
+	return policy, nil
+}
+
+func (p Policy) limit(route string) Limit {
+	if limit, ok := p.Routes[route]; ok {
+		return limit
+	}
+	return p.Default
+}
+
+func NewEnforcer(limiter Limiter, policy Policy) *Enforcer {
+	return &Enforcer{limiter: limiter, policy: policy}
+}
+
+// Enforcer enforces a Policy in front of the authentication. Clients are identified by their API key or token, or by
+// their address when they send no credentials. Credentials are only verified after the limit was applied, so made up
+// credentials would get buckets of their own. Requests rejected with 401 are therefore counted per address as well,
+// by the default limit across all routes, and once an address is out of failed attempts its credentials are not
+// checked anymore.
+type Enforcer struct {
+	limiter Limiter
+	policy  Policy
+}
+
+// Middleware returns the middleware limiting the requests per route and client. It must run before the authentication.
+// route maps a request to the pattern of the route serving it. Every response carries the RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers; rejected requests are answered with 429 and a Retry-After header.
+func (m *Enforcer) Middleware(route func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pattern := route(r)
+			limit := m.policy.limit(pattern)
+			addr := m.clientAddr(r)
+			credentials, ok := credentialsKey(r)
+			if !ok {
+				if m.allow(w, r, pattern+"|ip:"+addr, limit) {
+					next.ServeHTTP(w, r)
+				}
+				return
+			}
+			failedKey := "unauthorized|ip:" + addr
+			res, err := m.limiter.Check(r.Context(), failedKey, m.policy.Default)
+			if err != nil {
+				logging.FromContext(r.Context()).Error("rate limiter failed", "error", err)
+			} else if !res.Allowed {
+				reject(w, res)
+				return
+			}
+			if !m.allow(w, r, pattern+"|"+credentials, limit) {
+				return
+			}
+			recorder := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+			if recorder.status == http.StatusUnauthorized {
+				if _, err := m.limiter.Allow(r.Context(), failedKey, m.policy.Default); err != nil {
+					logging.FromContext(r.Context()).Error("rate limiter failed", "error", err)
+				}
+			}
+		})
+	}
+}
+
+// allow takes a token of key and sets the rate limit headers. It answers rejected requests with 429 and returns
+// whether the request may be served.
+func (m *Enforcer) allow(w http.ResponseWriter, r *http.Request, key string, limit Limit) bool {
+	res, err := m.limiter.Allow(r.Context(), key, limit)
+	if err != nil {
+		// A failing limiter must not take the api down with it.
+		logging.FromContext(r.Context()).Error("rate limiter failed", "error", err)
+		return true
+	}
+	if !res.Allowed {
+		reject(w, res)
+		return false
+	}
+	setHeaders(w, res)
+	return true
+}
+
+func setHeaders(w http.ResponseWriter, res Result) {
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+}
+
+func reject(w http.ResponseWriter, res Result) {
+	setHeaders(w, res)
+	w.Header().Set("Retry-After", strconv.Itoa(seconds(res.RetryAfter)))
+	http.Error(w, "Too many requests", http.StatusTooManyRequests)
+}
+
+// credentialsKey identifies clients by their credentials. They are hashed, so that they are not kept in memory.
+func credentialsKey(r *http.Request) (string, bool) {
+	if key := r.Header.Get(auth.APIKeyHeader); key != "" {
+		return "key:" + auth.HashAPIKey(key), true
+	}
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		sum := sha256.Sum256([]byte(authorization))
+		return "token:" + hex.EncodeToString(sum[:]), true
+	}
+	return "", false
+}
+
+// clientAddr returns the address of the client, see Policy.TrustedProxies.
+func (m *Enforcer) clientAddr(r *http.Request) string {
+	if addr := forwardedFor(r, m.policy.TrustedProxies); addr != "" {
+		return addr
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return host
+}
+
+// statusRecorder records the status code of the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap allows http.ResponseController to reach the underlying http.ResponseWriter.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// forwardedFor returns the address the outermost of the trusted proxies received the request from. Headers with fewer
+// addresses than trusted proxies did not pass all of them, so their leftmost address is the best guess.
+func forwardedFor(r *http.Request, trustedProxies int) string {
+	if trustedProxies < 1 {
+		return ""
+	}
+	var addrs []string
+	for _, value := range r.Header.Values("X-Forwarded-For") {
+		for _, addr := range strings.Split(value, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	if len(addrs) == 0 {
+		return ""
+	}
+	return addrs[max(len(addrs)-trustedProxies, 0)]
+}
+
+// seconds rounds d up to full seconds.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
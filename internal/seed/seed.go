@@ -0,0 +1,297 @@
+// Package seed generates synthetic partners for tests, benchmarks and local environments. Unlike the demo data, the
+// partners are spread around cities with distributions of radius, materials, rating and prices close to the real
+// dataset, so that ranking and distance bugs show.
+package seed
+
+import (
+	"customer-partner/internal/entities"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kmPerDegree is the length of a degree of latitude, and of longitude at the equator.
+const kmPerDegree = 111.32
+
+// defaultSpread is the spread of cities parsed without one.
+const defaultSpread = 10
+
+// insuranceExpiry is the end of the insurance of generated partners who are verified, far enough ahead that they stay
+// vetted.
+var insuranceExpiry = time.Date(2099, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// City is a centre partners are distributed around.
+type City struct {
+	Name      string
+	Latitude  float64
+	Longitude float64
+	// Spread is the standard deviation of the distance of partners to the centre in kilometers.
+	Spread float64
+	// Weight is the share of partners of the city relative to the other cities, e.g. its population.
+	Weight float64
+	// Districts are assigned to the partners at random, none when empty.
+	Districts []string
+}
+
+// DefaultCities are the largest cities of the service area, weighted by population in millions.
+var DefaultCities = []City{
+	{
+		Name: "Berlin", Latitude: 52.5200, Longitude: 13.4050, Spread: 12, Weight: 3.7,
+		Districts: []string{"Mitte", "Pankow", "Neukölln", "Spandau", "Steglitz-Zehlendorf", "Lichtenberg"},
+	},
+	{
+		Name: "Hamburg", Latitude: 53.5511, Longitude: 9.9937, Spread: 10, Weight: 1.9,
+		Districts: []string{"Altona", "Eimsbüttel", "Wandsbek", "Harburg", "Bergedorf"},
+	},
+	{
+		Name: "München", Latitude: 48.1372, Longitude: 11.5756, Spread: 8, Weight: 1.5,
+		Districts: []string{"Altstadt-Lehel", "Schwabing", "Pasing", "Sendling", "Trudering-Riem", "Bogenhausen"},
+	},
+	{
+		Name: "Köln", Latitude: 50.9375, Longitude: 6.9603, Spread: 8, Weight: 1.1,
+		Districts: []string{"Innenstadt", "Ehrenfeld", "Nippes", "Porz", "Mülheim"},
+	},
+	{
+		Name: "Frankfurt am Main", Latitude: 50.1109, Longitude: 8.6821, Spread: 7, Weight: 0.75,
+		Districts: []string{"Innenstadt", "Sachsenhausen", "Bornheim", "Höchst"},
+	},
+}
+
+// Options configure the generated partners.
+type Options struct {
+	// Count is the number of partners.
+	Count int
+	// Seed makes the partners reproducible: the same options always generate the same partners.
+	Seed int64
+	// Cities are the centres the partners are distributed around, DefaultCities when empty.
+	Cities []City
+}
+
+// weighted is a value drawn with the probability of its weight relative to the other values.
+type weighted[T any] struct {
+	value  T
+	weight float64
+}
+
+func pick[T any](r *rand.Rand, values []weighted[T]) T {
+	total := 0.0
+	for _, v := range values {
+		total += v.weight
+	}
+	n := r.Float64() * total
+	for _, v := range values {
+		if n < v.weight {
+			return v.value
+		}
+		n -= v.weight
+	}
+	return values[len(values)-1].value
+}
+
+// Most partners are small businesses working in and around their city, few travel far.
+var radii = []weighted[int]{{10, 15}, {20, 25}, {30, 25}, {50, 20}, {75, 10}, {100, 5}}
+
+// Ratings are skewed towards good ones, as customers mostly rate good jobs.
+var ratings = []weighted[int]{{1, 5}, {2, 10}, {3, 25}, {4, 35}, {5, 25}}
+
+// materialShares are the shares of partners experienced in each material, every partner is experienced in at least one.
+var materialShares = map[string]float64{"wood": 0.6, "carpet": 0.45, "tiles": 0.55}
+
+// basePrices are the typical lower bounds of the price per square meter of the materials in cents.
+var basePrices = map[string]int{"wood": 3500, "carpet": 1500, "tiles": 4500}
+
+// trades name partners by the material they are experienced in, "" for partners experienced in several.
+var trades = map[string]string{"wood": "Parkett", "carpet": "Teppich", "tiles": "Fliesen", "": "Bodenbeläge"}
+
+var (
+	familyNames = []string{
+		"Müller", "Schmidt", "Schneider", "Fischer", "Weber", "Meyer", "Wagner", "Becker", "Schulz", "Hoffmann",
+		"Koch", "Richter", "Klein", "Wolf", "Schröder", "Neumann", "Braun", "Zimmermann", "Krüger", "Hartmann",
+	}
+	legalForms = []string{"GmbH", "& Söhne", "e.K.", "GmbH & Co. KG", ""}
+)
+
+// Partners generates partners around the cities of the options. The partners have ids counting from "1" and external
+// ids like "SEED-000001", so that they can be imported. Nine out of ten are verified and insured, the others pending.
+func Partners(opts Options) []entities.Partner {
+	cities := weightedCities(opts.Cities)
+	r := rand.New(rand.NewSource(opts.Seed))
+	partners := make([]entities.Partner, 0, opts.Count)
+	for i := 0; i < opts.Count; i++ {
+		partners = append(partners, partner(r, i+1, pick(r, cities)))
+	}
+	return partners
+}
+
+// Customer is what a customer looks for: a partner experienced in the material near the location.
+type Customer struct {
+	Material  string
+	Latitude  float64
+	Longitude float64
+}
+
+// Most customers ask for wood and tiles, carpets are rarely laid by professionals.
+var customerMaterials = []weighted[string]{{"wood", 45}, {"tiles", 35}, {"carpet", 20}}
+
+// Customers generates customers around the cities of the options, distributed like the partners.
+func Customers(opts Options) []Customer {
+	cities := weightedCities(opts.Cities)
+	r := rand.New(rand.NewSource(opts.Seed))
+	customers := make([]Customer, 0, opts.Count)
+	for i := 0; i < opts.Count; i++ {
+		material := pick(r, customerMaterials)
+		lat, long := Around(r, pick(r, cities))
+		customers = append(customers, Customer{Material: material, Latitude: lat, Longitude: long})
+	}
+	return customers
+}
+
+// weightedCities weights the cities by their weight, DefaultCities when there are none.
+func weightedCities(cities []City) []weighted[City] {
+	if len(cities) == 0 {
+		cities = DefaultCities
+	}
+	result := make([]weighted[City], 0, len(cities))
+	for _, city := range cities {
+		result = append(result, weighted[City]{city, city.Weight})
+	}
+	return result
+}
+
+func partner(r *rand.Rand, n int, city City) entities.Partner {
+	materials := make([]string, 0, len(entities.Materials))
+	for len(materials) == 0 {
+		for _, material := range entities.Materials {
+			if r.Float64() < materialShares[material] {
+				materials = append(materials, material)
+			}
+		}
+	}
+	trade := trades[""]
+	if len(materials) == 1 {
+		trade = trades[materials[0]]
+	}
+	name := strings.TrimSpace(fmt.Sprintf("%s %s %s",
+		familyNames[r.Intn(len(familyNames))], trade, legalForms[r.Intn(len(legalForms))]))
+	lat, long := Around(r, city)
+	p := entities.Partner{
+		ID:                  strconv.Itoa(n),
+		ExternalID:          fmt.Sprintf("SEED-%06d", n),
+		Name:                name,
+		ExperiencedMaterial: materials,
+		Address:             entities.Address{Latitude: lat, Longitude: long, City: city.Name},
+		OperatingRadius:     pick(r, radii),
+		Rating:              pick(r, ratings),
+		VerificationStatus:  entities.VerificationPending,
+	}
+	if len(city.Districts) > 0 {
+		p.Address.District = city.Districts[r.Intn(len(city.Districts))]
+	}
+	if r.Float64() < 0.9 {
+		p.VerificationStatus = entities.VerificationVerified
+		expiry := insuranceExpiry
+		p.InsuranceExpiresAt = &expiry
+	}
+	for _, material := range materials {
+		if r.Float64() < 0.7 {
+			p.PriceLists = append(p.PriceLists, priceList(r, material))
+		}
+	}
+	if r.Float64() < 0.6 {
+		p.TimeZone = entities.DefaultTimeZone
+		p.OpeningHours = openingHours(r)
+	}
+	return p
+}
+
+// Around returns a location around the centre of the city: the distance to the centre is normally distributed with
+// the spread of the city, the direction is uniform.
+func Around(r *rand.Rand, city City) (lat, long float64) {
+	distance := math.Abs(r.NormFloat64() * city.Spread)
+	bearing := r.Float64() * 2 * math.Pi
+	lat = city.Latitude + distance*math.Cos(bearing)/kmPerDegree
+	long = city.Longitude + distance*math.Sin(bearing)/(kmPerDegree*math.Cos(city.Latitude*math.Pi/180))
+	return math.Max(-90, math.Min(90, round(lat))), math.Max(-180, math.Min(180, round(long)))
+}
+
+// round rounds coordinates to four decimals, about ten meters, like the addresses entered by partners.
+func round(degrees float64) float64 {
+	return math.Round(degrees*1e4) / 1e4
+}
+
+// priceList returns prices around the base price of the material, rounded to 50 cents.
+func priceList(r *rand.Rand, material string) entities.PriceList {
+	minPrice := int(float64(basePrices[material])*(0.8+0.4*r.Float64())) / 50 * 50
+	list := entities.PriceList{
+		Material:          material,
+		MinPerSquareMeter: minPrice,
+		MaxPerSquareMeter: minPrice + minPrice/2/50*50,
+		MinimumCharge:     (2 + r.Intn(5)) * 10000,
+	}
+	if r.Float64() < 0.5 {
+		list.TravelSurchargePerKm = (5 + r.Intn(11)) * 10
+		list.FreeTravelDistance = 10 + r.Intn(3)*5
+	}
+	return list
+}
+
+// openingHours returns the hours of a workshop open on weekdays and, for some, on Saturday mornings.
+func openingHours(r *rand.Rand) []entities.OpeningHours {
+	opens := []string{"07:00", "08:00"}[r.Intn(2)]
+	closes := []string{"16:00", "17:00", "18:00"}[r.Intn(3)]
+	hours := make([]entities.OpeningHours, 0, 6)
+	for _, weekday := range entities.Weekdays[:5] {
+		hours = append(hours, entities.OpeningHours{Weekday: weekday, Opens: opens, Closes: closes})
+	}
+	if r.Float64() < 0.4 {
+		hours = append(hours, entities.OpeningHours{Weekday: "saturday", Opens: "08:00", Closes: "12:00"})
+	}
+	return hours
+}
+
+// ParseCities parses a comma separated list of cities in the form "name=lat:long[:spread[:weight]]", e.g.
+// "München=48.1372:11.5756:8,Augsburg=48.3705:10.8978". The spread defaults to 10 km, the weight to 1.
+func ParseCities(s string) ([]City, error) {
+	var cities []City
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("city %q: expected name=lat:long", entry)
+		}
+		parts := strings.Split(value, ":")
+		if len(parts) < 2 || len(parts) > 4 {
+			return nil, fmt.Errorf("city %q: expected lat:long[:spread[:weight]]", entry)
+		}
+		numbers := []float64{0, 0, defaultSpread, 1}
+		for i, part := range parts {
+			n, err := strconv.ParseFloat(part, 64)
+			if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+				return nil, fmt.Errorf("city %q: invalid number %q", entry, part)
+			}
+			numbers[i] = n
+		}
+		city := City{Name: name, Latitude: numbers[0], Longitude: numbers[1], Spread: numbers[2], Weight: numbers[3]}
+		switch {
+		case city.Latitude < -90 || city.Latitude > 90:
+			return nil, fmt.Errorf("city %q: latitude must be between -90 and 90", entry)
+		case city.Longitude < -180 || city.Longitude > 180:
+			return nil, fmt.Errorf("city %q: longitude must be between -180 and 180", entry)
+		case city.Spread < 0:
+			return nil, fmt.Errorf("city %q: spread must not be negative", entry)
+		case city.Weight <= 0:
+			return nil, fmt.Errorf("city %q: weight must be positive", entry)
+		}
+		cities = append(cities, city)
+	}
+	if len(cities) == 0 {
+		return nil, fmt.Errorf("no cities in %q", s)
+	}
+	return cities, nil
+}
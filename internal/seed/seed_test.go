@@ -0,0 +1,108 @@
+package seed_test
+
+import (
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/seed"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartners(t *testing.T) {
+	partners := seed.Partners(seed.Options{Count: 2000, Seed: 42})
+
+	require.Len(t, partners, 2000)
+	assert.Equal(t, partners, seed.Partners(seed.Options{Count: 2000, Seed: 42}), "same seed must generate same partners")
+	assert.NotEqual(t, partners, seed.Partners(seed.Options{Count: 2000, Seed: 43}))
+	assert.Equal(t, "1", partners[0].ID)
+	assert.Equal(t, "SEED-000001", partners[0].ExternalID)
+
+	now := time.Now()
+	locations := map[entities.Address]bool{}
+	cities := map[string]int{}
+	ratings := map[int]int{}
+	vetted := 0
+	for _, partner := range partners {
+		require.NoError(t, domain.ValidatePartner(partner), partner.ID)
+		locations[partner.Address] = true
+		cities[partner.Address.City]++
+		ratings[partner.Rating]++
+		if domain.IsVetted(partner, now) {
+			vetted++
+		}
+	}
+	assert.Greater(t, len(locations), 1990, "partners must be spread")
+	assert.Len(t, cities, len(seed.DefaultCities))
+	assert.Greater(t, cities["Berlin"], cities["Frankfurt am Main"], "cities must be weighted")
+	assert.Greater(t, ratings[4], ratings[1], "ratings must be skewed towards good ones")
+	assert.InDelta(t, 0.9, float64(vetted)/float64(len(partners)), 0.05)
+}
+
+func TestPartners_Cities(t *testing.T) {
+	augsburg := seed.City{Name: "Augsburg", Latitude: 48.3705, Longitude: 10.8978, Spread: 5, Weight: 1}
+
+	partners := seed.Partners(seed.Options{Count: 500, Seed: 1, Cities: []seed.City{augsburg}})
+
+	for _, partner := range partners {
+		assert.Equal(t, "Augsburg", partner.Address.City)
+		assert.Empty(t, partner.Address.District)
+		// 35 km is seven times the spread, which a normal distribution practically never exceeds.
+		assert.Less(t, math.Abs(partner.Address.Latitude-augsburg.Latitude), 35/111.32)
+	}
+}
+
+func TestCustomers(t *testing.T) {
+	customers := seed.Customers(seed.Options{Count: 1000, Seed: 42})
+
+	require.Len(t, customers, 1000)
+	assert.Equal(t, customers, seed.Customers(seed.Options{Count: 1000, Seed: 42}),
+		"same seed must generate same customers")
+	materials := map[string]int{}
+	for _, customer := range customers {
+		materials[customer.Material]++
+		assert.Contains(t, entities.Materials, customer.Material)
+	}
+	assert.Greater(t, materials["wood"], materials["carpet"], "materials must be weighted")
+}
+
+func TestParseCities(t *testing.T) {
+	type testCase struct {
+		name      string
+		spec      string
+		expCities []seed.City
+		expErr    bool
+	}
+	tests := []testCase{
+		{
+			name: "Parses cities with defaults",
+			spec: "München=48.1372:11.5756:8:1.5, Augsburg=48.3705:10.8978",
+			expCities: []seed.City{
+				{Name: "München", Latitude: 48.1372, Longitude: 11.5756, Spread: 8, Weight: 1.5},
+				{Name: "Augsburg", Latitude: 48.3705, Longitude: 10.8978, Spread: 10, Weight: 1},
+			},
+		},
+		{name: "Fails without coordinates", spec: "Augsburg", expErr: true},
+		{name: "Fails on missing longitude", spec: "Augsburg=48.3705", expErr: true},
+		{name: "Fails on invalid number", spec: "Augsburg=48.3705:east", expErr: true},
+		{name: "Fails on latitude out of range", spec: "Augsburg=91:10.8978", expErr: true},
+		{name: "Fails on zero weight", spec: "Augsburg=48.3705:10.8978:5:0", expErr: true},
+		{name: "Fails on empty list", spec: " , ", expErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cities, err := seed.ParseCities(tt.spec)
+
+			if tt.expErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expCities, cities)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package tracing
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func NewTracedOfferRequestRepository(next domain.OfferRequestRepository) *TracedOfferRequestRepository {
+	return &TracedOfferRequestRepository{next: next}
+}
+
+// TracedOfferRequestRepository emits a client span for every repository call. Contact data is never recorded.
+type TracedOfferRequestRepository struct {
+	next domain.OfferRequestRepository
+}
+
+func (r *TracedOfferRequestRepository) CreateOfferRequest(
+	ctx context.Context,
+	request entities.OfferRequest,
+	events ...entities.Event,
+) error {
+	ctx, span := startSpan(ctx, "OfferRequestRepository.CreateOfferRequest",
+		attribute.String("offer_request.id", request.ID),
+		attribute.String("partner.id", request.PartnerID),
+	)
+	defer span.End()
+	err := r.next.CreateOfferRequest(ctx, request, events...)
+	endWithError(span, err)
+	return err
+}
+
+func (r *TracedOfferRequestRepository) GetOfferRequestByID(ctx context.Context, id string) (entities.OfferRequest, error) {
+	ctx, span := startSpan(ctx, "OfferRequestRepository.GetOfferRequestByID", attribute.String("offer_request.id", id))
+	defer span.End()
+	request, err := r.next.GetOfferRequestByID(ctx, id)
+	endWithError(span, err)
+	return request, err
+}
+
+func (r *TracedOfferRequestRepository) GetOfferRequestsByPartner(ctx context.Context, partnerID string) ([]entities.OfferRequest, error) {
+	ctx, span := startSpan(ctx, "OfferRequestRepository.GetOfferRequestsByPartner", attribute.String("partner.id", partnerID))
+	defer span.End()
+	requests, err := r.next.GetOfferRequestsByPartner(ctx, partnerID)
+	span.SetAttributes(attribute.Int("offer_request.count", len(requests)))
+	endWithError(span, err)
+	return requests, err
+}
+
+func (r *TracedOfferRequestRepository) PurgeContactData(ctx context.Context, createdBefore, purgedAt time.Time) (int, error) {
+	ctx, span := startSpan(ctx, "OfferRequestRepository.PurgeContactData")
+	defer span.End()
+	purged, err := r.next.PurgeContactData(ctx, createdBefore, purgedAt)
+	span.SetAttributes(attribute.Int("offer_request.purged", purged))
+	endWithError(span, err)
+	return purged, err
+}
+
+func (r *TracedOfferRequestRepository) GetOfferRequestsByContact(ctx context.Context, phone, email string) ([]entities.OfferRequest, error) {
+	ctx, span := startSpan(ctx, "OfferRequestRepository.GetOfferRequestsByContact")
+	defer span.End()
+	requests, err := r.next.GetOfferRequestsByContact(ctx, phone, email)
+	span.SetAttributes(attribute.Int("offer_request.count", len(requests)))
+	endWithError(span, err)
+	return requests, err
+}
+
+func (r *TracedOfferRequestRepository) GetOfferRequestsByCustomer(
+	ctx context.Context,
+	customerID string,
+) ([]entities.OfferRequest, error) {
+	ctx, span := startSpan(ctx, "OfferRequestRepository.GetOfferRequestsByCustomer")
+	defer span.End()
+	requests, err := r.next.GetOfferRequestsByCustomer(ctx, customerID)
+	span.SetAttributes(attribute.Int("offer_request.count", len(requests)))
+	endWithError(span, err)
+	return requests, err
+}
+
+func (r *TracedOfferRequestRepository) AnonymizeOfferRequests(ctx context.Context, ids []string, purgedAt time.Time) (int, error) {
+	ctx, span := startSpan(ctx, "OfferRequestRepository.AnonymizeOfferRequests")
+	defer span.End()
+	anonymized, err := r.next.AnonymizeOfferRequests(ctx, ids, purgedAt)
+	span.SetAttributes(attribute.Int("offer_request.anonymized", anonymized))
+	endWithError(span, err)
+	return anonymized, err
+}
+
+func (r *TracedOfferRequestRepository) CreateOfferRequests(
+	ctx context.Context,
+	requests []entities.OfferRequest,
+	events ...entities.Event,
+) error {
+	leadID := ""
+	if len(requests) > 0 {
+		leadID = requests[0].LeadID
+	}
+	ctx, span := startSpan(ctx, "OfferRequestRepository.CreateOfferRequests",
+		attribute.String("lead.id", leadID),
+		attribute.Int("offer_request.count", len(requests)),
+	)
+	defer span.End()
+	err := r.next.CreateOfferRequests(ctx, requests, events...)
+	endWithError(span, err)
+	return err
+}
+
+func (r *TracedOfferRequestRepository) GetOfferRequestsByLead(ctx context.Context, leadID string) ([]entities.OfferRequest, error) {
+	ctx, span := startSpan(ctx, "OfferRequestRepository.GetOfferRequestsByLead", attribute.String("lead.id", leadID))
+	defer span.End()
+	requests, err := r.next.GetOfferRequestsByLead(ctx, leadID)
+	span.SetAttributes(attribute.Int("offer_request.count", len(requests)))
+	endWithError(span, err)
+	return requests, err
+}
+
+func (r *TracedOfferRequestRepository) CountLeadsByPartner(ctx context.Context, partnerID string, since time.Time) (int, error) {
+	ctx, span := startSpan(ctx, "OfferRequestRepository.CountLeadsByPartner", attribute.String("partner.id", partnerID))
+	defer span.End()
+	count, err := r.next.CountLeadsByPartner(ctx, partnerID, since)
+	span.SetAttributes(attribute.Int("offer_request.count", count))
+	endWithError(span, err)
+	return count, err
+}
+
+func (r *TracedOfferRequestRepository) RespondToOfferRequest(
+	ctx context.Context,
+	id, response string,
+	respondedAt time.Time,
+	events ...entities.Event,
+) (entities.OfferRequest, error) {
+	ctx, span := startSpan(ctx, "OfferRequestRepository.RespondToOfferRequest",
+		attribute.String("offer_request.id", id),
+		attribute.String("offer_request.response", response),
+	)
+	defer span.End()
+	request, err := r.next.RespondToOfferRequest(ctx, id, response, respondedAt, events...)
+	endWithError(span, err)
+	return request, err
+}
@@ -0,0 +1,100 @@
+package tracing
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("customer-partner/internal/tracing")
+
+func NewTracedPartnerRepository(next domain.PartnerRepository) *TracedPartnerRepository {
+	return &TracedPartnerRepository{next: next}
+}
+
+// TracedPartnerRepository emits a client span for every repository call.
+type TracedPartnerRepository struct {
+	next domain.PartnerRepository
+}
+
+func (r *TracedPartnerRepository) GetPartnersByMaterial(ctx context.Context, material string) ([]entities.Partner, error) {
+	ctx, span := startSpan(ctx, "PartnerRepository.GetPartnersByMaterial", attribute.String("partner.material", material))
+	defer span.End()
+	partners, err := r.next.GetPartnersByMaterial(ctx, material)
+	span.SetAttributes(attribute.Int("partner.count", len(partners)))
+	endWithError(span, err)
+	return partners, err
+}
+
+func (r *TracedPartnerRepository) GetPartnerByID(ctx context.Context, id string) (entities.Partner, error) {
+	ctx, span := startSpan(ctx, "PartnerRepository.GetPartnerByID", attribute.String("partner.id", id))
+	defer span.End()
+	partner, err := r.next.GetPartnerByID(ctx, id)
+	endWithError(span, err)
+	return partner, err
+}
+
+func (r *TracedPartnerRepository) GetAllPartners(ctx context.Context) ([]entities.Partner, error) {
+	ctx, span := startSpan(ctx, "PartnerRepository.GetAllPartners")
+	defer span.End()
+	partners, err := r.next.GetAllPartners(ctx)
+	span.SetAttributes(attribute.Int("partner.count", len(partners)))
+	endWithError(span, err)
+	return partners, err
+}
+
+func (r *TracedPartnerRepository) GetPartnersWithExpiringCertifications(
+	ctx context.Context,
+	before time.Time,
+) ([]entities.Partner, error) {
+	ctx, span := startSpan(ctx, "PartnerRepository.GetPartnersWithExpiringCertifications")
+	defer span.End()
+	partners, err := r.next.GetPartnersWithExpiringCertifications(ctx, before)
+	span.SetAttributes(attribute.Int("partner.count", len(partners)))
+	endWithError(span, err)
+	return partners, err
+}
+
+func (r *TracedPartnerRepository) CreatePartner(ctx context.Context, partner entities.Partner, events ...entities.Event) error {
+	ctx, span := startSpan(ctx, "PartnerRepository.CreatePartner", attribute.String("partner.id", partner.ID))
+	defer span.End()
+	err := r.next.CreatePartner(ctx, partner, events...)
+	endWithError(span, err)
+	return err
+}
+
+func (r *TracedPartnerRepository) UpdatePartner(ctx context.Context, partner entities.Partner, events ...entities.Event) error {
+	ctx, span := startSpan(ctx, "PartnerRepository.UpdatePartner", attribute.String("partner.id", partner.ID))
+	defer span.End()
+	err := r.next.UpdatePartner(ctx, partner, events...)
+	endWithError(span, err)
+	return err
+}
+
+func (r *TracedPartnerRepository) DeletePartner(ctx context.Context, id string, events ...entities.Event) error {
+	ctx, span := startSpan(ctx, "PartnerRepository.DeletePartner", attribute.String("partner.id", id))
+	defer span.End()
+	err := r.next.DeletePartner(ctx, id, events...)
+	endWithError(span, err)
+	return err
+}
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endWithError marks the span as failed. A missing record is an expected outcome and does not fail the span.
+func endWithError(span trace.Span, err error) {
+	if err == nil || errors.Is(err, entities.ErrRecordNotExist) {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,84 @@
+package tracing_test
+
+import (
+	"context"
+	"customer-partner/internal/auth"
+	"customer-partner/internal/db"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/logging"
+	"customer-partner/internal/privacy"
+	"customer-partner/internal/tracing"
+	"customer-partner/internal/web"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestSpans shares one recorder across its subtests, because the tracers of the packages delegate to the first
+// global tracer provider only.
+func TestSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	repo := tracing.NewTracedPartnerRepository(db.NewPartnerInMemoryRepository(nil))
+	service := domain.NewPartnerService(repo, logging.Discard())
+
+	t.Run("Connects spans from handler to repository", func(t *testing.T) {
+		api := web.NewPartnerAPI(
+			web.Services{Partners: service},
+			auth.NewAuthenticator(nil, nil),
+			privacy.NewObfuscator(nil, privacy.DefaultDecimals),
+			nil,
+			logging.Discard(),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/partners?material=wood&lat=48.1351&long=11.5820", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		rec := httptest.NewRecorder()
+		api.Handler().ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		spans := map[string]sdktrace.ReadOnlySpan{}
+		for _, span := range recorder.Ended() {
+			spans[span.Name()] = span
+		}
+		require.Len(t, spans, 5)
+		server := spans["GET /partners"]
+		require.NotNil(t, server)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+
+		getPartners := spans["PartnerService.GetPartners"]
+		require.NotNil(t, getPartners)
+		assert.Equal(t, server.SpanContext().SpanID(), getPartners.Parent().SpanID())
+		for _, name := range []string{"PartnerRepository.GetPartnersByMaterial", "match", "sort"} {
+			require.Contains(t, spans, name)
+			assert.Equal(t, getPartners.SpanContext().SpanID(), spans[name].Parent().SpanID(), name)
+		}
+	})
+	t.Run("Records errors of the service", func(t *testing.T) {
+		_, err := service.GetPartner(context.Background(), "unknown")
+		require.Error(t, err)
+
+		var getPartner sdktrace.ReadOnlySpan
+		for _, span := range recorder.Ended() {
+			if span.Name() == "PartnerService.GetPartner" {
+				getPartner = span
+			}
+		}
+		require.NotNil(t, getPartner)
+		assert.Equal(t, codes.Error, getPartner.Status().Code)
+		assert.Len(t, getPartner.Events(), 1)
+	})
+}
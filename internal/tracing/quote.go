@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func NewTracedQuoteRepository(next domain.QuoteRepository) *TracedQuoteRepository {
+	return &TracedQuoteRepository{next: next}
+}
+
+// TracedQuoteRepository emits a client span for every repository call.
+type TracedQuoteRepository struct {
+	next domain.QuoteRepository
+}
+
+func (r *TracedQuoteRepository) CreateQuote(
+	ctx context.Context,
+	quote entities.Quote,
+	events ...entities.Event,
+) error {
+	ctx, span := startSpan(ctx, "QuoteRepository.CreateQuote",
+		attribute.String("quote.id", quote.ID),
+		attribute.String("offer_request.id", quote.OfferRequestID),
+	)
+	defer span.End()
+	err := r.next.CreateQuote(ctx, quote, events...)
+	endWithError(span, err)
+	return err
+}
+
+func (r *TracedQuoteRepository) GetQuoteByID(ctx context.Context, id string) (entities.Quote, error) {
+	ctx, span := startSpan(ctx, "QuoteRepository.GetQuoteByID", attribute.String("quote.id", id))
+	defer span.End()
+	quote, err := r.next.GetQuoteByID(ctx, id)
+	endWithError(span, err)
+	return quote, err
+}
+
+func (r *TracedQuoteRepository) GetQuotesByOfferRequest(ctx context.Context, offerRequestID string) ([]entities.Quote, error) {
+	ctx, span := startSpan(ctx, "QuoteRepository.GetQuotesByOfferRequest",
+		attribute.String("offer_request.id", offerRequestID),
+	)
+	defer span.End()
+	quotes, err := r.next.GetQuotesByOfferRequest(ctx, offerRequestID)
+	span.SetAttributes(attribute.Int("quote.count", len(quotes)))
+	endWithError(span, err)
+	return quotes, err
+}
+
+func (r *TracedQuoteRepository) DecideQuote(
+	ctx context.Context,
+	id, status string,
+	decidedAt time.Time,
+	events ...entities.Event,
+) (entities.Quote, error) {
+	ctx, span := startSpan(ctx, "QuoteRepository.DecideQuote",
+		attribute.String("quote.id", id),
+		attribute.String("quote.status", status),
+	)
+	defer span.End()
+	quote, err := r.next.DecideQuote(ctx, id, status, decidedAt, events...)
+	endWithError(span, err)
+	return quote, err
+}
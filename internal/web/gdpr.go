@@ -0,0 +1,103 @@
+package web
+
+import (
+	"context"
+	"customer-partner/internal/auth"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type DataRequestService interface {
+	Export(ctx context.Context, subject domain.DataSubject, actor string) (domain.DataExport, error)
+	Erase(ctx context.Context, subject domain.DataSubject, actor string) (domain.DataErasure, error)
+	GetDataRequests(ctx context.Context) ([]entities.DataRequest, error)
+}
+
+// dataSubjectBody identifies the data subject of an export or erasure. It is sent in the body rather than the query,
+// so that contact data does not end up in access logs.
+type dataSubjectBody struct {
+	Phone      string `json:"phone"`
+	Email      string `json:"email"`
+	CustomerID string `json:"customer_id"`
+	PartnerID  string `json:"partner_id"`
+}
+
+// ExportPersonalData returns all data the service holds about a customer or partner.
+func (a *PartnerAPI) ExportPersonalData(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "exportPersonalData")
+	subject, actor, ok := decodeDataSubject(w, r)
+	if !ok {
+		return
+	}
+	export, err := a.dataRequests.Export(r.Context(), subject, actor)
+	if writeDataRequestError(w, r, "exporting personal data failed", err) {
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, export)
+}
+
+// ErasePersonalData removes all personal data about a customer or partner.
+func (a *PartnerAPI) ErasePersonalData(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "erasePersonalData")
+	subject, actor, ok := decodeDataSubject(w, r)
+	if !ok {
+		return
+	}
+	erasure, err := a.dataRequests.Erase(r.Context(), subject, actor)
+	if writeDataRequestError(w, r, "erasing personal data failed", err) {
+		return
+	}
+	writeJSON(w, http.StatusOK, erasure)
+}
+
+// GetDataRequests returns the audit records of all exports and erasures.
+func (a *PartnerAPI) GetDataRequests(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "getDataRequests")
+	requests, err := a.dataRequests.GetDataRequests(r.Context())
+	if err != nil {
+		writeServiceError(w, logger, "getting data requests failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, requests)
+}
+
+// decodeDataSubject reads the data subject from the request body and the actor from the authenticated principal. It
+// responds with 400 and returns false when the body is invalid.
+func decodeDataSubject(w http.ResponseWriter, r *http.Request) (domain.DataSubject, string, bool) {
+	var body dataSubjectBody
+	if err := decodeJSON(w, r, &body); err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: %s", err.Error()), http.StatusBadRequest)
+		return domain.DataSubject{}, "", false
+	}
+	principal, _ := auth.FromContext(r.Context())
+	subject := domain.DataSubject{
+		Phone:      body.Phone,
+		Email:      body.Email,
+		CustomerID: body.CustomerID,
+		PartnerID:  body.PartnerID,
+	}
+	return subject, principal.Subject, true
+}
+
+// writeDataRequestError responds to the error of a data request and reports whether it did. A missing partner is
+// answered with 404.
+func writeDataRequestError(w http.ResponseWriter, r *http.Request, msg string, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case writeValidationError(w, err):
+	case errors.Is(err, entities.ErrRecordNotExist):
+		http.Error(w, "Not found", http.StatusNotFound)
+	default:
+		writeServiceError(w, logging.FromContext(r.Context()), msg, err)
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package web_test
+
+import (
+	"customer-partner/internal/auth"
+	"customer-partner/internal/auth/authtest"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/web"
+	"customer-partner/internal/web/mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+//go:generate mockery --name DataRequestService
+
+func TestPartnerAPI_ExportPersonalData(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	subject := domain.DataSubject{Phone: "+49 170 1234567"}
+	type testCase struct {
+		name          string
+		auth          string
+		body          string
+		expExport     bool
+		exportErr     error
+		expStatus     int
+		expBodyPrefix string
+	}
+	tests := []testCase{
+		{
+			name:      "Returns 403 for partner",
+			auth:      keys.PartnerToken(t, "partner", "1"),
+			body:      `{"phone":"+49 170 1234567"}`,
+			expStatus: http.StatusForbidden,
+		},
+		{
+			name:          "Returns 200 with export for admin",
+			auth:          keys.AdminToken(t, "admin"),
+			body:          `{"phone":"+49 170 1234567"}`,
+			expExport:     true,
+			expStatus:     http.StatusOK,
+			expBodyPrefix: `{"exported_at":"0001-01-01T00:00:00Z","offer_requests":[{"id":"abc"`,
+		},
+		{
+			name:          "Returns 400 on validation error",
+			auth:          keys.AdminToken(t, "admin"),
+			body:          `{"phone":"+49 170 1234567"}`,
+			expExport:     true,
+			exportErr:     &domain.ValidationError{Field: "subject", Reason: "required"},
+			expStatus:     http.StatusBadRequest,
+			expBodyPrefix: "Bad request: invalid input for parameter subject",
+		},
+		{
+			name:      "Returns 404 for unknown partner",
+			auth:      keys.AdminToken(t, "admin"),
+			body:      `{"phone":"+49 170 1234567"}`,
+			expExport: true,
+			exportErr: entities.ErrRecordNotExist,
+			expStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.DataRequestService{}
+			if tt.expExport {
+				service.On("Export", mock.Anything, subject, "admin").Return(domain.DataExport{
+					OfferRequests: []entities.OfferRequest{{ID: "abc", Phone: subject.Phone}},
+				}, tt.exportErr)
+			}
+			api := newAPI(web.Services{DataRequests: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodPost, "/gdpr/exports", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			assert.True(t, strings.HasPrefix(rec.Body.String(), tt.expBodyPrefix), rec.Body.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPartnerAPI_ErasePersonalData(t *testing.T) {
+	service := &mocks.DataRequestService{}
+	subject := domain.DataSubject{CustomerID: "sub:jane", PartnerID: "1"}
+	service.On("Erase", mock.Anything, subject, "apikey:test-admin").
+		Return(domain.DataErasure{PartnerDeleted: true}, nil)
+	api := newAPI(web.Services{DataRequests: service}, authtest.NewKeySet(t).Authenticator(t))
+	body := `{"customer_id":"sub:jane","partner_id":"1"}`
+	req := httptest.NewRequest(http.MethodPost, "/gdpr/erasures", strings.NewReader(body))
+	req.Header.Set(auth.APIKeyHeader, authtest.AdminAPIKey)
+	rec := httptest.NewRecorder()
+
+	api.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"erased_at":"0001-01-01T00:00:00Z","offer_requests":0,"partner_deleted":true}`, rec.Body.String())
+	service.AssertExpectations(t)
+}
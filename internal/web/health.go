@@ -0,0 +1,135 @@
+package web
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// checkTimeout limits the time a single readiness check may take.
+const checkTimeout = 2 * time.Second
+
+// HealthChecker is implemented by dependencies whose availability decides whether the service is ready, e.g. the
+// configured domain.PartnerRepository.
+type HealthChecker interface {
+	// Check returns an error when the dependency is not usable.
+	Check(ctx context.Context) error
+}
+
+// VersionInfo describes the running build.
+type VersionInfo struct {
+	Version   string    `json:"version"`
+	Revision  string    `json:"revision,omitempty"`
+	Modified  bool      `json:"modified"`
+	GoVersion string    `json:"go_version"`
+	StartTime time.Time `json:"start_time"`
+}
+
+// ReadVersionInfo collects the module version and the VCS revision from the build info of the binary.
+func ReadVersionInfo(startTime time.Time) VersionInfo {
+	info := VersionInfo{Version: "(devel)", StartTime: startTime.UTC()}
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.GoVersion = build.GoVersion
+	if build.Main.Version != "" {
+		info.Version = build.Main.Version
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
+
+func NewHealthAPI(version VersionInfo, checkers map[string]HealthChecker, logger *slog.Logger) *HealthAPI {
+	a := &HealthAPI{version: version, checkers: checkers, logger: logger, mux: http.NewServeMux()}
+	a.mux.HandleFunc("/healthz", a.GetHealth)
+	a.mux.HandleFunc("/readyz", a.GetReadiness)
+	a.mux.HandleFunc("/version", a.GetVersion)
+	return a
+}
+
+// HealthAPI serves the liveness, readiness and version endpoints used by load balancers and operators.
+type HealthAPI struct {
+	version      VersionInfo
+	checkers     map[string]HealthChecker
+	logger       *slog.Logger
+	mux          *http.ServeMux
+	shuttingDown atomic.Bool
+}
+
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Handler returns the http.Handler serving all routes of the api.
+func (a *HealthAPI) Handler() http.Handler {
+	return a.mux
+}
+
+// ShutDown lets the readiness check fail, so that load balancers stop sending new requests while the server drains.
+func (a *HealthAPI) ShutDown() {
+	a.shuttingDown.Store(true)
+}
+
+// GetHealth reports that the process is alive and able to serve requests.
+func (a *HealthAPI) GetHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+// GetReadiness reports whether all dependencies are usable and the server is not shutting down.
+func (a *HealthAPI) GetReadiness(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if a.shuttingDown.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, healthResponse{Status: "shutting_down"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+	resp := healthResponse{Status: "ready", Checks: map[string]string{}}
+	status := http.StatusOK
+	names := make([]string, 0, len(a.checkers))
+	for name := range a.checkers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := a.checkers[name].Check(ctx); err != nil {
+			a.logger.Warn("readiness check failed", "check", name, "error", err)
+			resp.Checks[name] = err.Error()
+			resp.Status = "not_ready"
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		resp.Checks[name] = "ok"
+	}
+	writeJSON(w, status, resp)
+}
+
+// GetVersion reports the version of the running build and the time the process started.
+func (a *HealthAPI) GetVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, a.version)
+}
@@ -0,0 +1,90 @@
+package web_test
+
+import (
+	"context"
+	"customer-partner/internal/logging"
+	"customer-partner/internal/web"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type checkerFunc func(ctx context.Context) error
+
+func (f checkerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestHealthAPI_GetReadiness(t *testing.T) {
+	ok := checkerFunc(func(context.Context) error { return nil })
+	failing := checkerFunc(func(context.Context) error { return errors.New("connection refused") })
+	type testCase struct {
+		name         string
+		checkers     map[string]web.HealthChecker
+		shuttingDown bool
+		expStatus    int
+		expBody      string
+	}
+	tests := []testCase{
+		{
+			name:      "Returns 200 when all checks pass",
+			checkers:  map[string]web.HealthChecker{"partner_repository": ok},
+			expStatus: http.StatusOK,
+			expBody:   `{"status":"ready","checks":{"partner_repository":"ok"}}`,
+		},
+		{
+			name:      "Returns 503 when a check fails",
+			checkers:  map[string]web.HealthChecker{"partner_repository": failing, "other": ok},
+			expStatus: http.StatusServiceUnavailable,
+			expBody:   `{"status":"not_ready","checks":{"other":"ok","partner_repository":"connection refused"}}`,
+		},
+		{
+			name:         "Returns 503 while shutting down",
+			checkers:     map[string]web.HealthChecker{"partner_repository": ok},
+			shuttingDown: true,
+			expStatus:    http.StatusServiceUnavailable,
+			expBody:      `{"status":"shutting_down"}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			api := web.NewHealthAPI(web.VersionInfo{}, tt.checkers, logging.Discard())
+			if tt.shuttingDown {
+				api.ShutDown()
+			}
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			assert.JSONEq(t, tt.expBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHealthAPI_GetHealthAndVersion(t *testing.T) {
+	start := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	api := web.NewHealthAPI(web.ReadVersionInfo(start), nil, logging.Discard())
+	api.ShutDown()
+
+	rec := httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var version web.VersionInfo
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &version))
+	assert.Equal(t, start, version.StartTime)
+	assert.NotEmpty(t, version.Version)
+	assert.NotEmpty(t, version.GoVersion)
+}
@@ -0,0 +1,34 @@
+package web
+
+import (
+	"context"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"errors"
+	"net/http"
+)
+
+type PartnerHistoryService interface {
+	GetPartnerHistory(ctx context.Context, id string) ([]entities.PartnerChange, error)
+}
+
+// GetPartnerHistory returns the audit trail of a partner. Partners may only read the history of their own record.
+func (a *PartnerAPI) GetPartnerHistory(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "getPartnerHistory")
+	id, ok := managedPartner(w, r)
+	if !ok {
+		return
+	}
+	changes, err := a.history.GetPartnerHistory(r.Context(), id)
+	if errors.Is(err, entities.ErrRecordNotExist) {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger.With("partner_id", id), "getting partner history failed", err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, changes)
+}
@@ -0,0 +1,96 @@
+package web_test
+
+import (
+	"customer-partner/internal/auth/authtest"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/web"
+	"customer-partner/internal/web/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+//go:generate mockery --name PartnerHistoryService
+
+func TestPartnerAPI_GetPartnerHistory(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	changes := []entities.PartnerChange{{
+		ID:        "c1",
+		PartnerID: "1",
+		Action:    entities.PartnerUpdated,
+		Actor:     "apikey:crm",
+		Changes:   []entities.FieldChange{{Field: "operating_radius", Before: 10, After: 20}},
+	}}
+	type testCase struct {
+		name       string
+		path       string
+		auth       string
+		expLookup  bool
+		serviceErr error
+		expStatus  int
+		expBody    string
+	}
+	tests := []testCase{
+		{
+			name:      "Returns 401 for anonymous caller",
+			path:      "/partners/1/history",
+			expStatus: http.StatusUnauthorized,
+		},
+		{
+			name:      "Returns 403 for other partner",
+			path:      "/partners/1/history",
+			auth:      keys.PartnerToken(t, "partner", "2"),
+			expStatus: http.StatusForbidden,
+		},
+		{
+			name:      "Returns 200 with history for the partner",
+			path:      "/partners/1/history",
+			auth:      keys.PartnerToken(t, "partner", "1"),
+			expLookup: true,
+			expStatus: http.StatusOK,
+			expBody: `[{"id":"c1","partner_id":"1","action":"update","actor":"apikey:crm",` +
+				`"changed_at":"0001-01-01T00:00:00Z","changes":[{"field":"operating_radius","before":10,"after":20}]}]`,
+		},
+		{
+			name:       "Returns 404 for unknown partner",
+			path:       "/partners/1/history",
+			auth:       keys.AdminToken(t, "admin"),
+			expLookup:  true,
+			serviceErr: entities.ErrRecordNotExist,
+			expStatus:  http.StatusNotFound,
+		},
+		{
+			name:      "Returns 404 for unknown subresource",
+			path:      "/partners/1/unknown",
+			auth:      keys.AdminToken(t, "admin"),
+			expStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.PartnerHistoryService{}
+			if tt.expLookup {
+				service.On("GetPartnerHistory", mock.Anything, "1").Return(changes, tt.serviceErr)
+			}
+			api := newAPI(web.Services{History: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			}
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			if tt.expBody != "" {
+				assert.JSONEq(t, tt.expBody, rec.Body.String())
+				assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
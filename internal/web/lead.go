@@ -0,0 +1,112 @@
+package web
+
+import (
+	"context"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type LeadService interface {
+	CreateLead(ctx context.Context, request domain.LeadRequest) (entities.Lead, error)
+	GetLead(ctx context.Context, id string) (entities.Lead, error)
+}
+
+// leadBody is the request body to create a lead. Pointers distinguish missing from zero values.
+type leadBody struct {
+	Material  *string           `json:"material"`
+	Address   *entities.Address `json:"address"`
+	FloorSize *float64          `json:"floor_size"`
+	Phone     *string           `json:"phone"`
+	Email     string            `json:"email"`
+	Language  string            `json:"language"`
+	Partners  *int              `json:"partners"`
+}
+
+// CreateLead requests offers from the best matching partners at once. Like offer requests, leads are created by
+// customers without an account and the response only contains the masked contact data.
+func (a *PartnerAPI) CreateLead(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "createLead")
+	var body leadBody
+	err := decodeJSON(w, r, &body)
+	switch {
+	case err != nil:
+	case body.Material == nil:
+		err = ErrMissingArgument("material")
+	case body.Address == nil:
+		err = ErrMissingArgument("address")
+	case body.FloorSize == nil:
+		err = ErrMissingArgument("floor_size")
+	case body.Phone == nil:
+		err = ErrMissingArgument("phone")
+	case body.Partners != nil && *body.Partners < 1:
+		err = ErrInvalidInput("partners")
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	partners := 0
+	if body.Partners != nil {
+		partners = *body.Partners
+	}
+	lead, err := a.leads.CreateLead(r.Context(), domain.LeadRequest{
+		GetPartnersOpts: domain.GetPartnersOpts{
+			Material:            *body.Material,
+			CustomerAddressLat:  body.Address.Latitude,
+			CustomerAddressLong: body.Address.Longitude,
+		},
+		FloorSize:  *body.FloorSize,
+		Phone:      *body.Phone,
+		Email:      body.Email,
+		Language:   body.Language,
+		Partners:   partners,
+		CustomerID: customerID(r.Context()),
+	})
+	if writeValidationError(w, err) {
+		return
+	}
+	if errors.Is(err, domain.ErrNoPartnerAvailable) {
+		http.Error(w, "Unprocessable entity: no partner available", http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger, "creating lead failed", err)
+		return
+	}
+	w.Header().Set("Location", "/leads/"+lead.ID)
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusCreated, maskLead(lead))
+}
+
+// GetLead returns a lead with the responses of the partners in the order they responded. The contact data is masked.
+func (a *PartnerAPI) GetLead(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "getLead")
+	id := strings.TrimPrefix(r.URL.Path, "/leads/")
+	lead, err := a.leads.GetLead(r.Context(), id)
+	if errors.Is(err, entities.ErrRecordNotExist) {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger.With("lead_id", id), "getting lead failed", err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, maskLead(lead))
+}
+
+func maskLead(lead entities.Lead) entities.Lead {
+	masked := make([]entities.OfferRequest, 0, len(lead.OfferRequests))
+	for _, request := range lead.OfferRequests {
+		masked = append(masked, maskContactData(request))
+	}
+	lead.OfferRequests = masked
+	return lead
+}
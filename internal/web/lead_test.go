@@ -0,0 +1,140 @@
+package web_test
+
+import (
+	"customer-partner/internal/auth/authtest"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/web"
+	"customer-partner/internal/web/mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+//go:generate mockery --name LeadService
+
+func TestPartnerAPI_CreateLead(t *testing.T) {
+	lead := entities.Lead{
+		ID:        "lead",
+		CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		OfferRequests: []entities.OfferRequest{{
+			ID:        "abc",
+			PartnerID: "1",
+			FloorSize: 42.5,
+			Phone:     "+49 170 1234567",
+			CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+			LeadID:    "lead",
+		}},
+	}
+	expRequest := domain.LeadRequest{
+		GetPartnersOpts: domain.GetPartnersOpts{Material: "wood", CustomerAddressLat: 48.1, CustomerAddressLong: 11.6},
+		FloorSize:       42.5,
+		Phone:           "+49 170 1234567",
+		Partners:        2,
+	}
+	validBody := `{"material":"wood","address":{"latitude":48.1,"longitude":11.6},"floor_size":42.5,` +
+		`"phone":"+49 170 1234567","partners":2}`
+	type testCase struct {
+		name       string
+		body       string
+		expCreate  bool
+		serviceErr error
+		expStatus  int
+		expBody    string
+	}
+	tests := []testCase{
+		{
+			name:      "Returns 201 with masked contact data",
+			body:      validBody,
+			expCreate: true,
+			expStatus: http.StatusCreated,
+			expBody: `{"id":"lead","created_at":"2024-05-01T12:00:00Z","offer_requests":[{"id":"abc","partner_id":"1",` +
+				`"floor_size":42.5,"phone":"+** *** *****67","created_at":"2024-05-01T12:00:00Z","lead_id":"lead"}]}`,
+		},
+		{
+			name:      "Returns 400 for missing material",
+			body:      `{"address":{"latitude":48.1,"longitude":11.6},"floor_size":42.5,"phone":"+49 170 1234567"}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Returns 400 for zero partners",
+			body: `{"material":"wood","address":{"latitude":48.1,"longitude":11.6},"floor_size":42.5,` +
+				`"phone":"+49 170 1234567","partners":0}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Returns 422 when no partner is available",
+			body:       validBody,
+			expCreate:  true,
+			serviceErr: domain.ErrNoPartnerAvailable,
+			expStatus:  http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.LeadService{}
+			if tt.expCreate {
+				service.On("CreateLead", mock.Anything, expRequest).Return(lead, tt.serviceErr)
+			}
+			api := newAPI(web.Services{Leads: service}, authtest.NewKeySet(t).Authenticator(t))
+			req := httptest.NewRequest(http.MethodPost, "/leads", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			if tt.expBody != "" {
+				assert.JSONEq(t, tt.expBody, rec.Body.String())
+				assert.Equal(t, "/leads/lead", rec.Header().Get("Location"))
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPartnerAPI_GetLead(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	type testCase struct {
+		name       string
+		auth       string
+		expLookup  bool
+		serviceErr error
+		expStatus  int
+	}
+	tests := []testCase{
+		{name: "Returns 403 for partner", auth: keys.PartnerToken(t, "partner", "1"), expStatus: http.StatusForbidden},
+		{name: "Returns 200 for admin", auth: keys.AdminToken(t, "admin"), expLookup: true, expStatus: http.StatusOK},
+		{
+			name:       "Returns 404 for unknown lead",
+			auth:       keys.AdminToken(t, "admin"),
+			expLookup:  true,
+			serviceErr: entities.ErrRecordNotExist,
+			expStatus:  http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.LeadService{}
+			if tt.expLookup {
+				service.On("GetLead", mock.Anything, "lead").Return(entities.Lead{ID: "lead"}, tt.serviceErr)
+			}
+			api := newAPI(web.Services{Leads: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodGet, "/leads/lead", nil)
+			req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package web
+
+import (
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// reasonBody is the request body to suspend or delete a partner.
+type reasonBody struct {
+	Reason *string `json:"reason"`
+}
+
+// SuspendPartner hides a partner from customers until an admin reinstates them.
+func (a *PartnerAPI) SuspendPartner(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "suspendPartner")
+	id, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/partners/"), "/")
+	reason, ok := decodeReason(w, r)
+	if !ok {
+		return
+	}
+	partner, err := a.service.SuspendPartner(r.Context(), id, reason)
+	if writeLifecycleError(w, err) {
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger.With("partner_id", id), "suspending partner failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, partner)
+}
+
+// ReinstatePartner lifts the suspension of a partner.
+func (a *PartnerAPI) ReinstatePartner(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "reinstatePartner")
+	id, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/partners/"), "/")
+	partner, err := a.service.ReinstatePartner(r.Context(), id)
+	if writeLifecycleError(w, err) {
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger.With("partner_id", id), "reinstating partner failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, partner)
+}
+
+// DeletePartner soft deletes a partner. The partner is kept for the offer requests referencing them, but answered with
+// 410 afterwards.
+func (a *PartnerAPI) DeletePartner(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "deletePartner")
+	id := strings.TrimPrefix(r.URL.Path, "/partners/")
+	reason, ok := decodeReason(w, r)
+	if !ok {
+		return
+	}
+	err := a.service.DeletePartner(r.Context(), id, reason)
+	if writeLifecycleError(w, err) {
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger.With("partner_id", id), "deleting partner failed", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeReason reads the reason from the request body. It responds with 400 when the body is invalid or the reason is
+// missing and reports false then.
+func decodeReason(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var body reasonBody
+	err := decodeJSON(w, r, &body)
+	if err == nil && body.Reason == nil {
+		err = ErrMissingArgument("reason")
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: %s", err.Error()), http.StatusBadRequest)
+		return "", false
+	}
+	return *body.Reason, true
+}
+
+// writeLifecycleError responds to the errors of suspending, reinstating and deleting a partner which are caused by the
+// request and reports whether it did.
+func writeLifecycleError(w http.ResponseWriter, err error) bool {
+	switch {
+	case writeValidationError(w, err):
+		return true
+	case errors.Is(err, entities.ErrRecordNotExist):
+		http.Error(w, "Not found", http.StatusNotFound)
+		return true
+	case errors.Is(err, entities.ErrPartnerDeleted):
+		http.Error(w, "Gone", http.StatusGone)
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,216 @@
+package web_test
+
+import (
+	"customer-partner/internal/auth/authtest"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/web"
+	"customer-partner/internal/web/mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPartnerAPI_GetPartner_Inactive(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	changedAt := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	suspended := entities.Partner{ID: "123", SuspendedAt: &changedAt, SuspensionReason: "Complaints"}
+	deleted := entities.Partner{ID: "123", DeletedAt: &changedAt, DeletionReason: "Business closed"}
+	type testCase struct {
+		name      string
+		auth      string
+		query     string
+		stored    entities.Partner
+		expGet    bool
+		expStatus int
+	}
+	tests := []testCase{
+		{name: "Returns 410 for deleted partner", stored: deleted, expGet: true, expStatus: http.StatusGone},
+		{name: "Returns 410 for deleted partner to admin", auth: keys.AdminToken(t, "admin"), stored: deleted, expGet: true, expStatus: http.StatusGone},
+		{name: "Returns 404 for suspended partner", stored: suspended, expGet: true, expStatus: http.StatusNotFound},
+		{name: "Returns 200 for suspended partner to themselves", auth: keys.PartnerToken(t, "partner", "123"), stored: suspended, expGet: true, expStatus: http.StatusOK},
+		{name: "Returns 200 for deleted partner to admin including inactive", auth: keys.AdminToken(t, "admin"), query: "?include_inactive=true", stored: deleted, expGet: true, expStatus: http.StatusOK},
+		{name: "Returns 403 on 'include_inactive' for partner", auth: keys.PartnerToken(t, "partner", "123"), query: "?include_inactive=true", expStatus: http.StatusForbidden},
+		{name: "Returns 400 on invalid 'include_inactive'", auth: keys.AdminToken(t, "admin"), query: "?include_inactive=sometimes", expStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.PartnerService{}
+			if tt.expGet {
+				service.On("GetPartner", mock.Anything, "123").Return(tt.stored, nil)
+			}
+			api := newAPI(web.Services{Partners: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodGet, "/partners/123"+tt.query, nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			}
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPartnerAPI_GetPartners_IncludeInactive(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	service := &mocks.PartnerService{}
+	service.On("GetPartners", mock.Anything, domain.GetPartnersOpts{
+		Material:            "wood",
+		CustomerAddressLong: 80.123,
+		CustomerAddressLat:  42.125,
+		IncludeInactive:     true,
+	}).Return([]entities.Partner{{ID: "1"}}, nil)
+	api := newAPI(web.Services{Partners: service}, keys.Authenticator(t))
+	req := httptest.NewRequest(http.MethodGet, "/partners?material=wood&long=80.123&lat=42.125&include_inactive=true", nil)
+	req.Header.Set("Authorization", authtest.Bearer(keys.AdminToken(t, "admin")))
+	rec := httptest.NewRecorder()
+
+	api.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	service.AssertExpectations(t)
+}
+
+func TestPartnerAPI_UpdatePartner_Deleted(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	deletedAt := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	service := &mocks.PartnerService{}
+	service.On("GetPartner", mock.Anything, "123").Return(entities.Partner{ID: "123", DeletedAt: &deletedAt}, nil)
+	api := newAPI(web.Services{Partners: service}, keys.Authenticator(t))
+	body := `{"name":"Floors","experienced_material":["wood"],"address":{"latitude":48.1,"longitude":11.6},"operating_radius":10}`
+	req := httptest.NewRequest(http.MethodPut, "/partners/123", strings.NewReader(body))
+	req.Header.Set("Authorization", authtest.Bearer(keys.AdminToken(t, "admin")))
+	rec := httptest.NewRecorder()
+
+	api.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusGone, rec.Code)
+	service.AssertExpectations(t)
+}
+
+func TestPartnerAPI_SuspendPartner(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	type testCase struct {
+		name       string
+		auth       string
+		body       string
+		expSuspend bool
+		serviceErr error
+		expStatus  int
+	}
+	tests := []testCase{
+		{name: "Returns 403 for partner", auth: keys.PartnerToken(t, "partner", "123"), body: `{"reason":"Complaints"}`, expStatus: http.StatusForbidden},
+		{name: "Returns 400 for missing reason", auth: keys.AdminToken(t, "admin"), body: `{}`, expStatus: http.StatusBadRequest},
+		{name: "Returns 200 for admin", auth: keys.AdminToken(t, "admin"), body: `{"reason":"Complaints"}`, expSuspend: true, expStatus: http.StatusOK},
+		{
+			name:       "Returns 400 on validation error",
+			auth:       keys.AdminToken(t, "admin"),
+			body:       `{"reason":"Complaints"}`,
+			expSuspend: true,
+			serviceErr: &domain.ValidationError{Field: "reason", Reason: "must not be empty"},
+			expStatus:  http.StatusBadRequest,
+		},
+		{
+			name:       "Returns 404 for unknown partner",
+			auth:       keys.AdminToken(t, "admin"),
+			body:       `{"reason":"Complaints"}`,
+			expSuspend: true,
+			serviceErr: entities.ErrRecordNotExist,
+			expStatus:  http.StatusNotFound,
+		},
+		{
+			name:       "Returns 410 for deleted partner",
+			auth:       keys.AdminToken(t, "admin"),
+			body:       `{"reason":"Complaints"}`,
+			expSuspend: true,
+			serviceErr: entities.ErrPartnerDeleted,
+			expStatus:  http.StatusGone,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.PartnerService{}
+			if tt.expSuspend {
+				service.On("SuspendPartner", mock.Anything, "123", "Complaints").
+					Return(entities.Partner{ID: "123", SuspensionReason: "Complaints"}, tt.serviceErr)
+			}
+			api := newAPI(web.Services{Partners: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodPost, "/partners/123/suspension", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPartnerAPI_ReinstatePartner(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	service := &mocks.PartnerService{}
+	service.On("ReinstatePartner", mock.Anything, "123").Return(entities.Partner{ID: "123"}, nil)
+	api := newAPI(web.Services{Partners: service}, keys.Authenticator(t))
+	req := httptest.NewRequest(http.MethodDelete, "/partners/123/suspension", nil)
+	req.Header.Set("Authorization", authtest.Bearer(keys.AdminToken(t, "admin")))
+	rec := httptest.NewRecorder()
+
+	api.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	service.AssertExpectations(t)
+}
+
+func TestPartnerAPI_DeletePartner(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	type testCase struct {
+		name       string
+		auth       string
+		body       string
+		expDelete  bool
+		serviceErr error
+		expStatus  int
+	}
+	tests := []testCase{
+		{name: "Returns 403 for partner", auth: keys.PartnerToken(t, "partner", "123"), body: `{"reason":"Closed"}`, expStatus: http.StatusForbidden},
+		{name: "Returns 400 for missing reason", auth: keys.AdminToken(t, "admin"), body: `{}`, expStatus: http.StatusBadRequest},
+		{name: "Returns 204 for admin", auth: keys.AdminToken(t, "admin"), body: `{"reason":"Closed"}`, expDelete: true, expStatus: http.StatusNoContent},
+		{
+			name:       "Returns 410 for deleted partner",
+			auth:       keys.AdminToken(t, "admin"),
+			body:       `{"reason":"Closed"}`,
+			expDelete:  true,
+			serviceErr: entities.ErrPartnerDeleted,
+			expStatus:  http.StatusGone,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.PartnerService{}
+			if tt.expDelete {
+				service.On("DeletePartner", mock.Anything, "123", "Closed").Return(tt.serviceErr)
+			}
+			api := newAPI(web.Services{Partners: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodDelete, "/partners/123", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package web
+
+import (
+	"crypto/rand"
+	"customer-partner/internal/logging"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader is the header used to receive and return the id of a request.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limits the length of request ids accepted from clients.
+const maxRequestIDLength = 128
+
+// Middleware wraps a http.Handler with additional behaviour.
+type Middleware func(http.Handler) http.Handler
+
+// chain applies the middlewares to h. The first middleware is the outermost one.
+func chain(h http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+// methods dispatches requests of a route to the handler registered for their method. Other methods are answered with
+// 405 and the allowed methods.
+type methods map[string]http.HandlerFunc
+
+func (m methods) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h, ok := m[r.Method]; ok {
+		h(w, r)
+		return
+	}
+	allowed := make([]string, 0, len(m))
+	for method := range m {
+		allowed = append(allowed, method)
+	}
+	sort.Strings(allowed)
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+}
+
+// subresources dispatches requests below a route by the path following the id, e.g. "history" for
+// "/partners/1/history". Patterns consist of segments, "*" matches any non-empty segment. The empty pattern serves
+// the resource itself, requests matching no pattern are answered with 404.
+type subresources struct {
+	prefix   string
+	handlers map[string]http.Handler
+}
+
+func (s subresources) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, s.prefix), "/")
+	for pattern, h := range s.handlers {
+		if matchSegments(pattern, rest) {
+			h.ServeHTTP(w, r)
+			return
+		}
+	}
+	http.Error(w, "Not found", http.StatusNotFound)
+}
+
+func matchSegments(pattern, path string) bool {
+	patternSegments, pathSegments := strings.Split(pattern, "/"), strings.Split(path, "/")
+	if len(patternSegments) != len(pathSegments) {
+		return false
+	}
+	for i, segment := range patternSegments {
+		if segment != pathSegments[i] && (segment != "*" || pathSegments[i] == "") {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestLogging assigns every request an id, taken from the X-Request-ID header when the client sent a valid one,
+// and returns it in the response. A logger annotated with the id is passed on through the request context and every
+// completed request is logged with its status, size and duration.
+func RequestLogging(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			reqLogger := logger.With("request_id", id)
+			if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+				reqLogger = reqLogger.With("trace_id", sc.TraceID().String())
+			}
+			ctx := logging.WithRequestID(r.Context(), id)
+			ctx = logging.WithContext(ctx, reqLogger)
+
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			level := slog.LevelInfo
+			if rec.Status() >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			reqLogger.LogAttrs(ctx, level, "request completed",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rec.Status()),
+				slog.Int("bytes", rec.bytes),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("remote_addr", r.RemoteAddr),
+				slog.String("user_agent", r.UserAgent()),
+			)
+		})
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// statusRecorder records the status code and the number of bytes written to a http.ResponseWriter.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Status returns the status code sent to the client.
+func (r *statusRecorder) Status() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
+
+// Unwrap allows http.ResponseController to reach the underlying http.ResponseWriter.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
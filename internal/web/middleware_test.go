@@ -0,0 +1,86 @@
+package web_test
+
+import (
+	"bytes"
+	"customer-partner/internal/logging"
+	"customer-partner/internal/web"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogging(t *testing.T) {
+	type testCase struct {
+		name        string
+		reqID       string
+		expSameID   bool
+		handlerCode int
+		expLevel    string
+	}
+	tests := []testCase{
+		{
+			name:        "Propagates valid request id from client",
+			reqID:       "abc-123",
+			expSameID:   true,
+			handlerCode: http.StatusOK,
+			expLevel:    "INFO",
+		},
+		{
+			name:        "Generates request id when client sent none",
+			reqID:       "",
+			expSameID:   false,
+			handlerCode: http.StatusNotFound,
+			expLevel:    "INFO",
+		},
+		{
+			name:        "Replaces invalid request id from client",
+			reqID:       "abc\n123",
+			expSameID:   false,
+			handlerCode: http.StatusInternalServerError,
+			expLevel:    "ERROR",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			var ctxID string
+			handler := web.RequestLogging(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxID = logging.RequestIDFromContext(r.Context())
+				w.WriteHeader(tt.handlerCode)
+				_, _ = w.Write([]byte("body"))
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/partners?material=wood", nil)
+			if tt.reqID != "" {
+				req.Header.Set(web.RequestIDHeader, tt.reqID)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			respID := rec.Header().Get(web.RequestIDHeader)
+			require.NotEmpty(t, respID)
+			assert.Equal(t, respID, ctxID)
+			if tt.expSameID {
+				assert.Equal(t, tt.reqID, respID)
+			} else {
+				assert.NotEqual(t, tt.reqID, respID)
+			}
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+			assert.Equal(t, tt.expLevel, entry["level"])
+			assert.Equal(t, respID, entry["request_id"])
+			assert.Equal(t, http.MethodGet, entry["method"])
+			assert.Equal(t, "/partners", entry["path"])
+			assert.Equal(t, float64(tt.handlerCode), entry["status"])
+			assert.Equal(t, float64(4), entry["bytes"])
+			assert.Contains(t, entry, "duration")
+		})
+	}
+}
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	domain "customer-partner/internal/domain"
 	entities "customer-partner/internal/entities"
 
@@ -14,20 +15,20 @@ type PartnerService struct {
 	mock.Mock
 }
 
-// GetPartner provides a mock function with given fields: id
-func (_m *PartnerService) GetPartner(id string) (entities.Partner, error) {
-	ret := _m.Called(id)
+// CreatePartner provides a mock function with given fields: ctx, partner
+func (_m *PartnerService) CreatePartner(ctx context.Context, partner entities.Partner) (entities.Partner, error) {
+	ret := _m.Called(ctx, partner)
 
 	var r0 entities.Partner
-	if rf, ok := ret.Get(0).(func(string) entities.Partner); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Partner) entities.Partner); ok {
+		r0 = rf(ctx, partner)
 	} else {
 		r0 = ret.Get(0).(entities.Partner)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, entities.Partner) error); ok {
+		r1 = rf(ctx, partner)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -35,20 +36,169 @@ func (_m *PartnerService) GetPartner(id string) (entities.Partner, error) {
 	return r0, r1
 }
 
-// GetPartners provides a mock function with given fields: opts
-func (_m *PartnerService) GetPartners(opts domain.GetPartnersOpts) []entities.Partner {
-	ret := _m.Called(opts)
+// DeletePartner provides a mock function with given fields: ctx, id, reason
+func (_m *PartnerService) DeletePartner(ctx context.Context, id string, reason string) error {
+	ret := _m.Called(ctx, id, reason)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllPartners provides a mock function with given fields: ctx
+func (_m *PartnerService) GetAllPartners(ctx context.Context) ([]entities.Partner, error) {
+	ret := _m.Called(ctx)
 
 	var r0 []entities.Partner
-	if rf, ok := ret.Get(0).(func(domain.GetPartnersOpts) []entities.Partner); ok {
-		r0 = rf(opts)
+	if rf, ok := ret.Get(0).(func(context.Context) []entities.Partner); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entities.Partner)
 		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPartner provides a mock function with given fields: ctx, id
+func (_m *PartnerService) GetPartner(ctx context.Context, id string) (entities.Partner, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 entities.Partner
+	if rf, ok := ret.Get(0).(func(context.Context, string) entities.Partner); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entities.Partner)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPartners provides a mock function with given fields: ctx, opts
+func (_m *PartnerService) GetPartners(ctx context.Context, opts domain.GetPartnersOpts) ([]entities.Partner, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 []entities.Partner
+	if rf, ok := ret.Get(0).(func(context.Context, domain.GetPartnersOpts) []entities.Partner); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Partner)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.GetPartnersOpts) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportPartners provides a mock function with given fields: ctx, rows, dryRun
+func (_m *PartnerService) ImportPartners(ctx context.Context, rows []domain.ImportRow, dryRun bool) (domain.ImportResult, error) {
+	ret := _m.Called(ctx, rows, dryRun)
+
+	var r0 domain.ImportResult
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.ImportRow, bool) domain.ImportResult); ok {
+		r0 = rf(ctx, rows, dryRun)
+	} else {
+		r0 = ret.Get(0).(domain.ImportResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.ImportRow, bool) error); ok {
+		r1 = rf(ctx, rows, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReinstatePartner provides a mock function with given fields: ctx, id
+func (_m *PartnerService) ReinstatePartner(ctx context.Context, id string) (entities.Partner, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 entities.Partner
+	if rf, ok := ret.Get(0).(func(context.Context, string) entities.Partner); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entities.Partner)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SuspendPartner provides a mock function with given fields: ctx, id, reason
+func (_m *PartnerService) SuspendPartner(ctx context.Context, id string, reason string) (entities.Partner, error) {
+	ret := _m.Called(ctx, id, reason)
+
+	var r0 entities.Partner
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) entities.Partner); ok {
+		r0 = rf(ctx, id, reason)
+	} else {
+		r0 = ret.Get(0).(entities.Partner)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePartner provides a mock function with given fields: ctx, partner
+func (_m *PartnerService) UpdatePartner(ctx context.Context, partner entities.Partner) (entities.Partner, error) {
+	ret := _m.Called(ctx, partner)
+
+	var r0 entities.Partner
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Partner) entities.Partner); ok {
+		r0 = rf(ctx, partner)
+	} else {
+		r0 = ret.Get(0).(entities.Partner)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, entities.Partner) error); ok {
+		r1 = rf(ctx, partner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 type mockConstructorTestingTNewPartnerService interface {
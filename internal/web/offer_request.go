@@ -0,0 +1,184 @@
+package web
+
+import (
+	"context"
+	"customer-partner/internal/auth"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"customer-partner/internal/privacy"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type OfferRequestService interface {
+	CreateOfferRequest(ctx context.Context, request entities.OfferRequest) (entities.OfferRequest, error)
+	GetOfferRequest(ctx context.Context, id string) (entities.OfferRequest, error)
+	GetOfferRequestsByPartner(ctx context.Context, partnerID string) ([]entities.OfferRequest, error)
+	RespondToOfferRequest(ctx context.Context, id, response string) (entities.OfferRequest, error)
+}
+
+// responseBody is the request body of the response of a partner to an offer request.
+type responseBody struct {
+	Response *string `json:"response"`
+}
+
+// offerRequestBody is the request body to create an offer request. Pointers distinguish missing from zero values.
+type offerRequestBody struct {
+	PartnerID *string  `json:"partner_id"`
+	FloorSize *float64 `json:"floor_size"`
+	Phone     *string  `json:"phone"`
+	Email     string   `json:"email"`
+	Language  string   `json:"language"`
+}
+
+// CreateOfferRequest stores the request of a customer for an offer. Customers do not need an account, the response
+// only contains the masked contact data. Customers with an account own the offer request and can decide on its quotes.
+func (a *PartnerAPI) CreateOfferRequest(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "createOfferRequest")
+	var body offerRequestBody
+	err := decodeJSON(w, r, &body)
+	switch {
+	case err != nil:
+	case body.PartnerID == nil:
+		err = ErrMissingArgument("partner_id")
+	case body.FloorSize == nil:
+		err = ErrMissingArgument("floor_size")
+	case body.Phone == nil:
+		err = ErrMissingArgument("phone")
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	request, err := a.offerRequests.CreateOfferRequest(r.Context(), entities.OfferRequest{
+		PartnerID:  *body.PartnerID,
+		FloorSize:  *body.FloorSize,
+		Phone:      *body.Phone,
+		Email:      body.Email,
+		Language:   body.Language,
+		CustomerID: customerID(r.Context()),
+	})
+	if writeValidationError(w, err) {
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger, "creating offer request failed", err)
+		return
+	}
+	w.Header().Set("Location", "/offer_requests/"+request.ID)
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusCreated, maskContactData(request))
+}
+
+// GetOfferRequests lists the offer requests sent to the partner given by the partner_id query parameter. Partners
+// may only list their own offer requests. The contact data is masked.
+func (a *PartnerAPI) GetOfferRequests(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "getOfferRequests")
+	if !r.URL.Query().Has("partner_id") {
+		http.Error(w, fmt.Sprintf("Bad request: %s", ErrMissingArgument("partner_id")), http.StatusBadRequest)
+		return
+	}
+	partnerID := r.URL.Query().Get("partner_id")
+	if principal, _ := auth.FromContext(r.Context()); !principal.CanManagePartner(partnerID) {
+		auth.Forbidden(w)
+		return
+	}
+	requests, err := a.offerRequests.GetOfferRequestsByPartner(r.Context(), partnerID)
+	if err != nil {
+		writeServiceError(w, logger.With("partner_id", partnerID), "getting offer requests failed", err)
+		return
+	}
+	masked := make([]entities.OfferRequest, 0, len(requests))
+	for _, request := range requests {
+		masked = append(masked, maskContactData(request))
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, masked)
+}
+
+// GetOfferRequest returns an offer request including the contact data, which the partner needs to prepare the
+// offer. Partners may only read the offer requests sent to them.
+func (a *PartnerAPI) GetOfferRequest(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "getOfferRequest")
+	id := strings.TrimPrefix(r.URL.Path, "/offer_requests/")
+	request, err := a.offerRequests.GetOfferRequest(r.Context(), id)
+	if errors.Is(err, entities.ErrRecordNotExist) {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger.With("offer_request_id", id), "getting offer request failed", err)
+		return
+	}
+	if principal, _ := auth.FromContext(r.Context()); !principal.CanManagePartner(request.PartnerID) {
+		auth.Forbidden(w)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, request)
+}
+
+// RespondToOfferRequest stores whether the partner accepts or declines an offer request. Partners may only respond to
+// the offer requests sent to them, once.
+func (a *PartnerAPI) RespondToOfferRequest(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "respondToOfferRequest")
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/offer_requests/"), "/response")
+	logger = logger.With("offer_request_id", id)
+	var body responseBody
+	err := decodeJSON(w, r, &body)
+	if err == nil && body.Response == nil {
+		err = ErrMissingArgument("response")
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	request, err := a.offerRequests.GetOfferRequest(r.Context(), id)
+	if errors.Is(err, entities.ErrRecordNotExist) {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger, "getting offer request failed", err)
+		return
+	}
+	if principal, _ := auth.FromContext(r.Context()); !principal.CanManagePartner(request.PartnerID) {
+		auth.Forbidden(w)
+		return
+	}
+	request, err = a.offerRequests.RespondToOfferRequest(r.Context(), id, *body.Response)
+	if writeValidationError(w, err) {
+		return
+	}
+	if errors.Is(err, entities.ErrAlreadyResponded) {
+		http.Error(w, "Conflict: offer request already responded", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger, "responding to offer request failed", err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, maskContactData(request))
+}
+
+// customerID returns the id of the customer calling, empty for anonymous customers and other callers.
+func customerID(ctx context.Context) string {
+	principal, _ := auth.FromContext(ctx)
+	return principal.CustomerID()
+}
+
+// maskContactData hides all but a hint of the phone number and email address of the customer.
+func maskContactData(request entities.OfferRequest) entities.OfferRequest {
+	request.Phone = privacy.MaskPhone(request.Phone)
+	if request.Email != "" {
+		request.Email = privacy.MaskEmail(request.Email)
+	}
+	return request
+}
@@ -0,0 +1,228 @@
+package web_test
+
+import (
+	"customer-partner/internal/auth/authtest"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/web"
+	"customer-partner/internal/web/mocks"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockery --name OfferRequestService
+
+func TestPartnerAPI_CreateOfferRequest(t *testing.T) {
+	created := entities.OfferRequest{
+		ID:        "abc",
+		PartnerID: "1",
+		FloorSize: 42.5,
+		Phone:     "+49 170 1234567",
+		Email:     "jane@example.com",
+		CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	type testCase struct {
+		name          string
+		body          string
+		expCreate     *entities.OfferRequest
+		createErr     error
+		expStatus     int
+		expBodyPrefix string
+	}
+	tests := []testCase{
+		{
+			name:      "Returns 201 with masked contact data",
+			body:      `{"partner_id":"1","floor_size":42.5,"phone":"+49 170 1234567","email":"jane@example.com"}`,
+			expCreate: &entities.OfferRequest{PartnerID: "1", FloorSize: 42.5, Phone: "+49 170 1234567", Email: "jane@example.com"},
+			expStatus: http.StatusCreated,
+			expBodyPrefix: `{"id":"abc","partner_id":"1","floor_size":42.5,"phone":"+** *** *****67",` +
+				`"email":"j***@example.com"`,
+		},
+		{
+			name:          "Returns 400 on missing phone",
+			body:          `{"partner_id":"1","floor_size":42.5}`,
+			expStatus:     http.StatusBadRequest,
+			expBodyPrefix: "Bad request: parameter phone missing",
+		},
+		{
+			name:          "Returns 400 on unknown partner",
+			body:          `{"partner_id":"9","floor_size":42.5,"phone":"+49 170 1234567"}`,
+			expCreate:     &entities.OfferRequest{PartnerID: "9", FloorSize: 42.5, Phone: "+49 170 1234567"},
+			createErr:     &domain.ValidationError{Field: "partner_id", Reason: "unknown partner"},
+			expStatus:     http.StatusBadRequest,
+			expBodyPrefix: "Bad request: invalid input for parameter partner_id",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OfferRequestService{}
+			if tt.expCreate != nil {
+				service.On("CreateOfferRequest", mock.Anything, *tt.expCreate).Return(created, tt.createErr)
+			}
+			api := newAPI(web.Services{OfferRequests: service}, authtest.NewKeySet(t).Authenticator(t))
+			req := httptest.NewRequest(http.MethodPost, "/offer_requests", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			assert.True(t, strings.HasPrefix(rec.Body.String(), tt.expBodyPrefix), rec.Body.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPartnerAPI_GetOfferRequests(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	request := entities.OfferRequest{ID: "abc", PartnerID: "1", FloorSize: 42.5, Phone: "+49 170 1234567"}
+
+	t.Run("Returns masked offer requests of own partner", func(t *testing.T) {
+		service := &mocks.OfferRequestService{}
+		service.On("GetOfferRequestsByPartner", mock.Anything, "1").Return([]entities.OfferRequest{request}, nil)
+		api := newAPI(web.Services{OfferRequests: service}, keys.Authenticator(t))
+		req := httptest.NewRequest(http.MethodGet, "/offer_requests?partner_id=1", nil)
+		req.Header.Set("Authorization", authtest.Bearer(keys.PartnerToken(t, "partner", "1")))
+		rec := httptest.NewRecorder()
+
+		api.Handler().ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var actual []entities.OfferRequest
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &actual))
+		require.Len(t, actual, 1)
+		assert.Equal(t, "+** *** *****67", actual[0].Phone)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	})
+
+	t.Run("Returns 403 for other partner", func(t *testing.T) {
+		service := &mocks.OfferRequestService{}
+		api := newAPI(web.Services{OfferRequests: service}, keys.Authenticator(t))
+		req := httptest.NewRequest(http.MethodGet, "/offer_requests?partner_id=1", nil)
+		req.Header.Set("Authorization", authtest.Bearer(keys.PartnerToken(t, "partner", "2")))
+		rec := httptest.NewRecorder()
+
+		api.Handler().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		service.AssertExpectations(t)
+	})
+}
+
+func TestPartnerAPI_GetOfferRequest(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	request := entities.OfferRequest{ID: "abc", PartnerID: "1", FloorSize: 42.5, Phone: "+49 170 1234567"}
+	type testCase struct {
+		name      string
+		auth      string
+		expStatus int
+	}
+	tests := []testCase{
+		{name: "Returns 401 for anonymous caller", expStatus: http.StatusUnauthorized},
+		{name: "Returns 403 for customer", auth: keys.CustomerToken(t, "customer"), expStatus: http.StatusForbidden},
+		{name: "Returns 403 for other partner", auth: keys.PartnerToken(t, "partner", "2"), expStatus: http.StatusForbidden},
+		{name: "Returns 200 for own partner", auth: keys.PartnerToken(t, "partner", "1"), expStatus: http.StatusOK},
+		{name: "Returns 200 for admin", auth: keys.AdminToken(t, "admin"), expStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OfferRequestService{}
+			service.On("GetOfferRequest", mock.Anything, "abc").Return(request, nil).Maybe()
+			api := newAPI(web.Services{OfferRequests: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodGet, "/offer_requests/abc", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			}
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			if tt.expStatus == http.StatusOK {
+				expBody, _ := json.Marshal(request)
+				assert.JSONEq(t, string(expBody), rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestPartnerAPI_RespondToOfferRequest(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	stored := entities.OfferRequest{ID: "abc", PartnerID: "1", Phone: "+49 170 1234567"}
+	type testCase struct {
+		name       string
+		auth       string
+		body       string
+		expRespond bool
+		serviceErr error
+		expStatus  int
+	}
+	tests := []testCase{
+		{
+			name:      "Returns 403 for other partner",
+			auth:      keys.PartnerToken(t, "partner", "2"),
+			body:      `{"response":"accepted"}`,
+			expStatus: http.StatusForbidden,
+		},
+		{
+			name:      "Returns 400 for missing response",
+			auth:      keys.PartnerToken(t, "partner", "1"),
+			body:      `{}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Returns 200 with masked contact data",
+			auth:       keys.PartnerToken(t, "partner", "1"),
+			body:       `{"response":"accepted"}`,
+			expRespond: true,
+			expStatus:  http.StatusOK,
+		},
+		{
+			name:       "Returns 409 when responded before",
+			auth:       keys.AdminToken(t, "admin"),
+			body:       `{"response":"accepted"}`,
+			expRespond: true,
+			serviceErr: entities.ErrAlreadyResponded,
+			expStatus:  http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OfferRequestService{}
+			service.On("GetOfferRequest", mock.Anything, "abc").Return(stored, nil).Maybe()
+			if tt.expRespond {
+				responded := stored
+				responded.Response = entities.OfferRequestAccepted
+				responded.ResponseRank = 1
+				service.On("RespondToOfferRequest", mock.Anything, "abc", entities.OfferRequestAccepted).
+					Return(responded, tt.serviceErr)
+			}
+			api := newAPI(web.Services{OfferRequests: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodPost, "/offer_requests/abc/response", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			if tt.expStatus == http.StatusOK {
+				var body entities.OfferRequest
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				assert.Equal(t, 1, body.ResponseRank)
+				assert.NotContains(t, rec.Body.String(), "1234567")
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
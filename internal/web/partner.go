@@ -1,78 +1,477 @@
 package web
 
 import (
+	"context"
+	"customer-partner/internal/auth"
 	"customer-partner/internal/domain"
 	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"customer-partner/internal/privacy"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type PartnerService interface {
-	GetPartners(opts domain.GetPartnersOpts) []entities.Partner
-	GetPartner(id string) (entities.Partner, error)
+	GetPartners(ctx context.Context, opts domain.GetPartnersOpts) ([]entities.Partner, error)
+	GetPartner(ctx context.Context, id string) (entities.Partner, error)
+	CreatePartner(ctx context.Context, partner entities.Partner) (entities.Partner, error)
+	UpdatePartner(ctx context.Context, partner entities.Partner) (entities.Partner, error)
+	SuspendPartner(ctx context.Context, id, reason string) (entities.Partner, error)
+	ReinstatePartner(ctx context.Context, id string) (entities.Partner, error)
+	DeletePartner(ctx context.Context, id, reason string) error
+	GetAllPartners(ctx context.Context) ([]entities.Partner, error)
+	ImportPartners(ctx context.Context, rows []domain.ImportRow, dryRun bool) (domain.ImportResult, error)
 }
 
-func NewPartnerAPI(service PartnerService) *PartnerAPI {
-	return &PartnerAPI{service: service}
+// RateLimiter limits the number of requests clients may send per route. Its middleware runs before the authentication,
+// so that it limits clients guessing credentials too. route maps a request to the pattern of the route serving it.
+type RateLimiter interface {
+	Middleware(route func(r *http.Request) string) func(http.Handler) http.Handler
+}
+
+// varyCredentials is the Vary header of responses which depend on the caller, so that shared caches do not hand out
+// exact addresses to the public.
+const varyCredentials = "Authorization, " + auth.APIKeyHeader
+
+// maxBodySize limits the size of request bodies.
+const maxBodySize = 1 << 20
+
+// Services are the domain services behind the api.
+type Services struct {
+	Partners      PartnerService
+	OfferRequests OfferRequestService
+	DataRequests  DataRequestService
+	History       PartnerHistoryService
+	Webhooks      WebhookService
+	Leads         LeadService
+	Quotes        QuoteService
+}
+
+// NewPartnerAPI creates the api. The obfuscator blurs the addresses of partners shown to the public, rateLimiter may
+// be nil to serve requests without limits.
+func NewPartnerAPI(
+	services Services,
+	authenticator *auth.Authenticator,
+	obfuscator *privacy.Obfuscator,
+	rateLimiter RateLimiter,
+	logger *slog.Logger,
+) *PartnerAPI {
+	a := &PartnerAPI{
+		service:       services.Partners,
+		offerRequests: services.OfferRequests,
+		dataRequests:  services.DataRequests,
+		history:       services.History,
+		webhooks:      services.Webhooks,
+		leads:         services.Leads,
+		quotes:        services.Quotes,
+		authenticator: authenticator,
+		obfuscator:    obfuscator,
+		rateLimiter:   rateLimiter,
+		logger:        logger,
+		mux:           http.NewServeMux(),
+	}
+	a.mux.Handle("/partners", methods{
+		http.MethodGet:  a.GetPartners,
+		http.MethodPost: auth.RequireRole(a.CreatePartner, auth.RoleAdmin),
+	})
+	a.mux.Handle("/partners/imports", methods{http.MethodPost: auth.RequireRole(a.ImportPartners, auth.RoleAdmin)})
+	a.mux.Handle("/partners/exports", methods{http.MethodGet: auth.RequireRole(a.ExportPartners, auth.RoleAdmin)})
+	a.mux.Handle("/partners/", subresources{prefix: "/partners/", handlers: map[string]http.Handler{
+		"": methods{
+			http.MethodGet:    a.GetPartner,
+			http.MethodPut:    auth.RequireRole(a.UpdatePartner, auth.RolePartner, auth.RoleAdmin),
+			http.MethodDelete: auth.RequireRole(a.DeletePartner, auth.RoleAdmin),
+		},
+		"suspension": methods{
+			http.MethodPost:   auth.RequireRole(a.SuspendPartner, auth.RoleAdmin),
+			http.MethodDelete: auth.RequireRole(a.ReinstatePartner, auth.RoleAdmin),
+		},
+		"history": methods{
+			http.MethodGet: auth.RequireRole(a.GetPartnerHistory, auth.RolePartner, auth.RoleAdmin),
+		},
+		"webhooks": methods{
+			http.MethodGet:  auth.RequireRole(a.GetWebhooks, auth.RolePartner, auth.RoleAdmin),
+			http.MethodPost: auth.RequireRole(a.CreateWebhook, auth.RolePartner, auth.RoleAdmin),
+		},
+		"webhooks/*": methods{
+			http.MethodDelete: auth.RequireRole(a.DeleteWebhook, auth.RolePartner, auth.RoleAdmin),
+		},
+		"webhook-deliveries": methods{
+			http.MethodGet: auth.RequireRole(a.GetWebhookDeliveries, auth.RolePartner, auth.RoleAdmin),
+		},
+	}})
+	a.mux.Handle("/offer_requests", methods{
+		http.MethodGet:  auth.RequireRole(a.GetOfferRequests, auth.RolePartner, auth.RoleAdmin),
+		http.MethodPost: a.CreateOfferRequest,
+	})
+	a.mux.Handle("/offer_requests/", subresources{prefix: "/offer_requests/", handlers: map[string]http.Handler{
+		"": methods{
+			http.MethodGet: auth.RequireRole(a.GetOfferRequest, auth.RolePartner, auth.RoleAdmin),
+		},
+		"response": methods{
+			http.MethodPost: auth.RequireRole(a.RespondToOfferRequest, auth.RolePartner, auth.RoleAdmin),
+		},
+		"quotes": methods{
+			http.MethodGet:  auth.RequireRole(a.GetQuotes, auth.RolePartner, auth.RoleCustomer, auth.RoleAdmin),
+			http.MethodPost: auth.RequireRole(a.CreateQuote, auth.RolePartner, auth.RoleAdmin),
+		},
+		"quotes/*": methods{
+			http.MethodGet: auth.RequireRole(a.GetQuote, auth.RolePartner, auth.RoleCustomer, auth.RoleAdmin),
+		},
+		"quotes/*/decision": methods{
+			http.MethodPost: auth.RequireRole(a.DecideQuote, auth.RoleCustomer, auth.RoleAdmin),
+		},
+	}})
+	a.mux.Handle("/leads", methods{http.MethodPost: a.CreateLead})
+	a.mux.Handle("/leads/", methods{http.MethodGet: auth.RequireRole(a.GetLead, auth.RoleAdmin)})
+	a.mux.Handle("/gdpr/exports", methods{http.MethodPost: auth.RequireRole(a.ExportPersonalData, auth.RoleAdmin)})
+	a.mux.Handle("/gdpr/erasures", methods{http.MethodPost: auth.RequireRole(a.ErasePersonalData, auth.RoleAdmin)})
+	a.mux.Handle("/gdpr/requests", methods{http.MethodGet: auth.RequireRole(a.GetDataRequests, auth.RoleAdmin)})
+	return a
 }
 
 // PartnerAPI provides the functionality to host the Matching Customer & Partner api
 type PartnerAPI struct {
-	service PartnerService
+	service       PartnerService
+	offerRequests OfferRequestService
+	dataRequests  DataRequestService
+	history       PartnerHistoryService
+	webhooks      WebhookService
+	leads         LeadService
+	quotes        QuoteService
+	authenticator *auth.Authenticator
+	obfuscator    *privacy.Obfuscator
+	rateLimiter   RateLimiter
+	logger        *slog.Logger
+	mux           *http.ServeMux
+}
+
+// Handler returns the http.Handler serving all routes of the api.
+func (a *PartnerAPI) Handler() http.Handler {
+	middlewares := []Middleware{Tracing(a.Route), RequestLogging(a.logger)}
+	if a.rateLimiter != nil {
+		middlewares = append(middlewares, a.rateLimiter.Middleware(a.Route))
+	}
+	return chain(a.mux, append(middlewares, a.authenticator.Middleware)...)
 }
 
-// ListenAndServe starts serving the api.
-// It is a blocking operation.
-func (a *PartnerAPI) ListenAndServe() {
-	http.HandleFunc("/partners", a.GetPartners)
-	http.HandleFunc("/partners/", a.GetPartner)
-	log.Fatal(http.ListenAndServe(":8080", nil))
+// Route returns the route pattern serving the request, e.g. "/partners/" for "/partners/123". It returns an empty
+// string when no route matches.
+func (a *PartnerAPI) Route(r *http.Request) string {
+	_, pattern := a.mux.Handler(r)
+	return pattern
 }
 
 func (a *PartnerAPI) GetPartners(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		fmt.Println("Endpoint Hit: getPartners")
-		err := validateGetPartnersRequest(r.URL.Query())
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Bad request: %s", err.Error()), http.StatusBadRequest)
-			return
-		}
-		opts, err := getPartnersOptsFromQuery(r.URL.Query())
-		if err != nil {
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-			return
-		}
-		_ = json.NewEncoder(w).Encode(a.service.GetPartners(opts))
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "getPartners")
+	err := validateGetPartnersRequest(r.URL.Query())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: %s", err.Error()), http.StatusBadRequest)
+		return
 	}
+	opts, err := getPartnersOptsFromQuery(r.URL.Query())
+	if err != nil {
+		logger.Error("parsing validated query failed", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	principal, _ := auth.FromContext(r.Context())
+	if (opts.IncludeUnverified || opts.IncludeInactive) && !principal.HasRole(auth.RoleAdmin) {
+		auth.Forbidden(w)
+		return
+	}
+	partners, err := a.service.GetPartners(r.Context(), opts)
+	if err != nil {
+		writeServiceError(w, logger, "getting partners failed", err)
+		return
+	}
+	w.Header().Set("Vary", varyCredentials)
+	views := make([]any, 0, len(partners))
+	for _, partner := range partners {
+		views = append(views, a.view(r.Context(), partner))
+	}
+	writeJSON(w, http.StatusOK, views)
 }
 
+// GetPartner returns a partner. Deleted partners are answered with 410 and suspended partners with 404, unless an admin
+// asks to include inactive partners. Suspended partners still see their own record.
 func (a *PartnerAPI) GetPartner(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		fmt.Println("Endpoint Hit: getPartner")
-		id := strings.TrimPrefix(r.URL.Path, "/partners/")
-		partners, err := a.service.GetPartner(id)
-		if errors.Is(err, entities.ErrRecordNotExist) {
-			http.Error(w, "Not found", http.StatusNotFound)
-			return
-		}
-		if err != nil {
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-			return
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "getPartner")
+	id := strings.TrimPrefix(r.URL.Path, "/partners/")
+	includeInactive, err := strconv.ParseBool(r.URL.Query().Get("include_inactive"))
+	if r.URL.Query().Has("include_inactive") && err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: %s", ErrInvalidInput("include_inactive")), http.StatusBadRequest)
+		return
+	}
+	principal, _ := auth.FromContext(r.Context())
+	if includeInactive && !principal.HasRole(auth.RoleAdmin) {
+		auth.Forbidden(w)
+		return
+	}
+	partner, err := a.service.GetPartner(r.Context(), id)
+	if errors.Is(err, entities.ErrRecordNotExist) {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger.With("partner_id", id), "getting partner failed", err)
+		return
+	}
+	if !includeInactive && partner.DeletedAt != nil {
+		http.Error(w, "Gone", http.StatusGone)
+		return
+	}
+	if !includeInactive && partner.SuspendedAt != nil && !principal.CanManagePartner(id) {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Vary", varyCredentials)
+	writeJSON(w, http.StatusOK, a.view(r.Context(), partner))
+}
+
+// publicPartner is the representation of a partner shown to the public. Many partners work from home, so the
+// coordinates of the address are blurred. Attributes added to entities.Partner stay private unless they are added
+// here.
+type publicPartner struct {
+	ID                  string                  `json:"id"`
+	Name                string                  `json:"name"`
+	ExperiencedMaterial []string                `json:"experienced_material"`
+	Address             entities.Address        `json:"address"`
+	OperatingRadius     int                     `json:"operating_radius"`
+	Rating              int                     `json:"rating"`
+	PriceLists          []entities.PriceList    `json:"price_lists,omitempty"`
+	PriceEstimate       *entities.PriceEstimate `json:"price_estimate,omitempty"`
+	TimeZone            string                  `json:"time_zone,omitempty"`
+	OpeningHours        []entities.OpeningHours `json:"opening_hours,omitempty"`
+	Holidays            []string                `json:"holidays,omitempty"`
+	OpenNow             *bool                   `json:"open_now,omitempty"`
+	NextAvailableAt     *time.Time              `json:"next_available_at,omitempty"`
+	VerificationStatus  string                  `json:"verification_status,omitempty"`
+	Certifications      []publicCertification   `json:"certifications,omitempty"`
+}
+
+// publicCertification is the representation of a certification shown to the public.
+type publicCertification struct {
+	Type      string    `json:"type"`
+	Issuer    string    `json:"issuer"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// view returns the representation of the partner for the caller. Admins and the partner themselves see the exact
+// record, everyone else a publicPartner.
+func (a *PartnerAPI) view(ctx context.Context, partner entities.Partner) any {
+	if principal, _ := auth.FromContext(ctx); principal.CanManagePartner(partner.ID) {
+		return partner
+	}
+	var certifications []publicCertification
+	for _, certification := range partner.Certifications {
+		certifications = append(certifications, publicCertification{
+			Type:      certification.Type,
+			Issuer:    certification.Issuer,
+			ExpiresAt: certification.ExpiresAt,
+		})
+	}
+	return publicPartner{
+		ID:                  partner.ID,
+		Name:                partner.Name,
+		ExperiencedMaterial: partner.ExperiencedMaterial,
+		Address:             a.obfuscator.Obfuscate(partner.ID, partner.Address),
+		OperatingRadius:     partner.OperatingRadius,
+		Rating:              partner.Rating,
+		PriceLists:          partner.PriceLists,
+		PriceEstimate:       partner.PriceEstimate,
+		TimeZone:            partner.TimeZone,
+		OpeningHours:        partner.OpeningHours,
+		Holidays:            partner.Holidays,
+		OpenNow:             partner.OpenNow,
+		NextAvailableAt:     partner.NextAvailableAt,
+		VerificationStatus:  partner.VerificationStatus,
+		Certifications:      certifications,
+	}
+}
+
+// partnerBody is the request body to create or update a partner. Pointers distinguish missing from zero values.
+type partnerBody struct {
+	Name                *string                  `json:"name"`
+	ExperiencedMaterial []string                 `json:"experienced_material"`
+	Address             *entities.Address        `json:"address"`
+	OperatingRadius     *int                     `json:"operating_radius"`
+	Rating              *int                     `json:"rating"`
+	DailyLeadCap        *int                     `json:"daily_lead_cap"`
+	Contact             *entities.Contact        `json:"contact"`
+	PriceLists          []entities.PriceList     `json:"price_lists"`
+	TimeZone            *string                  `json:"time_zone"`
+	OpeningHours        []entities.OpeningHours  `json:"opening_hours"`
+	Holidays            []string                 `json:"holidays"`
+	VerificationStatus  *string                  `json:"verification_status"`
+	Certifications      []entities.Certification `json:"certifications"`
+	InsuranceExpiresAt  *time.Time               `json:"insurance_expires_at"`
+}
+
+func (a *PartnerAPI) CreatePartner(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "createPartner")
+	body, err := decodePartnerBody(w, r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	partner := body.apply(entities.Partner{})
+	partner, err = a.service.CreatePartner(r.Context(), partner)
+	if writeValidationError(w, err) {
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger, "creating partner failed", err)
+		return
+	}
+	w.Header().Set("Location", "/partners/"+partner.ID)
+	writeJSON(w, http.StatusCreated, partner)
+}
+
+// UpdatePartner replaces the attributes of a partner. Partners may only update their own record and may not change
+// their rating or verification status. A verified partner changing their certifications or insurance expiry is
+// pending verification again.
+func (a *PartnerAPI) UpdatePartner(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "updatePartner")
+	id := strings.TrimPrefix(r.URL.Path, "/partners/")
+	principal, _ := auth.FromContext(r.Context())
+	if !principal.CanManagePartner(id) {
+		auth.Forbidden(w)
+		return
+	}
+	body, err := decodePartnerBody(w, r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	existing, err := a.service.GetPartner(r.Context(), id)
+	if errors.Is(err, entities.ErrRecordNotExist) {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger.With("partner_id", id), "getting partner failed", err)
+		return
+	}
+	if existing.DeletedAt != nil {
+		http.Error(w, "Gone", http.StatusGone)
+		return
+	}
+	changesRating := body.Rating != nil && *body.Rating != existing.Rating
+	changesVerification := body.VerificationStatus != nil && *body.VerificationStatus != existing.VerificationStatus
+	if (changesRating || changesVerification) && !principal.HasRole(auth.RoleAdmin) {
+		auth.Forbidden(w)
+		return
+	}
+	changed := body.apply(existing)
+	if changed.VerificationStatus == entities.VerificationVerified && changesVetting(existing, changed) &&
+		!principal.HasRole(auth.RoleAdmin) {
+		changed.VerificationStatus = entities.VerificationPending
+	}
+	partner, err := a.service.UpdatePartner(r.Context(), changed)
+	if writeValidationError(w, err) {
+		return
+	}
+	if errors.Is(err, entities.ErrRecordNotExist) {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, entities.ErrPartnerDeleted) {
+		http.Error(w, "Gone", http.StatusGone)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger.With("partner_id", id), "updating partner failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, partner)
+}
+
+// changesVetting reports whether the certifications or the insurance expiry of the changed partner differ from the
+// existing ones, which were vetted by admins. Flags of certifications are ignored.
+func changesVetting(existing, changed entities.Partner) bool {
+	if len(existing.Certifications) != len(changed.Certifications) {
+		return true
+	}
+	for i, certification := range changed.Certifications {
+		vetted := existing.Certifications[i]
+		if certification.Type != vetted.Type || certification.Issuer != vetted.Issuer ||
+			!certification.ExpiresAt.Equal(vetted.ExpiresAt) {
+			return true
 		}
-		_ = json.NewEncoder(w).Encode(partners)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
+	vetted, insured := existing.InsuranceExpiresAt, changed.InsuranceExpiresAt
+	return (vetted == nil) != (insured == nil) || vetted != nil && !vetted.Equal(*insured)
+}
+
+// decodePartnerBody reads the partner from the request body and checks that all required attributes are present.
+func decodePartnerBody(w http.ResponseWriter, r *http.Request) (partnerBody, error) {
+	var body partnerBody
+	if err := decodeJSON(w, r, &body); err != nil {
+		return partnerBody{}, err
+	}
+	switch {
+	case body.Name == nil:
+		return partnerBody{}, ErrMissingArgument("name")
+	case body.ExperiencedMaterial == nil:
+		return partnerBody{}, ErrMissingArgument("experienced_material")
+	case body.Address == nil:
+		return partnerBody{}, ErrMissingArgument("address")
+	case body.OperatingRadius == nil:
+		return partnerBody{}, ErrMissingArgument("operating_radius")
+	}
+	return body, nil
+}
+
+// apply sets the attributes of the body on the partner. The rating, the daily lead cap, the contact, the price lists,
+// the availability and the verification are only changed when they are present.
+func (b partnerBody) apply(partner entities.Partner) entities.Partner {
+	partner.Name = *b.Name
+	partner.ExperiencedMaterial = b.ExperiencedMaterial
+	partner.Address = *b.Address
+	partner.OperatingRadius = *b.OperatingRadius
+	if b.Rating != nil {
+		partner.Rating = *b.Rating
+	}
+	if b.DailyLeadCap != nil {
+		partner.DailyLeadCap = *b.DailyLeadCap
+	}
+	if b.Contact != nil {
+		partner.Contact = b.Contact
+	}
+	if b.PriceLists != nil {
+		partner.PriceLists = b.PriceLists
+	}
+	if b.TimeZone != nil {
+		partner.TimeZone = *b.TimeZone
+	}
+	if b.OpeningHours != nil {
+		partner.OpeningHours = b.OpeningHours
+	}
+	if b.Holidays != nil {
+		partner.Holidays = b.Holidays
+	}
+	if b.VerificationStatus != nil {
+		partner.VerificationStatus = *b.VerificationStatus
+	}
+	if b.Certifications != nil {
+		partner.Certifications = domain.KeepCertificationFlags(b.Certifications, partner.Certifications)
+	}
+	if b.InsuranceExpiresAt != nil {
+		partner.InsuranceExpiresAt = b.InsuranceExpiresAt
+	}
+	return partner
 }
 
 func getPartnersOptsFromQuery(params url.Values) (domain.GetPartnersOpts, error) {
@@ -88,6 +487,39 @@ func getPartnersOptsFromQuery(params url.Values) (domain.GetPartnersOpts, error)
 		Material:            params.Get("material"),
 		CustomerAddressLong: long,
 		CustomerAddressLat:  lat,
+		SortBy:              params.Get("sort"),
+	}
+	if params.Has("floor_size") {
+		opts.FloorSize, err = strconv.ParseFloat(params.Get("floor_size"), 64)
+		if err != nil {
+			return domain.GetPartnersOpts{}, err
+		}
+	}
+	if params.Has("available_from") {
+		opts.AvailableFrom, err = time.Parse(time.RFC3339, params.Get("available_from"))
+		if err != nil {
+			return domain.GetPartnersOpts{}, err
+		}
+		opts.AvailableFrom = opts.AvailableFrom.UTC()
+	}
+	if params.Has("include_unverified") {
+		opts.IncludeUnverified, err = strconv.ParseBool(params.Get("include_unverified"))
+		if err != nil {
+			return domain.GetPartnersOpts{}, err
+		}
+	}
+	if params.Has("include_inactive") {
+		opts.IncludeInactive, err = strconv.ParseBool(params.Get("include_inactive"))
+		if err != nil {
+			return domain.GetPartnersOpts{}, err
+		}
+	}
+	if params.Has("available_until") {
+		opts.AvailableUntil, err = time.Parse(time.RFC3339, params.Get("available_until"))
+		if err != nil {
+			return domain.GetPartnersOpts{}, err
+		}
+		opts.AvailableUntil = opts.AvailableUntil.UTC()
 	}
 	return opts, nil
 }
@@ -96,7 +528,7 @@ func validateGetPartnersRequest(params url.Values) error {
 	if !params.Has("material") {
 		return ErrMissingArgument("material")
 	}
-	if !stringInSlice(params.Get("material"), []string{"wood", "carpet", "tiles"}) {
+	if !stringInSlice(params.Get("material"), entities.Materials) {
 		return ErrInvalidInput("material")
 	}
 	if !params.Has("long") {
@@ -114,9 +546,86 @@ func validateGetPartnersRequest(params url.Values) error {
 	if lat, err := strconv.ParseFloat(params.Get("lat"), 64); err != nil || lat < -90 || lat > 90 {
 		return ErrInvalidInput("lat")
 	}
+	if params.Has("floor_size") {
+		size, err := strconv.ParseFloat(params.Get("floor_size"), 64)
+		if err != nil || size < domain.MinFloorSize || size > domain.MaxFloorSize {
+			return ErrInvalidInput("floor_size")
+		}
+	}
+	if params.Has("sort") && !stringInSlice(params.Get("sort"), domain.SortOrders) {
+		return ErrInvalidInput("sort")
+	}
+	if params.Get("sort") == domain.SortByPrice && !params.Has("floor_size") {
+		return ErrMissingArgument("floor_size")
+	}
+	if _, err := strconv.ParseBool(params.Get("include_unverified")); params.Has("include_unverified") && err != nil {
+		return ErrInvalidInput("include_unverified")
+	}
+	if _, err := strconv.ParseBool(params.Get("include_inactive")); params.Has("include_inactive") && err != nil {
+		return ErrInvalidInput("include_inactive")
+	}
+	var from time.Time
+	if params.Has("available_from") {
+		var err error
+		if from, err = time.Parse(time.RFC3339, params.Get("available_from")); err != nil {
+			return ErrInvalidInput("available_from")
+		}
+		if !params.Has("available_until") {
+			return ErrMissingArgument("available_until")
+		}
+	}
+	if params.Has("available_until") {
+		until, err := time.Parse(time.RFC3339, params.Get("available_until"))
+		if err != nil || !until.After(from) {
+			return ErrInvalidInput("available_until")
+		}
+	}
 	return nil
 }
 
+// writeJSON encodes body as the json response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// decodeJSON decodes the json request body into v. Errors name the offending attribute where possible.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(v)
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return ErrInvalidInput(strings.SplitN(typeErr.Field, ".", 2)[0])
+	default:
+		return errors.New("invalid json body")
+	}
+}
+
+// writeValidationError responds with 400 when err is a *domain.ValidationError and reports whether it did.
+func writeValidationError(w http.ResponseWriter, err error) bool {
+	var validationErr *domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+	http.Error(w, fmt.Sprintf("Bad request: %s", ErrInvalidInput(validationErr.Field)), http.StatusBadRequest)
+	return true
+}
+
+// writeServiceError responds to an unexpected error of a service. Requests which were canceled, e.g. because the
+// client disconnected, are answered with 503 and not logged as error.
+func writeServiceError(w http.ResponseWriter, logger *slog.Logger, msg string, err error) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		logger.Warn(msg, "error", err)
+		http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	logger.Error(msg, "error", err)
+	http.Error(w, "Internal server error", http.StatusInternalServerError)
+}
+
 func ErrMissingArgument(name string) error {
 	return fmt.Errorf("parameter %s missing", name)
 }
@@ -0,0 +1,103 @@
+package web
+
+import (
+	"bytes"
+	"customer-partner/internal/logging"
+	"customer-partner/internal/partnerio"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// maxImportSize limits the size of import files, which are far larger than other request bodies.
+const maxImportSize = 10 << 20
+
+// importContentTypes maps the media types of import files to their format.
+var importContentTypes = map[string]string{
+	"text/csv":             partnerio.FormatCSV,
+	"application/x-ndjson": partnerio.FormatJSONL,
+	"application/jsonl":    partnerio.FormatJSONL,
+}
+
+// ImportPartners creates or updates partners from a CSV or JSON Lines file in the request body. The format is taken
+// from the 'format' parameter or else the content type. Rows which cannot be imported are reported in the response,
+// the file as a whole is only rejected when it cannot be read.
+func (a *PartnerAPI) ImportPartners(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "importPartners")
+	params := r.URL.Query()
+	format := params.Get("format")
+	if !params.Has("format") {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format = importContentTypes[mediaType]
+	}
+	if format == "" {
+		http.Error(w, fmt.Sprintf("Bad request: %s", ErrMissingArgument("format")), http.StatusBadRequest)
+		return
+	}
+	if !stringInSlice(format, partnerio.Formats) {
+		http.Error(w, fmt.Sprintf("Bad request: %s", ErrInvalidInput("format")), http.StatusBadRequest)
+		return
+	}
+	dryRun, err := strconv.ParseBool(params.Get("dry_run"))
+	if params.Has("dry_run") && err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: %s", ErrInvalidInput("dry_run")), http.StatusBadRequest)
+		return
+	}
+	rows, err := partnerio.Read(http.MaxBytesReader(w, r.Body, maxImportSize), format)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		http.Error(w, "Bad request: file too large", http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, partnerio.ErrInvalidFile) {
+		http.Error(w, fmt.Sprintf("Bad request: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger, "reading partners failed", err)
+		return
+	}
+	result, err := a.service.ImportPartners(r.Context(), rows, dryRun)
+	if writeValidationError(w, err) {
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger, "importing partners failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// ExportPartners returns all partners, including suspended and deleted partners, as a CSV or JSON Lines file which
+// can be imported again. The format defaults to CSV.
+func (a *PartnerAPI) ExportPartners(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "exportPartners")
+	format := partnerio.FormatCSV
+	if r.URL.Query().Has("format") {
+		format = r.URL.Query().Get("format")
+	}
+	if !stringInSlice(format, partnerio.Formats) {
+		http.Error(w, fmt.Sprintf("Bad request: %s", ErrInvalidInput("format")), http.StatusBadRequest)
+		return
+	}
+	partners, err := a.service.GetAllPartners(r.Context())
+	if err != nil {
+		writeServiceError(w, logger, "getting partners failed", err)
+		return
+	}
+	// The file is written to a buffer first, so that a failure is answered with an error rather than a truncated file.
+	var file bytes.Buffer
+	if err := partnerio.Write(&file, format, partners); err != nil {
+		writeServiceError(w, logger, "writing partners failed", err)
+		return
+	}
+	w.Header().Set("Content-Type", partnerio.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"partners.%s\"", format))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = file.WriteTo(w)
+}
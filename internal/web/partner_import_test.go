@@ -0,0 +1,152 @@
+package web_test
+
+import (
+	"customer-partner/internal/auth/authtest"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/web"
+	"customer-partner/internal/web/mocks"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartnerAPI_ImportPartners(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	csvFile := "external_id,name,experienced_material,latitude,longitude,operating_radius\nSF-1,Floors,wood,48.1,11.6,10\n"
+	jsonlFile := `{"external_id":"SF-1","name":"Floors"}` + "\n"
+	type testCase struct {
+		name        string
+		auth        string
+		query       string
+		contentType string
+		body        string
+		expImport   bool
+		expDryRun   bool
+		serviceErr  error
+		expStatus   int
+	}
+	tests := []testCase{
+		{name: "Returns 403 for partner", auth: keys.PartnerToken(t, "partner", "123"), query: "?format=csv", body: csvFile, expStatus: http.StatusForbidden},
+		{name: "Returns 400 without format", auth: keys.AdminToken(t, "admin"), body: csvFile, expStatus: http.StatusBadRequest},
+		{name: "Returns 400 on unknown format", auth: keys.AdminToken(t, "admin"), query: "?format=xlsx", body: csvFile, expStatus: http.StatusBadRequest},
+		{name: "Returns 400 on invalid 'dry_run'", auth: keys.AdminToken(t, "admin"), query: "?format=csv&dry_run=maybe", body: csvFile, expStatus: http.StatusBadRequest},
+		{name: "Returns 400 on unknown column", auth: keys.AdminToken(t, "admin"), query: "?format=csv", body: "external_id,shoe_size\n", expStatus: http.StatusBadRequest},
+		{name: "Imports csv", auth: keys.AdminToken(t, "admin"), query: "?format=csv", body: csvFile, expImport: true, expStatus: http.StatusOK},
+		{name: "Imports in dry run", auth: keys.AdminToken(t, "admin"), query: "?format=csv&dry_run=true", body: csvFile, expImport: true, expDryRun: true, expStatus: http.StatusOK},
+		{
+			name:        "Takes format from content type",
+			auth:        keys.AdminToken(t, "admin"),
+			contentType: "application/x-ndjson",
+			body:        jsonlFile,
+			expImport:   true,
+			expStatus:   http.StatusOK,
+		},
+		{
+			name:       "Returns 400 on too many rows",
+			auth:       keys.AdminToken(t, "admin"),
+			query:      "?format=csv",
+			body:       csvFile,
+			expImport:  true,
+			serviceErr: &domain.ValidationError{Field: "rows", Reason: "at most 10000 rows allowed"},
+			expStatus:  http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.PartnerService{}
+			result := domain.ImportResult{DryRun: tt.expDryRun, Created: 1, Errors: []domain.ImportError{}}
+			if tt.expImport {
+				hasRow := mock.MatchedBy(func(rows []domain.ImportRow) bool {
+					return len(rows) == 1 && rows[0].Partner.ExternalID == "SF-1"
+				})
+				service.On("ImportPartners", mock.Anything, hasRow, tt.expDryRun).Return(result, tt.serviceErr)
+			}
+			api := newAPI(web.Services{Partners: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodPost, "/partners/imports"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			service.AssertExpectations(t)
+			if tt.expStatus != http.StatusOK {
+				return
+			}
+			var actual domain.ImportResult
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&actual))
+			assert.Equal(t, result, actual)
+		})
+	}
+}
+
+func TestPartnerAPI_ExportPartners(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	partners := []entities.Partner{{ID: "1", ExternalID: "SF-1", Name: "Floors", ExperiencedMaterial: []string{"wood"}}}
+	type testCase struct {
+		name           string
+		auth           string
+		query          string
+		expExport      bool
+		expStatus      int
+		expContentType string
+		expBody        string
+	}
+	tests := []testCase{
+		{name: "Returns 403 for partner", auth: keys.PartnerToken(t, "partner", "1"), expStatus: http.StatusForbidden},
+		{name: "Returns 400 on unknown format", auth: keys.AdminToken(t, "admin"), query: "?format=xlsx", expStatus: http.StatusBadRequest},
+		{
+			name:           "Exports csv by default",
+			auth:           keys.AdminToken(t, "admin"),
+			expExport:      true,
+			expStatus:      http.StatusOK,
+			expContentType: "text/csv; charset=utf-8",
+			expBody:        "1,SF-1,Floors,wood,0,0",
+		},
+		{
+			name:           "Exports json lines",
+			auth:           keys.AdminToken(t, "admin"),
+			query:          "?format=jsonl",
+			expExport:      true,
+			expStatus:      http.StatusOK,
+			expContentType: "application/x-ndjson",
+			expBody:        `{"id":"1","external_id":"SF-1","name":"Floors"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.PartnerService{}
+			if tt.expExport {
+				service.On("GetAllPartners", mock.Anything).Return(partners, nil)
+			}
+			api := newAPI(web.Services{Partners: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodGet, "/partners/exports"+tt.query, nil)
+			req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			service.AssertExpectations(t)
+			if tt.expStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tt.expContentType, rec.Header().Get("Content-Type"))
+			assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+			assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+			assert.Contains(t, rec.Body.String(), tt.expBody)
+		})
+	}
+}
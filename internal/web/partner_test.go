@@ -1,21 +1,45 @@
 package web_test
 
 import (
+	"context"
+	"customer-partner/internal/auth"
+	"customer-partner/internal/auth/authtest"
 	"customer-partner/internal/domain"
 	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"customer-partner/internal/privacy"
 	"customer-partner/internal/web"
 	"customer-partner/internal/web/mocks"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 //go:generate mockery --name PartnerService
 
+var obfuscator = privacy.NewObfuscator([]byte("test"), privacy.DefaultDecimals)
+
+// newAPI creates the api without rate limits.
+func newAPI(services web.Services, authenticator *auth.Authenticator) *web.PartnerAPI {
+	return web.NewPartnerAPI(services, authenticator, obfuscator, nil, logging.Discard())
+}
+
+// public returns the partner as shown to anonymous callers.
+func public(p entities.Partner) entities.Partner {
+	p.Address = obfuscator.Obfuscate(p.ID, p.Address)
+	p.Contact = nil
+	return p
+}
+
 func TestPartnerAPI_GetPartner(t *testing.T) {
 	type testCase struct {
 		name           string
@@ -31,7 +55,7 @@ func TestPartnerAPI_GetPartner(t *testing.T) {
 			serviceReturn2: nil,
 			expStatus:      http.StatusOK,
 			expBody: func() string {
-				body, _ := json.Marshal(entities.Partner{})
+				body, _ := json.Marshal(public(entities.Partner{}))
 				return fmt.Sprintf("%s\n", body)
 			},
 		},
@@ -49,8 +73,8 @@ func TestPartnerAPI_GetPartner(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			service := &mocks.PartnerService{}
-			service.On("GetPartner", "123").Return(tt.serviceReturn1, tt.serviceReturn2)
-			api := web.NewPartnerAPI(service)
+			service.On("GetPartner", mock.Anything, "123").Return(tt.serviceReturn1, tt.serviceReturn2)
+			api := newAPI(web.Services{Partners: service}, auth.NewAuthenticator(nil, nil))
 
 			assert.HTTPStatusCode(t, api.GetPartner, http.MethodGet, "/partners/123", nil, tt.expStatus)
 			assert.HTTPBodyContains(t, api.GetPartner, http.MethodGet, "/partners/123", nil, tt.expBody())
@@ -59,15 +83,73 @@ func TestPartnerAPI_GetPartner(t *testing.T) {
 	}
 }
 
+func TestPartnerAPI_GetPartner_Privacy(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	stored := entities.Partner{
+		ID:                  "123",
+		Name:                "Floors",
+		ExperiencedMaterial: []string{"wood"},
+		Address:             entities.Address{Latitude: 48.13743, Longitude: 11.57549, City: "München", District: "Altstadt"},
+		OperatingRadius:     10,
+		Rating:              4,
+		Contact:             &entities.Contact{Email: "info@floors.example"},
+	}
+	type testCase struct {
+		name       string
+		auth       string
+		expPartner entities.Partner
+	}
+	tests := []testCase{
+		{name: "Blurs address for anonymous caller", expPartner: public(stored)},
+		{name: "Blurs address for customer", auth: keys.CustomerToken(t, "customer"), expPartner: public(stored)},
+		{name: "Blurs address for other partner", auth: keys.PartnerToken(t, "partner", "234"), expPartner: public(stored)},
+		{name: "Shows exact address to partner", auth: keys.PartnerToken(t, "partner", "123"), expPartner: stored},
+		{name: "Shows exact address to admin", auth: keys.AdminToken(t, "admin"), expPartner: stored},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.PartnerService{}
+			service.On("GetPartner", mock.Anything, "123").Return(stored, nil)
+			api := newAPI(web.Services{Partners: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodGet, "/partners/123", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			}
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Header().Get("Vary"), "Authorization")
+			expBody, _ := json.Marshal(tt.expPartner)
+			assert.JSONEq(t, string(expBody), rec.Body.String())
+			service.AssertExpectations(t)
+		})
+	}
+	assert.NotEqual(t, stored.Address, public(stored).Address)
+}
+
 func TestPartnerAPI_GetPartners(t *testing.T) {
 	type testCase struct {
 		name           string
 		urlValues      url.Values
 		serviceReturn  []entities.Partner
+		serviceErr     error
 		expServiceCall bool
+		expOpts        *domain.GetPartnersOpts
 		expStatus      int
 		expBody        func() string
 	}
+	estimated := entities.Partner{
+		ID:         "123",
+		PriceLists: []entities.PriceList{{Material: "wood", MinPerSquareMeter: 3000, MaxPerSquareMeter: 4000}},
+		PriceEstimate: &entities.PriceEstimate{
+			Currency: "EUR",
+			Min:      360000,
+			Max:      480000,
+		},
+	}
 	tests := []testCase{
 		{
 			name:           "Returns 400 on missing query parameter 'material'",
@@ -137,6 +219,173 @@ func TestPartnerAPI_GetPartners(t *testing.T) {
 			expStatus:      http.StatusBadRequest,
 			expBody:        func() string { return "Bad request: invalid input for parameter lat\n" },
 		},
+		{
+			name: "Returns 400 on out of bounds for query parameter 'floor_size'",
+			urlValues: url.Values{
+				"material":   []string{"wood"},
+				"long":       []string{"80.123"},
+				"lat":        []string{"42.125"},
+				"floor_size": []string{"0"},
+			},
+			expServiceCall: false,
+			expStatus:      http.StatusBadRequest,
+			expBody:        func() string { return "Bad request: invalid input for parameter floor_size\n" },
+		},
+		{
+			name: "Returns 400 on invalid input for query parameter 'sort'",
+			urlValues: url.Values{
+				"material": []string{"wood"},
+				"long":     []string{"80.123"},
+				"lat":      []string{"42.125"},
+				"sort":     []string{"cheapest"},
+			},
+			expServiceCall: false,
+			expStatus:      http.StatusBadRequest,
+			expBody:        func() string { return "Bad request: invalid input for parameter sort\n" },
+		},
+		{
+			name: "Returns 400 on sort by price without 'floor_size'",
+			urlValues: url.Values{
+				"material": []string{"wood"},
+				"long":     []string{"80.123"},
+				"lat":      []string{"42.125"},
+				"sort":     []string{"price"},
+			},
+			expServiceCall: false,
+			expStatus:      http.StatusBadRequest,
+			expBody:        func() string { return "Bad request: parameter floor_size missing\n" },
+		},
+		{
+			name: "Returns 200 with price estimates sorted by price",
+			urlValues: url.Values{
+				"material":   []string{"wood"},
+				"long":       []string{"80.123"},
+				"lat":        []string{"42.125"},
+				"floor_size": []string{"120"},
+				"sort":       []string{"price"},
+			},
+			serviceReturn:  []entities.Partner{estimated},
+			expServiceCall: true,
+			expOpts: &domain.GetPartnersOpts{
+				Material:            "wood",
+				CustomerAddressLong: 80.123,
+				CustomerAddressLat:  42.125,
+				FloorSize:           120,
+				SortBy:              domain.SortByPrice,
+			},
+			expStatus: http.StatusOK,
+			expBody: func() string {
+				body, _ := json.Marshal([]entities.Partner{public(estimated)})
+				return fmt.Sprintf("%s\n", body)
+			},
+		},
+		{
+			name: "Returns 400 on 'available_from' without 'available_until'",
+			urlValues: url.Values{
+				"material":       []string{"wood"},
+				"long":           []string{"80.123"},
+				"lat":            []string{"42.125"},
+				"available_from": []string{"2024-05-06T08:00:00Z"},
+			},
+			expServiceCall: false,
+			expStatus:      http.StatusBadRequest,
+			expBody:        func() string { return "Bad request: parameter available_until missing\n" },
+		},
+		{
+			name: "Returns 400 on 'available_until' before 'available_from'",
+			urlValues: url.Values{
+				"material":        []string{"wood"},
+				"long":            []string{"80.123"},
+				"lat":             []string{"42.125"},
+				"available_from":  []string{"2024-05-06T08:00:00Z"},
+				"available_until": []string{"2024-05-06T07:00:00Z"},
+			},
+			expServiceCall: false,
+			expStatus:      http.StatusBadRequest,
+			expBody:        func() string { return "Bad request: invalid input for parameter available_until\n" },
+		},
+		{
+			name: "Returns 400 on invalid input for query parameter 'available_from'",
+			urlValues: url.Values{
+				"material":        []string{"wood"},
+				"long":            []string{"80.123"},
+				"lat":             []string{"42.125"},
+				"available_from":  []string{"monday"},
+				"available_until": []string{"2024-05-06T07:00:00Z"},
+			},
+			expServiceCall: false,
+			expStatus:      http.StatusBadRequest,
+			expBody:        func() string { return "Bad request: invalid input for parameter available_from\n" },
+		},
+		{
+			name: "Returns 400 on invalid input for query parameter 'include_unverified'",
+			urlValues: url.Values{
+				"material":           []string{"wood"},
+				"long":               []string{"80.123"},
+				"lat":                []string{"42.125"},
+				"include_unverified": []string{"sometimes"},
+			},
+			expServiceCall: false,
+			expStatus:      http.StatusBadRequest,
+			expBody:        func() string { return "Bad request: invalid input for parameter include_unverified\n" },
+		},
+		{
+			name: "Returns 403 on 'include_unverified' for public caller",
+			urlValues: url.Values{
+				"material":           []string{"wood"},
+				"long":               []string{"80.123"},
+				"lat":                []string{"42.125"},
+				"include_unverified": []string{"true"},
+			},
+			expServiceCall: false,
+			expStatus:      http.StatusForbidden,
+			expBody:        func() string { return "Forbidden" },
+		},
+		{
+			name: "Returns 400 on invalid input for query parameter 'include_inactive'",
+			urlValues: url.Values{
+				"material":         []string{"wood"},
+				"long":             []string{"80.123"},
+				"lat":              []string{"42.125"},
+				"include_inactive": []string{"sometimes"},
+			},
+			expServiceCall: false,
+			expStatus:      http.StatusBadRequest,
+			expBody:        func() string { return "Bad request: invalid input for parameter include_inactive\n" },
+		},
+		{
+			name: "Returns 403 on 'include_inactive' for public caller",
+			urlValues: url.Values{
+				"material":         []string{"wood"},
+				"long":             []string{"80.123"},
+				"lat":              []string{"42.125"},
+				"include_inactive": []string{"true"},
+			},
+			expServiceCall: false,
+			expStatus:      http.StatusForbidden,
+			expBody:        func() string { return "Forbidden" },
+		},
+		{
+			name: "Returns 200 for partners available in window",
+			urlValues: url.Values{
+				"material":        []string{"wood"},
+				"long":            []string{"80.123"},
+				"lat":             []string{"42.125"},
+				"available_from":  []string{"2024-05-06T08:00:00+02:00"},
+				"available_until": []string{"2024-05-10T18:00:00+02:00"},
+			},
+			serviceReturn:  []entities.Partner{},
+			expServiceCall: true,
+			expOpts: &domain.GetPartnersOpts{
+				Material:            "wood",
+				CustomerAddressLong: 80.123,
+				CustomerAddressLat:  42.125,
+				AvailableFrom:       time.Date(2024, 5, 6, 6, 0, 0, 0, time.UTC),
+				AvailableUntil:      time.Date(2024, 5, 10, 16, 0, 0, 0, time.UTC),
+			},
+			expStatus: http.StatusOK,
+			expBody:   func() string { return "[]\n" },
+		},
 		{
 			name: "Returns 200 with valid body on empty list",
 			urlValues: url.Values{
@@ -160,23 +409,51 @@ func TestPartnerAPI_GetPartners(t *testing.T) {
 			expServiceCall: true,
 			expStatus:      http.StatusOK,
 			expBody: func() string {
-				body, _ := json.Marshal([]entities.Partner{{}})
+				body, _ := json.Marshal([]entities.Partner{public(entities.Partner{})})
 				return fmt.Sprintf("%s\n", body)
 			},
 		},
+		{
+			name: "Returns 500 on service error",
+			urlValues: url.Values{
+				"material": []string{"wood"},
+				"long":     []string{"80.123"},
+				"lat":      []string{"42.125"},
+			},
+			serviceErr:     errors.New("connection lost"),
+			expServiceCall: true,
+			expStatus:      http.StatusInternalServerError,
+			expBody:        func() string { return "Internal server error\n" },
+		},
+		{
+			name: "Returns 503 when request was canceled",
+			urlValues: url.Values{
+				"material": []string{"wood"},
+				"long":     []string{"80.123"},
+				"lat":      []string{"42.125"},
+			},
+			serviceErr:     context.Canceled,
+			expServiceCall: true,
+			expStatus:      http.StatusServiceUnavailable,
+			expBody:        func() string { return "Service unavailable\n" },
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			service := &mocks.PartnerService{}
 			if tt.expServiceCall {
-				service.On("GetPartners", domain.GetPartnersOpts{
+				opts := domain.GetPartnersOpts{
 					Material:            "wood",
 					CustomerAddressLong: 80.123,
 					CustomerAddressLat:  42.125,
-				}).Return(tt.serviceReturn)
+				}
+				if tt.expOpts != nil {
+					opts = *tt.expOpts
+				}
+				service.On("GetPartners", mock.Anything, opts).Return(tt.serviceReturn, tt.serviceErr)
 			}
-			api := web.NewPartnerAPI(service)
+			api := newAPI(web.Services{Partners: service}, auth.NewAuthenticator(nil, nil))
 
 			assert.HTTPStatusCode(t, api.GetPartners, http.MethodGet, "/partners", tt.urlValues, tt.expStatus)
 			assert.HTTPBodyContains(t, api.GetPartners, http.MethodGet, "/partners", tt.urlValues, tt.expBody())
@@ -184,3 +461,323 @@ func TestPartnerAPI_GetPartners(t *testing.T) {
 		})
 	}
 }
+
+func TestPartnerAPI_GetPartners_IncludeUnverified(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	service := &mocks.PartnerService{}
+	service.On("GetPartners", mock.Anything, domain.GetPartnersOpts{
+		Material:            "wood",
+		CustomerAddressLong: 80.123,
+		CustomerAddressLat:  42.125,
+		IncludeUnverified:   true,
+	}).Return([]entities.Partner{{ID: "1", VerificationStatus: entities.VerificationPending}}, nil)
+	api := newAPI(web.Services{Partners: service}, keys.Authenticator(t))
+	req := httptest.NewRequest(http.MethodGet, "/partners?material=wood&long=80.123&lat=42.125&include_unverified=true", nil)
+	req.Header.Set("Authorization", authtest.Bearer(keys.AdminToken(t, "admin")))
+	rec := httptest.NewRecorder()
+
+	api.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"verification_status":"pending"`)
+	service.AssertExpectations(t)
+}
+
+func TestPartnerAPI_UpdatePartner(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	stored := entities.Partner{
+		ID:                  "123",
+		Name:                "Floors",
+		ExperiencedMaterial: []string{"wood"},
+		Address:             entities.Address{Latitude: 48.1, Longitude: 11.6},
+		OperatingRadius:     10,
+		Rating:              4,
+	}
+	body := func(radius, rating int) string {
+		return fmt.Sprintf(`{"name":"Floors","experienced_material":["wood"],`+
+			`"address":{"latitude":48.1,"longitude":11.6},"operating_radius":%d,"rating":%d}`, radius, rating)
+	}
+	updated := stored
+	updated.OperatingRadius = 20
+	type testCase struct {
+		name          string
+		auth          string
+		id            string
+		body          string
+		expGet        bool
+		getErr        error
+		expUpdate     *entities.Partner
+		updateErr     error
+		expStatus     int
+		expBodyPrefix string
+	}
+	tests := []testCase{
+		{
+			name:      "Returns 401 for anonymous caller",
+			id:        "123",
+			body:      body(20, 4),
+			expStatus: http.StatusUnauthorized,
+		},
+		{
+			name:      "Returns 403 for customer",
+			auth:      keys.CustomerToken(t, "customer"),
+			id:        "123",
+			body:      body(20, 4),
+			expStatus: http.StatusForbidden,
+		},
+		{
+			name:      "Returns 403 for partner updating other partner",
+			auth:      keys.PartnerToken(t, "partner", "234"),
+			id:        "123",
+			body:      body(20, 4),
+			expStatus: http.StatusForbidden,
+		},
+		{
+			name:      "Returns 403 for partner changing own rating",
+			auth:      keys.PartnerToken(t, "partner", "123"),
+			id:        "123",
+			body:      body(20, 5),
+			expGet:    true,
+			expStatus: http.StatusForbidden,
+		},
+		{
+			name: "Returns 403 for partner verifying themselves",
+			auth: keys.PartnerToken(t, "partner", "123"),
+			id:   "123",
+			body: `{"name":"Floors","experienced_material":["wood"],"address":{"latitude":48.1,"longitude":11.6},` +
+				`"operating_radius":10,"verification_status":"verified"}`,
+			expGet:    true,
+			expStatus: http.StatusForbidden,
+		},
+		{
+			name:          "Returns 200 for partner updating own record",
+			auth:          keys.PartnerToken(t, "partner", "123"),
+			id:            "123",
+			body:          body(20, 4),
+			expGet:        true,
+			expUpdate:     &updated,
+			expStatus:     http.StatusOK,
+			expBodyPrefix: `{"id":"123","name":"Floors"`,
+		},
+		{
+			name:          "Returns 200 for admin changing rating",
+			auth:          keys.AdminToken(t, "admin"),
+			id:            "123",
+			body:          body(10, 2),
+			expGet:        true,
+			expUpdate:     &entities.Partner{ID: "123", Name: "Floors", ExperiencedMaterial: []string{"wood"}, Address: stored.Address, OperatingRadius: 10, Rating: 2},
+			expStatus:     http.StatusOK,
+			expBodyPrefix: `{"id":"123"`,
+		},
+		{
+			name:          "Returns 400 on missing attribute",
+			auth:          keys.AdminToken(t, "admin"),
+			id:            "123",
+			body:          `{"name":"Floors"}`,
+			expStatus:     http.StatusBadRequest,
+			expBodyPrefix: "Bad request: parameter experienced_material missing",
+		},
+		{
+			name:          "Returns 400 on attribute of wrong type",
+			auth:          keys.AdminToken(t, "admin"),
+			id:            "123",
+			body:          `{"name":"Floors","operating_radius":"far"}`,
+			expStatus:     http.StatusBadRequest,
+			expBodyPrefix: "Bad request: invalid input for parameter operating_radius",
+		},
+		{
+			name:          "Returns 400 on validation error",
+			auth:          keys.AdminToken(t, "admin"),
+			id:            "123",
+			body:          body(0, 4),
+			expGet:        true,
+			expUpdate:     &entities.Partner{ID: "123", Name: "Floors", ExperiencedMaterial: []string{"wood"}, Address: stored.Address, OperatingRadius: 0, Rating: 4},
+			updateErr:     &domain.ValidationError{Field: "operating_radius", Reason: "must be positive"},
+			expStatus:     http.StatusBadRequest,
+			expBodyPrefix: "Bad request: invalid input for parameter operating_radius",
+		},
+		{
+			name:      "Returns 404 for unknown partner",
+			auth:      keys.AdminToken(t, "admin"),
+			id:        "123",
+			body:      body(20, 4),
+			expGet:    true,
+			getErr:    entities.ErrRecordNotExist,
+			expStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.PartnerService{}
+			if tt.expGet {
+				service.On("GetPartner", mock.Anything, tt.id).Return(stored, tt.getErr)
+			}
+			if tt.expUpdate != nil {
+				service.On("UpdatePartner", mock.Anything, *tt.expUpdate).Return(*tt.expUpdate, tt.updateErr)
+			}
+			api := newAPI(web.Services{Partners: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodPut, "/partners/"+tt.id, strings.NewReader(tt.body))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			}
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			assert.True(t, strings.HasPrefix(rec.Body.String(), tt.expBodyPrefix), rec.Body.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPartnerAPI_UpdatePartner_CertificationFlags(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	expires := time.Date(2099, 6, 30, 0, 0, 0, 0, time.UTC)
+	flagged := time.Date(2099, 6, 1, 0, 0, 0, 0, time.UTC)
+	stored := entities.Partner{
+		ID:                  "123",
+		Name:                "Floors",
+		ExperiencedMaterial: []string{"wood"},
+		Address:             entities.Address{Latitude: 48.1, Longitude: 11.6},
+		OperatingRadius:     10,
+		Certifications:      []entities.Certification{{Type: "FSC", Issuer: "FSC", ExpiresAt: expires, FlaggedAt: &flagged}},
+	}
+	updated := stored
+	updated.Certifications = []entities.Certification{
+		{Type: "FSC", Issuer: "FSC", ExpiresAt: expires, FlaggedAt: &flagged},
+		{Type: "Master", Issuer: "HWK", ExpiresAt: expires},
+	}
+	service := &mocks.PartnerService{}
+	service.On("GetPartner", mock.Anything, "123").Return(stored, nil)
+	service.On("UpdatePartner", mock.Anything, updated).Return(updated, nil)
+	api := newAPI(web.Services{Partners: service}, keys.Authenticator(t))
+	body := `{"name":"Floors","experienced_material":["wood"],"address":{"latitude":48.1,"longitude":11.6},` +
+		`"operating_radius":10,"certifications":[` +
+		`{"type":"FSC","issuer":"FSC","expires_at":"2099-06-30T00:00:00Z"},` +
+		`{"type":"Master","issuer":"HWK","expires_at":"2099-06-30T00:00:00Z","flagged_at":"2099-06-01T00:00:00Z"}]}`
+	req := httptest.NewRequest(http.MethodPut, "/partners/123", strings.NewReader(body))
+	req.Header.Set("Authorization", authtest.Bearer(keys.PartnerToken(t, "partner", "123")))
+	rec := httptest.NewRecorder()
+
+	api.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	service.AssertExpectations(t)
+}
+
+func TestPartnerAPI_UpdatePartner_Vetting(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	expires := time.Date(2099, 6, 30, 0, 0, 0, 0, time.UTC)
+	stored := entities.Partner{
+		ID:                  "123",
+		Name:                "Floors",
+		ExperiencedMaterial: []string{"wood"},
+		Address:             entities.Address{Latitude: 48.1, Longitude: 11.6},
+		OperatingRadius:     10,
+		VerificationStatus:  entities.VerificationVerified,
+		Certifications:      []entities.Certification{{Type: "FSC", Issuer: "FSC", ExpiresAt: expires}},
+		InsuranceExpiresAt:  &expires,
+	}
+	body := func(certificationExpiry, insuranceExpiry string) string {
+		return `{"name":"Floors","experienced_material":["wood"],"address":{"latitude":48.1,"longitude":11.6},` +
+			`"operating_radius":10,"certifications":[{"type":"FSC","issuer":"FSC","expires_at":"` +
+			certificationExpiry + `"}],"insurance_expires_at":"` + insuranceExpiry + `"}`
+	}
+	type testCase struct {
+		name      string
+		auth      string
+		body      string
+		expStatus string
+	}
+	tests := []testCase{
+		{
+			name:      "Keeps verification of partner not changing vetted attributes",
+			auth:      keys.PartnerToken(t, "partner", "123"),
+			body:      body("2099-06-30T00:00:00Z", "2099-06-30T00:00:00Z"),
+			expStatus: entities.VerificationVerified,
+		},
+		{
+			name:      "Resets verification of partner changing certifications",
+			auth:      keys.PartnerToken(t, "partner", "123"),
+			body:      body("2100-06-30T00:00:00Z", "2099-06-30T00:00:00Z"),
+			expStatus: entities.VerificationPending,
+		},
+		{
+			name:      "Resets verification of partner changing insurance expiry",
+			auth:      keys.PartnerToken(t, "partner", "123"),
+			body:      body("2099-06-30T00:00:00Z", "2100-06-30T00:00:00Z"),
+			expStatus: entities.VerificationPending,
+		},
+		{
+			name:      "Keeps verification of admin changing certifications",
+			auth:      keys.AdminToken(t, "admin"),
+			body:      body("2100-06-30T00:00:00Z", "2100-06-30T00:00:00Z"),
+			expStatus: entities.VerificationVerified,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.PartnerService{}
+			service.On("GetPartner", mock.Anything, "123").Return(stored, nil)
+			var updated entities.Partner
+			service.On("UpdatePartner", mock.Anything, mock.AnythingOfType("entities.Partner")).
+				Run(func(args mock.Arguments) { updated = args.Get(1).(entities.Partner) }).
+				Return(stored, nil)
+			api := newAPI(web.Services{Partners: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodPut, "/partners/123", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			assert.Equal(t, tt.expStatus, updated.VerificationStatus)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPartnerAPI_CreatePartner(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	input := entities.Partner{
+		Name:                "Floors",
+		ExperiencedMaterial: []string{"tiles"},
+		Address:             entities.Address{Latitude: 48.1, Longitude: 11.6},
+		OperatingRadius:     10,
+	}
+	created := input
+	created.ID = "abc"
+	body := `{"name":"Floors","experienced_material":["tiles"],"address":{"latitude":48.1,"longitude":11.6},"operating_radius":10}`
+
+	t.Run("Returns 403 for partner", func(t *testing.T) {
+		service := &mocks.PartnerService{}
+		api := newAPI(web.Services{Partners: service}, keys.Authenticator(t))
+		req := httptest.NewRequest(http.MethodPost, "/partners", strings.NewReader(body))
+		req.Header.Set("Authorization", authtest.Bearer(keys.PartnerToken(t, "partner", "abc")))
+		rec := httptest.NewRecorder()
+
+		api.Handler().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		service.AssertExpectations(t)
+	})
+	t.Run("Returns 201 for admin with api key", func(t *testing.T) {
+		service := &mocks.PartnerService{}
+		service.On("CreatePartner", mock.Anything, input).Return(created, nil)
+		api := newAPI(web.Services{Partners: service}, keys.Authenticator(t))
+		req := httptest.NewRequest(http.MethodPost, "/partners", strings.NewReader(body))
+		req.Header.Set(auth.APIKeyHeader, authtest.AdminAPIKey)
+		rec := httptest.NewRecorder()
+
+		api.Handler().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "/partners/abc", rec.Header().Get("Location"))
+		expBody, _ := json.Marshal(created)
+		assert.JSONEq(t, string(expBody), rec.Body.String())
+		service.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,216 @@
+package web
+
+import (
+	"context"
+	"customer-partner/internal/auth"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type QuoteService interface {
+	CreateQuote(ctx context.Context, quote entities.Quote) (entities.Quote, error)
+	GetQuote(ctx context.Context, id string) (entities.Quote, error)
+	GetQuotesByOfferRequest(ctx context.Context, offerRequestID string) ([]entities.Quote, error)
+	DecideQuote(ctx context.Context, id, decision string) (entities.Quote, error)
+}
+
+// quoteBody is the request body of a quote. Pointers distinguish missing from zero values.
+type quoteBody struct {
+	Currency   *string                  `json:"currency"`
+	LineItems  []entities.QuoteLineItem `json:"line_items"`
+	VATRate    *int                     `json:"vat_rate"`
+	ValidUntil *time.Time               `json:"valid_until"`
+	Notes      string                   `json:"notes"`
+}
+
+// decisionBody is the request body of the decision of a customer on a quote.
+type decisionBody struct {
+	Decision *string `json:"decision"`
+}
+
+// CreateQuote stores the quote of a partner for an offer request sent to them. The amounts are calculated from the
+// line items, a pending quote sent before is superseded.
+func (a *PartnerAPI) CreateQuote(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "createQuote")
+	offerRequestID, _ := quotePath(r)
+	logger = logger.With("offer_request_id", offerRequestID)
+	var body quoteBody
+	err := decodeJSON(w, r, &body)
+	switch {
+	case err != nil:
+	case body.Currency == nil:
+		err = ErrMissingArgument("currency")
+	case body.LineItems == nil:
+		err = ErrMissingArgument("line_items")
+	case body.VATRate == nil:
+		err = ErrMissingArgument("vat_rate")
+	case body.ValidUntil == nil:
+		err = ErrMissingArgument("valid_until")
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	if _, ok := a.quotedOfferRequest(w, r, offerRequestID, canQuote); !ok {
+		return
+	}
+	quote, err := a.quotes.CreateQuote(r.Context(), entities.Quote{
+		OfferRequestID: offerRequestID,
+		Currency:       *body.Currency,
+		LineItems:      body.LineItems,
+		VATRate:        *body.VATRate,
+		ValidUntil:     body.ValidUntil.UTC(),
+		Notes:          body.Notes,
+	})
+	if writeValidationError(w, err) {
+		return
+	}
+	if errors.Is(err, entities.ErrOfferRequestClosed) {
+		http.Error(w, "Conflict: offer request closed", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger, "creating quote failed", err)
+		return
+	}
+	w.Header().Set("Location", "/offer_requests/"+offerRequestID+"/quotes/"+quote.ID)
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusCreated, quote)
+}
+
+// GetQuotes lists the quotes of an offer request, oldest first. The partner of the offer request and the customer who
+// created it may read them.
+func (a *PartnerAPI) GetQuotes(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "getQuotes")
+	offerRequestID, _ := quotePath(r)
+	if _, ok := a.quotedOfferRequest(w, r, offerRequestID, canReadQuotes); !ok {
+		return
+	}
+	quotes, err := a.quotes.GetQuotesByOfferRequest(r.Context(), offerRequestID)
+	if err != nil {
+		writeServiceError(w, logger.With("offer_request_id", offerRequestID), "getting quotes failed", err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, quotes)
+}
+
+// GetQuote returns a quote of an offer request. The partner of the offer request and the customer who created it may
+// read it.
+func (a *PartnerAPI) GetQuote(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "getQuote")
+	offerRequestID, quoteID := quotePath(r)
+	if _, ok := a.quotedOfferRequest(w, r, offerRequestID, canReadQuotes); !ok {
+		return
+	}
+	quote, err := a.quotes.GetQuote(r.Context(), quoteID)
+	if errors.Is(err, entities.ErrRecordNotExist) || err == nil && quote.OfferRequestID != offerRequestID {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger.With("quote_id", quoteID), "getting quote failed", err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, quote)
+}
+
+// DecideQuote stores whether the customer accepts or declines a pending quote. Only the customer who created the
+// offer request decides, accepting a quote orders the offer request.
+func (a *PartnerAPI) DecideQuote(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "decideQuote")
+	offerRequestID, quoteID := quotePath(r)
+	logger = logger.With("quote_id", quoteID)
+	var body decisionBody
+	err := decodeJSON(w, r, &body)
+	if err == nil && body.Decision == nil {
+		err = ErrMissingArgument("decision")
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	if _, ok := a.quotedOfferRequest(w, r, offerRequestID, canDecideQuotes); !ok {
+		return
+	}
+	quote, err := a.quotes.GetQuote(r.Context(), quoteID)
+	if errors.Is(err, entities.ErrRecordNotExist) || err == nil && quote.OfferRequestID != offerRequestID {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger, "getting quote failed", err)
+		return
+	}
+	quote, err = a.quotes.DecideQuote(r.Context(), quoteID, *body.Decision)
+	if writeValidationError(w, err) {
+		return
+	}
+	if errors.Is(err, entities.ErrQuoteNotPending) {
+		http.Error(w, "Conflict: quote not pending", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger, "deciding quote failed", err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, quote)
+}
+
+// quotedOfferRequest returns the offer request the quotes belong to. It responds with 404 when the offer request does
+// not exist, with 403 when allowed rejects the caller and reports false in both cases.
+func (a *PartnerAPI) quotedOfferRequest(
+	w http.ResponseWriter,
+	r *http.Request,
+	id string,
+	allowed func(principal auth.Principal, request entities.OfferRequest) bool,
+) (entities.OfferRequest, bool) {
+	request, err := a.offerRequests.GetOfferRequest(r.Context(), id)
+	if errors.Is(err, entities.ErrRecordNotExist) {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return entities.OfferRequest{}, false
+	}
+	if err != nil {
+		writeServiceError(w, logging.FromContext(r.Context()).With("offer_request_id", id),
+			"getting offer request failed", err)
+		return entities.OfferRequest{}, false
+	}
+	if principal, _ := auth.FromContext(r.Context()); !allowed(principal, request) {
+		auth.Forbidden(w)
+		return entities.OfferRequest{}, false
+	}
+	return request, true
+}
+
+func canQuote(principal auth.Principal, request entities.OfferRequest) bool {
+	return principal.CanManagePartner(request.PartnerID)
+}
+
+func canReadQuotes(principal auth.Principal, request entities.OfferRequest) bool {
+	return principal.CanManagePartner(request.PartnerID) || principal.CanActForCustomer(request.CustomerID)
+}
+
+func canDecideQuotes(principal auth.Principal, request entities.OfferRequest) bool {
+	return principal.CanActForCustomer(request.CustomerID)
+}
+
+// quotePath returns the ids in the path of a quote route like "/offer_requests/1/quotes/2/decision". quoteID is empty
+// for the quotes of the offer request.
+func quotePath(r *http.Request) (offerRequestID, quoteID string) {
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/offer_requests/"), "/")
+	if len(segments) > 2 {
+		quoteID = segments[2]
+	}
+	return segments[0], quoteID
+}
@@ -0,0 +1,171 @@
+package web_test
+
+import (
+	"customer-partner/internal/auth/authtest"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/web"
+	"customer-partner/internal/web/mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+//go:generate mockery --name QuoteService
+
+const quoteBody = `{
+	"currency": "EUR",
+	"line_items": [{"kind": "labour", "description": "Laying", "quantity": 16, "unit": "h", "unit_price": 5500}],
+	"vat_rate": 1900,
+	"valid_until": "2099-01-01T00:00:00Z"
+}`
+
+func TestPartnerAPI_CreateQuote(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	type testCase struct {
+		name       string
+		auth       string
+		body       string
+		expCreate  bool
+		serviceErr error
+		expStatus  int
+	}
+	tests := []testCase{
+		{name: "Returns 403 for other partner", auth: keys.PartnerToken(t, "partner", "2"), body: quoteBody, expStatus: http.StatusForbidden},
+		{name: "Returns 403 for customer", auth: keys.CustomerToken(t, "customer"), body: quoteBody, expStatus: http.StatusForbidden},
+		{name: "Returns 400 for missing currency", auth: keys.PartnerToken(t, "partner", "1"), body: `{"vat_rate":1900}`, expStatus: http.StatusBadRequest},
+		{name: "Returns 201 for partner of offer request", auth: keys.PartnerToken(t, "partner", "1"), body: quoteBody, expCreate: true, expStatus: http.StatusCreated},
+		{
+			name:       "Returns 409 for closed offer request",
+			auth:       keys.AdminToken(t, "admin"),
+			body:       quoteBody,
+			expCreate:  true,
+			serviceErr: entities.ErrOfferRequestClosed,
+			expStatus:  http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			offerRequests := &mocks.OfferRequestService{}
+			offerRequests.On("GetOfferRequest", mock.Anything, "abc").
+				Return(entities.OfferRequest{ID: "abc", PartnerID: "1", CustomerID: "customer"}, nil).Maybe()
+			quotes := &mocks.QuoteService{}
+			if tt.expCreate {
+				quotes.On("CreateQuote", mock.Anything, mock.MatchedBy(func(q entities.Quote) bool {
+					return q.OfferRequestID == "abc" && q.Currency == "EUR" && q.VATRate == 1900 && len(q.LineItems) == 1
+				})).Return(entities.Quote{ID: "q1", OfferRequestID: "abc"}, tt.serviceErr)
+			}
+			api := newAPI(web.Services{OfferRequests: offerRequests, Quotes: quotes}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodPost, "/offer_requests/abc/quotes", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			if tt.expStatus == http.StatusCreated {
+				assert.Equal(t, "/offer_requests/abc/quotes/q1", rec.Header().Get("Location"))
+			}
+			quotes.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPartnerAPI_GetQuotes(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	type testCase struct {
+		name      string
+		auth      string
+		expList   bool
+		expStatus int
+	}
+	tests := []testCase{
+		{name: "Returns 200 for customer of offer request", auth: keys.CustomerToken(t, "customer"), expList: true, expStatus: http.StatusOK},
+		{name: "Returns 200 for partner of offer request", auth: keys.PartnerToken(t, "partner", "1"), expList: true, expStatus: http.StatusOK},
+		{name: "Returns 403 for other customer", auth: keys.CustomerToken(t, "other"), expStatus: http.StatusForbidden},
+		{name: "Returns 403 for other partner", auth: keys.PartnerToken(t, "partner", "2"), expStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			offerRequests := &mocks.OfferRequestService{}
+			offerRequests.On("GetOfferRequest", mock.Anything, "abc").
+				Return(entities.OfferRequest{ID: "abc", PartnerID: "1", CustomerID: "customer"}, nil)
+			quotes := &mocks.QuoteService{}
+			if tt.expList {
+				quotes.On("GetQuotesByOfferRequest", mock.Anything, "abc").
+					Return([]entities.Quote{{ID: "q1", OfferRequestID: "abc"}}, nil)
+			}
+			api := newAPI(web.Services{OfferRequests: offerRequests, Quotes: quotes}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodGet, "/offer_requests/abc/quotes", nil)
+			req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			quotes.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPartnerAPI_DecideQuote(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	type testCase struct {
+		name       string
+		auth       string
+		quote      entities.Quote
+		body       string
+		expDecide  bool
+		serviceErr error
+		expStatus  int
+	}
+	pending := entities.Quote{ID: "q1", OfferRequestID: "abc", Status: entities.QuotePending}
+	otherRequest := entities.Quote{ID: "q1", OfferRequestID: "other", Status: entities.QuotePending}
+	tests := []testCase{
+		{name: "Returns 403 for partner", auth: keys.PartnerToken(t, "partner", "1"), quote: pending, body: `{"decision":"accepted"}`, expStatus: http.StatusForbidden},
+		{name: "Returns 403 for other customer", auth: keys.CustomerToken(t, "other"), quote: pending, body: `{"decision":"accepted"}`, expStatus: http.StatusForbidden},
+		{name: "Returns 400 for missing decision", auth: keys.CustomerToken(t, "customer"), quote: pending, body: `{}`, expStatus: http.StatusBadRequest},
+		{name: "Returns 404 for quote of other offer request", auth: keys.CustomerToken(t, "customer"), quote: otherRequest, body: `{"decision":"accepted"}`, expStatus: http.StatusNotFound},
+		{name: "Returns 200 for customer of offer request", auth: keys.CustomerToken(t, "customer"), quote: pending, body: `{"decision":"accepted"}`, expDecide: true, expStatus: http.StatusOK},
+		{
+			name:       "Returns 409 for quote not pending",
+			auth:       keys.CustomerToken(t, "customer"),
+			quote:      pending,
+			body:       `{"decision":"accepted"}`,
+			expDecide:  true,
+			serviceErr: entities.ErrQuoteNotPending,
+			expStatus:  http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			offerRequests := &mocks.OfferRequestService{}
+			offerRequests.On("GetOfferRequest", mock.Anything, "abc").
+				Return(entities.OfferRequest{ID: "abc", PartnerID: "1", CustomerID: "customer"}, nil).Maybe()
+			quotes := &mocks.QuoteService{}
+			quotes.On("GetQuote", mock.Anything, "q1").Return(tt.quote, nil).Maybe()
+			if tt.expDecide {
+				decided := tt.quote
+				decided.Status = entities.QuoteAccepted
+				quotes.On("DecideQuote", mock.Anything, "q1", entities.QuoteAccepted).Return(decided, tt.serviceErr)
+			}
+			api := newAPI(web.Services{OfferRequests: offerRequests, Quotes: quotes}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodPost, "/offer_requests/abc/quotes/q1/decision", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			quotes.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("customer-partner/internal/web")
+
+// Tracing starts a server span for every request. A trace context sent by the client in the W3C traceparent header is
+// continued. route maps a request to the pattern of the route serving it and is used as span name.
+func Tracing(route func(r *http.Request) string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			pattern := route(r)
+			ctx, span := tracer.Start(ctx, fmt.Sprintf("%s %s", r.Method, pattern),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", r.Method),
+					attribute.String("http.route", pattern),
+					attribute.String("url.path", r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			span.SetAttributes(attribute.Int("http.response.status_code", rec.Status()))
+			if rec.Status() >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(rec.Status()))
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package web
+
+import (
+	"context"
+	"customer-partner/internal/auth"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/logging"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type WebhookService interface {
+	CreateWebhookSubscription(
+		ctx context.Context,
+		subscription entities.WebhookSubscription,
+	) (entities.WebhookSubscription, error)
+	GetWebhookSubscriptions(ctx context.Context, partnerID string) ([]entities.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, partnerID, id string) error
+	GetWebhookDeliveries(ctx context.Context, partnerID string, status string) ([]entities.WebhookDelivery, error)
+}
+
+// webhookStatuses lists the values of the status filter of webhook deliveries.
+var webhookStatuses = []string{
+	entities.WebhookDeliveryPending,
+	entities.WebhookDeliveryDelivered,
+	entities.WebhookDeliveryDeadLetter,
+}
+
+// webhookBody is the request body to create a webhook subscription.
+type webhookBody struct {
+	URL        *string  `json:"url"`
+	EventTypes []string `json:"event_types"`
+}
+
+// CreateWebhook subscribes a url of a partner to events. The response contains the secret the payloads are signed
+// with, it cannot be read again.
+func (a *PartnerAPI) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "createWebhook")
+	partnerID, ok := managedPartner(w, r)
+	if !ok {
+		return
+	}
+	var body webhookBody
+	err := decodeJSON(w, r, &body)
+	switch {
+	case err != nil:
+	case body.URL == nil:
+		err = ErrMissingArgument("url")
+	case body.EventTypes == nil:
+		err = ErrMissingArgument("event_types")
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	subscription, err := a.webhooks.CreateWebhookSubscription(r.Context(), entities.WebhookSubscription{
+		PartnerID:  partnerID,
+		URL:        *body.URL,
+		EventTypes: body.EventTypes,
+	})
+	if writeValidationError(w, err) {
+		return
+	}
+	if errors.Is(err, entities.ErrRecordNotExist) {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger.With("partner_id", partnerID), "creating webhook failed", err)
+		return
+	}
+	w.Header().Set("Location", "/partners/"+partnerID+"/webhooks/"+subscription.ID)
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusCreated, subscription)
+}
+
+// GetWebhooks lists the webhook subscriptions of a partner without their secrets.
+func (a *PartnerAPI) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "getWebhooks")
+	partnerID, ok := managedPartner(w, r)
+	if !ok {
+		return
+	}
+	subscriptions, err := a.webhooks.GetWebhookSubscriptions(r.Context(), partnerID)
+	if err != nil {
+		writeServiceError(w, logger.With("partner_id", partnerID), "getting webhooks failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, subscriptions)
+}
+
+// DeleteWebhook removes a webhook subscription of a partner.
+func (a *PartnerAPI) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "deleteWebhook")
+	partnerID, ok := managedPartner(w, r)
+	if !ok {
+		return
+	}
+	id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	err := a.webhooks.DeleteWebhookSubscription(r.Context(), partnerID, id)
+	if errors.Is(err, entities.ErrRecordNotExist) {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, logger.With("partner_id", partnerID), "deleting webhook failed", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries lists the deliveries to the webhooks of a partner with their attempts, newest first. The
+// status query parameter filters them, e.g. status=dead_letter lists the deliveries which were given up.
+func (a *PartnerAPI) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	logger.Debug("endpoint hit", "endpoint", "getWebhookDeliveries")
+	partnerID, ok := managedPartner(w, r)
+	if !ok {
+		return
+	}
+	status := r.URL.Query().Get("status")
+	if r.URL.Query().Has("status") && !stringInSlice(status, webhookStatuses) {
+		http.Error(w, fmt.Sprintf("Bad request: %s", ErrInvalidInput("status")), http.StatusBadRequest)
+		return
+	}
+	deliveries, err := a.webhooks.GetWebhookDeliveries(r.Context(), partnerID, status)
+	if err != nil {
+		writeServiceError(w, logger.With("partner_id", partnerID), "getting webhook deliveries failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+// managedPartner returns the id of the partner in the path of a subresource like "/partners/1/webhooks". It responds
+// with 403 and reports false when the caller may not manage the partner.
+func managedPartner(w http.ResponseWriter, r *http.Request) (string, bool) {
+	partnerID, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/partners/"), "/")
+	if principal, _ := auth.FromContext(r.Context()); !principal.CanManagePartner(partnerID) {
+		auth.Forbidden(w)
+		return "", false
+	}
+	return partnerID, true
+}
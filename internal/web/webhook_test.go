@@ -0,0 +1,161 @@
+package web_test
+
+import (
+	"customer-partner/internal/auth/authtest"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/web"
+	"customer-partner/internal/web/mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+//go:generate mockery --name WebhookService
+
+func TestPartnerAPI_CreateWebhook(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	input := entities.WebhookSubscription{
+		PartnerID:  "1",
+		URL:        "https://crm.example.com/hooks",
+		EventTypes: []string{entities.EventOfferRequested},
+	}
+	type testCase struct {
+		name       string
+		auth       string
+		body       string
+		expCreate  bool
+		serviceErr error
+		expStatus  int
+		expBody    string
+	}
+	tests := []testCase{
+		{
+			name:      "Returns 403 for other partner",
+			auth:      keys.PartnerToken(t, "partner", "2"),
+			body:      `{"url":"https://crm.example.com/hooks","event_types":["OfferRequested"]}`,
+			expStatus: http.StatusForbidden,
+		},
+		{
+			name:      "Returns 400 for missing url",
+			auth:      keys.PartnerToken(t, "partner", "1"),
+			body:      `{"event_types":["OfferRequested"]}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "Returns 201 with the secret",
+			auth:      keys.PartnerToken(t, "partner", "1"),
+			body:      `{"url":"https://crm.example.com/hooks","event_types":["OfferRequested"]}`,
+			expCreate: true,
+			expStatus: http.StatusCreated,
+			expBody: `{"id":"w1","partner_id":"1","url":"https://crm.example.com/hooks","secret":"whsec_abc",` +
+				`"event_types":["OfferRequested"],"created_at":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:       "Returns 404 for unknown partner",
+			auth:       keys.AdminToken(t, "admin"),
+			body:       `{"url":"https://crm.example.com/hooks","event_types":["OfferRequested"]}`,
+			expCreate:  true,
+			serviceErr: entities.ErrRecordNotExist,
+			expStatus:  http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.WebhookService{}
+			if tt.expCreate {
+				created := input
+				created.ID = "w1"
+				created.Secret = "whsec_abc"
+				service.On("CreateWebhookSubscription", mock.Anything, input).Return(created, tt.serviceErr)
+			}
+			api := newAPI(web.Services{Webhooks: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodPost, "/partners/1/webhooks", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", authtest.Bearer(tt.auth))
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			if tt.expBody != "" {
+				assert.JSONEq(t, tt.expBody, rec.Body.String())
+				assert.Equal(t, "/partners/1/webhooks/w1", rec.Header().Get("Location"))
+				assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPartnerAPI_DeleteWebhook(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	type testCase struct {
+		name       string
+		serviceErr error
+		expStatus  int
+	}
+	tests := []testCase{
+		{name: "Returns 204 after deleting", expStatus: http.StatusNoContent},
+		{name: "Returns 404 for unknown webhook", serviceErr: entities.ErrRecordNotExist, expStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.WebhookService{}
+			service.On("DeleteWebhookSubscription", mock.Anything, "1", "w1").Return(tt.serviceErr)
+			api := newAPI(web.Services{Webhooks: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodDelete, "/partners/1/webhooks/w1", nil)
+			req.Header.Set("Authorization", authtest.Bearer(keys.PartnerToken(t, "partner", "1")))
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expStatus, rec.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPartnerAPI_GetWebhookDeliveries(t *testing.T) {
+	keys := authtest.NewKeySet(t)
+	type testCase struct {
+		name      string
+		query     string
+		expStatus string
+		expCode   int
+	}
+	tests := []testCase{
+		{name: "Returns all deliveries", expCode: http.StatusOK},
+		{
+			name:      "Filters deliveries by status",
+			query:     "?status=dead_letter",
+			expStatus: entities.WebhookDeliveryDeadLetter,
+			expCode:   http.StatusOK,
+		},
+		{name: "Returns 400 for unknown status", query: "?status=lost", expCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.WebhookService{}
+			if tt.expCode == http.StatusOK {
+				service.On("GetWebhookDeliveries", mock.Anything, "1", tt.expStatus).
+					Return([]entities.WebhookDelivery{}, nil)
+			}
+			api := newAPI(web.Services{Webhooks: service}, keys.Authenticator(t))
+			req := httptest.NewRequest(http.MethodGet, "/partners/1/webhook-deliveries"+tt.query, nil)
+			req.Header.Set("Authorization", authtest.Bearer(keys.PartnerToken(t, "partner", "1")))
+			rec := httptest.NewRecorder()
+
+			api.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expCode, rec.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
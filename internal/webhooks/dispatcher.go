@@ -0,0 +1,275 @@
+// Package webhooks delivers events to the webhook subscriptions of partners with signed requests, retries failed
+// deliveries with exponential backoff and moves deliveries which keep failing to the dead letters.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"customer-partner/internal/domain"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/events"
+	"customer-partner/internal/logging"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// DeliveryIDHeader carries the id of the delivery, which stays the same for all attempts.
+const DeliveryIDHeader = "X-Webhook-ID"
+
+// batchSize limits the number of deliveries read at once.
+const batchSize = 100
+
+// Config controls the attempts of deliveries.
+type Config struct {
+	// MaxAttempts is the number of attempts after which a delivery is moved to the dead letters.
+	MaxAttempts int
+	// InitialBackoff is the delay after the first failed attempt. It doubles with every further attempt up to
+	// MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Timeout limits the time of a single attempt.
+	Timeout time.Duration
+}
+
+// DefaultConfig retries a delivery for about three and a half hours.
+var DefaultConfig = Config{
+	MaxAttempts:    12,
+	InitialBackoff: 10 * time.Second,
+	MaxBackoff:     time.Hour,
+	Timeout:        10 * time.Second,
+}
+
+// NewDispatcher creates the dispatcher. client may be nil to use a client which does not follow redirects and only
+// connects to public addresses.
+func NewDispatcher(repository domain.WebhookRepository, client *http.Client, cfg Config, logger *slog.Logger) *Dispatcher {
+	if client == nil {
+		client = newClient(domain.PublicAddress)
+	}
+	return &Dispatcher{repository: repository, client: client, cfg: cfg, logger: logger, now: time.Now}
+}
+
+// newClient returns a client which does not follow redirects and refuses to connect to addresses not allowed by
+// allow. The address is checked after the host name was resolved, so that names of partners resolving to internal
+// addresses are refused as well. Proxies are not used, because the address of the proxy would be checked instead.
+func newClient(allow func(net.IP) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !allow(ip) {
+				return fmt.Errorf("address %s is not public", host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Dispatcher turns events into deliveries to the subscriptions of the partner the event is about and delivers them.
+// It is an events.Publisher for the relay, the deliveries are sent by Deliver in the background, so that slow
+// receivers do not hold up other publishers.
+type Dispatcher struct {
+	repository domain.WebhookRepository
+	client     *http.Client
+	cfg        Config
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+var _ events.Publisher = (*Dispatcher)(nil)
+
+// Publish creates a pending delivery for every subscription of the partner which subscribed to the type of the
+// event. Events which are published again are delivered only once.
+func (d *Dispatcher) Publish(ctx context.Context, event entities.Event) error {
+	partnerID, ok := partnerOf(event)
+	if !ok {
+		return nil
+	}
+	subscriptions, err := d.repository.GetWebhookSubscriptionsByPartner(ctx, partnerID)
+	if err != nil {
+		return err
+	}
+	now := d.now().UTC()
+	for _, subscription := range subscriptions {
+		if !subscribed(subscription, event.Type) {
+			continue
+		}
+		err := d.repository.CreateWebhookDelivery(ctx, entities.WebhookDelivery{
+			ID:             deliveryID(subscription.ID, event.ID),
+			SubscriptionID: subscription.ID,
+			PartnerID:      partnerID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Status:         entities.WebhookDeliveryPending,
+			Attempts:       []entities.WebhookAttempt{},
+			NextAttemptAt:  &now,
+			CreatedAt:      now,
+			Event:          event,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deliver attempts all deliveries which are due. It is run periodically.
+func (d *Dispatcher) Deliver(ctx context.Context) error {
+	for {
+		deliveries, err := d.repository.GetDueWebhookDeliveries(ctx, d.now(), batchSize)
+		if err != nil {
+			return err
+		}
+		for _, delivery := range deliveries {
+			if err := d.attempt(ctx, delivery); err != nil {
+				return err
+			}
+		}
+		if len(deliveries) < batchSize {
+			return nil
+		}
+	}
+}
+
+// attempt sends the delivery once and stores the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, delivery entities.WebhookDelivery) error {
+	logger := logging.FromContextOr(ctx, d.logger).With(
+		"webhook_delivery_id", delivery.ID,
+		"webhook_id", delivery.SubscriptionID,
+		"partner_id", delivery.PartnerID,
+	)
+	attempt := entities.WebhookAttempt{At: d.now().UTC()}
+	subscription, err := d.repository.GetWebhookSubscription(ctx, delivery.SubscriptionID)
+	switch {
+	case errors.Is(err, entities.ErrRecordNotExist):
+		attempt.Error = "subscription deleted"
+		delivery.Attempts = append(delivery.Attempts, attempt)
+		delivery.Status = entities.WebhookDeliveryDeadLetter
+		delivery.NextAttemptAt = nil
+		return d.repository.UpdateWebhookDelivery(ctx, delivery)
+	case err != nil:
+		return err
+	}
+
+	attempt.StatusCode, err = d.send(ctx, subscription, delivery)
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	switch {
+	case err == nil:
+		delivery.Status = entities.WebhookDeliveryDelivered
+		delivery.NextAttemptAt = nil
+		logger.Debug("webhook delivered", "attempts", len(delivery.Attempts))
+	case len(delivery.Attempts) >= d.cfg.MaxAttempts:
+		delivery.Status = entities.WebhookDeliveryDeadLetter
+		delivery.NextAttemptAt = nil
+		logger.Warn("webhook delivery moved to dead letters", "attempts", len(delivery.Attempts), "error", err)
+	default:
+		next := attempt.At.Add(d.backoff(len(delivery.Attempts)))
+		delivery.NextAttemptAt = &next
+		logger.Info("webhook delivery failed", "attempts", len(delivery.Attempts), "next_attempt_at", next, "error", err)
+	}
+	return d.repository.UpdateWebhookDelivery(ctx, delivery)
+}
+
+// send posts the signed event to the subscription and returns the status code of the response.
+func (d *Dispatcher) send(
+	ctx context.Context,
+	subscription entities.WebhookSubscription,
+	delivery entities.WebhookDelivery,
+) (int, error) {
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(events.EventIDHeader, delivery.EventID)
+	req.Header.Set(events.EventTypeHeader, delivery.EventType)
+	req.Header.Set(DeliveryIDHeader, delivery.ID)
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, d.now(), body))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay after the given number of failed attempts.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.InitialBackoff
+	for i := 1; i < attempts && delay < d.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.cfg.MaxBackoff {
+		return d.cfg.MaxBackoff
+	}
+	return delay
+}
+
+// partnerOf returns the id of the partner an event is about. ok is false for events partners cannot subscribe to.
+func partnerOf(event entities.Event) (partnerID string, ok bool) {
+	switch event.Type {
+	case entities.EventOfferRequested:
+		var data domain.OfferRequested
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return "", false
+		}
+		return data.PartnerID, true
+	case entities.EventPartnerUpdated, entities.EventPartnerDeleted, entities.EventCertificationExpiring:
+		return event.AggregateID, true
+	case entities.EventQuoteAccepted, entities.EventQuoteDeclined:
+		var data domain.QuoteDecided
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return "", false
+		}
+		return data.PartnerID, true
+	}
+	return "", false
+}
+
+func subscribed(subscription entities.WebhookSubscription, eventType string) bool {
+	for _, t := range subscription.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// deliveryID derives the id of the delivery of an event to a subscription.
+func deliveryID(subscriptionID, eventID string) string {
+	sum := sha256.Sum256([]byte(subscriptionID + "/" + eventID))
+	return hex.EncodeToString(sum[:16])
+}
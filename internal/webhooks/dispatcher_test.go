@@ -0,0 +1,234 @@
+package webhooks
+
+import (
+	"context"
+	"customer-partner/internal/db"
+	"customer-partner/internal/entities"
+	"customer-partner/internal/events"
+	"customer-partner/internal/logging"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clock is the time of the dispatcher and the receivers in tests.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// receiver is a webhook endpoint of a partner. It verifies the signature and answers with the configured status.
+type receiver struct {
+	mu       sync.Mutex
+	clock    *clock
+	status   int
+	received []string
+	errors   []error
+}
+
+func (rc *receiver) setStatus(status int) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.status = status
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if err := Verify("whsec_test", r.Header.Get(SignatureHeader), body, time.Minute, rc.clock.Now()); err != nil {
+		rc.errors = append(rc.errors, err)
+	}
+	var event entities.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		rc.errors = append(rc.errors, err)
+	}
+	rc.received = append(rc.received, r.Header.Get(events.EventTypeHeader)+" "+event.ID+" "+r.Header.Get(DeliveryIDHeader))
+	w.WriteHeader(rc.status)
+}
+
+func offerRequested(t *testing.T, id, partnerID string) entities.Event {
+	data, err := json.Marshal(map[string]any{"id": id, "partner_id": partnerID})
+	require.NoError(t, err)
+	return entities.Event{ID: "event-" + id, Type: entities.EventOfferRequested, AggregateID: id, Data: data}
+}
+
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+	clk := &clock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	rc := &receiver{clock: clk, status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+	repo := db.NewWebhookInMemoryRepository()
+	require.NoError(t, repo.CreateWebhookSubscription(ctx, entities.WebhookSubscription{
+		ID:         "sub",
+		PartnerID:  "1",
+		URL:        server.URL,
+		Secret:     "whsec_test",
+		EventTypes: []string{entities.EventOfferRequested},
+	}))
+	cfg := Config{MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: time.Hour, Timeout: time.Second}
+	dispatcher := NewDispatcher(repo, server.Client(), cfg, logging.Discard())
+	dispatcher.now = clk.Now
+
+	event := offerRequested(t, "o1", "1")
+	require.NoError(t, dispatcher.Publish(ctx, event))
+	require.NoError(t, dispatcher.Publish(ctx, event), "events published twice are delivered once")
+	require.NoError(t, dispatcher.Publish(ctx, offerRequested(t, "o2", "2")), "events of other partners are ignored")
+	partnerUpdated := entities.Event{ID: "event-p1", Type: entities.EventPartnerUpdated, AggregateID: "1"}
+	require.NoError(t, dispatcher.Publish(ctx, partnerUpdated), "events without subscription are ignored")
+
+	require.NoError(t, dispatcher.Deliver(ctx))
+	deliveries, err := repo.GetWebhookDeliveriesByPartner(ctx, "1")
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	delivery := deliveries[0]
+	assert.Equal(t, entities.WebhookDeliveryPending, delivery.Status)
+	require.Len(t, delivery.Attempts, 1)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.Attempts[0].StatusCode)
+	assert.Equal(t, clk.Now().Add(time.Minute), *delivery.NextAttemptAt)
+
+	require.NoError(t, dispatcher.Deliver(ctx))
+	deliveries, err = repo.GetWebhookDeliveriesByPartner(ctx, "1")
+	require.NoError(t, err)
+	assert.Len(t, deliveries[0].Attempts, 1, "deliveries are not attempted before the backoff passed")
+
+	clk.Set(*delivery.NextAttemptAt)
+	rc.setStatus(http.StatusNoContent)
+	require.NoError(t, dispatcher.Deliver(ctx))
+	deliveries, err = repo.GetWebhookDeliveriesByPartner(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, entities.WebhookDeliveryDelivered, deliveries[0].Status)
+	assert.Len(t, deliveries[0].Attempts, 2)
+	assert.Nil(t, deliveries[0].NextAttemptAt)
+
+	assert.Empty(t, rc.errors)
+	assert.Equal(t, []string{
+		"OfferRequested event-o1 " + delivery.ID,
+		"OfferRequested event-o1 " + delivery.ID,
+	}, rc.received)
+}
+
+func TestDispatcher_DeadLetter(t *testing.T) {
+	ctx := context.Background()
+	clk := &clock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	rc := &receiver{clock: clk, status: http.StatusInternalServerError}
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+	repo := db.NewWebhookInMemoryRepository()
+	for _, id := range []string{"failing", "deleted"} {
+		require.NoError(t, repo.CreateWebhookSubscription(ctx, entities.WebhookSubscription{
+			ID:         id,
+			PartnerID:  "1",
+			URL:        server.URL + "/" + id,
+			Secret:     "whsec_test",
+			EventTypes: []string{entities.EventOfferRequested},
+		}))
+	}
+	cfg := Config{MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: 90 * time.Second, Timeout: time.Second}
+	dispatcher := NewDispatcher(repo, server.Client(), cfg, logging.Discard())
+	dispatcher.now = clk.Now
+	require.NoError(t, dispatcher.Publish(ctx, offerRequested(t, "o1", "1")))
+	require.NoError(t, repo.DeleteWebhookSubscription(ctx, "deleted"))
+
+	var nextAttempts []time.Duration
+	for i := 0; i < 3; i++ {
+		require.NoError(t, dispatcher.Deliver(ctx))
+		deliveries, err := repo.GetDueWebhookDeliveries(ctx, clk.Now().Add(time.Hour), 10)
+		require.NoError(t, err)
+		if len(deliveries) == 0 {
+			break
+		}
+		nextAttempts = append(nextAttempts, deliveries[0].NextAttemptAt.Sub(clk.Now()))
+		clk.Set(*deliveries[0].NextAttemptAt)
+	}
+
+	assert.Equal(t, []time.Duration{time.Minute, 90 * time.Second}, nextAttempts, "backoff doubles up to the maximum")
+	deliveries, err := repo.GetWebhookDeliveriesByPartner(ctx, "1")
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	for _, delivery := range deliveries {
+		assert.Equal(t, entities.WebhookDeliveryDeadLetter, delivery.Status)
+		assert.Nil(t, delivery.NextAttemptAt)
+		if delivery.SubscriptionID == "deleted" {
+			require.Len(t, delivery.Attempts, 1)
+			assert.Equal(t, "subscription deleted", delivery.Attempts[0].Error)
+		} else {
+			require.Len(t, delivery.Attempts, 3)
+			assert.Equal(t, "unexpected status 500", delivery.Attempts[2].Error)
+		}
+	}
+}
+
+func TestDispatcher_DoesNotFollowRedirects(t *testing.T) {
+	ctx := context.Background()
+	internal := &receiver{clock: &clock{now: time.Now()}, status: http.StatusOK}
+	internalServer := httptest.NewServer(internal)
+	t.Cleanup(internalServer.Close)
+	redirect := httptest.NewServer(http.RedirectHandler(internalServer.URL, http.StatusTemporaryRedirect))
+	t.Cleanup(redirect.Close)
+	repo := db.NewWebhookInMemoryRepository()
+	require.NoError(t, repo.CreateWebhookSubscription(ctx, entities.WebhookSubscription{
+		ID:         "sub",
+		PartnerID:  "1",
+		URL:        redirect.URL,
+		EventTypes: []string{entities.EventOfferRequested},
+	}))
+	allowAll := func(net.IP) bool { return true }
+	dispatcher := NewDispatcher(repo, newClient(allowAll), DefaultConfig, logging.Discard())
+	require.NoError(t, dispatcher.Publish(ctx, offerRequested(t, "o1", "1")))
+
+	require.NoError(t, dispatcher.Deliver(ctx))
+
+	deliveries, err := repo.GetWebhookDeliveriesByPartner(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTemporaryRedirect, deliveries[0].Attempts[0].StatusCode)
+	assert.Empty(t, internal.received)
+}
+
+func TestDispatcher_RefusesInternalAddresses(t *testing.T) {
+	ctx := context.Background()
+	internal := &receiver{clock: &clock{now: time.Now()}, status: http.StatusOK}
+	server := httptest.NewServer(internal)
+	t.Cleanup(server.Close)
+	repo := db.NewWebhookInMemoryRepository()
+	// The name resolves to the loopback address of the server, like a name of a partner pointing into the network.
+	require.NoError(t, repo.CreateWebhookSubscription(ctx, entities.WebhookSubscription{
+		ID:         "sub",
+		PartnerID:  "1",
+		URL:        "http://localhost:" + strconv.Itoa(server.Listener.Addr().(*net.TCPAddr).Port),
+		EventTypes: []string{entities.EventOfferRequested},
+	}))
+	dispatcher := NewDispatcher(repo, nil, DefaultConfig, logging.Discard())
+	require.NoError(t, dispatcher.Publish(ctx, offerRequested(t, "o1", "1")))
+
+	require.NoError(t, dispatcher.Deliver(ctx))
+
+	deliveries, err := repo.GetWebhookDeliveriesByPartner(ctx, "1")
+	require.NoError(t, err)
+	require.Len(t, deliveries[0].Attempts, 1)
+	assert.Contains(t, deliveries[0].Attempts[0].Error, "is not public")
+	assert.Equal(t, entities.WebhookDeliveryPending, deliveries[0].Status)
+	assert.Empty(t, internal.received)
+}
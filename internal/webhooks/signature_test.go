@@ -0,0 +1,71 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	sentAt := time.Unix(1714564800, 0)
+	body := []byte(`{"id":"e1"}`)
+	signature := Sign("whsec_test", sentAt, body)
+	type testCase struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		expErr error
+	}
+	tests := []testCase{
+		{name: "Accepts valid signature", secret: "whsec_test", header: signature, body: body, now: sentAt},
+		{
+			name:   "Accepts one of several signatures, e.g. while the secret is rotated",
+			secret: "whsec_test",
+			header: signature + ",v1=00ff",
+			body:   body,
+			now:    sentAt,
+		},
+		{
+			name:   "Rejects other secret",
+			secret: "whsec_other",
+			header: signature,
+			body:   body,
+			now:    sentAt,
+			expErr: ErrInvalidSignature,
+		},
+		{
+			name:   "Rejects changed body",
+			secret: "whsec_test",
+			header: signature,
+			body:   []byte(`{"id":"e2"}`),
+			now:    sentAt,
+			expErr: ErrInvalidSignature,
+		},
+		{
+			name:   "Rejects changed timestamp",
+			secret: "whsec_test",
+			header: "t=1714564801" + signature[len("t=1714564800"):],
+			body:   body,
+			now:    sentAt,
+			expErr: ErrInvalidSignature,
+		},
+		{
+			name:   "Rejects old signature",
+			secret: "whsec_test",
+			header: signature,
+			body:   body,
+			now:    sentAt.Add(6 * time.Minute),
+			expErr: ErrExpiredSignature,
+		},
+		{name: "Rejects malformed header", secret: "whsec_test", header: "garbage", body: body, expErr: ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expErr, Verify(tt.secret, tt.header, tt.body, 5*time.Minute, tt.now))
+		})
+	}
+}